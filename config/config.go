@@ -1,7 +1,7 @@
 package config
 
 import (
-	"log"
+	"log/slog"
 	"os"
 
 	"github.com/gocroot/helper/at"
@@ -21,12 +21,14 @@ var PhoneNumber string = os.Getenv("PHONENUMBER")
 var Profile itmodel.Profile
 
 func SetEnv() {
-	if ErrorMongoconn != nil {
-		log.Println(ErrorMongoconn.Error())
+	db := Mongoconn()
+	if db == nil {
+		slog.Error("profil whatsauth tidak dimuat", "error", MongoconnError())
+		return
 	}
-	Profile, err := atdb.GetOneDoc[itmodel.Profile](Mongoconn, "profile", primitive.M{"phonenumber": PhoneNumber})
+	Profile, err := atdb.GetOneDoc[itmodel.Profile](db, "profile", primitive.M{"phonenumber": PhoneNumber})
 	if err != nil {
-		log.Println(err)
+		slog.Error("profil whatsauth tidak ditemukan", "phonenumber", PhoneNumber, "error", err)
 	}
 	PublicKeyWhatsAuth = Profile.PublicKey
 	WAAPIToken = Profile.Token
@@ -4,18 +4,10 @@ import (
 	"net/http"
 )
 
-// Daftar origins yang diizinkan
-var Origins = []string{
-	"https://www.bukupedia.co.id",
-	"https://naskah.bukupedia.co.id",
-	"https://bukupedia.co.id",
-	"http://127.0.0.1:5500", // Origin lokal untuk pengujian
-    "https://itung.in.my.id",
-}
-
-// Fungsi untuk memeriksa apakah origin diizinkan
+// Fungsi untuk memeriksa apakah origin diizinkan, daftarnya dari konfigurasi (CORS_ORIGINS)
 func isAllowedOrigin(origin string) bool {
-	for _, o := range Origins {
+	settings, _ := Load()
+	for _, o := range settings.Origins {
 		if o == origin {
 			return true
 		}
@@ -32,7 +24,7 @@ func SetAccessControlHeaders(w http.ResponseWriter, r *http.Request) bool {
         // Tangani preflight request (OPTIONS)
         if r.Method == http.MethodOptions {
             w.Header().Set("Access-Control-Allow-Credentials", "true")
-            w.Header().Set("Access-Control-Allow-Headers", "Content-Type,Login")
+            w.Header().Set("Access-Control-Allow-Headers", "Content-Type,Login,X-Request-ID")
             w.Header().Set("Access-Control-Allow-Methods", "POST, GET, DELETE, PUT, OPTIONS")
             w.Header().Set("Access-Control-Allow-Origin", origin)  // Set header origin yang diizinkan
             w.Header().Set("Access-Control-Max-Age", "3600")       // Cache preflight request selama 1 jam
@@ -44,6 +36,7 @@ func SetAccessControlHeaders(w http.ResponseWriter, r *http.Request) bool {
         w.Header().Set("Access-Control-Allow-Credentials", "true")
         w.Header().Set("Access-Control-Allow-Origin", origin) // Set origin yang diizinkan
         w.Header().Set("Access-Control-Allow-Methods", "POST, GET, DELETE, PUT, OPTIONS") // Pastikan metode lainnya diizinkan
+        w.Header().Set("Access-Control-Expose-Headers", "X-Total-Count, X-Total-Pages, X-Page, X-Limit, X-Next-Cursor, X-Request-ID") // Info halaman pada list endpoint dan ID request
         return false
     }
 
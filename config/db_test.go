@@ -0,0 +1,49 @@
+package config
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func TestLazyDBRetriesAfterFailure(t *testing.T) {
+	db := &mongo.Database{}
+	calls := 0
+	l := &lazyDB{dial: func() (*mongo.Database, error) {
+		calls++
+		if calls == 1 {
+			return nil, errors.New("server selection timeout")
+		}
+		return db, nil
+	}}
+	if got := l.get(); got != nil || l.lastErr() == nil {
+		t.Fatalf("get setelah gagal = %v, error %v, want nil dan error", got, l.lastErr())
+	}
+	if got := l.get(); got != db || l.lastErr() != nil {
+		t.Fatalf("get kedua = %v, error %v, want koneksi baru tanpa error", got, l.lastErr())
+	}
+	if got := l.get(); got != db || calls != 2 {
+		t.Errorf("get ketiga = %v, dial %d kali, want koneksi tersimpan", got, calls)
+	}
+}
+
+func TestLazyDBConcurrentGet(t *testing.T) {
+	l := &lazyDB{dial: func() (*mongo.Database, error) {
+		return nil, errors.New("server selection timeout")
+	}}
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			l.get()
+			l.lastErr()
+		}()
+	}
+	wg.Wait()
+	if l.lastErr() == nil {
+		t.Error("error koneksi terakhir harus tersimpan")
+	}
+}
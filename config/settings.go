@@ -0,0 +1,242 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/gocroot/helper/logger"
+	"github.com/gocroot/helper/validasi"
+	"gopkg.in/yaml.v2"
+)
+
+// Profil lingkungan yang didukung, dipilih dengan APP_ENV
+const (
+	EnvDev     = "dev"
+	EnvStaging = "staging"
+	EnvProd    = "prod"
+)
+
+// Nama environment variable konfigurasi. Nilai environment menimpa isi file.
+const (
+	EnvVarProfile  = "APP_ENV"
+	EnvVarFile     = "CONFIG_FILE"
+	EnvVarMongo    = "MONGOSTRING"
+	EnvVarMongoDB  = "MONGODB_NAME"
+	EnvVarGeo      = "MONGOSTRINGGEO"
+	EnvVarGeoDB    = "GEODB_NAME"
+	EnvVarOrigins  = "CORS_ORIGINS" // Dipisah koma
+	EnvVarLogLevel = "LOG_LEVEL"
+	EnvVarMetrics  = "METRICS_TOKEN"   // /metrics wajib memakai Authorization: Bearer <token>, kosong berarti /metrics ditutup
+	EnvVarProxies  = "TRUSTED_PROXIES" // IP atau CIDR proxy yang header X-Forwarded-For-nya dipercaya, dipisah koma
+	EnvVarCron     = "CRON_SECRET"     // Token Authorization: Bearer untuk endpoint cron, kosong berarti cron ditolak
+	EnvVarLegacy   = "LEGACY_OWNER"    // Nomor akun penjual pemilik data lama, hanya dipakai run/backfillowner
+	defaultMongoDB = "akuntan"
+	defaultGeoDB   = "Geo"
+	// defaultDevOrigin adalah origin lokal untuk pengujian frontend di profil dev
+	defaultDevOrigin = "http://127.0.0.1:5500"
+)
+
+// Settings adalah konfigurasi yang berbeda di setiap lingkungan
+type Settings struct {
+	Env      string   `yaml:"-"`
+	MongoURI string   `yaml:"mongo_uri"`
+	MongoDB  string   `yaml:"mongo_db"`
+	GeoURI   string   `yaml:"geo_uri"` // Kosong berarti memakai cluster MongoURI
+	GeoDB    string   `yaml:"geo_db"`
+	Origins  []string `yaml:"origins"`   // Origin CORS yang diizinkan
+	LogLevel string   `yaml:"log_level"` // debug, info, warn atau error
+	// MetricsToken melindungi endpoint /metrics, kosong berarti /metrics ditutup. Sebaiknya diisi lewat environment
+	MetricsToken string `yaml:"metrics_token"`
+	// CronSecret melindungi endpoint cron yang dipanggil scheduler, sebaiknya diisi lewat environment
+	CronSecret string `yaml:"cron_secret"`
+	// TrustedProxies adalah IP atau CIDR load balancer di depan aplikasi. Kosong berarti X-Forwarded-For diabaikan.
+	TrustedProxies []string `yaml:"trusted_proxies"`
+	// LegacyOwner adalah akun penjual yang menerima data lama tanpa pemilik saat backfill sekali jalan
+	LegacyOwner string `yaml:"legacy_owner"`
+}
+
+// settingsFile adalah isi file konfigurasi: bagian default lalu timpaan per profil
+type settingsFile struct {
+	Default  Settings            `yaml:"default"`
+	Profiles map[string]Settings `yaml:"profiles"`
+}
+
+var (
+	loadOnce sync.Once
+	loaded   Settings
+	errLoad  error
+)
+
+// Load membaca dan memvalidasi konfigurasi sekali: nilai bawaan, file CONFIG_FILE, lalu environment
+func Load() (Settings, error) {
+	loadOnce.Do(func() {
+		loaded, errLoad = read(os.Getenv, os.ReadFile)
+	})
+	return loaded, errLoad
+}
+
+// MustLoad menghentikan aplikasi saat start jika konfigurasi tidak lengkap atau tidak valid
+func MustLoad() Settings {
+	s, err := Load()
+	if err != nil {
+		slog.Error("konfigurasi tidak valid", "error", err)
+		os.Exit(1)
+	}
+	return s
+}
+
+// read menyusun konfigurasi dari sumber yang diberikan sehingga bisa diuji tanpa environment asli
+func read(getenv func(string) string, readFile func(string) ([]byte, error)) (s Settings, err error) {
+	s.Env = strings.TrimSpace(getenv(EnvVarProfile))
+	if s.Env == "" {
+		s.Env = EnvDev
+	}
+	if path := getenv(EnvVarFile); path != "" {
+		if err = readSettingsFile(&s, path, readFile); err != nil {
+			return
+		}
+	}
+
+	override(&s.MongoURI, getenv(EnvVarMongo))
+	override(&s.MongoDB, getenv(EnvVarMongoDB))
+	override(&s.GeoURI, getenv(EnvVarGeo))
+	override(&s.GeoDB, getenv(EnvVarGeoDB))
+	override(&s.LogLevel, getenv(EnvVarLogLevel))
+	override(&s.MetricsToken, getenv(EnvVarMetrics))
+	override(&s.CronSecret, getenv(EnvVarCron))
+	override(&s.LegacyOwner, getenv(EnvVarLegacy))
+	if v := getenv(EnvVarOrigins); v != "" {
+		s.Origins = splitList(v)
+	}
+	if v := getenv(EnvVarProxies); v != "" {
+		s.TrustedProxies = splitList(v)
+	}
+
+	if len(s.Origins) == 0 && s.Env == EnvDev {
+		s.Origins = []string{defaultDevOrigin}
+	}
+	if s.MongoDB == "" {
+		s.MongoDB = defaultMongoDB
+	}
+	if s.GeoDB == "" {
+		s.GeoDB = defaultGeoDB
+	}
+	if s.LogLevel == "" {
+		s.LogLevel = "info"
+	}
+	if s.GeoURI == "" {
+		s.GeoURI = s.MongoURI
+	}
+	return s, s.Validate()
+}
+
+// readSettingsFile menggabungkan bagian default dan profil aktif dari file YAML
+func readSettingsFile(s *Settings, path string, readFile func(string) ([]byte, error)) error {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+	default:
+		return fmt.Errorf("%s: format file konfigurasi harus YAML (.yaml atau .yml)", path)
+	}
+	raw, err := readFile(path)
+	if err != nil {
+		return fmt.Errorf("membaca %s: %w", path, err)
+	}
+	var file settingsFile
+	if err = yaml.UnmarshalStrict(raw, &file); err != nil {
+		return fmt.Errorf("membaca %s: %w", path, err)
+	}
+	for name := range file.Profiles {
+		if !validEnv(name) {
+			return fmt.Errorf("%s: profil %q tidak dikenal, gunakan dev, staging atau prod", path, name)
+		}
+	}
+	merge(s, file.Default)
+	merge(s, file.Profiles[s.Env])
+	return nil
+}
+
+// Validate mengumpulkan semua kesalahan konfigurasi agar bisa diperbaiki sekaligus
+func (s Settings) Validate() error {
+	var errs []error
+	if !validEnv(s.Env) {
+		errs = append(errs, fmt.Errorf("%s=%q tidak dikenal, gunakan dev, staging atau prod", EnvVarProfile, s.Env))
+	}
+	if s.MongoURI == "" {
+		errs = append(errs, fmt.Errorf("%s wajib diisi", EnvVarMongo))
+	} else if !validMongoURI(s.MongoURI) {
+		errs = append(errs, fmt.Errorf("%s harus diawali mongodb:// atau mongodb+srv://", EnvVarMongo))
+	}
+	if s.GeoURI != "" && !validMongoURI(s.GeoURI) {
+		errs = append(errs, fmt.Errorf("%s harus diawali mongodb:// atau mongodb+srv://", EnvVarGeo))
+	}
+	if len(s.Origins) == 0 && s.Env != EnvDev {
+		errs = append(errs, fmt.Errorf("%s wajib diisi untuk profil %s", EnvVarOrigins, s.Env))
+	}
+	if _, err := logger.ParseLevel(s.LogLevel); err != nil {
+		errs = append(errs, fmt.Errorf("%s: %w", EnvVarLogLevel, err))
+	}
+	for _, p := range s.TrustedProxies {
+		if _, _, err := net.ParseCIDR(p); err != nil && net.ParseIP(p) == nil {
+			errs = append(errs, fmt.Errorf("%s: %q bukan IP atau CIDR", EnvVarProxies, p))
+		}
+	}
+	if s.LegacyOwner != "" && !validasi.Phone(s.LegacyOwner) {
+		errs = append(errs, fmt.Errorf("%s harus nomor telepon yang diawali 62", EnvVarLegacy))
+	}
+	for _, o := range s.Origins {
+		u, err := url.Parse(o)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" || u.Path != "" {
+			errs = append(errs, fmt.Errorf("origin %q harus berupa skema dan host, misalnya https://itung.in.my.id", o))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func validEnv(env string) bool {
+	return env == EnvDev || env == EnvStaging || env == EnvProd
+}
+
+func validMongoURI(uri string) bool {
+	return strings.HasPrefix(uri, "mongodb://") || strings.HasPrefix(uri, "mongodb+srv://")
+}
+
+// splitList memecah daftar yang dipisah koma dan membuang isian kosong
+func splitList(v string) (list []string) {
+	for _, item := range strings.Split(v, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return
+}
+
+func override(field *string, value string) {
+	if value = strings.TrimSpace(value); value != "" {
+		*field = value
+	}
+}
+
+// merge menimpa field s dengan field from yang diisi
+func merge(s *Settings, from Settings) {
+	override(&s.MongoURI, from.MongoURI)
+	override(&s.MongoDB, from.MongoDB)
+	override(&s.GeoURI, from.GeoURI)
+	override(&s.GeoDB, from.GeoDB)
+	override(&s.LogLevel, from.LogLevel)
+	override(&s.MetricsToken, from.MetricsToken)
+	override(&s.CronSecret, from.CronSecret)
+	override(&s.LegacyOwner, from.LegacyOwner)
+	if len(from.Origins) > 0 {
+		s.Origins = from.Origins
+	}
+	if len(from.TrustedProxies) > 0 {
+		s.TrustedProxies = from.TrustedProxies
+	}
+}
@@ -1,79 +1,96 @@
 package controller
 
 import (
-	"context"
-	"encoding/csv"
-	"encoding/json"
 	"fmt"
 	"net/http"
 	"time"
 
 	"github.com/gocroot/config"
-	"github.com/gocroot/helper/at"
-	"github.com/gocroot/helper/atdb"
+	"github.com/gocroot/helper/audit"
+	"github.com/gocroot/helper/ekspor"
+	"github.com/gocroot/helper/paging"
+	"github.com/gocroot/helper/keuangan"
+	"github.com/gocroot/helper/pajak"
+	"github.com/gocroot/helper/respon"
+	"github.com/gocroot/helper/stok"
 	"github.com/gocroot/model"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
 )
 
 // Fungsi untuk menambahkan produk baru
 func CreateProduct(w http.ResponseWriter, r *http.Request) {
+	owner, ok := getOwner(w, r)
+	if !ok {
+		return
+	}
 	var product model.Product
-	if err := json.NewDecoder(r.Body).Decode(&product); err != nil {
-		var response model.Response
-		response.Status = "Error: Bad Request"
-		response.Response = err.Error()
-		at.WriteJSON(w, http.StatusBadRequest, response)
+	if !respon.Decode(w, r, &product) {
 		return
 	}
 
 	// Inisialisasi data produk baru dengan ObjectID untuk ID
 	newProduct := model.Product{
 		ID:          primitive.NewObjectID(),
+		Owner:       owner,
 		Name:        product.Name,
 		Description: product.Description,
 		Price:       product.Price,
 		Category:    product.Category,
 		Stock:       product.Stock,
+		Cost:        product.Cost,
+		TaxRate:     product.TaxRate,
 		CreatedAt:   time.Now(),
 	}
+	if newProduct.TaxRate != nil && !pajak.ValidRate(*newProduct.TaxRate) {
+		respon.Error(w, http.StatusBadRequest, "Tarif PPN harus antara 0 dan 100 persen", "")
+		return
+	}
 
 	// Insert produk ke dalam MongoDB
-	_, err := atdb.InsertOneDoc(config.Mongoconn, "products", newProduct)
+	err := repos.Products.Insert(r.Context(), newProduct)
 	if err != nil {
-		var response model.Response
-		response.Status = "Error: Gagal Insert Database"
-		response.Response = err.Error()
-		at.WriteJSON(w, http.StatusInternalServerError, response)
+		respon.Error(w, http.StatusInternalServerError, "Gagal Insert Database", err.Error())
 		return
 	}
 
-	// Kirim respon sukses
-	response := map[string]interface{}{
-		"status":  "success",
-		"message": "Produk berhasil ditambahkan",
-		"data":    newProduct,
+	// Catat stok awal sebagai mutasi opening balance
+	if err = stok.RecordSet(config.Mongoconn(), model.Product{}, newProduct, "Stok awal"); err != nil {
+		respon.Error(w, http.StatusInternalServerError, "Gagal mencatat stok awal", err.Error())
+		return
+	}
+	if !logAudit(w, r, owner, audit.EntityProduct, newProduct.ID, audit.ActionCreate, nil, newProduct) {
+		return
 	}
-	at.WriteJSON(w, http.StatusCreated, response)
+
+	// Kirim respon sukses
+	respon.Success(w, http.StatusCreated, "Produk berhasil ditambahkan", newProduct)
+}
+
+// productPaging adalah parameter list produk: ?q= nama/deskripsi, ?category=, ?sort=name|price|stock|createdAt
+var productPaging = paging.Options{
+	SearchFields: []string{"name", "description", "category"},
+	Filters:      map[string]string{"category": "category"},
+	SortFields:   []string{"name", "price", "stock", "createdAt"},
 }
 
 // Fungsi untuk mendapatkan daftar produk
 func GetProducts(w http.ResponseWriter, r *http.Request) {
-	// Ambil semua data produk dari MongoDB
-	data, err := atdb.GetAllDoc[[]model.Product](config.Mongoconn, "products", primitive.M{})
+	owner, ok := getOwner(w, r)
+	if !ok {
+		return
+	}
+	q, err := paging.Parse(r.URL.Query(), audit.Live(bson.M{"owner": owner}), productPaging)
 	if err != nil {
-		var response model.Response
-		response.Status = "Error: Data produk tidak ditemukan"
-		response.Response = err.Error()
-		at.WriteJSON(w, http.StatusNotFound, response)
+		respon.Error(w, http.StatusBadRequest, "Parameter query tidak valid", err.Error())
 		return
 	}
-
-	if len(data) == 0 {
-		var response model.Response
-		response.Status = "Error: Data produk kosong"
-		at.WriteJSON(w, http.StatusNotFound, response)
+	// Ambil satu halaman data produk dari MongoDB
+	data, meta, err := repos.Products.Find(r.Context(), q)
+	if err != nil {
+		respon.Error(w, http.StatusInternalServerError, "Gagal mengambil data produk", err.Error())
 		return
 	}
 
@@ -91,81 +108,71 @@ func GetProducts(w http.ResponseWriter, r *http.Request) {
 		})
 	}
 
-	// Kirim data produk sebagai respon
-	at.WriteJSON(w, http.StatusOK, products)
+	// Kirim data produk sebagai respon, info halaman di header
+	respon.List(w, products, meta)
 }
 
 // Fungsi untuk mendapatkan detail produk berdasarkan ID
 func GetProductByID(w http.ResponseWriter, r *http.Request) {
+	owner, ok := getOwner(w, r)
+	if !ok {
+		return
+	}
 	// Ambil parameter ID dari URL
 	productID := r.URL.Query().Get("id")
 	if productID == "" {
-		var response model.Response
-		response.Status = "Error: ID Produk tidak ditemukan"
-		at.WriteJSON(w, http.StatusBadRequest, response)
+		respon.Error(w, http.StatusBadRequest, "ID Produk tidak ditemukan", "")
 		return
 	}
 
 	objectID, err := primitive.ObjectIDFromHex(productID)
 	if err != nil {
-		var response model.Response
-		response.Status = "Error: ID Produk tidak valid"
-		at.WriteJSON(w, http.StatusBadRequest, response)
+		respon.Error(w, http.StatusBadRequest, "ID Produk tidak valid", "")
 		return
 	}
 
 	// Ambil produk dari MongoDB
-	var product model.Product
-	filter := bson.M{"_id": objectID}
-	err = config.Mongoconn.Collection("products").FindOne(context.TODO(), filter).Decode(&product)
+	filter := audit.Live(bson.M{"_id": objectID, "owner": owner})
+	product, err := repos.Products.Get(r.Context(), filter)
 	if err != nil {
-		var response model.Response
-		response.Status = "Error: Produk tidak ditemukan"
-		at.WriteJSON(w, http.StatusNotFound, response)
+		respon.Error(w, http.StatusNotFound, "Produk tidak ditemukan", "")
 		return
 	}
 
 	// Kirim data produk sebagai respon
-	response := map[string]interface{}{
-		"status":  "success",
-		"message": "Produk ditemukan",
-		"data":    product,
-	}
-	at.WriteJSON(w, http.StatusOK, response)
+	respon.Success(w, http.StatusOK, "Produk ditemukan", product)
 }
 
 // Fungsi untuk mengupdate produk berdasarkan ID
 func UpdateProduct(w http.ResponseWriter, r *http.Request) {
+	owner, ok := getOwner(w, r)
+	if !ok {
+		return
+	}
 	// Ambil parameter ID dari URL
 	productID := r.URL.Query().Get("id")
 	if productID == "" {
-		var response model.Response
-		response.Status = "Error: ID Produk tidak ditemukan"
-		at.WriteJSON(w, http.StatusBadRequest, response)
+		respon.Error(w, http.StatusBadRequest, "ID Produk tidak ditemukan", "")
 		return
 	}
 
 	objectID, err := primitive.ObjectIDFromHex(productID)
 	if err != nil {
-		var response model.Response
-		response.Status = "Error: ID Produk tidak valid"
-		at.WriteJSON(w, http.StatusBadRequest, response)
+		respon.Error(w, http.StatusBadRequest, "ID Produk tidak valid", "")
 		return
 	}
 
-	// Decode data produk yang akan diupdate
+	// Decode data produk yang akan diupdate, field angka berupa pointer agar nilai nol tetap bisa diset
 	var requestBody struct {
-		Name        string  `json:"name"`
-		Description string  `json:"description"`
-		Price       float64 `json:"price"`
-		Category    string  `json:"category"`
-		Stock       int     `json:"stock"`
+		Name        string   `json:"name"`
+		Description string   `json:"description"`
+		Price       *float64 `json:"price"`
+		Category    string   `json:"category"`
+		Stock       *int     `json:"stock"`
+		Cost        *float64 `json:"cost"`
+		TaxRate     *float64 `json:"tax_rate"` // Nilai negatif menghapus tarif khusus produk
 	}
-	err = json.NewDecoder(r.Body).Decode(&requestBody)
-	if err != nil {
-		var response model.Response
-		response.Status = "Error: Gagal membaca data JSON"
-		at.WriteJSON(w, http.StatusBadRequest, response)
+	if !respon.Decode(w, r, &requestBody) {
 		return
 	}
 
@@ -177,233 +184,180 @@ func UpdateProduct(w http.ResponseWriter, r *http.Request) {
 	if requestBody.Description != "" {
 		updateData["description"] = requestBody.Description
 	}
-	if requestBody.Price != 0 {
-		updateData["price"] = requestBody.Price
+	if requestBody.Price != nil {
+		updateData["price"] = *requestBody.Price
 	}
 	if requestBody.Category != "" {
 		updateData["category"] = requestBody.Category
 	}
-	if requestBody.Stock != 0 {
-		updateData["stock"] = requestBody.Stock
+	if requestBody.Stock != nil {
+		if *requestBody.Stock < 0 {
+			respon.Error(w, http.StatusBadRequest, "Stok tidak boleh negatif", "")
+			return
+		}
+		updateData["stock"] = *requestBody.Stock
+	}
+	if requestBody.Cost != nil {
+		updateData["cost"] = *requestBody.Cost
+	}
+	update := bson.M{}
+	if requestBody.TaxRate != nil {
+		if *requestBody.TaxRate > 100 {
+			respon.Error(w, http.StatusBadRequest, "Tarif PPN harus antara 0 dan 100 persen", "")
+			return
+		}
+		if *requestBody.TaxRate < 0 {
+			update["$unset"] = bson.M{"tax_rate": ""}
+		} else {
+			updateData["tax_rate"] = *requestBody.TaxRate
+		}
 	}
 	updateData["updatedAt"] = time.Now()
 
+	// Simpan kondisi sebelum update untuk mencatat penyesuaian stok
+	filter := audit.Live(bson.M{"_id": objectID, "owner": owner})
+	before, err := repos.Products.Get(r.Context(), filter)
+	if err != nil {
+		respon.Error(w, http.StatusNotFound, "Produk tidak ditemukan", "")
+		return
+	}
+
 	// Update produk di MongoDB
-	filter := bson.M{"_id": objectID}
-	update := bson.M{"$set": updateData}
-	_, err = config.Mongoconn.Collection("products").UpdateOne(context.TODO(), filter, update)
+	update["$set"] = updateData
+	_, err = repos.Products.Update(r.Context(), filter, update)
 	if err != nil {
-		var response model.Response
-		response.Status = "Error: Gagal mengupdate produk"
-		response.Response = err.Error()
-		at.WriteJSON(w, http.StatusNotModified, response)
+		respon.Error(w, http.StatusInternalServerError, "Gagal mengupdate produk", err.Error())
 		return
 	}
 
-	// Kirim respon sukses
-	response := map[string]interface{}{
-		"status":  "success",
-		"message": "Produk berhasil diupdate",
-		"data":    updateData,
+	if requestBody.Stock != nil {
+		after := before
+		after.Stock = *requestBody.Stock
+		if err = stok.RecordSet(config.Mongoconn(), before, after, "Penyesuaian stok dari update produk"); err != nil {
+			respon.Error(w, http.StatusInternalServerError, "Gagal mencatat penyesuaian stok", err.Error())
+			return
+		}
+	}
+	if _, ok = logUpdate(w, r, owner, audit.EntityProduct, objectID, before); !ok {
+		return
 	}
-	at.WriteJSON(w, http.StatusOK, response)
+
+	// Kirim respon sukses
+	respon.Success(w, http.StatusOK, "Produk berhasil diupdate", updateData)
 }
 
 // Fungsi untuk menghapus produk berdasarkan ID
 func DeleteProduct(w http.ResponseWriter, r *http.Request) {
+	owner, ok := getOwner(w, r)
+	if !ok {
+		return
+	}
 	// Ambil parameter ID dari URL
 	productID := r.URL.Query().Get("id")
 	if productID == "" {
-		var response model.Response
-		response.Status = "Error: ID Produk tidak ditemukan"
-		at.WriteJSON(w, http.StatusBadRequest, response)
+		respon.Error(w, http.StatusBadRequest, "ID Produk tidak ditemukan", "")
 		return
 	}
 
 	// Konversi productID dari string ke ObjectID MongoDB
 	objectID, err := primitive.ObjectIDFromHex(productID)
 	if err != nil {
-		var response model.Response
-		response.Status = "Error: ID Produk tidak valid"
-		at.WriteJSON(w, http.StatusBadRequest, response)
+		respon.Error(w, http.StatusBadRequest, "ID Produk tidak valid", "")
 		return
 	}
 
-	// Hapus data produk berdasarkan ID
-	filter := bson.M{"_id": objectID}
-	deleteResult, err := config.Mongoconn.Collection("products").DeleteOne(context.TODO(), filter)
-	if err != nil {
-		var response model.Response
-		response.Status = "Error: Gagal menghapus produk"
-		response.Response = err.Error()
-		at.WriteJSON(w, http.StatusInternalServerError, response)
+	// Tandai produk sebagai terhapus, data lama tetap tersimpan untuk audit
+	deleted, err := audit.SoftDelete[model.Product](config.Mongoconn(), auditActor(r, owner), audit.EntityProduct, objectID)
+	if err == mongo.ErrNoDocuments {
+		respon.Error(w, http.StatusNotFound, "Produk tidak ditemukan", "")
 		return
 	}
-
-	// Periksa apakah ada produk yang dihapus
-	if deleteResult.DeletedCount == 0 {
-		var response model.Response
-		response.Status = "Error: Produk tidak ditemukan"
-		at.WriteJSON(w, http.StatusNotFound, response)
+	if err != nil {
+		respon.Error(w, http.StatusInternalServerError, "Gagal menghapus produk", err.Error())
 		return
 	}
 
 	// Kirim respon sukses
-	response := map[string]interface{}{
-		"status":  "success",
-		"message": "Produk berhasil dihapus",
-		"data":    deleteResult,
-	}
-	at.WriteJSON(w, http.StatusOK, response)
+	respon.Success(w, http.StatusOK, "Produk berhasil dihapus", deleted)
 }
 
 
 
 
 
-// Fungsi untuk mengekspor data produk ke CSV
+// Fungsi untuk mengekspor data produk ke CSV, ?format=xlsx untuk Excel
 func ExportProductsToCSV(w http.ResponseWriter, r *http.Request) {
-	var products []model.Product
-
-	// Ambil data produk dari MongoDB
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
-
-	cursor, err := config.Mongoconn.Collection("products").Find(ctx, bson.M{})
-	if err != nil {
-		var response model.Response
-		response.Status = "Error: Gagal mengambil data produk"
-		at.WriteJSON(w, http.StatusInternalServerError, response)
-		return
-	}
-	defer cursor.Close(ctx)
-
-	for cursor.Next(ctx) {
-		var prod model.Product
-		if err := cursor.Decode(&prod); err != nil {
-			var response model.Response
-			response.Status = "Error: Gagal mendekode data produk"
-			at.WriteJSON(w, http.StatusInternalServerError, response)
-			return
-		}
-		products = append(products, prod)
-	}
-
-	// Tentukan header respons sebagai file CSV
-	w.Header().Set("Content-Disposition", "attachment; filename=products.csv")
-	w.Header().Set("Content-Type", "text/csv")
-
-	// Buat writer CSV
-	csvWriter := csv.NewWriter(w)
-	defer csvWriter.Flush()
-
-	// Tulis header CSV
-	headers := []string{"ID", "Name", "Price", "Category", "Description", "Stock", "Created At", "Updated At"}
-	if err := csvWriter.Write(headers); err != nil {
-		var response model.Response
-		response.Status = "Error: Gagal menulis header CSV"
-		at.WriteJSON(w, http.StatusInternalServerError, response)
-		return
-	}
-
-	// Tulis data produk ke CSV
-	for _, product := range products {
-		row := []string{
-			product.ID.Hex(),
-			product.Name,
-			formatPrice(product.Price),        // Format harga
-			product.Category,
-			product.Description,
-			formatStock(product.Stock),        // Format stok
-			product.CreatedAt.Format(time.RFC3339),
-			product.UpdatedAt.Format(time.RFC3339),
-		}
-		if err := csvWriter.Write(row); err != nil {
-			var response model.Response
-			response.Status = "Error: Gagal menulis data produk ke CSV"
-			at.WriteJSON(w, http.StatusInternalServerError, response)
-			return
-		}
-	}
-
-	// Kirimkan respons sukses
-	var response model.Response
-	response.Status = "Success: Produk berhasil diekspor ke CSV"
-	at.WriteJSON(w, http.StatusOK, response)
-}
-
-
-// Fungsi untuk format harga menjadi string
-func formatPrice(price float64) string {
-	return fmt.Sprintf("%.2f", price)
+	exportEntity(w, r, ekspor.Products)
 }
 
-// Fungsi untuk format stok menjadi string
-func formatStock(stock int) string {
-	return fmt.Sprintf("%d", stock)
+// Fungsi untuk mengekspor data pelanggan ke CSV, ?format=xlsx untuk Excel
+func ExportCustomersToCSV(w http.ResponseWriter, r *http.Request) {
+	exportEntity(w, r, ekspor.Customers)
 }
 
 // controller pelanggan
 // CreateCustomer handles creating a new customer
 func CreateCustomer(w http.ResponseWriter, r *http.Request) {
+	owner, ok := getOwner(w, r)
+	if !ok {
+		return
+	}
 	var customer model.Customer
 
 	// Decode data pelanggan dari body permintaan
-	if err := json.NewDecoder(r.Body).Decode(&customer); err != nil {
-		var response model.Response
-		response.Status = "Error: Bad Request"
-		response.Response = err.Error()
-		at.WriteJSON(w, http.StatusBadRequest, response)
+	if !respon.Decode(w, r, &customer) {
 		return
 	}
 
 	// Inisialisasi data pelanggan baru dengan ObjectID untuk ID
 	newCustomer := model.Customer{
 		ID:        primitive.NewObjectID(),
+		Owner:     owner,
 		Name:      customer.Name,
 		Email:     customer.Email,
 		Phone:     customer.Phone,
 		Address:   customer.Address,
+		NPWP:      customer.NPWP,
 		CreatedAt: time.Now(),
 		UpdatedAt: time.Now(),
 	}
 
 	// Insert pelanggan ke dalam MongoDB
-	_, err := atdb.InsertOneDoc(config.Mongoconn, "customers", newCustomer)
+	err := repos.Customers.Insert(r.Context(), newCustomer)
 	if err != nil {
-		var response model.Response
-		response.Status = "Error: Gagal Insert Database"
-		response.Response = err.Error()
-		at.WriteJSON(w, http.StatusInternalServerError, response)
+		respon.Error(w, http.StatusInternalServerError, "Gagal Insert Database", err.Error())
+		return
+	}
+	if !logAudit(w, r, owner, audit.EntityCustomer, newCustomer.ID, audit.ActionCreate, nil, newCustomer) {
 		return
 	}
 
 	// Kirim respon sukses
-	response := map[string]interface{}{
-		"status":  "success",
-		"message": "Pelanggan berhasil ditambahkan",
-		"data":    newCustomer,
-	}
-	at.WriteJSON(w, http.StatusCreated, response)
+	respon.Success(w, http.StatusCreated, "Pelanggan berhasil ditambahkan", newCustomer)
 }
 
 
+// customerPaging adalah parameter list pelanggan: ?q= nama/email/telepon, ?sort=name|createdAt
+var customerPaging = paging.Options{
+	SearchFields: []string{"name", "email", "phone"},
+	SortFields:   []string{"name", "createdAt"},
+}
+
 // GetCustomers handles retrieving all customers
 func GetCustomers(w http.ResponseWriter, r *http.Request) {
-	// Ambil semua data pelanggan dari MongoDB
-	data, err := atdb.GetAllDoc[[]model.Customer](config.Mongoconn, "customers", primitive.M{})
+	owner, ok := getOwner(w, r)
+	if !ok {
+		return
+	}
+	q, err := paging.Parse(r.URL.Query(), audit.Live(bson.M{"owner": owner}), customerPaging)
 	if err != nil {
-		var response model.Response
-		response.Status = "Error: Data pelanggan tidak ditemukan"
-		response.Response = err.Error()
-		at.WriteJSON(w, http.StatusNotFound, response)
+		respon.Error(w, http.StatusBadRequest, "Parameter query tidak valid", err.Error())
 		return
 	}
-
-	if len(data) == 0 {
-		var response model.Response
-		response.Status = "Error: Data pelanggan kosong"
-		at.WriteJSON(w, http.StatusNotFound, response)
+	// Ambil satu halaman data pelanggan dari MongoDB
+	data, meta, err := repos.Customers.Find(r.Context(), q)
+	if err != nil {
+		respon.Error(w, http.StatusInternalServerError, "Gagal mengambil data pelanggan", err.Error())
 		return
 	}
 
@@ -422,69 +376,60 @@ func GetCustomers(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Kirim data pelanggan sebagai respon
-	at.WriteJSON(w, http.StatusOK, customers)
+	respon.List(w, customers, meta)
 }
 
 
 
 // GetCustomerByID handles retrieving a customer by ID
 func GetCustomerByID(w http.ResponseWriter, r *http.Request) {
+	owner, ok := getOwner(w, r)
+	if !ok {
+		return
+	}
 	// Ambil parameter ID dari URL
 	customerID := r.URL.Query().Get("id")
 	if customerID == "" {
-		var response model.Response
-		response.Status = "Error: ID Pelanggan tidak ditemukan"
-		at.WriteJSON(w, http.StatusBadRequest, response)
+		respon.Error(w, http.StatusBadRequest, "ID Pelanggan tidak ditemukan", "")
 		return
 	}
 
 	// Konversi ID dari string ke ObjectID MongoDB
 	objectID, err := primitive.ObjectIDFromHex(customerID)
 	if err != nil {
-		var response model.Response
-		response.Status = "Error: ID Pelanggan tidak valid"
-		at.WriteJSON(w, http.StatusBadRequest, response)
+		respon.Error(w, http.StatusBadRequest, "ID Pelanggan tidak valid", "")
 		return
 	}
 
 	// Ambil data pelanggan dari MongoDB
-	var customer model.Customer
-	filter := bson.M{"_id": objectID}
-	err = config.Mongoconn.Collection("customers").FindOne(context.TODO(), filter).Decode(&customer)
+	customer, err := repos.Customers.Get(r.Context(), audit.Live(bson.M{"_id": objectID, "owner": owner}))
 	if err != nil {
-		var response model.Response
-		response.Status = "Error: Pelanggan tidak ditemukan"
-		at.WriteJSON(w, http.StatusNotFound, response)
+		respon.Error(w, http.StatusNotFound, "Pelanggan tidak ditemukan", "")
 		return
 	}
 
 	// Kirim data pelanggan sebagai respon
-	response := map[string]interface{}{
-		"status":  "success",
-		"message": "Pelanggan ditemukan",
-		"data":    customer,
-	}
-	at.WriteJSON(w, http.StatusOK, response)
+	respon.Success(w, http.StatusOK, "Pelanggan ditemukan", customer)
 }
 
 
 // UpdateCustomer handles updating a customer by ID
 func UpdateCustomer(w http.ResponseWriter, r *http.Request) {
+	owner, ok := getOwner(w, r)
+	if !ok {
+		return
+	}
 	// Ambil parameter ID dari URL
 	customerID := r.URL.Query().Get("id")
 	if customerID == "" {
-		var response model.Response
-		response.Status = "Error: ID Pelanggan tidak ditemukan"
-		at.WriteJSON(w, http.StatusBadRequest, response)
+		respon.Error(w, http.StatusBadRequest, "ID Pelanggan tidak ditemukan", "")
 		return
 	}
 
 	// Konversi ID dari string ke ObjectID MongoDB
 	objectID, err := primitive.ObjectIDFromHex(customerID)
 	if err != nil {
-		var response model.Response
-		response.Status = "Error: ID Pelanggan tidak valid"
-		at.WriteJSON(w, http.StatusBadRequest, response)
+		respon.Error(w, http.StatusBadRequest, "ID Pelanggan tidak valid", "")
 		return
 	}
 
@@ -494,12 +439,9 @@ func UpdateCustomer(w http.ResponseWriter, r *http.Request) {
 		Email   string `json:"email"`
 		Phone   string `json:"phone"`
 		Address string `json:"address"`
+		NPWP    string `json:"npwp"`
 	}
-	err = json.NewDecoder(r.Body).Decode(&requestBody)
-	if err != nil {
-		var response model.Response
-		response.Status = "Error: Gagal membaca data JSON"
-		at.WriteJSON(w, http.StatusBadRequest, response)
+	if !respon.Decode(w, r, &requestBody) {
 		return
 	}
 
@@ -517,77 +459,69 @@ func UpdateCustomer(w http.ResponseWriter, r *http.Request) {
 	if requestBody.Address != "" {
 		updateData["address"] = requestBody.Address
 	}
+	if requestBody.NPWP != "" {
+		updateData["npwp"] = requestBody.NPWP
+	}
 	updateData["updatedAt"] = time.Now()
 
+	// Simpan kondisi sebelum update untuk audit log
+	filter := audit.Live(bson.M{"_id": objectID, "owner": owner})
+	before, err := repos.Customers.Get(r.Context(), filter)
+	if err != nil {
+		respon.Error(w, http.StatusNotFound, "Pelanggan tidak ditemukan", "")
+		return
+	}
+
 	// Update pelanggan di MongoDB
-	filter := bson.M{"_id": objectID}
 	update := bson.M{"$set": updateData}
-	_, err = config.Mongoconn.Collection("customers").UpdateOne(context.TODO(), filter, update)
+	_, err = repos.Customers.Update(r.Context(), filter, update)
 	if err != nil {
-		var response model.Response
-		response.Status = "Error: Gagal mengupdate pelanggan"
-		response.Response = err.Error()
-		at.WriteJSON(w, http.StatusNotModified, response)
+		respon.Error(w, http.StatusInternalServerError, "Gagal mengupdate pelanggan", err.Error())
+		return
+	}
+	if _, ok = logUpdate(w, r, owner, audit.EntityCustomer, objectID, before); !ok {
 		return
 	}
 
 	// Kirim respon sukses
-	response := map[string]interface{}{
-		"status":  "success",
-		"message": "Pelanggan berhasil diupdate",
-		"data":    updateData,
-	}
-	at.WriteJSON(w, http.StatusOK, response)
+	respon.Success(w, http.StatusOK, "Pelanggan berhasil diupdate", updateData)
 }
 
 
 
 // DeleteCustomer handles deleting a customer by ID
 func DeleteCustomer(w http.ResponseWriter, r *http.Request) {
+	owner, ok := getOwner(w, r)
+	if !ok {
+		return
+	}
 	// Ambil parameter ID dari URL
 	customerID := r.URL.Query().Get("id")
 	if customerID == "" {
-		var response model.Response
-		response.Status = "Error: ID Pelanggan tidak ditemukan"
-		at.WriteJSON(w, http.StatusBadRequest, response)
+		respon.Error(w, http.StatusBadRequest, "ID Pelanggan tidak ditemukan", "")
 		return
 	}
 
 	// Konversi customerID dari string ke ObjectID MongoDB
 	objectID, err := primitive.ObjectIDFromHex(customerID)
 	if err != nil {
-		var response model.Response
-		response.Status = "Error: ID Pelanggan tidak valid"
-		at.WriteJSON(w, http.StatusBadRequest, response)
+		respon.Error(w, http.StatusBadRequest, "ID Pelanggan tidak valid", "")
 		return
 	}
 
-	// Hapus data pelanggan berdasarkan ID
-	filter := bson.M{"_id": objectID}
-	deleteResult, err := config.Mongoconn.Collection("customers").DeleteOne(context.TODO(), filter)
-	if err != nil {
-		var response model.Response
-		response.Status = "Error: Gagal menghapus pelanggan"
-		response.Response = err.Error()
-		at.WriteJSON(w, http.StatusInternalServerError, response)
+	// Tandai pelanggan sebagai terhapus, data lama tetap tersimpan untuk audit
+	deleted, err := audit.SoftDelete[model.Customer](config.Mongoconn(), auditActor(r, owner), audit.EntityCustomer, objectID)
+	if err == mongo.ErrNoDocuments {
+		respon.Error(w, http.StatusNotFound, "Pelanggan tidak ditemukan", "")
 		return
 	}
-
-	// Periksa apakah ada pelanggan yang dihapus
-	if deleteResult.DeletedCount == 0 {
-		var response model.Response
-		response.Status = "Error: Pelanggan tidak ditemukan"
-		at.WriteJSON(w, http.StatusNotFound, response)
+	if err != nil {
+		respon.Error(w, http.StatusInternalServerError, "Gagal menghapus pelanggan", err.Error())
 		return
 	}
 
 	// Kirim respon sukses
-	response := map[string]interface{}{
-		"status":  "success",
-		"message": "Pelanggan berhasil dihapus",
-		"data":    deleteResult,
-	}
-	at.WriteJSON(w, http.StatusOK, response)
+	respon.Success(w, http.StatusOK, "Pelanggan berhasil dihapus", deleted)
 }
 
 
@@ -596,128 +530,120 @@ func DeleteCustomer(w http.ResponseWriter, r *http.Request) {
 
 
 // Handler Laporan
-// Handler untuk membuat laporan keuangan
+// Handler untuk membuat laporan keuangan, angka dihitung server dari transaksi yang tersimpan
 func CreateFinancialReport(w http.ResponseWriter, r *http.Request) {
+	owner, ok := getOwner(w, r)
+	if !ok {
+		return
+	}
 	var report model.LaporanAkuntan
 
-	// Decode data laporan dari body permintaan
-	if err := json.NewDecoder(r.Body).Decode(&report); err != nil {
-		var response model.Response
-		response.Status = "Error: Bad Request"
-		response.Response = err.Error()
-		at.WriteJSON(w, http.StatusBadRequest, response)
+	// Decode periode laporan dari body permintaan, nilai income/expenses/profit dari klien diabaikan
+	if !respon.Decode(w, r, &report) {
 		return
 	}
 
-	// Parse tanggal dari string ke time.Time
-	startDate, err := time.Parse("2006-01-02", report.StartDate)
+	newReport, err := computeProfitLoss(w, owner, report.StartDate, report.EndDate)
 	if err != nil {
-		var response model.Response
-		response.Status = "Error: Invalid start date format. Use YYYY-MM-DD"
-		response.Response = err.Error()
-		at.WriteJSON(w, http.StatusBadRequest, response)
 		return
 	}
-	endDate, err := time.Parse("2006-01-02", report.EndDate)
+	newReport.ID = primitive.NewObjectID()
+	newReport.Owner = owner
+	newReport.CreatedAt = time.Now()
+
+	// Insert laporan ke dalam MongoDB
+	err = repos.Reports.Insert(r.Context(), newReport)
 	if err != nil {
-		var response model.Response
-		response.Status = "Error: Invalid end date format. Use YYYY-MM-DD"
-		response.Response = err.Error()
-		at.WriteJSON(w, http.StatusBadRequest, response)
+		respon.Error(w, http.StatusInternalServerError, "Gagal Insert Database", err.Error())
+		return
+	}
+	if !logAudit(w, r, owner, audit.EntityReport, newReport.ID, audit.ActionCreate, nil, newReport) {
 		return
 	}
 
-	// Inisialisasi data laporan baru dengan ObjectID untuk ID dan mengisi income, expenses, profit
-	newReport := model.LaporanAkuntan{
-		ID:             primitive.NewObjectID(),
-		StartDate:      report.StartDate,
-		EndDate:        report.EndDate,
-		StartDateTime:  startDate,
-		EndDateTime:    endDate,
-		Income:         report.Income,  // Pastikan income dikirim dalam JSON
-		Expenses:       report.Expenses, // Pastikan expenses dikirim dalam JSON
-		Profit:         report.Profit, // Pastikan profit dikirim dalam JSON
-		CreatedAt:      time.Now(),
+	// Kirim respon sukses
+	respon.Success(w, http.StatusCreated, "Laporan keuangan berhasil dibuat", newReport)
+}
+
+// Handler untuk melihat laba rugi tanpa menyimpan laporan: ?startDate=YYYY-MM-DD&endDate=YYYY-MM-DD
+func GetProfitLoss(w http.ResponseWriter, r *http.Request) {
+	owner, ok := getOwner(w, r)
+	if !ok {
+		return
+	}
+	report, err := computeProfitLoss(w, owner, r.URL.Query().Get("startDate"), r.URL.Query().Get("endDate"))
+	if err != nil {
+		return
 	}
 
-	// Insert laporan ke dalam MongoDB
-	_, err = atdb.InsertOneDoc(config.Mongoconn, "financial_reports", newReport)
+	respon.Success(w, http.StatusOK, "Laporan laba rugi", report)
+}
+
+// computeProfitLoss memvalidasi periode lalu menghitung laba rugi, respon error sudah ditulis jika err tidak nil
+func computeProfitLoss(w http.ResponseWriter, owner, startDate, endDate string) (report model.LaporanAkuntan, err error) {
+	start, end, err := keuangan.ParsePeriod(startDate, endDate)
 	if err != nil {
-		var response model.Response
-		response.Status = "Error: Gagal Insert Database"
-		response.Response = err.Error()
-		at.WriteJSON(w, http.StatusInternalServerError, response)
+		respon.Error(w, http.StatusBadRequest, "Invalid date format. Use YYYY-MM-DD", err.Error())
+		return
+	}
+	if end.Before(start) {
+		err = fmt.Errorf("endDate sebelum startDate")
+		respon.Error(w, http.StatusBadRequest, "Periode laporan tidak valid", err.Error())
 		return
 	}
 
-	// Kirim respon sukses
-	response := map[string]interface{}{
-		"status":  "success",
-		"message": "Laporan keuangan berhasil dibuat",
-		"data":    newReport,
+	report, err = keuangan.ProfitLoss(config.Mongoconn(), owner, startDate, endDate)
+	if err != nil {
+		respon.Error(w, http.StatusInternalServerError, "Gagal menghitung laporan keuangan", err.Error())
+		return
 	}
-	at.WriteJSON(w, http.StatusCreated, response)
+	return
 }
 
 
 
 // Fungsi untuk mendapatkan laporan keuangan berdasarkan ID
 func GetFinancialReportByID(w http.ResponseWriter, r *http.Request) {
+	owner, ok := getOwner(w, r)
+	if !ok {
+		return
+	}
 	// Ambil parameter ID dari URL
 	reportID := r.URL.Query().Get("id")
 	if reportID == "" {
-		var response model.Response
-		response.Status = "Error: ID Laporan tidak ditemukan"
-		at.WriteJSON(w, http.StatusBadRequest, response)
+		respon.Error(w, http.StatusBadRequest, "ID Laporan tidak ditemukan", "")
 		return
 	}
 
 	// Konversi ID dari string ke ObjectID MongoDB
 	objectID, err := primitive.ObjectIDFromHex(reportID)
 	if err != nil {
-		var response model.Response
-		response.Status = "Error: ID Laporan tidak valid"
-		at.WriteJSON(w, http.StatusBadRequest, response)
+		respon.Error(w, http.StatusBadRequest, "ID Laporan tidak valid", "")
 		return
 	}
 
 	// Ambil data laporan keuangan dari MongoDB
-	var report model.LaporanAkuntan
-	filter := bson.M{"_id": objectID}
-	err = config.Mongoconn.Collection("financial_reports").FindOne(context.TODO(), filter).Decode(&report)
+	report, err := repos.Reports.Get(r.Context(), audit.Live(bson.M{"_id": objectID, "owner": owner}))
 	if err != nil {
-		var response model.Response
-		response.Status = "Error: Laporan keuangan tidak ditemukan"
-		at.WriteJSON(w, http.StatusNotFound, response)
+		respon.Error(w, http.StatusNotFound, "Laporan keuangan tidak ditemukan", "")
 		return
 	}
 
 	// Kirim data laporan sebagai respon
-	response := map[string]interface{}{
-		"status":  "success",
-		"message": "Laporan keuangan ditemukan",
-		"data":    report,
-	}
-	at.WriteJSON(w, http.StatusOK, response)
+	respon.Success(w, http.StatusOK, "Laporan keuangan ditemukan", report)
 }
 
 
 // Handler untuk mendapatkan semua laporan keuangan
 func GetFinancialReports(w http.ResponseWriter, r *http.Request) {
-	// Ambil semua data laporan keuangan dari MongoDB
-	data, err := atdb.GetAllDoc[[]model.LaporanAkuntan](config.Mongoconn, "financial_reports", primitive.M{})
-	if err != nil {
-		var response model.Response
-		response.Status = "Error: Data laporan keuangan tidak ditemukan"
-		response.Response = err.Error()
-		at.WriteJSON(w, http.StatusNotFound, response)
+	owner, ok := getOwner(w, r)
+	if !ok {
 		return
 	}
-
-	if len(data) == 0 {
-		var response model.Response
-		response.Status = "Error: Data laporan keuangan kosong"
-		at.WriteJSON(w, http.StatusNotFound, response)
+	// Ambil semua data laporan keuangan dari MongoDB
+	data, err := repos.Reports.List(r.Context(), audit.Live(bson.M{"owner": owner}))
+	if err != nil {
+		respon.Error(w, http.StatusInternalServerError, "Gagal mengambil data laporan keuangan", err.Error())
 		return
 	}
 
@@ -729,61 +655,63 @@ func GetFinancialReports(w http.ResponseWriter, r *http.Request) {
 			"startDate":      report.StartDate,
 			"endDate":        report.EndDate,
 			"income":         report.Income,
+			"returns":        report.Returns,
 			"expenses":       report.Expenses,
 			"profit":         report.Profit,
+			"cogs":           report.COGS,
+			"grossProfit":    report.GrossProfit,
+			"expenseByCategory": report.ExpenseByCategory,
 			"createdAt":      report.CreatedAt,
 		})
 	}
 
 	// Kirim data laporan keuangan sebagai respon
-	at.WriteJSON(w, http.StatusOK, reports)
+	respon.OK(w, reports)
 }
 
 
 // Fungsi untuk menghapus laporan keuangan berdasarkan ID
 func DeleteFinancialReport(w http.ResponseWriter, r *http.Request) {
+	owner, ok := getOwner(w, r)
+	if !ok {
+		return
+	}
 	// Ambil parameter ID laporan dari URL
 	reportID := r.URL.Query().Get("id")
 	if reportID == "" {
-		var response model.Response
-		response.Status = "Error: ID Laporan tidak ditemukan"
-		at.WriteJSON(w, http.StatusBadRequest, response)
+		respon.Error(w, http.StatusBadRequest, "ID Laporan tidak ditemukan", "")
 		return
 	}
 
 	// Konversi reportID dari string ke ObjectID MongoDB
 	objectID, err := primitive.ObjectIDFromHex(reportID)
 	if err != nil {
-		var response model.Response
-		response.Status = "Error: ID Laporan tidak valid"
-		at.WriteJSON(w, http.StatusBadRequest, response)
+		respon.Error(w, http.StatusBadRequest, "ID Laporan tidak valid", "")
 		return
 	}
 
-	// Hapus data laporan keuangan berdasarkan ID menggunakan atdb.DeleteOneDoc
-	deleteResult, err := atdb.DeleteOneDoc(config.Mongoconn, "financial_reports", bson.M{"_id": objectID})
+	// Laporan periode yang sudah ditutup tidak boleh dihapus
+	report, err := repos.Reports.Get(r.Context(), audit.Live(bson.M{"_id": objectID, "owner": owner}))
 	if err != nil {
-		var response model.Response
-		response.Status = "Error: Gagal menghapus laporan keuangan"
-		response.Response = err.Error()
-		at.WriteJSON(w, http.StatusInternalServerError, response)
+		respon.Error(w, http.StatusNotFound, "Laporan tidak ditemukan", "")
+		return
+	}
+	if !periodOpen(w, owner, report.StartDateTime, report.EndDateTime) {
 		return
 	}
 
-	// Periksa apakah ada laporan yang dihapus
-	if deleteResult.DeletedCount == 0 {
-		var response model.Response
-		response.Status = "Error: Laporan tidak ditemukan"
-		at.WriteJSON(w, http.StatusNotFound, response)
+	// Tandai laporan sebagai terhapus, data lama tetap tersimpan untuk audit
+	deleted, err := audit.SoftDelete[model.LaporanAkuntan](config.Mongoconn(), auditActor(r, owner), audit.EntityReport, objectID)
+	if err == mongo.ErrNoDocuments {
+		respon.Error(w, http.StatusNotFound, "Laporan tidak ditemukan", "")
+		return
+	}
+	if err != nil {
+		respon.Error(w, http.StatusInternalServerError, "Gagal menghapus laporan keuangan", err.Error())
 		return
 	}
 
 	// Kirim respon sukses
-	response := map[string]interface{}{
-		"status":  "success",
-		"message": "Laporan keuangan berhasil dihapus",
-		"data":    deleteResult,
-	}
-	at.WriteJSON(w, http.StatusOK, response)
+	respon.Success(w, http.StatusOK, "Laporan keuangan berhasil dihapus", deleted)
 }
 
@@ -0,0 +1,137 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gocroot/config"
+	"github.com/gocroot/helper/absensi"
+	"github.com/gocroot/helper/atdb"
+	"github.com/gocroot/helper/respon"
+	"github.com/gocroot/mod/presensi"
+	"github.com/gocroot/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Handler untuk rekap kehadiran harian satu pegawai: ?employee_id= dan ?month=YYYY-MM
+func GetAttendance(respw http.ResponseWriter, req *http.Request) {
+	owner, ok := getOwner(respw, req)
+	if !ok {
+		return
+	}
+	employeeID, err := primitive.ObjectIDFromHex(req.URL.Query().Get("employee_id"))
+	if err != nil {
+		respon.Error(respw, http.StatusBadRequest, "ID pegawai tidak valid", "")
+		return
+	}
+	summaries, err := absensi.Monthly(config.Mongoconn(), owner, monthParam(req, "month"), employeeID)
+	if err != nil {
+		respon.Error(respw, http.StatusBadRequest, "Gagal menghitung kehadiran", err.Error())
+		return
+	}
+	if len(summaries) == 0 {
+		respon.Error(respw, http.StatusNotFound, "Pegawai tidak ditemukan", "")
+		return
+	}
+	respon.OK(respw, summaries[0])
+}
+
+// Handler untuk rekap hadir, terlambat dan tidak hadir semua pegawai: ?month=YYYY-MM
+func GetAttendanceSummary(respw http.ResponseWriter, req *http.Request) {
+	owner, ok := getOwner(respw, req)
+	if !ok {
+		return
+	}
+	summaries, err := absensi.Monthly(config.Mongoconn(), owner, monthParam(req, "month"), primitive.NilObjectID)
+	if err != nil {
+		respon.Error(respw, http.StatusBadRequest, "Gagal menghitung kehadiran", err.Error())
+		return
+	}
+	for i := range summaries {
+		summaries[i].Days = nil
+	}
+	respon.OK(respw, summaries)
+}
+
+// Handler untuk daftar lokasi presensi toko
+func GetAttendanceLocations(respw http.ResponseWriter, req *http.Request) {
+	owner, ok := getOwner(respw, req)
+	if !ok {
+		return
+	}
+	locations, err := atdb.GetAllDoc[[]presensi.Lokasi](config.Mongoconn(), absensi.LokasiCollection, bson.M{"owner": owner})
+	if err != nil {
+		respon.Error(respw, http.StatusInternalServerError, "Gagal mengambil lokasi presensi", err.Error())
+		return
+	}
+	respon.OK(respw, locations)
+}
+
+// Handler untuk mendaftarkan batas wilayah toko sebagai lokasi presensi pegawai
+func CreateAttendanceLocation(respw http.ResponseWriter, req *http.Request) {
+	owner, ok := getOwner(respw, req)
+	if !ok {
+		return
+	}
+	var lokasi presensi.Lokasi
+	if !respon.Decode(respw, req, &lokasi) {
+		return
+	}
+	if strings.TrimSpace(lokasi.Nama) == "" || lokasi.Batas.Type != "Polygon" || lokasi.Batas.Coordinates == nil {
+		respon.Error(respw, http.StatusBadRequest, "Nama dan batas lokasi berupa Polygon wajib diisi", "")
+		return
+	}
+	lokasi.ID = primitive.NewObjectID()
+	lokasi.Owner = owner
+	if lokasi.Kategori == "" {
+		lokasi.Kategori = "toko"
+	}
+	if _, err := atdb.InsertOneDoc(config.Mongoconn(), absensi.LokasiCollection, lokasi); err != nil {
+		respon.Error(respw, http.StatusInternalServerError, "Gagal menyimpan lokasi presensi", err.Error())
+		return
+	}
+
+	respon.Success(respw, http.StatusCreated, "Lokasi presensi berhasil ditambahkan", lokasi)
+}
+
+// Handler untuk menghapus lokasi presensi toko: ?id=
+func DeleteAttendanceLocation(respw http.ResponseWriter, req *http.Request) {
+	owner, ok := getOwner(respw, req)
+	if !ok {
+		return
+	}
+	objectID, err := primitive.ObjectIDFromHex(req.URL.Query().Get("id"))
+	if err != nil {
+		respon.Error(respw, http.StatusBadRequest, "ID lokasi tidak valid", "")
+		return
+	}
+	result, err := atdb.DeleteOneDoc(config.Mongoconn(), absensi.LokasiCollection, bson.M{"_id": objectID, "owner": owner})
+	if err != nil {
+		respon.Error(respw, http.StatusInternalServerError, "Gagal menghapus lokasi presensi", err.Error())
+		return
+	}
+	if result.DeletedCount == 0 {
+		respon.Error(respw, http.StatusNotFound, "Lokasi presensi tidak ditemukan", "")
+		return
+	}
+
+	respon.Success(respw, http.StatusOK, "Lokasi presensi berhasil dihapus", nil)
+}
+
+// validSchedule memeriksa jam masuk, toleransi terlambat dan hari kerja pada profil toko
+func validSchedule(store model.StoreProfile) error {
+	if err := absensi.ValidShiftStart(store.ShiftStart); err != nil {
+		return err
+	}
+	if store.LateGraceMinutes < 0 {
+		return errors.New("late_grace_minutes tidak boleh negatif")
+	}
+	for _, d := range store.WorkDays {
+		if d < 0 || d > 6 {
+			return errors.New("work_days berisi 0 (Minggu) sampai 6 (Sabtu)")
+		}
+	}
+	return nil
+}
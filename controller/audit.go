@@ -0,0 +1,208 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/gocroot/config"
+	"github.com/gocroot/helper/atdb"
+	"github.com/gocroot/helper/audit"
+	"github.com/gocroot/helper/ledger"
+	"github.com/gocroot/helper/paging"
+	"github.com/gocroot/helper/respon"
+	"github.com/gocroot/helper/stok"
+	"github.com/gocroot/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// auditPaging adalah parameter list audit log: ?entity=, ?action=, ?user=, ?from=&to= pada createdAt
+var auditPaging = paging.Options{
+	DateField:  "createdAt",
+	Filters:    map[string]string{"entity": "entity", "action": "action", "user": "user"},
+	SortFields: []string{"createdAt"},
+}
+
+// deletedPaging adalah parameter list data terhapus: ?sort=deleted_at
+var deletedPaging = paging.Options{
+	SortFields: []string{"deleted_at"},
+}
+
+// auditActor mengambil pelaku perubahan dari sesi login dan IP klien untuk data milik owner
+func auditActor(req *http.Request, owner string) audit.Actor {
+	actor := audit.FromRequest(req)
+	actor.Owner = owner
+	return actor
+}
+
+// logAudit mencatat perubahan ke audit log, jika gagal respon error ditulis dan hasilnya false
+func logAudit(respw http.ResponseWriter, req *http.Request, owner, entity string, id primitive.ObjectID, action string, before, after interface{}) bool {
+	if err := audit.Log(config.Mongoconn(), auditActor(req, owner), entity, id, action, before, after); err != nil {
+		respon.Error(respw, http.StatusInternalServerError, "Gagal mencatat audit log", err.Error())
+		return false
+	}
+	return true
+}
+
+// logUpdate mengambil data terbaru lalu mencatat perubahannya dari before ke audit log
+func logUpdate[T any](respw http.ResponseWriter, req *http.Request, owner, entity string, id primitive.ObjectID, before T) (after T, ok bool) {
+	after, err := atdb.GetOneDoc[T](config.Mongoconn(), audit.Collections[entity], bson.M{"_id": id, "owner": owner})
+	if err != nil {
+		respon.Error(respw, http.StatusInternalServerError, "Gagal mengambil data untuk audit log", err.Error())
+		return
+	}
+	return after, logAudit(respw, req, owner, entity, id, audit.ActionUpdate, before, after)
+}
+
+// Handler untuk jejak audit: ?entity=&entity_id=&action=&user=&from=&to=
+func GetAuditLogs(respw http.ResponseWriter, req *http.Request) {
+	owner, ok := getOwner(respw, req)
+	if !ok {
+		return
+	}
+	base := bson.M{"owner": owner}
+	if id := req.URL.Query().Get("entity_id"); id != "" {
+		objectID, err := primitive.ObjectIDFromHex(id)
+		if err != nil {
+			respon.Error(respw, http.StatusBadRequest, "entity_id tidak valid", "")
+			return
+		}
+		base["entity_id"] = objectID
+	}
+	q, err := paging.Parse(req.URL.Query(), base, auditPaging)
+	if err != nil {
+		respon.Error(respw, http.StatusBadRequest, "Parameter query tidak valid", err.Error())
+		return
+	}
+	data, meta, err := paging.Find[model.AuditLog](config.Mongoconn(), audit.Collection, q)
+	if err != nil {
+		respon.Error(respw, http.StatusInternalServerError, "Gagal mengambil audit log", err.Error())
+		return
+	}
+	respon.List(respw, data, meta)
+}
+
+// Handler untuk daftar data yang sudah dihapus dan masih bisa dipulihkan: ?entity=
+func GetDeletedRecords(respw http.ResponseWriter, req *http.Request) {
+	owner, ok := getOwner(respw, req)
+	if !ok {
+		return
+	}
+	coll, found := audit.Collections[req.URL.Query().Get("entity")]
+	if !found {
+		respon.Error(respw, http.StatusBadRequest, "Entitas tidak valid", audit.ErrUnknownEntity.Error())
+		return
+	}
+	q, err := paging.Parse(req.URL.Query(), audit.Deleted(bson.M{"owner": owner}), deletedPaging)
+	if err != nil {
+		respon.Error(respw, http.StatusBadRequest, "Parameter query tidak valid", err.Error())
+		return
+	}
+	data, meta, err := paging.Find[bson.M](config.Mongoconn(), coll, q)
+	if err != nil {
+		respon.Error(respw, http.StatusInternalServerError, "Gagal mengambil data terhapus", err.Error())
+		return
+	}
+	respon.List(respw, data, meta)
+}
+
+// Handler untuk memulihkan data yang terhapus: ?entity=&id=.
+// Penjualan mengurangi stok dan memposting ulang jurnalnya, pengeluaran memposting ulang jurnalnya.
+func RestoreRecord(respw http.ResponseWriter, req *http.Request) {
+	owner, ok := getOwner(respw, req)
+	if !ok {
+		return
+	}
+	entity := req.URL.Query().Get("entity")
+	if _, found := audit.Collections[entity]; !found {
+		respon.Error(respw, http.StatusBadRequest, "Entitas tidak valid", audit.ErrUnknownEntity.Error())
+		return
+	}
+	objectID, err := primitive.ObjectIDFromHex(req.URL.Query().Get("id"))
+	if err != nil {
+		respon.Error(respw, http.StatusBadRequest, "ID tidak valid", "")
+		return
+	}
+
+	actor := auditActor(req, owner)
+	var restored interface{}
+	switch entity {
+	case audit.EntitySales:
+		restored, ok = restoreSale(respw, actor, objectID)
+	case audit.EntityPayroll:
+		// Jurnal gaji sudah dibalik dan periodenya bisa sudah dibuat ulang, payroll dibuat lagi lewat /payroll
+		respon.Error(respw, http.StatusBadRequest, "Payroll yang dihapus tidak bisa dipulihkan", "buat ulang payroll untuk periode tersebut")
+		return
+	case audit.EntityExpense:
+		var expense model.ExpenseTransaction
+		expense, err = atdb.GetOneDoc[model.ExpenseTransaction](config.Mongoconn(), audit.Collections[entity], audit.Deleted(bson.M{"_id": objectID, "owner": owner}))
+		if err != nil {
+			ok = writeRestoreError(respw, err)
+			break
+		}
+		if !periodOpen(respw, owner, expense.ExpenseDate) {
+			return
+		}
+		expense, err = audit.Restore[model.ExpenseTransaction](config.Mongoconn(), actor, entity, objectID)
+		if err == nil {
+			_, err = ledger.Post(config.Mongoconn(), ledger.ExpenseEntry(expense))
+		}
+		restored, ok = expense, writeRestoreError(respw, err)
+	default:
+		restored, err = audit.Restore[bson.M](config.Mongoconn(), actor, entity, objectID)
+		ok = writeRestoreError(respw, err)
+	}
+	if !ok {
+		return
+	}
+
+	respon.Success(respw, http.StatusOK, "Data berhasil dipulihkan", restored)
+}
+
+// restoreSale mengurangi lagi stok penjualan yang terhapus, memulihkannya lalu memposting jurnal penjualan dan cicilannya
+func restoreSale(respw http.ResponseWriter, actor audit.Actor, id primitive.ObjectID) (trx model.SalesTransaction, ok bool) {
+	trx, err := atdb.GetOneDoc[model.SalesTransaction](config.Mongoconn(), audit.Collections[audit.EntitySales], audit.Deleted(bson.M{"_id": id, "owner": actor.Owner}))
+	if err != nil {
+		return trx, writeRestoreError(respw, err)
+	}
+	if !periodOpen(respw, actor.Owner, trx.TransactionDate) {
+		return
+	}
+	trx.AllowBackorder = trx.Backorder
+	shortages, err := stok.ReserveSale(config.Mongoconn(), &trx)
+	if err == stok.ErrInsufficientStock {
+		respon.FailData(respw, http.StatusConflict, respon.CodeInsufficientStock, "Stok tidak mencukupi untuk memulihkan penjualan", "Tambah stok produk lalu ulangi", shortages)
+		return
+	}
+	if err != nil {
+		respon.Error(respw, http.StatusInternalServerError, "Gagal mengurangi stok", err.Error())
+		return
+	}
+
+	if _, err = audit.Restore[model.SalesTransaction](config.Mongoconn(), actor, audit.EntitySales, id); err != nil {
+		stok.RestockSale(config.Mongoconn(), trx, "Pembatalan: gagal memulihkan penjualan")
+		return trx, writeRestoreError(respw, err)
+	}
+	trx.DeletedAt, trx.DeletedBy = nil, ""
+	_, err = ledger.Post(config.Mongoconn(), ledger.SalesEntry(trx))
+	for _, payment := range trx.Payments {
+		if err != nil {
+			break
+		}
+		_, err = ledger.Post(config.Mongoconn(), ledger.ReceiptEntry(trx, payment))
+	}
+	return trx, writeRestoreError(respw, err)
+}
+
+// writeRestoreError menulis respon error pemulihan, hasilnya true jika tidak ada error
+func writeRestoreError(respw http.ResponseWriter, err error) bool {
+	if err == nil {
+		return true
+	}
+	if err == mongo.ErrNoDocuments {
+		respon.Error(respw, http.StatusNotFound, "Data terhapus tidak ditemukan", "")
+		return false
+	}
+	respon.Error(respw, http.StatusInternalServerError, "Gagal memulihkan data", err.Error())
+	return false
+}
@@ -6,13 +6,14 @@ import (
 	"net/http"
 	"net/url"
 	"os"
-	"regexp"
 	"time"
 
 	"github.com/gocroot/config"
-	"github.com/gocroot/helper/at"
 	"github.com/gocroot/helper/atdb"
 	"github.com/gocroot/helper/auth"
+	"github.com/gocroot/helper/respon"
+	"github.com/gocroot/helper/tenant"
+	"github.com/gocroot/helper/validasi"
 	"github.com/gocroot/helper/watoken"
 	"github.com/gocroot/model"
 	"go.mongodb.org/mongo-driver/bson"
@@ -28,27 +29,21 @@ func Auth(w http.ResponseWriter, r *http.Request) {
 		Token string `json:"token"`
 	}
 	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusBadRequest)
-		json.NewEncoder(w).Encode(map[string]string{"message": "Invalid request"})
+		respon.Error(w, http.StatusBadRequest, "Invalid request", err.Error())
 		return
 	}
 
 	// Ambil kredensial dari database
-	creds, err := atdb.GetOneDoc[auth.GoogleCredential](config.Mongoconn, "credentials", bson.M{})
+	creds, err := atdb.GetOneDoc[auth.GoogleCredential](config.Mongoconn(), "credentials", bson.M{})
 	if err != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusBadGateway)
-		json.NewEncoder(w).Encode(map[string]string{"message": "Database Connection Problem: Unable to fetch credentials"})
+		respon.Error(w, http.StatusBadGateway, "Database Connection Problem: Unable to fetch credentials", err.Error())
 		return
 	}
 
 	// Verifikasi ID token menggunakan client_id
 	payload, err := auth.VerifyIDToken(request.Token, creds.ClientID)
 	if err != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusUnauthorized)
-		json.NewEncoder(w).Encode(map[string]string{"message": "Invalid token: Token verification failed"})
+		respon.Error(w, http.StatusUnauthorized, "Invalid token: Token verification failed", err.Error())
 		return
 	}
 
@@ -62,38 +57,22 @@ func Auth(w http.ResponseWriter, r *http.Request) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	collection := config.Mongoconn.Collection("user")
+	collection := config.Mongoconn().Collection("user")
 	filter := bson.M{"email": userInfo.Email}
 
 	var existingUser model.Userdomyikado
 	err = collection.FindOne(ctx, filter).Decode(&existingUser)
 	if err != nil || existingUser.PhoneNumber == "" {
 		// User does not exist or exists but has no phone number, request QR scan
-		response := map[string]interface{}{
-			"message": "Please scan the QR code to provide your phone number",
-			"user":    userInfo,
-			"token":   "",
-		}
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusUnauthorized)
-		json.NewEncoder(w).Encode(response)
+		respon.FailData(w, http.StatusUnauthorized, respon.CodeUnauthorized, "Please scan the QR code to provide your phone number", "", bson.M{"user": userInfo, "token": ""})
 		return
 	} else if existingUser.PhoneNumber != "" {
 		token, err := watoken.EncodeforHours(existingUser.PhoneNumber, existingUser.Name, config.PrivateKey, 18) // Generating a token for 18 hours
 		if err != nil {
-			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(http.StatusInternalServerError)
-			json.NewEncoder(w).Encode(map[string]string{"message": "Token generation failed"})
+			respon.Error(w, http.StatusInternalServerError, "Token generation failed", err.Error())
 			return
 		}
-		response := map[string]interface{}{
-			"message": "Authenticated successfully",
-			"user":    userInfo,
-			"token":   token,
-		}
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusOK)
-		json.NewEncoder(w).Encode(response)
+		respon.Success(w, http.StatusOK, "Authenticated successfully", bson.M{"user": userInfo, "token": token})
 		return
 	}
 
@@ -103,18 +82,11 @@ func Auth(w http.ResponseWriter, r *http.Request) {
 	opts := options.Update().SetUpsert(true)
 	_, err = collection.UpdateOne(ctx, filter, update, opts)
 	if err != nil {
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusInternalServerError)
-		json.NewEncoder(w).Encode(map[string]string{"message": "Failed to save user info: Database update failed"})
+		respon.Error(w, http.StatusInternalServerError, "Failed to save user info: Database update failed", err.Error())
 		return
 	}
 
-	response := map[string]interface{}{
-		"user": userInfo,
-	}
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(response)
+	respon.OK(w, bson.M{"user": userInfo})
 }
 
 func GeneratePasswordHandler(respw http.ResponseWriter, r *http.Request) {
@@ -123,10 +95,7 @@ func GeneratePasswordHandler(respw http.ResponseWriter, r *http.Request) {
 		Captcha     string `json:"captcha"`
 	}
 	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
-		var respn model.Response
-		respn.Status = "Invalid Request"
-		respn.Response = err.Error()
-		at.WriteJSON(respw, http.StatusBadRequest, respn)
+		respon.Error(respw, http.StatusBadRequest, "Invalid Request", err.Error())
 		return
 	}
 	// Validate CAPTCHA
@@ -135,10 +104,7 @@ func GeneratePasswordHandler(respw http.ResponseWriter, r *http.Request) {
 		"response": {request.Captcha},
 	})
 	if err != nil {
-		var respn model.Response
-		respn.Status = "Failed to verify captcha"
-		respn.Response = err.Error()
-		at.WriteJSON(respw, http.StatusServiceUnavailable, respn)
+		respon.Error(respw, http.StatusServiceUnavailable, "Failed to verify captcha", err.Error())
 		return
 	}
 	defer captchaResponse.Body.Close()
@@ -147,64 +113,45 @@ func GeneratePasswordHandler(respw http.ResponseWriter, r *http.Request) {
 		Success bool `json:"success"`
 	}
 	if err := json.NewDecoder(captchaResponse.Body).Decode(&captchaResult); err != nil {
-		var respn model.Response
-		respn.Status = "Failed to decode captcha response"
-		respn.Response = err.Error()
-		at.WriteJSON(respw, http.StatusInternalServerError, respn)
+		respon.Error(respw, http.StatusInternalServerError, "Failed to decode captcha response", err.Error())
 		return
 	}
 	if !captchaResult.Success {
-		var respn model.Response
-		respn.Status = "Unauthorized"
-		respn.Response = "Invalid captcha"
-		at.WriteJSON(respw, http.StatusUnauthorized, respn)
+		respon.Error(respw, http.StatusUnauthorized, "Invalid captcha", "")
 		return
 	}
 
 	// Validate phone number
-	re := regexp.MustCompile(`^62\d{9,15}$`)
-	if !re.MatchString(request.PhoneNumber) {
-		var respn model.Response
-		respn.Status = "Bad Request"
-		respn.Response = "Invalid phone number format"
-		at.WriteJSON(respw, http.StatusBadRequest, respn)
+	if !validasi.Phone(request.PhoneNumber) {
+		respon.Error(respw, http.StatusBadRequest, "Invalid phone number format", "")
 		return
 	}
 
 	// Check if phone number exists in the 'user' collection
 	userFilter := bson.M{"phonenumber": request.PhoneNumber}
-	_, err = atdb.GetOneDoc[model.Userdomyikado](config.Mongoconn, "user", userFilter)
+	_, err = atdb.GetOneDoc[model.Userdomyikado](config.Mongoconn(), "user", userFilter)
 	if err != nil {
-		var respn model.Response
-		respn.Status = "Unauthorized"
-		respn.Response = "Phone number not registered"
-		at.WriteJSON(respw, http.StatusUnauthorized, respn)
+		respon.Error(respw, http.StatusUnauthorized, "Phone number not registered", "")
 		return
 	}
 
 	// Generate random password
 	randomPassword, err := auth.GenerateRandomPassword(12)
 	if err != nil {
-		var respn model.Response
-		respn.Status = "Failed to generate password"
-		respn.Response = err.Error()
-		at.WriteJSON(respw, http.StatusInternalServerError, respn)
+		respon.Error(respw, http.StatusInternalServerError, "Failed to generate password", err.Error())
 		return
 	}
 
 	// Hash the password
 	hashedPassword, err := auth.HashPassword(randomPassword)
 	if err != nil {
-		var respn model.Response
-		respn.Status = "Failed to hash password"
-		respn.Response = err.Error()
-		at.WriteJSON(respw, http.StatusInternalServerError, respn)
+		respon.Error(respw, http.StatusInternalServerError, "Failed to hash password", err.Error())
 		return
 	}
 
 	// Update or insert the user in the database
 	stpFilter := bson.M{"phonenumber": request.PhoneNumber}
-	_, err = atdb.GetOneDoc[model.Stp](config.Mongoconn, "stp", stpFilter)
+	_, err = atdb.GetOneDoc[model.Stp](config.Mongoconn(), "stp", stpFilter)
 	var responseMessage string
 
 	if err == mongo.ErrNoDocuments {
@@ -214,12 +161,9 @@ func GeneratePasswordHandler(respw http.ResponseWriter, r *http.Request) {
 			PasswordHash: hashedPassword,
 			CreatedAt:    time.Now(),
 		}
-		_, err = atdb.InsertOneDoc(config.Mongoconn, "stp", newUser)
+		_, err = atdb.InsertOneDoc(config.Mongoconn(), "stp", newUser)
 		if err != nil {
-			var respn model.Response
-			respn.Status = "Failed to insert new user"
-			respn.Response = err.Error()
-			at.WriteJSON(respw, http.StatusNotModified, respn)
+			respon.Error(respw, http.StatusInternalServerError, "Failed to insert new user", err.Error())
 			return
 		}
 		responseMessage = "New user created and password generated successfully"
@@ -230,23 +174,16 @@ func GeneratePasswordHandler(respw http.ResponseWriter, r *http.Request) {
 			"password":    hashedPassword,
 			"createdAt":   time.Now(),
 		}
-		_, err = atdb.UpdateOneDoc(config.Mongoconn, "stp", stpFilter, stpUpdate)
+		_, err = atdb.UpdateOneDoc(config.Mongoconn(), "stp", stpFilter, stpUpdate)
 		if err != nil {
-			var respn model.Response
-			respn.Status = "Failed to update user"
-			respn.Response = err.Error()
-			at.WriteJSON(respw, http.StatusInternalServerError, respn)
+			respon.Error(respw, http.StatusInternalServerError, "Failed to update user", err.Error())
 			return
 		}
 		responseMessage = "User info updated and password generated successfully"
 	}
 
 	// Respond with success and the generated password
-	response := map[string]interface{}{
-		"message":     responseMessage,
-		"phonenumber": request.PhoneNumber,
-	}
-	at.WriteJSON(respw, http.StatusOK, response)
+	respon.Success(respw, http.StatusOK, responseMessage, bson.M{"phonenumber": request.PhoneNumber})
 
 	// Send the random password via WhatsApp
 	auth.SendWhatsAppPassword(respw, request.PhoneNumber, randomPassword)
@@ -262,80 +199,53 @@ func VerifyPasswordHandler(respw http.ResponseWriter, r *http.Request) {
 		Password    string `json:"password"`
 	}
 	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
-		var respn model.Response
-		respn.Status = "Invalid Request"
-		respn.Response = err.Error()
-		at.WriteJSON(respw, http.StatusBadRequest, respn)
+		respon.Error(respw, http.StatusBadRequest, "Invalid Request", err.Error())
 		return
 	}
 
 	// Implementasi rate limiting
 	limiter := rl.GetLimiter(request.PhoneNumber)
 	if !limiter.Allow() {
-		var respn model.Response
-		respn.Status = "Too Many Requests"
-		respn.Response = "Please try again later."
-		at.WriteJSON(respw, http.StatusTooManyRequests, respn)
+		respon.Error(respw, http.StatusTooManyRequests, "Please try again later.", "")
 		return
 	}
 
 	// Find user in the database
 	userFilter := bson.M{"phonenumber": request.PhoneNumber}
-	user, err := atdb.GetOneDoc[model.Stp](config.Mongoconn, "stp", userFilter)
+	user, err := atdb.GetOneDoc[model.Stp](config.Mongoconn(), "stp", userFilter)
 	if err != nil {
-		var respn model.Response
-		respn.Status = "Failed to verify password"
-		respn.Response = err.Error()
-		at.WriteJSON(respw, http.StatusUnauthorized, respn)
+		respon.Error(respw, http.StatusUnauthorized, "Failed to verify password", err.Error())
 		return
 	}
 
 	// Verify password and expiry
 	if time.Now().After(user.CreatedAt.Add(4 * time.Minute)) {
-		var respn model.Response
-		respn.Status = "Unauthorized"
-		respn.Response = "Password Expired"
-		at.WriteJSON(respw, http.StatusUnauthorized, respn)
+		respon.Error(respw, http.StatusUnauthorized, "Password Expired", "")
 		return
 	}
 
 	err = bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(request.Password))
 	if err != nil {
-		var respn model.Response
-		respn.Status = "Failed to verify password"
-		respn.Response = err.Error()
-		at.WriteJSON(respw, http.StatusUnauthorized, respn)
+		respon.Error(respw, http.StatusUnauthorized, "Failed to verify password", err.Error())
 		return
 	}
 
 	// Find user in the 'user' collection
 	myiUserFilter := bson.M{"phonenumber": request.PhoneNumber}
-	existingUser, err := atdb.GetOneDoc[model.Userdomyikado](config.Mongoconn, "user", myiUserFilter)
+	existingUser, err := atdb.GetOneDoc[model.Userdomyikado](config.Mongoconn(), "user", myiUserFilter)
 	if err != nil {
-		var respn model.Response
-		respn.Status = "Unauthorized"
-		respn.Response = "Phone number not registered"
-		at.WriteJSON(respw, http.StatusUnauthorized, respn)
+		respon.Error(respw, http.StatusUnauthorized, "Phone number not registered", "")
 		return
 	}
 
 	token, err := watoken.EncodeforHours(existingUser.PhoneNumber, existingUser.Name, config.PrivateKey, 18)
 	if err != nil {
-		var respn model.Response
-		respn.Status = "Failed to give the token"
-		respn.Response = err.Error()
-		at.WriteJSON(respw, http.StatusInternalServerError, respn)
+		respon.Error(respw, http.StatusInternalServerError, "Failed to give the token", err.Error())
 		return
 	}
 
-	response := map[string]interface{}{
-		"message": "Authenticated successfully",
-		"token":   token,
-		"name":    existingUser.Name,
-	}
-
 	// Respond with success
-	at.WriteJSON(respw, http.StatusOK, response)
+	respon.Success(respw, http.StatusOK, "Authenticated successfully", bson.M{"token": token, "name": existingUser.Name})
 }
 
 func ResendPasswordHandler(respw http.ResponseWriter, r *http.Request) {
@@ -343,36 +253,27 @@ func ResendPasswordHandler(respw http.ResponseWriter, r *http.Request) {
 		PhoneNumber string `json:"phonenumber"`
 	}
 	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
-		var respn model.Response
-		respn.Status = "Invalid Request"
-		respn.Response = err.Error()
-		at.WriteJSON(respw, http.StatusBadRequest, respn)
+		respon.Error(respw, http.StatusBadRequest, "Invalid Request", err.Error())
 		return
 	}
 
 	// Generate random password
 	randomPassword, err := auth.GenerateRandomPassword(12)
 	if err != nil {
-		var respn model.Response
-		respn.Status = "Failed to generate password"
-		respn.Response = err.Error()
-		at.WriteJSON(respw, http.StatusInternalServerError, respn)
+		respon.Error(respw, http.StatusInternalServerError, "Failed to generate password", err.Error())
 		return
 	}
 
 	// Hash the password
 	hashedPassword, err := auth.HashPassword(randomPassword)
 	if err != nil {
-		var respn model.Response
-		respn.Status = "Failed to hash password"
-		respn.Response = err.Error()
-		at.WriteJSON(respw, http.StatusInternalServerError, respn)
+		respon.Error(respw, http.StatusInternalServerError, "Failed to hash password", err.Error())
 		return
 	}
 
 	// Check if phone number exists in the 'stp' collection
 	stpFilter := bson.M{"phonenumber": request.PhoneNumber}
-	_, stpErr := atdb.GetOneDoc[model.Stp](config.Mongoconn, "stp", stpFilter)
+	_, stpErr := atdb.GetOneDoc[model.Stp](config.Mongoconn(), "stp", stpFilter)
 
 	if stpErr == mongo.ErrNoDocuments {
 		// Document not found, insert new one
@@ -381,31 +282,21 @@ func ResendPasswordHandler(respw http.ResponseWriter, r *http.Request) {
 			PasswordHash: hashedPassword,
 			CreatedAt:    time.Now(),
 		}
-		_, err = atdb.InsertOneDoc(config.Mongoconn, "stp", newUser)
+		_, err = atdb.InsertOneDoc(config.Mongoconn(), "stp", newUser)
 		if err != nil {
-			var respn model.Response
-			respn.Status = "Failed to insert new user"
-			respn.Response = err.Error()
-			at.WriteJSON(respw, http.StatusInternalServerError, respn)
+			respon.Error(respw, http.StatusInternalServerError, "Failed to insert new user", err.Error())
 			return
 		}
 		responseMessage := "New user created and password generated successfully"
 
 		// Respond with success and the generated password
-		response := map[string]interface{}{
-			"message":     responseMessage,
-			"phonenumber": request.PhoneNumber,
-		}
-		at.WriteJSON(respw, http.StatusOK, response)
+		respon.Success(respw, http.StatusOK, responseMessage, bson.M{"phonenumber": request.PhoneNumber})
 
 		// Send the random password via WhatsApp
 		auth.SendWhatsAppPassword(respw, request.PhoneNumber, randomPassword)
 		return
 	} else if stpErr != nil {
-		var respn model.Response
-		respn.Status = "Failed to fetch user info"
-		respn.Response = stpErr.Error()
-		at.WriteJSON(respw, http.StatusInternalServerError, respn)
+		respon.Error(respw, http.StatusInternalServerError, "Failed to fetch user info", stpErr.Error())
 		return
 	}
 
@@ -415,54 +306,39 @@ func ResendPasswordHandler(respw http.ResponseWriter, r *http.Request) {
 		"password":    hashedPassword,
 		"createdAt":   time.Now(),
 	}
-	_, err = atdb.UpdateOneDoc(config.Mongoconn, "stp", stpFilter, stpUpdate)
+	_, err = atdb.UpdateOneDoc(config.Mongoconn(), "stp", stpFilter, stpUpdate)
 	if err != nil {
-		var respn model.Response
-		respn.Status = "Failed to update user"
-		respn.Response = err.Error()
-		at.WriteJSON(respw, http.StatusInternalServerError, respn)
+		respon.Error(respw, http.StatusInternalServerError, "Failed to update user", err.Error())
 		return
 	}
 	responseMessage := "User info updated and password generated successfully"
 
-	response := map[string]interface{}{
-		"message":     responseMessage,
-		"phonenumber": request.PhoneNumber,
-	}
-	at.WriteJSON(respw, http.StatusOK, response)
+	respon.Success(respw, http.StatusOK, responseMessage, bson.M{"phonenumber": request.PhoneNumber})
 
 	// Send the random password via WhatsApp
 	auth.SendWhatsAppPassword(respw, request.PhoneNumber, randomPassword)
 }
 
 func RegisterAkunPenjual(respw http.ResponseWriter, r *http.Request) {
-	var request model.Userdomyikado
-	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
-		respn := model.Response{
-			Status:   "Invalid Request",
-			Response: err.Error(),
-		}
-		at.WriteJSON(respw, http.StatusBadRequest, respn)
+	var request struct {
+		Name        string `json:"name" validate:"required"`
+		PhoneNumber string `json:"phonenumber" validate:"required,phone"`
+		Email       string `json:"email" validate:"required,email"`
+		Password    string `json:"password" validate:"required"`
+	}
+	if !respon.Decode(respw, r, &request) {
 		return
 	}
-
-	re := regexp.MustCompile(`^62\d{9,15}$`)
-	if !re.MatchString(request.PhoneNumber) {
-		respn := model.Response{
-			Status:   "Bad Request",
-			Response: "Invalid phone number format",
-		}
-		at.WriteJSON(respw, http.StatusBadRequest, respn)
+	// Nomor telepon menjadi pemilik data toko, jadi satu nomor atau email tidak boleh didaftarkan dua kali
+	filter := bson.M{"$or": []bson.M{{"phonenumber": request.PhoneNumber}, {"email": request.Email}}}
+	if _, err := atdb.GetOneDoc[model.Userdomyikado](config.Mongoconn(), "user", filter); err == nil {
+		respon.Error(respw, http.StatusConflict, "Nomor telepon atau email sudah terdaftar", "")
 		return
 	}
 
 	hashedPassword, err := auth.HashPassword(request.Password)
 	if err != nil {
-		respn := model.Response{
-			Status:   "Failed to hash password",
-			Response: err.Error(),
-		}
-		at.WriteJSON(respw, http.StatusInternalServerError, respn)
+		respon.Error(respw, http.StatusInternalServerError, "Gagal hash password", err.Error())
 		return
 	}
 
@@ -478,83 +354,64 @@ func RegisterAkunPenjual(respw http.ResponseWriter, r *http.Request) {
 		Password:      hashedPassword,
 	}
 
-	_, err = atdb.InsertOneDoc(config.Mongoconn, "user", newUser)
+	_, err = atdb.InsertOneDoc(config.Mongoconn(), "user", newUser)
+	if mongo.IsDuplicateKeyError(err) {
+		respon.Error(respw, http.StatusConflict, "Nomor telepon atau email sudah terdaftar", "")
+		return
+	}
 	if err != nil {
-		respn := model.Response{
-			Status:   "Failed to insert new user",
-			Response: err.Error(),
-		}
-		at.WriteJSON(respw, http.StatusInternalServerError, respn)
+		respon.Error(respw, http.StatusInternalServerError, "Gagal menyimpan akun baru", err.Error())
 		return
 	}
 
-	response := map[string]interface{}{
-		"message":       "New user created successfully",
+	respon.Success(respw, http.StatusCreated, "New user created successfully", map[string]interface{}{
 		"name":          newUser.Name,
 		"phonenumber":   newUser.PhoneNumber,
 		"email":         newUser.Email,
 		"team":          newUser.Team,
 		"scope":         newUser.Scope,
 		"jumlahAntrian": newUser.JumlahAntrian,
-	}
-
-	at.WriteJSON(respw, http.StatusOK, response)
+	})
 }
 
 func LoginAkunPenjual(respw http.ResponseWriter, r *http.Request) {
-	var userRequest model.Userdomyikado
-
-	if err := json.NewDecoder(r.Body).Decode(&userRequest); err != nil {
-		response := model.Response{
-			Status:   "Invalid Request",
-			Response: err.Error(),
-		}
-		at.WriteJSON(respw, http.StatusBadRequest, response)
+	var userRequest struct {
+		Email    string `json:"email" validate:"required"`
+		Password string `json:"password" validate:"required"`
+	}
+	if !respon.Decode(respw, r, &userRequest) {
 		return
 	}
 
 	var storedUser model.Userdomyikado
-	err := config.Mongoconn.Collection("user").FindOne(context.Background(), bson.M{"email": userRequest.Email}).Decode(&storedUser)
+	err := config.Mongoconn().Collection("user").FindOne(context.Background(), bson.M{"email": userRequest.Email}).Decode(&storedUser)
 	if err != nil {
-		response := model.Response{
-			Status:   "Error: Toko tidak ditemukan",
-			Response: "Error: " + err.Error(),
-		}
-		at.WriteJSON(respw, http.StatusNotFound, response)
+		respon.Error(respw, http.StatusNotFound, "Toko tidak ditemukan", err.Error())
 		return
 	}
 
 	err = bcrypt.CompareHashAndPassword([]byte(storedUser.Password), []byte(userRequest.Password))
 	if err != nil {
-		response := model.Response{
-			Status:   "Failed to verify password",
-			Response: "Invalid password",
-		}
-		at.WriteJSON(respw, http.StatusUnauthorized, response)
+		respon.Error(respw, http.StatusUnauthorized, "Password salah", "")
 		return
 	}
 
 	encryptedToken, err := watoken.EncodeforHours(storedUser.PhoneNumber, storedUser.Name, config.PRIVATEKEY, 18)
 	if err != nil {
-		var respn model.Response
-		respn.Status = "Error: token gagal generate"
-		respn.Response = ", Error: " + err.Error()
-		at.WriteJSON(respw, http.StatusNotFound, respn)
+		respon.Error(respw, http.StatusInternalServerError, "Token gagal dibuat", err.Error())
 		return
 	}
 
-	response := map[string]interface{}{
-		"message": "Login successful",
+	respon.Success(respw, http.StatusOK, "Login successful", map[string]interface{}{
 		"name":    storedUser.Name,
 		"email":   storedUser.Email,
 		"phone":   storedUser.PhoneNumber,
 		"team":    storedUser.Team,
 		"scope":   storedUser.Scope,
+		"role":    tenant.NormalizeRole(storedUser.Role),
 		"token":   encryptedToken,
 		"antrian": storedUser.JumlahAntrian,
-	}
-
-	at.WriteJSON(respw, http.StatusOK, response)
+	})
 }
 
 // func LoginAkunPenjual(respw http.ResponseWriter, r *http.Request) {
@@ -572,7 +429,7 @@ func LoginAkunPenjual(respw http.ResponseWriter, r *http.Request) {
 
 // 	// Cari user berdasarkan email
 // 	var storedUser model.Userdomyikado
-// 	err := config.Mongoconn.Collection("user").FindOne(context.Background(), bson.M{"email": userRequest.Email}).Decode(&storedUser)
+// 	err := config.Mongoconn().Collection("user").FindOne(context.Background(), bson.M{"email": userRequest.Email}).Decode(&storedUser)
 // 	if err != nil {
 // 		response := model.Response{
 // 			Status:   "Error: Toko tidak ditemukan",
@@ -9,6 +9,7 @@ import (
 	"github.com/gocroot/helper/at"
 	"github.com/gocroot/helper/atapi"
 	"github.com/gocroot/helper/atdb"
+	"github.com/gocroot/helper/respon"
 	"github.com/gocroot/helper/watoken"
 	"github.com/gocroot/model"
 	"github.com/whatsauth/itmodel"
@@ -19,111 +20,74 @@ import (
 func GetDataSenders(respw http.ResponseWriter, req *http.Request) {
 	payload, err := watoken.Decode(config.PublicKeyWhatsAuth, at.GetLoginFromHeader(req))
 	if err != nil {
-		var respn model.Response
-		respn.Status = "Error : Token Tidak Valid"
-		respn.Info = at.GetSecretFromHeader(req)
-		respn.Location = "Decode Token Error"
-		respn.Response = err.Error()
-		at.WriteJSON(respw, http.StatusForbidden, respn)
+		respon.Error(respw, http.StatusForbidden, "Token Tidak Valid", err.Error())
 		return
 	}
-	_, err = atdb.GetOneDoc[model.Userdomyikado](config.Mongoconn, "user", primitive.M{"phonenumber": payload.Id})
+	_, err = atdb.GetOneDoc[model.Userdomyikado](config.Mongoconn(), "user", primitive.M{"phonenumber": payload.Id})
 	if err != nil {
-		var respn model.Response
-		respn.Status = "Error : Data user tidak di temukan"
-		respn.Response = err.Error()
-		at.WriteJSON(respw, http.StatusNotImplemented, respn)
+		respon.Error(respw, http.StatusNotImplemented, "Data user tidak di temukan", err.Error())
 		return
 	}
-	existingprjs, err := atdb.GetAllDoc[[]model.SenderDasboard](config.Mongoconn, "sender", primitive.M{})
+	existingprjs, err := atdb.GetAllDoc[[]model.SenderDasboard](config.Mongoconn(), "sender", primitive.M{})
 	if err != nil {
-		var respn model.Response
-		respn.Status = "Error : Data senders tidak di temukan"
-		respn.Response = err.Error()
-		at.WriteJSON(respw, http.StatusNotFound, respn)
+		respon.Error(respw, http.StatusNotFound, "Data senders tidak di temukan", err.Error())
 		return
 	}
 	if len(existingprjs) == 0 {
-		var respn model.Response
-		respn.Status = "Error : Data senders tidak di temukan"
-		respn.Response = "Kakak belum input sender, silahkan input dulu ya"
-		at.WriteJSON(respw, http.StatusNotFound, respn)
+		respon.Error(respw, http.StatusNotFound, "Data senders tidak di temukan", "Kakak belum input sender, silahkan input dulu ya")
 		return
 	}
-	at.WriteJSON(respw, http.StatusOK, existingprjs)
+	respon.OK(respw, existingprjs)
 }
 
 func GetDataSendersTerblokir(respw http.ResponseWriter, req *http.Request) {
 	payload, err := watoken.Decode(config.PublicKeyWhatsAuth, at.GetLoginFromHeader(req))
 	if err != nil {
-		var respn model.Response
-		respn.Status = "Error : Token Tidak Valid"
-		respn.Info = at.GetSecretFromHeader(req)
-		respn.Location = "Decode Token Error"
-		respn.Response = err.Error()
-		at.WriteJSON(respw, http.StatusForbidden, respn)
+		respon.Error(respw, http.StatusForbidden, "Token Tidak Valid", err.Error())
 		return
 	}
-	_, err = atdb.GetOneDoc[model.Userdomyikado](config.Mongoconn, "user", primitive.M{"phonenumber": payload.Id})
+	_, err = atdb.GetOneDoc[model.Userdomyikado](config.Mongoconn(), "user", primitive.M{"phonenumber": payload.Id})
 	if err != nil {
-		var respn model.Response
-		respn.Status = "Error : Data user tidak di temukan"
-		respn.Response = err.Error()
-		at.WriteJSON(respw, http.StatusNotImplemented, respn)
+		respon.Error(respw, http.StatusNotImplemented, "Data user tidak di temukan", err.Error())
 		return
 	}
-	existingprjs, err := atdb.GetAllDoc[[]model.SenderDasboard](config.Mongoconn, "bin", primitive.M{})
+	existingprjs, err := atdb.GetAllDoc[[]model.SenderDasboard](config.Mongoconn(), "bin", primitive.M{})
 	if err != nil {
-		var respn model.Response
-		respn.Status = "Error : Data senders tidak di temukan"
-		respn.Response = err.Error()
-		at.WriteJSON(respw, http.StatusNotFound, respn)
+		respon.Error(respw, http.StatusNotFound, "Data senders tidak di temukan", err.Error())
 		return
 	}
 	if len(existingprjs) == 0 {
-		var respn model.Response
-		respn.Status = "Error : Data senders tidak di temukan"
-		respn.Response = "Kakak belum input sender, silahkan input dulu ya"
-		at.WriteJSON(respw, http.StatusNotFound, respn)
+		respon.Error(respw, http.StatusNotFound, "Data senders tidak di temukan", "Kakak belum input sender, silahkan input dulu ya")
 		return
 	}
-	at.WriteJSON(respw, http.StatusOK, existingprjs)
+	respon.OK(respw, existingprjs)
 }
 
 func GetRekapBlast(respw http.ResponseWriter, req *http.Request) {
-	var respn model.Response
 	payload, err := watoken.Decode(config.PublicKeyWhatsAuth, at.GetLoginFromHeader(req))
 	if err != nil {
-		respn.Status = "Error : Token Tidak Valid"
-		respn.Info = at.GetSecretFromHeader(req)
-		respn.Location = "Decode Token Error"
-		respn.Response = err.Error()
-		at.WriteJSON(respw, http.StatusForbidden, respn)
+		respon.Error(respw, http.StatusForbidden, "Token Tidak Valid", err.Error())
 		return
 	}
 	//check eksistensi user
-	docuser, err := atdb.GetOneDoc[model.Userdomyikado](config.Mongoconn, "user", primitive.M{"phonenumber": payload.Id})
+	docuser, err := atdb.GetOneDoc[model.Userdomyikado](config.Mongoconn(), "user", primitive.M{"phonenumber": payload.Id})
 	if err != nil {
 		docuser.PhoneNumber = payload.Id
 		docuser.Name = payload.Alias
-		at.WriteJSON(respw, http.StatusNotFound, docuser)
+		respon.FailData(respw, http.StatusNotFound, respon.CodeNotFound, "User belum terdaftar", "", docuser)
 		return
 	}
 	docuser.Name = payload.Alias
 	//melakukan pengambilan data belum terlayani
 	// Menghitung jumlah dokumen dalam koleksi
-	countqueue, err := atdb.GetCountDoc(config.Mongoconn, "peserta", bson.M{})
+	countqueue, err := atdb.GetCountDoc(config.Mongoconn(), "peserta", bson.M{})
 	if err != nil {
-		respn.Status = "Error : penghitungan data queue"
-		respn.Response = err.Error()
-		at.WriteJSON(respw, http.StatusConflict, respn)
+		respon.Error(respw, http.StatusConflict, "penghitungan data queue", err.Error())
 		return
 	}
-	countsent, err := atdb.GetCountDoc(config.Mongoconn, "sent", bson.M{})
+	countsent, err := atdb.GetCountDoc(config.Mongoconn(), "sent", bson.M{})
 	if err != nil {
-		respn.Status = "Error : penghitungan data sent"
-		respn.Response = err.Error()
-		at.WriteJSON(respw, http.StatusConflict, respn)
+		respon.Error(respw, http.StatusConflict, "penghitungan data sent", err.Error())
 		return
 	}
 
@@ -132,43 +96,33 @@ func GetRekapBlast(respw http.ResponseWriter, req *http.Request) {
 		Done: int(countsent),
 		All:  int(countqueue + countsent),
 	}
-	at.WriteJSON(respw, http.StatusOK, rekap)
+	respon.OK(respw, rekap)
 }
 
 // melakukan pendaftaran nomor blast dengan pengecekan apakah suda link device
 func PutNomorBlast(respw http.ResponseWriter, req *http.Request) {
 	payload, err := watoken.Decode(config.PublicKeyWhatsAuth, at.GetLoginFromHeader(req))
 	if err != nil {
-		var respn model.Response
-		respn.Status = "Error : Token Tidak Valid "
-		respn.Info = at.GetSecretFromHeader(req)
-		respn.Location = "Decode Token Error: " + at.GetLoginFromHeader(req)
-		respn.Response = err.Error()
-		at.WriteJSON(respw, http.StatusForbidden, respn)
+		respon.Error(respw, http.StatusForbidden, "Token Tidak Valid", err.Error())
 		return
 	}
 	var newbot model.SenderDasboard
 	err = json.NewDecoder(req.Body).Decode(&newbot)
 	if err != nil {
-		var respn model.Response
-		respn.Status = "Error : Body tidak valid"
-		respn.Response = err.Error()
-		at.WriteJSON(respw, http.StatusBadRequest, respn)
+		respon.Error(respw, http.StatusBadRequest, "Body tidak valid", err.Error())
 		return
 	}
-	docuser, err := atdb.GetOneDoc[model.Userdomyikado](config.Mongoconn, "user", primitive.M{"phonenumber": payload.Id})
+	docuser, err := atdb.GetOneDoc[model.Userdomyikado](config.Mongoconn(), "user", primitive.M{"phonenumber": payload.Id})
 	if err != nil {
 		docuser.PhoneNumber = payload.Id
 		docuser.Name = payload.Alias
-		at.WriteJSON(respw, http.StatusNotFound, docuser)
+		respon.FailData(respw, http.StatusNotFound, respon.CodeNotFound, "User belum terdaftar", "", docuser)
 		return
 	}
 	docuser.Name = payload.Alias
 	//check apakah user sudah linked device atau belum
 	if docuser.LinkedDevice == "" {
-		var respn model.Response
-		respn.Status = "Error : User belum melakukan linked device"
-		at.WriteJSON(respw, http.StatusExpectationFailed, respn)
+		respon.Error(respw, http.StatusExpectationFailed, "User belum melakukan linked device", "")
 		return
 	}
 	//check validasi nomor inputan dengan mengirimkan pesan
@@ -179,33 +133,32 @@ func PutNomorBlast(respw http.ResponseWriter, req *http.Request) {
 	}
 	httpstatuscode, _, err := atapi.PostStructWithToken[model.Response]("token", config.WAAPIToken, newmsg, config.WAAPIMessage)
 	if httpstatuscode != 200 || err != nil {
-		var respn model.Response
-		respn.Status = "Error : Nomor yang diinputkan tidak valid"
+		detail := http.StatusText(httpstatuscode)
 		if err != nil {
-			respn.Response = err.Error()
+			detail = err.Error()
 		}
-		at.WriteJSON(respw, http.StatusExpectationFailed, respn)
+		respon.Error(respw, http.StatusExpectationFailed, "Nomor yang diinputkan tidak valid", detail)
 		return
 	}
 	//request linked device nomor yang didaftarkan
 	tokenbotbaru, err := watoken.Encode(newbot.Phonenumber, config.PrivateKey)
 	if err != nil {
-		at.WriteJSON(respw, http.StatusMisdirectedRequest, docuser)
+		respon.FailData(respw, http.StatusMisdirectedRequest, respon.CodeFor(http.StatusMisdirectedRequest), "Gagal membuat token nomor blast", err.Error(), docuser)
 		return
 	}
 	hcode, qrstat, err := atapi.Get[model.QRStatus](config.WAAPIGetDevice + tokenbotbaru)
 	if err != nil {
-		at.WriteJSON(respw, http.StatusMisdirectedRequest, docuser)
+		respon.FailData(respw, http.StatusMisdirectedRequest, respon.CodeFor(http.StatusMisdirectedRequest), "Gagal memeriksa status device", err.Error(), docuser)
 		return
 	}
 	if hcode != http.StatusOK {
-		at.WriteJSON(respw, http.StatusFailedDependency, docuser)
+		respon.FailData(respw, http.StatusFailedDependency, respon.CodeFor(http.StatusFailedDependency), "Status device tidak bisa diambil", http.StatusText(hcode), docuser)
 		return
 	}
 	//insert ke coll sender dan profile
-	contohsender, err := atdb.GetOneLatestDoc[itmodel.Profile](config.Mongoconn, "sender", bson.M{})
+	contohsender, err := atdb.GetOneLatestDoc[itmodel.Profile](config.Mongoconn(), "sender", bson.M{})
 	if err != nil {
-		at.WriteJSON(respw, http.StatusFailedDependency, docuser)
+		respon.FailData(respw, http.StatusFailedDependency, respon.CodeFor(http.StatusFailedDependency), "Contoh sender tidak ditemukan", err.Error(), docuser)
 		return
 	}
 	contohsender.Botname = docuser.Name
@@ -219,12 +172,12 @@ func PutNomorBlast(respw http.ResponseWriter, req *http.Request) {
 	contohsender.URL = baseURL + newbot.Phonenumber
 	contohsender.Token, err = watoken.EncodeforHours(newbot.Phonenumber, docuser.Name, config.PrivateKey, 43830)
 	if err != nil {
-		at.WriteJSON(respw, http.StatusFailedDependency, docuser)
+		respon.FailData(respw, http.StatusFailedDependency, respon.CodeFor(http.StatusFailedDependency), "Gagal membuat token sender", err.Error(), docuser)
 		return
 	}
-	_, err = atdb.InsertOneDoc(config.Mongoconn, "sender", contohsender)
+	_, err = atdb.InsertOneDoc(config.Mongoconn(), "sender", contohsender)
 	if err != nil {
-		at.WriteJSON(respw, http.StatusFailedDependency, docuser)
+		respon.FailData(respw, http.StatusFailedDependency, respon.CodeFor(http.StatusFailedDependency), "Gagal menyimpan sender", err.Error(), docuser)
 		return
 	}
 	//daftarkan ke webhook agar bot aktif dan insert kan ke profile
@@ -234,17 +187,16 @@ func PutNomorBlast(respw http.ResponseWriter, req *http.Request) {
 	}
 	httpstatuscode, _, err = atapi.PostStructWithToken[model.Response]("token", contohsender.Token, whdt, config.WAAPIGetToken)
 	if httpstatuscode != 200 || err != nil {
-		var respn model.Response
-		respn.Status = "Error : Gagal mendaftarkan ke webhook"
+		detail := http.StatusText(httpstatuscode)
 		if err != nil {
-			respn.Response = err.Error()
+			detail = err.Error()
 		}
-		at.WriteJSON(respw, http.StatusExpectationFailed, respn)
+		respon.Error(respw, http.StatusExpectationFailed, "Gagal mendaftarkan ke webhook", detail)
 		return
 	}
-	_, err = atdb.InsertOneDoc(config.Mongoconn, "profile", contohsender)
+	_, err = atdb.InsertOneDoc(config.Mongoconn(), "profile", contohsender)
 	if err != nil {
-		at.WriteJSON(respw, http.StatusFailedDependency, docuser)
+		respon.FailData(respw, http.StatusFailedDependency, respon.CodeFor(http.StatusFailedDependency), "Gagal menyimpan profile", err.Error(), docuser)
 		return
 	}
 
@@ -258,16 +210,15 @@ func PutNomorBlast(respw http.ResponseWriter, req *http.Request) {
 		}
 		httpstatuscode, _, err = atapi.PostStructWithToken[model.Response]("token", config.WAAPIToken, newmsg, config.WAAPITextMessage)
 		if httpstatuscode != 200 || err != nil {
-			var respn model.Response
-			respn.Status = "Error : Nomor yang diinputkan tidak valid"
+			detail := http.StatusText(httpstatuscode)
 			if err != nil {
-				respn.Response = err.Error()
+				detail = err.Error()
 			}
-			at.WriteJSON(respw, http.StatusExpectationFailed, respn)
+			respon.Error(respw, http.StatusExpectationFailed, "Nomor yang diinputkan tidak valid", detail)
 			return
 		}
 	}
 	//kirim ke frontend
-	at.WriteJSON(respw, http.StatusOK, qrstat)
+	respon.OK(respw, qrstat)
 }
 
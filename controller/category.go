@@ -1,121 +1,124 @@
 package controller
 
 import (
-	"encoding/json"
 	"net/http"
 	"time"
 
-	"github.com/gocroot/config"
-	"github.com/gocroot/helper/at"
-	"github.com/gocroot/helper/atdb"
+	"github.com/gocroot/helper/paging"
+	"github.com/gocroot/helper/pajak"
+	"github.com/gocroot/helper/respon"
 	"github.com/gocroot/model"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 func CreateCategory(respw http.ResponseWriter, req *http.Request) {
+	owner, ok := getOwner(respw, req)
+	if !ok {
+		return
+	}
 	var category model.Category
-	if err := json.NewDecoder(req.Body).Decode(&category); err != nil {
-		var respn model.Response
-		respn.Status = "Error: Bad Request"
-		respn.Response = err.Error()
-		at.WriteJSON(respw, http.StatusBadRequest, respn)
+	if !respon.Decode(respw, req, &category) {
 		return
 	}
 
 	newCategory := model.Category{
+		ID:          primitive.NewObjectID(),
+		Owner:       owner,
 		Name:        category.Name,
 		Description: category.Description,
+		TaxRate:     category.TaxRate,
 		CreatedAt:   time.Now(),
 		UpdatedAt:   time.Now(),
 	}
-
-	_, err := atdb.InsertOneDoc(config.Mongoconn, "kategori", newCategory)
-	if err != nil {
-		var respn model.Response
-		respn.Status = "Error: Gagal Insert Database"
-		respn.Response = err.Error()
-		at.WriteJSON(respw, http.StatusNotModified, respn)
+	if newCategory.TaxRate != nil && !pajak.ValidRate(*newCategory.TaxRate) {
+		respon.Error(respw, http.StatusBadRequest, "Tarif PPN harus antara 0 dan 100 persen", "")
 		return
 	}
 
-	response := map[string]interface{}{
-		"status":  "success",
-		"message": "Kategori berhasil ditambahkan",
-		"data":    newCategory,
+	if err := repos.Categories.Insert(req.Context(), newCategory); err != nil {
+		respon.Error(respw, http.StatusInternalServerError, "Gagal Insert Database", err.Error())
+		return
 	}
-	at.WriteJSON(respw, http.StatusOK, response)
+
+	respon.Success(respw, http.StatusCreated, "Kategori berhasil ditambahkan", newCategory)
+}
+
+// categoryPaging adalah parameter list kategori: ?q= nama/deskripsi, ?sort=name|createdAt
+var categoryPaging = paging.Options{
+	SearchFields: []string{"name", "description"},
+	SortFields:   []string{"name", "createdAt"},
 }
 
 func GetAllCategory(respw http.ResponseWriter, req *http.Request) {
-	data, err := atdb.GetAllDoc[[]model.Category](config.Mongoconn, "kategori", primitive.M{})
-	if err != nil || len(data) == 0 {
-		var respn model.Response
-		respn.Status = "Error: Data kategori tidak ditemukan atau kosong"
-		respn.Response = err.Error()
-		at.WriteJSON(respw, http.StatusNotFound, respn)
+	owner, ok := getOwner(respw, req)
+	if !ok {
+		return
+	}
+	q, err := paging.Parse(req.URL.Query(), bson.M{"owner": owner}, categoryPaging)
+	if err != nil {
+		respon.Error(respw, http.StatusBadRequest, "Parameter query tidak valid", err.Error())
+		return
+	}
+	data, meta, err := repos.Categories.Find(req.Context(), q)
+	if err != nil {
+		respon.Error(respw, http.StatusInternalServerError, "Gagal mengambil data kategori", err.Error())
 		return
 	}
 
-	at.WriteJSON(respw, http.StatusOK, data)
+	respon.List(respw, data, meta)
 }
 
 func GetCategoryByID(respw http.ResponseWriter, req *http.Request) {
+	owner, ok := getOwner(respw, req)
+	if !ok {
+		return
+	}
 	categoryID := req.URL.Query().Get("id")
 	if categoryID == "" {
-		var respn model.Response
-		respn.Status = "Error: ID Category tidak ditemukan"
-		at.WriteJSON(respw, http.StatusBadRequest, respn)
+		respon.Error(respw, http.StatusBadRequest, "ID Category tidak ditemukan", "")
 		return
 	}
 
 	objectID, err := primitive.ObjectIDFromHex(categoryID)
 	if err != nil {
-		var respn model.Response
-		respn.Status = "Error: ID Category tidak valid"
-		at.WriteJSON(respw, http.StatusBadRequest, respn)
+		respon.Error(respw, http.StatusBadRequest, "ID Category tidak valid", "")
 		return
 	}
 
-	var category model.Category
-	filter := bson.M{"_id": objectID}
-	_, err = atdb.GetOneDoc[model.Category](config.Mongoconn, "kategori", filter)
+	filter := bson.M{"_id": objectID, "owner": owner}
+	category, err := repos.Categories.Get(req.Context(), filter)
 	if err != nil {
-		var respn model.Response
-		respn.Status = "Error: Category tidak ditemukan"
-		at.WriteJSON(respw, http.StatusNotFound, respn)
+		respon.Error(respw, http.StatusNotFound, "Category tidak ditemukan", "")
 		return
 	}
 
-	at.WriteJSON(respw, http.StatusOK, category)
+	respon.OK(respw, category)
 }
 
 func UpdateCategory(respw http.ResponseWriter, req *http.Request) {
+	owner, ok := getOwner(respw, req)
+	if !ok {
+		return
+	}
 	categoryID := req.URL.Query().Get("id")
 	if categoryID == "" {
-		var respn model.Response
-		respn.Status = "Error: ID Category tidak ditemukan"
-		at.WriteJSON(respw, http.StatusBadRequest, respn)
+		respon.Error(respw, http.StatusBadRequest, "ID Category tidak ditemukan", "")
 		return
 	}
 
 	objectID, err := primitive.ObjectIDFromHex(categoryID)
 	if err != nil {
-		var respn model.Response
-		respn.Status = "Error: ID Category tidak valid"
-		at.WriteJSON(respw, http.StatusBadRequest, respn)
+		respon.Error(respw, http.StatusBadRequest, "ID Category tidak valid", "")
 		return
 	}
 
 	var requestBody struct {
-		Name        string `json:"name"`
-		Description string `json:"description"`
+		Name        string   `json:"name"`
+		Description string   `json:"description"`
+		TaxRate     *float64 `json:"tax_rate"` // Nilai negatif menghapus tarif kategori
 	}
-	err = json.NewDecoder(req.Body).Decode(&requestBody)
-	if err != nil {
-		var respn model.Response
-		respn.Status = "Error: Gagal membaca data JSON"
-		at.WriteJSON(respw, http.StatusBadRequest, respn)
+	if !respon.Decode(respw, req, &requestBody) {
 		return
 	}
 
@@ -126,49 +129,59 @@ func UpdateCategory(respw http.ResponseWriter, req *http.Request) {
 	if requestBody.Description != "" {
 		updateData["description"] = requestBody.Description
 	}
+	update := bson.M{}
+	if requestBody.TaxRate != nil {
+		if *requestBody.TaxRate > 100 {
+			respon.Error(respw, http.StatusBadRequest, "Tarif PPN harus antara 0 dan 100 persen", "")
+			return
+		}
+		if *requestBody.TaxRate < 0 {
+			update["$unset"] = bson.M{"tax_rate": ""}
+		} else {
+			updateData["tax_rate"] = *requestBody.TaxRate
+		}
+	}
 	updateData["updatedAt"] = time.Now()
-
-	update := bson.M{"$set": updateData}
-	_, err = atdb.UpdateOneDoc(config.Mongoconn, "kategori", bson.M{"_id": objectID}, update)
+	update["$set"] = updateData
+	matched, err := repos.Categories.Update(req.Context(), bson.M{"_id": objectID, "owner": owner}, update)
 	if err != nil {
-		var respn model.Response
-		respn.Status = "Error: Gagal mengupdate category"
-		respn.Response = err.Error()
-		at.WriteJSON(respw, http.StatusNotModified, respn)
+		respon.Error(respw, http.StatusInternalServerError, "Gagal mengupdate category", err.Error())
+		return
+	}
+	if matched == 0 {
+		respon.Error(respw, http.StatusNotFound, "Category tidak ditemukan", "")
 		return
 	}
 
-	at.WriteJSON(respw, http.StatusOK, updateData)
+	respon.OK(respw, updateData)
 }
 
 func DeleteCategory(respw http.ResponseWriter, req *http.Request) {
+	owner, ok := getOwner(respw, req)
+	if !ok {
+		return
+	}
 	categoryID := req.URL.Query().Get("id")
 	if categoryID == "" {
-		var respn model.Response
-		respn.Status = "Error: ID Category tidak ditemukan"
-		at.WriteJSON(respw, http.StatusBadRequest, respn)
+		respon.Error(respw, http.StatusBadRequest, "ID Category tidak ditemukan", "")
 		return
 	}
 
 	objectID, err := primitive.ObjectIDFromHex(categoryID)
 	if err != nil {
-		var respn model.Response
-		respn.Status = "Error: ID Category tidak valid"
-		at.WriteJSON(respw, http.StatusBadRequest, respn)
+		respon.Error(respw, http.StatusBadRequest, "ID Category tidak valid", "")
 		return
 	}
 
-	deleteResult, err := atdb.DeleteOneDoc(config.Mongoconn, "kategori", bson.M{"_id": objectID})
-	if err != nil || deleteResult.DeletedCount == 0 {
-		var respn model.Response
-		respn.Status = "Error: Gagal menghapus category atau category tidak ditemukan"
-		respn.Response = err.Error()
-		at.WriteJSON(respw, http.StatusInternalServerError, respn)
+	deleted, err := repos.Categories.Delete(req.Context(), bson.M{"_id": objectID, "owner": owner})
+	if err != nil {
+		respon.Error(respw, http.StatusInternalServerError, "Gagal menghapus category", err.Error())
+		return
+	}
+	if deleted == 0 {
+		respon.Error(respw, http.StatusNotFound, "Category tidak ditemukan", "")
 		return
 	}
 
-	at.WriteJSON(respw, http.StatusOK, map[string]interface{}{
-		"status":  "success",
-		"message": "Category berhasil dihapus",
-	})
+	respon.Success(respw, http.StatusOK, "Category berhasil dihapus", nil)
 }
@@ -0,0 +1,96 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gocroot/helper/repo"
+	"github.com/gocroot/helper/respon"
+	"github.com/gocroot/helper/tenant"
+	"github.com/gocroot/model"
+)
+
+// useFakeRepos mengganti repository handler dengan fake di memori selama satu test
+func useFakeRepos(t *testing.T) repo.Repositories {
+	old := repos
+	repos = repo.NewFake()
+	t.Cleanup(func() { repos = old })
+	return repos
+}
+
+// call menjalankan handler sebagai akun penjual yang sudah login
+func call(h http.HandlerFunc, owner, method, target, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	req = tenant.WithSession(req, tenant.Session{Phone: owner, Owner: owner, Role: tenant.RoleOwner})
+	rec := httptest.NewRecorder()
+	h(rec, req)
+	return rec
+}
+
+func TestCategoryHandlers(t *testing.T) {
+	useFakeRepos(t)
+
+	rec := call(CreateCategory, "62811", "POST", "/categories", `{"name":"Minuman","description":"Kopi dan teh","tax_rate":11}`)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("create = %d %s", rec.Code, rec.Body)
+	}
+	var created struct {
+		Data model.Category `json:"data"`
+	}
+	json.Unmarshal(rec.Body.Bytes(), &created)
+	id := created.Data.ID.Hex()
+	call(CreateCategory, "62811", "POST", "/categories", `{"name":"Makanan"}`)
+	call(CreateCategory, "62899", "POST", "/categories", `{"name":"Milik toko lain"}`)
+
+	rec = call(GetAllCategory, "62811", "GET", "/categories?q=minum", "")
+	var list struct {
+		Status string           `json:"status"`
+		Data   []model.Category `json:"data"`
+		Meta   respon.Meta      `json:"meta"`
+	}
+	json.Unmarshal(rec.Body.Bytes(), &list)
+	if rec.Code != http.StatusOK || list.Status != respon.StatusSuccess || len(list.Data) != 1 || list.Data[0].Name != "Minuman" ||
+		list.Meta.Total == nil || *list.Meta.Total != 1 || rec.Header().Get("X-Total-Count") != "1" {
+		t.Errorf("list = %d %s", rec.Code, rec.Body)
+	}
+
+	if rec = call(GetCategoryByID, "62899", "GET", "/category?id="+id, ""); rec.Code != http.StatusNotFound {
+		t.Errorf("toko lain membaca kategori: %d", rec.Code)
+	}
+
+	rec = call(CreateCategory, "62811", "POST", "/categories", `{"name":"  ","description":"Tanpa nama"}`)
+	var invalid respon.Envelope
+	json.Unmarshal(rec.Body.Bytes(), &invalid)
+	if rec.Code != http.StatusBadRequest || invalid.Code != respon.CodeValidation || len(invalid.Errors) != 1 || invalid.Errors[0].Field != "name" {
+		t.Errorf("kategori tanpa nama = %d %s", rec.Code, rec.Body)
+	}
+	if rec = call(CreateCategory, "62811", "POST", "/categories", `{"name":`); rec.Code != http.StatusBadRequest || !strings.Contains(rec.Body.String(), `"code":"bad_request"`) {
+		t.Errorf("body rusak = %d %s", rec.Code, rec.Body)
+	}
+
+	rec = call(UpdateCategory, "62811", "PUT", "/category?id="+id, `{"name":"Minuman Dingin","tax_rate":-1}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("update = %d %s", rec.Code, rec.Body)
+	}
+	rec = call(GetCategoryByID, "62811", "GET", "/category?id="+id, "")
+	var got model.Category
+	json.Unmarshal(rec.Body.Bytes(), &struct {
+		Data *model.Category `json:"data"`
+	}{&got})
+	if got.Name != "Minuman Dingin" || got.Description != "Kopi dan teh" || got.TaxRate != nil {
+		t.Errorf("setelah update = %+v", got)
+	}
+
+	if rec = call(DeleteCategory, "62899", "DELETE", "/category?id="+id, ""); rec.Code != http.StatusNotFound {
+		t.Errorf("toko lain menghapus kategori: %d", rec.Code)
+	}
+	if rec = call(DeleteCategory, "62811", "DELETE", "/category?id="+id, ""); rec.Code != http.StatusOK {
+		t.Errorf("delete = %d %s", rec.Code, rec.Body)
+	}
+	if rec = call(GetCategoryByID, "62811", "GET", "/category?id="+id, ""); rec.Code != http.StatusNotFound {
+		t.Errorf("kategori terhapus masih ada: %d", rec.Code)
+	}
+}
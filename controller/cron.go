@@ -0,0 +1,31 @@
+package controller
+
+import (
+	"crypto/subtle"
+	"net/http"
+
+	"github.com/gocroot/config"
+	"github.com/gocroot/helper/respon"
+)
+
+// RequireCron adalah middleware route untuk endpoint yang dipanggil scheduler, wajib Authorization: Bearer CRON_SECRET.
+// Jika CRON_SECRET kosong semua panggilan ditolak.
+func RequireCron(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(respw http.ResponseWriter, req *http.Request) {
+		settings, _ := config.Load()
+		if !bearerMatches(req, settings.CronSecret) {
+			respon.Error(respw, http.StatusUnauthorized, "Token cron tidak valid", "")
+			return
+		}
+		next.ServeHTTP(respw, req)
+	})
+}
+
+// bearerMatches membandingkan header Authorization dengan token secara constant time, token kosong tidak pernah cocok
+func bearerMatches(req *http.Request, token string) bool {
+	if token == "" {
+		return false
+	}
+	want := "Bearer " + token
+	return subtle.ConstantTimeCompare([]byte(req.Header.Get("Authorization")), []byte(want)) == 1
+}
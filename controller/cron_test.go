@@ -0,0 +1,27 @@
+package controller
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+func TestBearerMatches(t *testing.T) {
+	cases := []struct {
+		header string
+		token  string
+		want   bool
+	}{
+		{"Bearer rahasia", "rahasia", true},
+		{"Bearer salah", "rahasia", false},
+		{"rahasia", "rahasia", false},
+		{"", "", false},
+		{"Bearer ", "", false},
+	}
+	for _, c := range cases {
+		req := httptest.NewRequest("POST", "/cron/harian", nil)
+		req.Header.Set("Authorization", c.header)
+		if got := bearerMatches(req, c.token); got != c.want {
+			t.Errorf("bearerMatches(%q, %q) = %v, want %v", c.header, c.token, got, c.want)
+		}
+	}
+}
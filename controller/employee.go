@@ -1,67 +1,77 @@
 package controller
 
 import (
-	"encoding/json"
 	"net/http"
 	"time"
 
 	"github.com/gocroot/config"
+	"github.com/gocroot/helper/respon"
 	"github.com/gocroot/model"
-	"github.com/gocroot/helper/at"
-	"github.com/gocroot/helper/atdb"
+	"github.com/gocroot/helper/audit"
+	"github.com/gocroot/helper/gaji"
+	"github.com/gocroot/helper/paging"
+	"github.com/gocroot/helper/tenant"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
 )
 
 // CreateEmployee handles creating a new employee
 func CreateEmployee(respw http.ResponseWriter, req *http.Request) {
+	owner, ok := getOwner(respw, req)
+	if !ok {
+		return
+	}
 	var newEmployee model.Employee
-	if err := json.NewDecoder(req.Body).Decode(&newEmployee); err != nil {
-		var respn model.Response
-		respn.Status = "Error: Bad Request"
-		respn.Response = err.Error()
-		at.WriteJSON(respw, http.StatusBadRequest, respn)
+	if !respon.Decode(respw, req, &newEmployee) {
+		return
+	}
+
+	if err := gaji.Validate(newEmployee); err != nil {
+		respon.Error(respw, http.StatusBadRequest, "Komponen gaji tidak valid", err.Error())
 		return
 	}
 
 	newEmployee.ID = primitive.NewObjectID()
+	newEmployee.Owner = owner
 	newEmployee.CreatedAt = time.Now()
 	newEmployee.UpdatedAt = time.Now()
 
 	// Insert into MongoDB collection
-	_, err := atdb.InsertOneDoc(config.Mongoconn, "employee", newEmployee)
+	err := repos.Employees.Insert(req.Context(), newEmployee)
 	if err != nil {
-		var respn model.Response
-		respn.Status = "Error: Gagal Insert Database"
-		respn.Response = err.Error()
-		at.WriteJSON(respw, http.StatusNotModified, respn)
+		respon.Error(respw, http.StatusInternalServerError, "Gagal Insert Database", err.Error())
 		return
 	}
-
-	response := map[string]interface{}{
-		"status":  "success",
-		"message": "Employee created successfully",
-		"data":    newEmployee,
+	if !logAudit(respw, req, owner, audit.EntityEmployee, newEmployee.ID, audit.ActionCreate, nil, newEmployee) {
+		return
 	}
-	at.WriteJSON(respw, http.StatusOK, response)
+
+	respon.Success(respw, http.StatusCreated, "Employee created successfully", newEmployee)
+}
+
+// employeePaging adalah parameter list employee: ?q= nama/email/telepon, ?position=, ?sort=name|position|created_at
+var employeePaging = paging.Options{
+	SearchFields: []string{"name", "email", "phone_number"},
+	Filters:      map[string]string{"position": "position"},
+	SortFields:   []string{"name", "position", "created_at"},
 }
 
 // GetAllEmployees returns all employees
 func GetAllEmployees(respw http.ResponseWriter, req *http.Request) {
-	data, err := atdb.GetAllDoc[[]model.Employee](config.Mongoconn, "employee", bson.M{})
+	owner, ok := getOwner(respw, req)
+	if !ok {
+		return
+	}
+	q, err := paging.Parse(req.URL.Query(), audit.Live(bson.M{"owner": owner}), employeePaging)
 	if err != nil {
-		var respn model.Response
-		respn.Status = "Error: Data employees tidak ditemukan"
-		respn.Response = err.Error()
-		at.WriteJSON(respw, http.StatusNotFound, respn)
+		respon.Error(respw, http.StatusBadRequest, "Parameter query tidak valid", err.Error())
 		return
 	}
-
-	if len(data) == 0 {
-		var respn model.Response
-		respn.Status = "Error: Data employees kosong"
-		at.WriteJSON(respw, http.StatusNotFound, respn)
+	data, meta, err := repos.Employees.Find(req.Context(), q)
+	if err != nil {
+		respon.Error(respw, http.StatusInternalServerError, "Gagal mengambil data employees", err.Error())
 		return
 	}
 
@@ -75,139 +85,140 @@ func GetAllEmployees(respw http.ResponseWriter, req *http.Request) {
 		})
 	}
 
-	at.WriteJSON(respw, http.StatusOK, employees)
+	respon.List(respw, employees, meta)
 }
 
 // GetEmployeeByID retrieves an employee by ID
 func GetEmployeeByID(respw http.ResponseWriter, req *http.Request) {
+	owner, ok := getOwner(respw, req)
+	if !ok {
+		return
+	}
 	employeeID := req.URL.Query().Get("id")
 	if employeeID == "" {
-		var respn model.Response
-		respn.Status = "Error: ID Employee tidak ditemukan"
-		at.WriteJSON(respw, http.StatusBadRequest, respn)
+		respon.Error(respw, http.StatusBadRequest, "ID Employee tidak ditemukan", "")
 		return
 	}
 
 	objectID, err := primitive.ObjectIDFromHex(employeeID)
 	if err != nil {
-		var respn model.Response
-		respn.Status = "Error: ID Employee tidak valid"
-		at.WriteJSON(respw, http.StatusBadRequest, respn)
+		respon.Error(respw, http.StatusBadRequest, "ID Employee tidak valid", "")
 		return
 	}
 
-	var employee model.Employee
-	filter := bson.M{"_id": objectID}
-	_, err = atdb.GetOneDoc[model.Employee](config.Mongoconn, "employee", filter)
+	filter := audit.Live(bson.M{"_id": objectID, "owner": owner})
+	employee, err := repos.Employees.Get(req.Context(), filter)
 	if err != nil {
-		var respn model.Response
-		respn.Status = "Error: Employee tidak ditemukan"
-		at.WriteJSON(respw, http.StatusNotFound, respn)
+		respon.Error(respw, http.StatusNotFound, "Employee tidak ditemukan", "")
 		return
 	}
-
-	response := map[string]interface{}{
-		"status":  "success",
-		"message": "Employee ditemukan",
-		"data":    employee,
+	// Kasir tidak boleh melihat data gaji
+	if s, _ := tenant.FromRequest(req); s.Role == tenant.RoleCashier {
+		employee.BaseSalary, employee.Allowances, employee.Deductions = 0, nil, nil
+		employee.AbsentDeduction, employee.LateDeduction = 0, 0
 	}
-	at.WriteJSON(respw, http.StatusOK, response)
+
+	respon.Success(respw, http.StatusOK, "Employee ditemukan", employee)
 }
 
 // UpdateEmployee updates an employee by ID
 func UpdateEmployee(respw http.ResponseWriter, req *http.Request) {
+	owner, ok := getOwner(respw, req)
+	if !ok {
+		return
+	}
 	employeeID := req.URL.Query().Get("id")
 	if employeeID == "" {
-		var respn model.Response
-		respn.Status = "Error: ID Employee tidak ditemukan"
-		at.WriteJSON(respw, http.StatusBadRequest, respn)
+		respon.Error(respw, http.StatusBadRequest, "ID Employee tidak ditemukan", "")
 		return
 	}
 
 	objectID, err := primitive.ObjectIDFromHex(employeeID)
 	if err != nil {
-		var respn model.Response
-		respn.Status = "Error: ID Employee tidak valid"
-		at.WriteJSON(respw, http.StatusBadRequest, respn)
+		respon.Error(respw, http.StatusBadRequest, "ID Employee tidak valid", "")
 		return
 	}
 
 	var updatedEmployee model.Employee
-	if err := json.NewDecoder(req.Body).Decode(&updatedEmployee); err != nil {
-		var respn model.Response
-		respn.Status = "Error: Gagal membaca data JSON"
-		respn.Response = err.Error()
-		at.WriteJSON(respw, http.StatusBadRequest, respn)
+	if !respon.Decode(respw, req, &updatedEmployee) {
+		return
+	}
+	if err := gaji.Validate(updatedEmployee); err != nil {
+		respon.Error(respw, http.StatusBadRequest, "Komponen gaji tidak valid", err.Error())
 		return
 	}
 
 	update := bson.M{
 		"$set": bson.M{
-			"name":         updatedEmployee.Name,
-			"email":        updatedEmployee.Email,
-			"phone_number": updatedEmployee.PhoneNumber,
-			"position":     updatedEmployee.Position,
-			"updated_at":   time.Now(),
+			"name":                 updatedEmployee.Name,
+			"email":                updatedEmployee.Email,
+			"phone_number":         updatedEmployee.PhoneNumber,
+			"position":             updatedEmployee.Position,
+			"base_salary":          updatedEmployee.BaseSalary,
+			"allowances":           updatedEmployee.Allowances,
+			"deductions":           updatedEmployee.Deductions,
+			"tax_status":           updatedEmployee.TaxStatus,
+			"bpjs_kesehatan":       updatedEmployee.BPJSKesehatan,
+			"bpjs_ketenagakerjaan": updatedEmployee.BPJSKetenagakerjaan,
+			"shift_start":          updatedEmployee.ShiftStart,
+			"absent_deduction":     updatedEmployee.AbsentDeduction,
+			"late_deduction":       updatedEmployee.LateDeduction,
+			"updated_at":           time.Now(),
 		},
 	}
 
-	// Perform the update
-	filter := bson.M{"_id": objectID}
-	_, err = atdb.UpdateOneDoc(config.Mongoconn, "employee", filter, update)
+	// Simpan kondisi sebelum update untuk audit log
+	filter := audit.Live(bson.M{"_id": objectID, "owner": owner})
+	before, err := repos.Employees.Get(req.Context(), filter)
 	if err != nil {
-		var respn model.Response
-		respn.Status = "Error: Gagal mengupdate employee"
-		respn.Response = err.Error()
-		at.WriteJSON(respw, http.StatusNotModified, respn)
+		respon.Error(respw, http.StatusNotFound, "Employee tidak ditemukan", "")
 		return
 	}
 
-	response := map[string]interface{}{
-		"status":  "success",
-		"message": "Employee updated successfully",
-		"data":    update,
+	// Perform the update
+	matched, err := repos.Employees.Update(req.Context(), filter, update)
+	if err != nil {
+		respon.Error(respw, http.StatusInternalServerError, "Gagal mengupdate employee", err.Error())
+		return
+	}
+	if matched == 0 {
+		respon.Error(respw, http.StatusNotFound, "Employee tidak ditemukan", "")
+		return
 	}
-	at.WriteJSON(respw, http.StatusOK, response)
+	if _, ok = logUpdate(respw, req, owner, audit.EntityEmployee, objectID, before); !ok {
+		return
+	}
+
+	respon.Success(respw, http.StatusOK, "Employee updated successfully", update)
 }
 
 // DeleteEmployee deletes an employee by ID
 func DeleteEmployee(respw http.ResponseWriter, req *http.Request) {
+	owner, ok := getOwner(respw, req)
+	if !ok {
+		return
+	}
 	employeeID := req.URL.Query().Get("id")
 	if employeeID == "" {
-		var respn model.Response
-		respn.Status = "Error: ID Employee tidak ditemukan"
-		at.WriteJSON(respw, http.StatusBadRequest, respn)
+		respon.Error(respw, http.StatusBadRequest, "ID Employee tidak ditemukan", "")
 		return
 	}
 
 	objectID, err := primitive.ObjectIDFromHex(employeeID)
 	if err != nil {
-		var respn model.Response
-		respn.Status = "Error: ID Employee tidak valid"
-		at.WriteJSON(respw, http.StatusBadRequest, respn)
+		respon.Error(respw, http.StatusBadRequest, "ID Employee tidak valid", "")
 		return
 	}
 
-	filter := bson.M{"_id": objectID}
-	deleteResult, err := atdb.DeleteOneDoc(config.Mongoconn, "employee", filter)
-	if err != nil {
-		var respn model.Response
-		respn.Status = "Error: Gagal menghapus employee"
-		respn.Response = err.Error()
-		at.WriteJSON(respw, http.StatusInternalServerError, respn)
+	_, err = audit.SoftDelete[model.Employee](config.Mongoconn(), auditActor(req, owner), audit.EntityEmployee, objectID)
+	if err == mongo.ErrNoDocuments {
+		respon.Error(respw, http.StatusNotFound, "Employee tidak ditemukan", "")
 		return
 	}
-
-	if deleteResult.DeletedCount == 0 {
-		var respn model.Response
-		respn.Status = "Error: Employee tidak ditemukan"
-		at.WriteJSON(respw, http.StatusNotFound, respn)
+	if err != nil {
+		respon.Error(respw, http.StatusInternalServerError, "Gagal menghapus employee", err.Error())
 		return
 	}
 
-	response := map[string]interface{}{
-		"status":  "success",
-		"message": "Employee deleted successfully",
-	}
-	at.WriteJSON(respw, http.StatusOK, response)
+	respon.Success(respw, http.StatusOK, "Employee deleted successfully", nil)
 }
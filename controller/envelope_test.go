@@ -0,0 +1,39 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gocroot/helper/respon"
+)
+
+func TestLegacyHandlersUseEnvelope(t *testing.T) {
+	cases := []struct {
+		name   string
+		h      http.HandlerFunc
+		body   string
+		status int
+		code   string
+	}{
+		{"google auth body rusak", Auth, `{"token":`, http.StatusBadRequest, respon.CodeBadRequest},
+		{"user tanpa token", GetDataUser, "", http.StatusForbidden, respon.CodeForbidden},
+		{"project tanpa token", GetDataProject, "", http.StatusForbidden, respon.CodeForbidden},
+		{"sender tanpa token", GetDataSenders, "", http.StatusForbidden, respon.CodeForbidden},
+		{"approve tanpa nohp", ApproveBimbinganbyPoin, "{}", http.StatusForbidden, respon.CodeForbidden},
+	}
+	for _, c := range cases {
+		rec := httptest.NewRecorder()
+		c.h(rec, httptest.NewRequest("POST", "/", strings.NewReader(c.body)))
+		var env respon.Envelope
+		if err := json.Unmarshal(rec.Body.Bytes(), &env); err != nil {
+			t.Errorf("%s: body bukan JSON: %s", c.name, rec.Body)
+			continue
+		}
+		if rec.Code != c.status || env.Status != respon.StatusError || env.Code != c.code || env.Message == "" {
+			t.Errorf("%s: respon = %d %s", c.name, rec.Code, rec.Body)
+		}
+	}
+}
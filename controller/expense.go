@@ -1,59 +1,86 @@
 package controller
 
 import (
-	"encoding/json"
 	"net/http"
 	"time"
 
 	"github.com/gocroot/config"
-	"github.com/gocroot/helper/at"
-	"github.com/gocroot/helper/atdb"
+	"github.com/gocroot/helper/audit"
+	"github.com/gocroot/helper/ekspor"
+	"github.com/gocroot/helper/ledger"
+	"github.com/gocroot/helper/paging"
+	"github.com/gocroot/helper/respon"
 	"github.com/gocroot/model"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
 )
 
 // Fungsi untuk menambahkan transaksi pengeluaran baru
 func CreateExpenseTransaction(respw http.ResponseWriter, req *http.Request) {
+	owner, ok := getOwner(respw, req)
+	if !ok {
+		return
+	}
 	var expense model.ExpenseTransaction
-	if err := json.NewDecoder(req.Body).Decode(&expense); err != nil {
-		var respn model.Response
-		respn.Status = "Error: Bad Request"
-		respn.Response = err.Error()
-		at.WriteJSON(respw, http.StatusBadRequest, respn)
+	if !respon.Decode(respw, req, &expense) {
+		return
+	}
+
+	if expense.TaxAmount < 0 || expense.TaxAmount > expense.Amount {
+		respon.Error(respw, http.StatusBadRequest, "PPN masukan harus antara 0 dan jumlah pengeluaran", "")
+		return
+	}
+	if !periodOpen(respw, owner, expense.ExpenseDate) {
 		return
 	}
 
 	// Inisialisasi data transaksi pengeluaran baru
 	expense.ID = primitive.NewObjectID()
+	expense.Owner = owner
 	expense.CreatedAt = time.Now()
 	expense.UpdatedAt = time.Now()
 
-	_, err := atdb.InsertOneDoc(config.Mongoconn, "expense_transaction", expense)
+	err := repos.Expenses.Insert(req.Context(), expense)
 	if err != nil {
-		var respn model.Response
-		respn.Status = "Error: Gagal Insert Database"
-		respn.Response = err.Error()
-		at.WriteJSON(respw, http.StatusNotModified, respn)
+		respon.Error(respw, http.StatusInternalServerError, "Gagal Insert Database", err.Error())
 		return
 	}
 
-	response := map[string]interface{}{
-		"status":  "success",
-		"message": "Transaksi pengeluaran berhasil ditambahkan",
-		"data":    expense,
+	// Posting jurnal pengeluaran ke buku besar
+	if _, err = ledger.Post(config.Mongoconn(), ledger.ExpenseEntry(expense)); err != nil {
+		respon.Error(respw, http.StatusInternalServerError, "Gagal posting jurnal pengeluaran", err.Error())
+		return
+	}
+	if !logAudit(respw, req, owner, audit.EntityExpense, expense.ID, audit.ActionCreate, nil, expense) {
+		return
 	}
-	at.WriteJSON(respw, http.StatusOK, response)
+
+	respon.Success(respw, http.StatusCreated, "Transaksi pengeluaran berhasil ditambahkan", expense)
+}
+
+// expensePaging adalah parameter list pengeluaran: ?q=, ?from=&to= pada expense_date, ?category=, ?payment_method=
+var expensePaging = paging.Options{
+	SearchFields: []string{"expense_name", "notes"},
+	DateField:    "expense_date",
+	Filters:      map[string]string{"category": "category", "payment_method": "payment_method"},
+	SortFields:   []string{"expense_date", "amount", "expense_name", "created_at"},
 }
 
 // Fungsi untuk mendapatkan daftar semua transaksi pengeluaran
 func GetExpenses(respw http.ResponseWriter, req *http.Request) {
-	data, err := atdb.GetAllDoc[[]model.ExpenseTransaction](config.Mongoconn, "expense_transaction", primitive.M{})
+	owner, ok := getOwner(respw, req)
+	if !ok {
+		return
+	}
+	q, err := paging.Parse(req.URL.Query(), audit.Live(bson.M{"owner": owner}), expensePaging)
+	if err != nil {
+		respon.Error(respw, http.StatusBadRequest, "Parameter query tidak valid", err.Error())
+		return
+	}
+	data, meta, err := repos.Expenses.Find(req.Context(), q)
 	if err != nil {
-		var respn model.Response
-		respn.Status = "Error: Data pengeluaran tidak ditemukan"
-		respn.Response = err.Error()
-		at.WriteJSON(respw, http.StatusNotFound, respn)
+		respon.Error(respw, http.StatusInternalServerError, "Gagal mengambil data pengeluaran", err.Error())
 		return
 	}
 
@@ -73,74 +100,69 @@ func GetExpenses(respw http.ResponseWriter, req *http.Request) {
 		})
 	}
 
-	at.WriteJSON(respw, http.StatusOK, expenses)
+	respon.List(respw, expenses, meta)
 }
 
 // Fungsi untuk mendapatkan detail transaksi pengeluaran berdasarkan ID
 func GetExpenseByID(respw http.ResponseWriter, req *http.Request) {
+	owner, ok := getOwner(respw, req)
+	if !ok {
+		return
+	}
 	expenseID := req.URL.Query().Get("id")
 	if expenseID == "" {
-		var respn model.Response
-		respn.Status = "Error: ID Pengeluaran tidak ditemukan"
-		at.WriteJSON(respw, http.StatusBadRequest, respn)
+		respon.Error(respw, http.StatusBadRequest, "ID Pengeluaran tidak ditemukan", "")
 		return
 	}
 
 	objectID, err := primitive.ObjectIDFromHex(expenseID)
 	if err != nil {
-		var respn model.Response
-		respn.Status = "Error: ID Pengeluaran tidak valid"
-		at.WriteJSON(respw, http.StatusBadRequest, respn)
+		respon.Error(respw, http.StatusBadRequest, "ID Pengeluaran tidak valid", "")
 		return
 	}
 
-	var expense model.ExpenseTransaction
-	filter := bson.M{"_id": objectID}
-	_, err = atdb.GetOneDoc[model.ExpenseTransaction](config.Mongoconn, "expense_transaction", filter)
+	filter := audit.Live(bson.M{"_id": objectID, "owner": owner})
+	expense, err := repos.Expenses.Get(req.Context(), filter)
 	if err != nil {
-		var respn model.Response
-		respn.Status = "Error: Pengeluaran tidak ditemukan"
-		at.WriteJSON(respw, http.StatusNotFound, respn)
+		respon.Error(respw, http.StatusNotFound, "Pengeluaran tidak ditemukan", "")
 		return
 	}
 
-	response := map[string]interface{}{
-		"status":  "success",
-		"message": "Pengeluaran ditemukan",
-		"data":    expense,
-	}
-	at.WriteJSON(respw, http.StatusOK, response)
+	respon.Success(respw, http.StatusOK, "Pengeluaran ditemukan", expense)
 }
 
 // Fungsi untuk mengupdate transaksi pengeluaran berdasarkan ID
 func UpdateExpense(respw http.ResponseWriter, req *http.Request) {
+	owner, ok := getOwner(respw, req)
+	if !ok {
+		return
+	}
 	expenseID := req.URL.Query().Get("id")
 	if expenseID == "" {
-		var respn model.Response
-		respn.Status = "Error: ID Pengeluaran tidak ditemukan"
-		at.WriteJSON(respw, http.StatusBadRequest, respn)
+		respon.Error(respw, http.StatusBadRequest, "ID Pengeluaran tidak ditemukan", "")
 		return
 	}
 
 	objectID, err := primitive.ObjectIDFromHex(expenseID)
 	if err != nil {
-		var respn model.Response
-		respn.Status = "Error: ID Pengeluaran tidak valid"
-		at.WriteJSON(respw, http.StatusBadRequest, respn)
+		respon.Error(respw, http.StatusBadRequest, "ID Pengeluaran tidak valid", "")
 		return
 	}
 
 	var updatedExpense model.ExpenseTransaction
-	if err := json.NewDecoder(req.Body).Decode(&updatedExpense); err != nil {
-		var respn model.Response
-		respn.Status = "Error: Gagal membaca data JSON"
-		at.WriteJSON(respw, http.StatusBadRequest, respn)
+	if !respon.Decode(respw, req, &updatedExpense) {
+		return
+	}
+
+	if updatedExpense.TaxAmount < 0 || updatedExpense.TaxAmount > updatedExpense.Amount {
+		respon.Error(respw, http.StatusBadRequest, "PPN masukan harus antara 0 dan jumlah pengeluaran", "")
 		return
 	}
 
 	updateData := bson.M{
 		"expense_name":   updatedExpense.ExpenseName,
 		"amount":         updatedExpense.Amount,
+		"tax_amount":     updatedExpense.TaxAmount,
 		"category":       updatedExpense.Category,
 		"payment_method": updatedExpense.PaymentMethod,
 		"expense_date":   updatedExpense.ExpenseDate,
@@ -148,123 +170,91 @@ func UpdateExpense(respw http.ResponseWriter, req *http.Request) {
 		"updated_at":     time.Now(),
 	}
 
-	filter := bson.M{"_id": objectID}
-	_, err = atdb.UpdateOneDoc(config.Mongoconn, "expense_transaction", filter, bson.M{"$set": updateData})
+	// Simpan kondisi sebelum update untuk audit log
+	filter := audit.Live(bson.M{"_id": objectID, "owner": owner})
+	before, err := repos.Expenses.Get(req.Context(), filter)
+	if err != nil {
+		respon.Error(respw, http.StatusNotFound, "Pengeluaran tidak ditemukan", "")
+		return
+	}
+	// Tanggal lama dan baru sama-sama tidak boleh berada di periode tertutup
+	if !periodOpen(respw, owner, before.ExpenseDate, updatedExpense.ExpenseDate) {
+		return
+	}
+	matched, err := repos.Expenses.Update(req.Context(), filter, bson.M{"$set": updateData})
 	if err != nil {
-		var respn model.Response
-		respn.Status = "Error: Gagal mengupdate pengeluaran"
-		respn.Response = err.Error()
-		at.WriteJSON(respw, http.StatusNotModified, respn)
+		respon.Error(respw, http.StatusInternalServerError, "Gagal mengupdate pengeluaran", err.Error())
+		return
+	}
+	if matched == 0 {
+		respon.Error(respw, http.StatusNotFound, "Pengeluaran tidak ditemukan", "")
 		return
 	}
 
-	response := map[string]interface{}{
-		"status":  "success",
-		"message": "Pengeluaran berhasil diupdate",
-		"data":    updateData,
+	// Balik jurnal lama lalu posting ulang sesuai data terbaru
+	stored, err := repos.Expenses.Get(req.Context(), filter)
+	if err == nil {
+		err = ledger.Repost(config.Mongoconn(), ledger.ExpenseEntry(stored))
+	}
+	if err != nil {
+		respon.Error(respw, http.StatusInternalServerError, "Gagal posting ulang jurnal pengeluaran", err.Error())
+		return
+	}
+	if !logAudit(respw, req, owner, audit.EntityExpense, objectID, audit.ActionUpdate, before, stored) {
+		return
 	}
-	at.WriteJSON(respw, http.StatusOK, response)
+
+	respon.Success(respw, http.StatusOK, "Pengeluaran berhasil diupdate", updateData)
 }
 
 // Fungsi untuk menghapus transaksi pengeluaran berdasarkan ID
 func DeleteExpense(respw http.ResponseWriter, req *http.Request) {
+	owner, ok := getOwner(respw, req)
+	if !ok {
+		return
+	}
 	expenseID := req.URL.Query().Get("id")
 	if expenseID == "" {
-		var respn model.Response
-		respn.Status = "Error: ID Pengeluaran tidak ditemukan"
-		at.WriteJSON(respw, http.StatusBadRequest, respn)
+		respon.Error(respw, http.StatusBadRequest, "ID Pengeluaran tidak ditemukan", "")
 		return
 	}
 
 	objectID, err := primitive.ObjectIDFromHex(expenseID)
 	if err != nil {
-		var respn model.Response
-		respn.Status = "Error: ID Pengeluaran tidak valid"
-		at.WriteJSON(respw, http.StatusBadRequest, respn)
+		respon.Error(respw, http.StatusBadRequest, "ID Pengeluaran tidak valid", "")
 		return
 	}
 
-	filter := bson.M{"_id": objectID}
-	deleteResult, err := atdb.DeleteOneDoc(config.Mongoconn, "expense_transaction", filter)
+	filter := audit.Live(bson.M{"_id": objectID, "owner": owner})
+	expense, err := repos.Expenses.Get(req.Context(), filter)
 	if err != nil {
-		var respn model.Response
-		respn.Status = "Error: Gagal menghapus pengeluaran"
-		respn.Response = err.Error()
-		at.WriteJSON(respw, http.StatusInternalServerError, respn)
+		respon.Error(respw, http.StatusNotFound, "Pengeluaran tidak ditemukan", "")
 		return
 	}
-
-	if deleteResult.DeletedCount == 0 {
-		var respn model.Response
-		respn.Status = "Error: Pengeluaran tidak ditemukan"
-		at.WriteJSON(respw, http.StatusNotFound, respn)
+	if !periodOpen(respw, owner, expense.ExpenseDate) {
 		return
 	}
 
-	response := map[string]interface{}{
-		"status":  "success",
-		"message": "Pengeluaran berhasil dihapus",
+	// Tandai pengeluaran sebagai terhapus lalu balik jurnalnya
+	_, err = audit.SoftDelete[model.ExpenseTransaction](config.Mongoconn(), auditActor(req, owner), audit.EntityExpense, objectID)
+	if err == mongo.ErrNoDocuments {
+		respon.Error(respw, http.StatusNotFound, "Pengeluaran tidak ditemukan", "")
+		return
+	}
+	if err != nil {
+		respon.Error(respw, http.StatusInternalServerError, "Gagal menghapus pengeluaran", err.Error())
+		return
 	}
-	at.WriteJSON(respw, http.StatusOK, response)
-}
-
-// Fungsi untuk mengekspor data pengeluaran ke CSV
-// func ExportExpensesToCSV(respw http.ResponseWriter, req *http.Request) {
-// 	var expenses []model.ExpenseTransaction
-// 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-// 	defer cancel()
-
-// 	cursor, err := config.ExpenseTransactionCollection.Find(ctx, bson.M{})
-// 	if err != nil {
-// 		var respn model.Response
-// 		respn.Status = "Error: Gagal mengambil data pengeluaran"
-// 		at.WriteJSON(respw, http.StatusInternalServerError, respn)
-// 		return
-// 	}
-// 	defer cursor.Close(ctx)
-
-// 	for cursor.Next(ctx) {
-// 		var expense model.ExpenseTransaction
-// 		if err := cursor.Decode(&expense); err != nil {
-// 			var respn model.Response
-// 			respn.Status = "Error: Gagal mendekode data pengeluaran"
-// 			at.WriteJSON(respw, http.StatusInternalServerError, respn)
-// 			return
-// 		}
-// 		expenses = append(expenses, expense)
-// 	}
-
-// 	respw.Header().Set("Content-Disposition", "attachment; filename=expenses.csv")
-// 	respw.Header().Set("Content-Type", "text/csv")
 
-// 	csvWriter := csv.NewWriter(respw)
-// 	defer csvWriter.Flush()
+	if err = ledger.ReverseSameDate(config.Mongoconn(), ledger.SourceExpense, objectID); err != nil {
+		respon.Error(respw, http.StatusInternalServerError, "Gagal membalik jurnal pengeluaran", err.Error())
+		return
+	}
 
-// 	headers := []string{"ID", "Expense Name", "Amount", "Category", "Payment Method", "Expense Date", "Notes", "Created At", "Updated At"}
-// 	if err := csvWriter.Write(headers); err != nil {
-// 		var respn model.Response
-// 		respn.Status = "Error: Gagal menulis header CSV"
-// 		at.WriteJSON(respw, http.StatusInternalServerError, respn)
-// 		return
-// 	}
+	respon.Success(respw, http.StatusOK, "Pengeluaran berhasil dihapus", nil)
+}
 
-// 	for _, expense := range expenses {
-// 		record := []string{
-// 			expense.ID.Hex(),
-// 			expense.ExpenseName,
-// 			fmt.Sprintf("%.2f", expense.Amount),
-// 			expense.Category,
-// 			expense.PaymentMethod,
-// 			expense.ExpenseDate.String(),
-// 			expense.Notes,
-// 			expense.CreatedAt.String(),
-// 			expense.UpdatedAt.String(),
-// 		}
-// 		if err := csvWriter.Write(record); err != nil {
-// 			var respn model.Response
-// 			respn.Status = "Error: Gagal menulis data CSV"
-// 			at.WriteJSON(respw, http.StatusInternalServerError, respn)
-// 			return
-// 		}
-// 	}
-// }
+// Fungsi untuk mengekspor data pengeluaran ke CSV, opsional ?from=&to= dan ?format=xlsx
+func ExportExpensesToCSV(respw http.ResponseWriter, req *http.Request) {
+	exportEntity(respw, req, ekspor.Expenses)
+}
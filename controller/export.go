@@ -0,0 +1,59 @@
+package controller
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gocroot/config"
+	"github.com/gocroot/helper/ekspor"
+	"github.com/gocroot/helper/logger"
+	"github.com/gocroot/helper/respon"
+)
+
+// Fungsi untuk mengekspor data: ?entity=products|customers|expenses|sales, ?format=csv|xlsx, opsional ?from=&to= (YYYY-MM-DD)
+func ExportData(respw http.ResponseWriter, req *http.Request) {
+	entity, ok := ekspor.Entities[req.URL.Query().Get("entity")]
+	if !ok {
+		respon.Error(respw, http.StatusBadRequest, "Entity tidak valid", "Gunakan products, customers, expenses atau sales")
+		return
+	}
+	exportEntity(respw, req, entity)
+}
+
+// exportEntity menulis file ekspor langsung ke respon. Kesalahan setelah file mulai dikirim hanya bisa dicatat di log.
+func exportEntity(respw http.ResponseWriter, req *http.Request, entity ekspor.Exporter) {
+	owner, ok := getOwner(respw, req)
+	if !ok {
+		return
+	}
+	format := req.URL.Query().Get("format")
+	if format == "" {
+		format = ekspor.FormatCSV
+	}
+	var from, to time.Time
+	for param, t := range map[string]*time.Time{"from": &from, "to": &to} {
+		if s := req.URL.Query().Get(param); s != "" {
+			parsed, err := time.Parse("2006-01-02", s)
+			if err != nil {
+				respon.Error(respw, http.StatusBadRequest, "Format "+param+" harus YYYY-MM-DD", err.Error())
+				return
+			}
+			*t = parsed
+		}
+	}
+	rw, err := ekspor.NewWriter(format, respw)
+	if err != nil {
+		respon.Error(respw, http.StatusBadRequest, "Format ekspor tidak valid", err.Error())
+		return
+	}
+
+	respw.Header().Set("Content-Disposition", "attachment; filename="+entity.FileName()+"."+format)
+	respw.Header().Set("Content-Type", ekspor.ContentType(format))
+	rows, err := entity.Export(config.Mongoconn(), owner, from, to, rw)
+	if err == nil {
+		err = rw.Close()
+	}
+	if err != nil {
+		logger.FromContext(req.Context()).Error("ekspor gagal", "entity", entity.FileName(), "rows", rows, "error", err)
+	}
+}
@@ -9,53 +9,41 @@ import (
 	"github.com/gocroot/helper/at"
 	"github.com/gocroot/helper/atdb"
 	"github.com/gocroot/helper/ghupload"
+	"github.com/gocroot/helper/respon"
 	"github.com/gocroot/helper/watoken"
 	"github.com/gocroot/model"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 func MenuUploadFileHandler(w http.ResponseWriter, r *http.Request) {
-	var respn model.Response
 	payload, err := watoken.Decode(config.PublicKeyWhatsAuth, at.GetLoginFromHeader(r))
 	if err != nil {
-		respn.Status = "Error : Token Tidak Valid"
-		respn.Info = at.GetSecretFromHeader(r)
-		respn.Location = "Decode Token Error"
-		respn.Response = err.Error()
-		at.WriteJSON(w, http.StatusForbidden, respn)
+		respon.Error(w, http.StatusForbidden, "Token Tidak Valid", err.Error())
 		return
 	}
-	_, err = atdb.GetOneDoc[model.Userdomyikado](config.Mongoconn, "user", primitive.M{"phonenumber": payload.Id})
+	_, err = atdb.GetOneDoc[model.Userdomyikado](config.Mongoconn(), "user", primitive.M{"phonenumber": payload.Id})
 	if err != nil {
-		respn.Status = "Error : Data user tidak di temukan"
-		respn.Response = err.Error()
-		at.WriteJSON(w, http.StatusNotImplemented, respn)
+		respon.Error(w, http.StatusNotImplemented, "Data user tidak di temukan", err.Error())
 		return
 	}
 	lapakid := at.GetParam(r)
 	objectId, _ := primitive.ObjectIDFromHex(lapakid)
-	prj, err := atdb.GetOneDoc[model.Project](config.Mongoconn, "project", primitive.M{"_id": objectId})
+	prj, err := atdb.GetOneDoc[model.Project](config.Mongoconn(), "project", primitive.M{"_id": objectId})
 	if err != nil {
-		respn.Status = "Error : Data lapak tidak di temukan"
-		respn.Response = err.Error()
-		at.WriteJSON(w, http.StatusNotImplemented, respn)
+		respon.Error(w, http.StatusNotImplemented, "Data lapak tidak di temukan", err.Error())
 		return
 	}
 
 	file, header, err := r.FormFile("menufile")
 	if err != nil {
-		respn.Status = "Error : File tidak ada"
-		respn.Response = err.Error()
-		at.WriteJSON(w, http.StatusBadRequest, respn)
+		respon.Error(w, http.StatusBadRequest, "File tidak ada", err.Error())
 		return
 	}
 	defer file.Close()
 	// Read the file content
 	fileContent, err := io.ReadAll(file)
 	if err != nil {
-		respn.Status = "Error : File tidak bisa dibaca"
-		respn.Response = err.Error()
-		at.WriteJSON(w, http.StatusInternalServerError, respn)
+		respon.Error(w, http.StatusInternalServerError, "File tidak bisa dibaca", err.Error())
 		return
 	}
 	// Calculate hash of the file content
@@ -73,16 +61,15 @@ func MenuUploadFileHandler(w http.ResponseWriter, r *http.Request) {
 	// Use GithubUpload function to upload the file to GitHub
 	content, _, err := ghupload.GithubUpload(GitHubAccessToken, GitHubAuthorName, GitHubAuthorEmail, fileContent, githubOrg, githubRepo, pathFile, replace)
 	if err != nil {
-		respn.Status = "Error : File tidak bisa diupload ke github"
-		respn.Response = err.Error()
-		at.WriteJSON(w, http.StatusInternalServerError, respn)
+		respon.Error(w, http.StatusInternalServerError, "File tidak bisa diupload ke github", err.Error())
 		return
 	}
 
 	// Respond with success message
-	respn.Info = hashedFileName
-	respn.Location = "/" + githubRepo + "/" + *content.Content.Path
-	respn.Response = *content.Content.URL
-	respn.Status = *content.Content.HTMLURL
-	at.WriteJSON(w, http.StatusOK, respn)
+	respon.Success(w, http.StatusCreated, "File berhasil diupload", primitive.M{
+		"name":     hashedFileName,
+		"path":     "/" + githubRepo + "/" + *content.Content.Path,
+		"url":      *content.Content.URL,
+		"html_url": *content.Content.HTMLURL,
+	})
 }
\ No newline at end of file
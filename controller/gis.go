@@ -7,6 +7,7 @@ import (
 	"github.com/gocroot/config"
 	"github.com/gocroot/helper/at"
 	"github.com/gocroot/helper/atdb"
+	"github.com/gocroot/helper/respon"
 	"github.com/gocroot/helper/watoken"
 	"github.com/gocroot/model"
 	"go.mongodb.org/mongo-driver/bson"
@@ -29,7 +30,7 @@ import (
 // 	}
 	
 	
-// 	region, err := atdb.GetOneDoc[model.Region](config.MongoconnGeo, "region", filter)
+// 	region, err := atdb.GetOneDoc[model.Region](config.MongoconnGeo(), "region", filter)
 // 	if err != nil {
 // 		at.WriteJSON(respw, http.StatusNotFound, region)
 // 		return
@@ -41,12 +42,7 @@ import (
 func GetRegion(respw http.ResponseWriter, req *http.Request) {
 	_, err := watoken.Decode(config.PublicKeyWhatsAuth, at.GetLoginFromHeader(req))
 	if err != nil {
-		var respn model.Response
-		respn.Status = "Error : Token Tidak Valid "
-		respn.Info = config.PublicKeyWhatsAuth
-		respn.Location = "Decode Token Error: " + at.GetLoginFromHeader(req)
-		respn.Response = err.Error()
-		at.WriteJSON(respw, http.StatusForbidden, respn)
+		respon.Error(respw, http.StatusForbidden, "Token Tidak Valid", err.Error())
 		return
 	}
 
@@ -54,10 +50,7 @@ func GetRegion(respw http.ResponseWriter, req *http.Request) {
 	var longlat model.LongLat
 	err = json.NewDecoder(req.Body).Decode(&longlat)
 	if err != nil {
-		var respn model.Response
-		respn.Status = "Error : Body tidak valid"
-		respn.Response = err.Error()
-		at.WriteJSON(respw, http.StatusBadRequest, respn)
+		respon.Error(respw, http.StatusBadRequest, "Body tidak valid", err.Error())
 		return
 	}
 
@@ -74,9 +67,9 @@ func GetRegion(respw http.ResponseWriter, req *http.Request) {
 	}
 
 	// Cari region berdasarkan filter
-	region, err := atdb.GetOneDoc[model.Region](config.MongoconnGeo, "region", filter)
+	region, err := atdb.GetOneDoc[model.Region](config.MongoconnGeo(), "region", filter)
 	if err != nil {
-		at.WriteJSON(respw, http.StatusNotFound, bson.M{"error": "Region not found"})
+		respon.Error(respw, http.StatusNotFound, "Region not found", err.Error())
 		return
 	}
 
@@ -101,7 +94,7 @@ func GetRegion(respw http.ResponseWriter, req *http.Request) {
 	}
 
 	// Kirim respon dalam format GeoJSON
-	at.WriteJSON(respw, http.StatusOK, geoJSON)
+	respon.OK(respw, geoJSON)
 }
 
 
@@ -110,12 +103,7 @@ func GetRegion(respw http.ResponseWriter, req *http.Request) {
 func GetRoads(respw http.ResponseWriter, req *http.Request) {
 	_, err := watoken.Decode(config.PublicKeyWhatsAuth, at.GetLoginFromHeader(req))
 	if err != nil {
-		var respn model.Response
-		respn.Status = "Error : Token Tidak Valid "
-		respn.Info = config.PublicKeyWhatsAuth
-		respn.Location = "Decode Token Error: " + at.GetLoginFromHeader(req)
-		respn.Response = err.Error()
-		at.WriteJSON(respw, http.StatusForbidden, respn)
+		respon.Error(respw, http.StatusForbidden, "Token Tidak Valid", err.Error())
 		return
 	}
 
@@ -123,10 +111,7 @@ func GetRoads(respw http.ResponseWriter, req *http.Request) {
 	var longlat model.LongLat
 	err = json.NewDecoder(req.Body).Decode(&longlat)
 	if err != nil {
-		var respn model.Response
-		respn.Status = "Error : Body tidak valid"
-		respn.Response = err.Error()
-		at.WriteJSON(respw, http.StatusBadRequest, respn)
+		respon.Error(respw, http.StatusBadRequest, "Body tidak valid", err.Error())
 		return
 	}
 
@@ -143,10 +128,10 @@ func GetRoads(respw http.ResponseWriter, req *http.Request) {
 			},
 	}
 
-	roads, err := atdb.GetAllDoc[[]model.Roads](config.MongoconnGeo, "roads", filter)
+	roads, err := atdb.GetAllDoc[[]model.Roads](config.MongoconnGeo(), "roads", filter)
 	if err != nil {
-		at.WriteJSON(respw, http.StatusNotFound, roads)
+		respon.Error(respw, http.StatusNotFound, "Roads not found", err.Error())
 		return
 	}
-	at.WriteJSON(respw, http.StatusOK, roads)
+	respon.OK(respw, roads)
 }
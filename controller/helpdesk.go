@@ -3,12 +3,12 @@ package controller
 import (
 	"encoding/json"
 	"net/http"
-	"strconv"
 
 	"github.com/gocroot/config"
 	"github.com/gocroot/helper/at"
 	"github.com/gocroot/helper/atdb"
 	"github.com/gocroot/helper/report"
+	"github.com/gocroot/helper/respon"
 	"github.com/gocroot/helper/watoken"
 	"github.com/gocroot/model"
 	"go.mongodb.org/mongo-driver/bson"
@@ -17,137 +17,94 @@ import (
 
 // pindahkan task dari to do ke doing
 func PutTaskUser(w http.ResponseWriter, r *http.Request) {
-	var respn model.Response
 	payload, err := watoken.Decode(config.PublicKeyWhatsAuth, at.GetLoginFromHeader(r))
 	if err != nil {
-		respn.Status = "Error : Token Tidak Valid"
-		respn.Info = at.GetSecretFromHeader(r)
-		respn.Location = "Decode Token Error"
-		respn.Response = err.Error()
-		at.WriteJSON(w, http.StatusForbidden, respn)
+		respon.Error(w, http.StatusForbidden, "Token Tidak Valid", err.Error())
 		return
 	}
 	//check eksistensi user
-	docuser, err := atdb.GetOneDoc[model.Userdomyikado](config.Mongoconn, "user", primitive.M{"phonenumber": payload.Id})
+	docuser, err := atdb.GetOneDoc[model.Userdomyikado](config.Mongoconn(), "user", primitive.M{"phonenumber": payload.Id})
 	if err != nil {
 		docuser.PhoneNumber = payload.Id
 		docuser.Name = payload.Alias
-		at.WriteJSON(w, http.StatusNotFound, docuser)
+		respon.FailData(w, http.StatusNotFound, respon.CodeNotFound, "User belum terdaftar", "", docuser)
 		return
 	}
 	var task report.TaskList
 	err = json.NewDecoder(r.Body).Decode(&task)
 	if err != nil {
-		respn.Status = "Error : Body Tidak Valid"
-		respn.Info = at.GetSecretFromHeader(r)
-		respn.Location = "Decode Body Error"
-		respn.Response = err.Error()
-		at.WriteJSON(w, http.StatusBadRequest, respn)
+		respon.Error(w, http.StatusBadRequest, "Body Tidak Valid", err.Error())
 		return
 	}
-	taskuser, err := atdb.GetOneDoc[report.TaskList](config.Mongoconn, "tasklist", bson.M{"_id": task.ID})
+	taskuser, err := atdb.GetOneDoc[report.TaskList](config.Mongoconn(), "tasklist", bson.M{"_id": task.ID})
 	if err != nil {
-		at.WriteJSON(w, http.StatusNotFound, taskuser)
+		respon.Error(w, http.StatusNotFound, "Task tidak ditemukan", err.Error())
 		return
 	}
-	insertid, err := atdb.InsertOneDoc(config.Mongoconn, "taskdoing", taskuser)
+	insertid, err := atdb.InsertOneDoc(config.Mongoconn(), "taskdoing", taskuser)
 	if err != nil {
-		respn.Status = "Error : Gagal insert ke doing"
-		respn.Info = insertid.Hex()
-		respn.Location = "InsertOneDoc"
-		respn.Response = err.Error()
-		at.WriteJSON(w, http.StatusNotFound, respn)
+		respon.Error(w, http.StatusNotFound, "Gagal insert ke doing", err.Error())
 		return
 	}
-	rest, err := atdb.DeleteOneDoc(config.Mongoconn, "tasklist", bson.M{"_id": task.ID})
+	rest, err := atdb.DeleteOneDoc(config.Mongoconn(), "tasklist", bson.M{"_id": task.ID})
 	if err != nil {
-		respn.Status = "Error : Gagal hapus di tasklist"
-		respn.Info = strconv.FormatInt(rest.DeletedCount, 10)
-		respn.Location = "DeleteOneDoc"
-		respn.Response = err.Error()
-		at.WriteJSON(w, http.StatusNotFound, respn)
+		respon.Error(w, http.StatusNotFound, "Gagal hapus di tasklist", err.Error())
 		return
 	}
-	respn.Info = strconv.FormatInt(rest.DeletedCount, 10)
-	respn.Status = insertid.Hex()
-	at.WriteJSON(w, http.StatusOK, respn)
+	respon.Success(w, http.StatusOK, "Task dipindahkan ke doing", bson.M{"id": insertid.Hex(), "deleted": rest.DeletedCount})
 }
 
 // pindahkan task dari doing ke done
 func PostTaskUser(w http.ResponseWriter, r *http.Request) {
-	var respn model.Response
 	payload, err := watoken.Decode(config.PublicKeyWhatsAuth, at.GetLoginFromHeader(r))
 	if err != nil {
-		respn.Status = "Error : Token Tidak Valid"
-		respn.Info = at.GetSecretFromHeader(r)
-		respn.Location = "Decode Token Error"
-		respn.Response = err.Error()
-		at.WriteJSON(w, http.StatusForbidden, respn)
+		respon.Error(w, http.StatusForbidden, "Token Tidak Valid", err.Error())
 		return
 	}
 	//check eksistensi user
-	docuser, err := atdb.GetOneDoc[model.Userdomyikado](config.Mongoconn, "user", primitive.M{"phonenumber": payload.Id})
+	docuser, err := atdb.GetOneDoc[model.Userdomyikado](config.Mongoconn(), "user", primitive.M{"phonenumber": payload.Id})
 	if err != nil {
 		docuser.PhoneNumber = payload.Id
 		docuser.Name = payload.Alias
-		at.WriteJSON(w, http.StatusNotFound, docuser)
+		respon.FailData(w, http.StatusNotFound, respon.CodeNotFound, "User belum terdaftar", "", docuser)
 		return
 	}
 	var task report.TaskList
 	err = json.NewDecoder(r.Body).Decode(&task)
 	if err != nil {
-		respn.Status = "Error : Body Tidak Valid"
-		respn.Info = at.GetSecretFromHeader(r)
-		respn.Location = "Decode Body Error"
-		respn.Response = err.Error()
-		at.WriteJSON(w, http.StatusBadRequest, respn)
+		respon.Error(w, http.StatusBadRequest, "Body Tidak Valid", err.Error())
 		return
 	}
-	taskuser, err := atdb.GetOneDoc[report.TaskList](config.Mongoconn, "taskdoing", bson.M{"_id": task.ID})
+	taskuser, err := atdb.GetOneDoc[report.TaskList](config.Mongoconn(), "taskdoing", bson.M{"_id": task.ID})
 	if err != nil {
-		at.WriteJSON(w, http.StatusNotFound, taskuser)
+		respon.Error(w, http.StatusNotFound, "Task tidak ditemukan", err.Error())
 		return
 	}
-	insertid, err := atdb.InsertOneDoc(config.Mongoconn, "taskdone", taskuser)
+	insertid, err := atdb.InsertOneDoc(config.Mongoconn(), "taskdone", taskuser)
 	if err != nil {
-		respn.Status = "Error : Gagal insert ke taskdone"
-		respn.Info = insertid.Hex()
-		respn.Location = "InsertOneDoc"
-		respn.Response = err.Error()
-		at.WriteJSON(w, http.StatusNotFound, respn)
+		respon.Error(w, http.StatusNotFound, "Gagal insert ke taskdone", err.Error())
 		return
 	}
-	rest, err := atdb.DeleteOneDoc(config.Mongoconn, "taskdoing", bson.M{"_id": task.ID})
+	rest, err := atdb.DeleteOneDoc(config.Mongoconn(), "taskdoing", bson.M{"_id": task.ID})
 	if err != nil {
-		respn.Status = "Error : Gagal hapus di taskdoing"
-		respn.Info = strconv.FormatInt(rest.DeletedCount, 10)
-		respn.Location = "DeleteOneDoc"
-		respn.Response = err.Error()
-		at.WriteJSON(w, http.StatusNotFound, respn)
+		respon.Error(w, http.StatusNotFound, "Gagal hapus di taskdoing", err.Error())
 		return
 	}
-	respn.Info = strconv.FormatInt(rest.DeletedCount, 10)
-	respn.Status = insertid.Hex()
-	at.WriteJSON(w, http.StatusOK, respn)
+	respon.Success(w, http.StatusOK, "Task dipindahkan ke done", bson.M{"id": insertid.Hex(), "deleted": rest.DeletedCount})
 }
 
 func GetHelpdeskAll(respw http.ResponseWriter, req *http.Request) {
 	payload, err := watoken.Decode(config.PublicKeyWhatsAuth, at.GetLoginFromHeader(req))
 	if err != nil {
-		var respn model.Response
-		respn.Status = "Error : Token Tidak Valid"
-		respn.Info = at.GetSecretFromHeader(req)
-		respn.Location = "Decode Token Error"
-		respn.Response = err.Error()
-		at.WriteJSON(respw, http.StatusForbidden, respn)
+		respon.Error(respw, http.StatusForbidden, "Token Tidak Valid", err.Error())
 		return
 	}
 	//check eksistensi user
-	docuser, err := atdb.GetOneDoc[model.Userdomyikado](config.Mongoconn, "user", primitive.M{"phonenumber": payload.Id})
+	docuser, err := atdb.GetOneDoc[model.Userdomyikado](config.Mongoconn(), "user", primitive.M{"phonenumber": payload.Id})
 	if err != nil {
 		docuser.PhoneNumber = payload.Id
 		docuser.Name = payload.Alias
-		at.WriteJSON(respw, http.StatusNotFound, docuser)
+		respon.FailData(respw, http.StatusNotFound, respon.CodeNotFound, "User belum terdaftar", "", docuser)
 		return
 	}
 	docuser.Name = payload.Alias
@@ -158,7 +115,7 @@ func GetHelpdeskAll(respw http.ResponseWriter, req *http.Request) {
 		},
 		"user.phonenumber": docuser.PhoneNumber,
 	}
-	userbelumterlayani, _ := atdb.GetAllDoc[[]model.Laporan](config.Mongoconn, "helpdeskuser", filterbelumterlayani)
+	userbelumterlayani, _ := atdb.GetAllDoc[[]model.Laporan](config.Mongoconn(), "helpdeskuser", filterbelumterlayani)
 	//melakukan pengambilan data sudah terlayani
 	filtersudahterlayani := bson.M{
 		"terlayani": bson.M{
@@ -166,37 +123,32 @@ func GetHelpdeskAll(respw http.ResponseWriter, req *http.Request) {
 		},
 		"user.phonenumber": docuser.PhoneNumber,
 	}
-	usersudahterlayani, _ := atdb.GetAllDoc[[]model.Laporan](config.Mongoconn, "helpdeskuser", filtersudahterlayani)
+	usersudahterlayani, _ := atdb.GetAllDoc[[]model.Laporan](config.Mongoconn(), "helpdeskuser", filtersudahterlayani)
 	//melakukan pengambilan semu data user terlayani atau belum
 	filtersemua := bson.M{
 		"user.phonenumber": docuser.PhoneNumber,
 	}
-	usersemua, _ := atdb.GetAllDoc[[]model.Laporan](config.Mongoconn, "helpdeskuser", filtersemua)
+	usersemua, _ := atdb.GetAllDoc[[]model.Laporan](config.Mongoconn(), "helpdeskuser", filtersemua)
 	rekap := model.HelpdeskRekap{
 		ToDo: len(userbelumterlayani),
 		Done: len(usersudahterlayani),
 		All:  len(usersemua),
 	}
-	at.WriteJSON(respw, http.StatusOK, rekap)
+	respon.OK(respw, rekap)
 }
 
 func GetLatestHelpdeskMasuk(respw http.ResponseWriter, req *http.Request) {
 	payload, err := watoken.Decode(config.PublicKeyWhatsAuth, at.GetLoginFromHeader(req))
 	if err != nil {
-		var respn model.Response
-		respn.Status = "Error : Token Tidak Valid"
-		respn.Info = at.GetSecretFromHeader(req)
-		respn.Location = "Decode Token Error"
-		respn.Response = err.Error()
-		at.WriteJSON(respw, http.StatusForbidden, respn)
+		respon.Error(respw, http.StatusForbidden, "Token Tidak Valid", err.Error())
 		return
 	}
 	//check eksistensi user
-	docuser, err := atdb.GetOneDoc[model.Userdomyikado](config.Mongoconn, "user", primitive.M{"phonenumber": payload.Id})
+	docuser, err := atdb.GetOneDoc[model.Userdomyikado](config.Mongoconn(), "user", primitive.M{"phonenumber": payload.Id})
 	if err != nil {
 		docuser.PhoneNumber = payload.Id
 		docuser.Name = payload.Alias
-		at.WriteJSON(respw, http.StatusNotFound, docuser)
+		respon.FailData(respw, http.StatusNotFound, respon.CodeNotFound, "User belum terdaftar", "", docuser)
 		return
 	}
 	docuser.Name = payload.Alias
@@ -207,31 +159,26 @@ func GetLatestHelpdeskMasuk(respw http.ResponseWriter, req *http.Request) {
 		},
 		"user.phonenumber": docuser.PhoneNumber,
 	}
-	userbelumterlayani, err := atdb.GetOneLatestDoc[model.Laporan](config.Mongoconn, "helpdeskuser", filterbelumterlayani)
+	userbelumterlayani, err := atdb.GetOneLatestDoc[model.Laporan](config.Mongoconn(), "helpdeskuser", filterbelumterlayani)
 	if err != nil {
-		at.WriteJSON(respw, http.StatusNotFound, userbelumterlayani)
+		respon.Error(respw, http.StatusNotFound, "Data helpdesk tidak ditemukan", err.Error())
 		return
 	}
-	at.WriteJSON(respw, http.StatusOK, userbelumterlayani)
+	respon.OK(respw, userbelumterlayani)
 }
 
 func GetLatestHelpdeskSelesai(respw http.ResponseWriter, req *http.Request) {
 	payload, err := watoken.Decode(config.PublicKeyWhatsAuth, at.GetLoginFromHeader(req))
 	if err != nil {
-		var respn model.Response
-		respn.Status = "Error : Token Tidak Valid"
-		respn.Info = at.GetSecretFromHeader(req)
-		respn.Location = "Decode Token Error"
-		respn.Response = err.Error()
-		at.WriteJSON(respw, http.StatusForbidden, respn)
+		respon.Error(respw, http.StatusForbidden, "Token Tidak Valid", err.Error())
 		return
 	}
 	//check eksistensi user
-	docuser, err := atdb.GetOneDoc[model.Userdomyikado](config.Mongoconn, "user", primitive.M{"phonenumber": payload.Id})
+	docuser, err := atdb.GetOneDoc[model.Userdomyikado](config.Mongoconn(), "user", primitive.M{"phonenumber": payload.Id})
 	if err != nil {
 		docuser.PhoneNumber = payload.Id
 		docuser.Name = payload.Alias
-		at.WriteJSON(respw, http.StatusNotFound, docuser)
+		respon.FailData(respw, http.StatusNotFound, respon.CodeNotFound, "User belum terdaftar", "", docuser)
 		return
 	}
 	docuser.Name = payload.Alias
@@ -242,38 +189,33 @@ func GetLatestHelpdeskSelesai(respw http.ResponseWriter, req *http.Request) {
 		},
 		"user.phonenumber": docuser.PhoneNumber,
 	}
-	userbelumterlayani, err := atdb.GetOneLatestDoc[model.Laporan](config.Mongoconn, "helpdeskuser", filtersudahterlayani)
+	userbelumterlayani, err := atdb.GetOneLatestDoc[model.Laporan](config.Mongoconn(), "helpdeskuser", filtersudahterlayani)
 	if err != nil {
-		at.WriteJSON(respw, http.StatusNotFound, userbelumterlayani)
+		respon.Error(respw, http.StatusNotFound, "Data helpdesk tidak ditemukan", err.Error())
 		return
 	}
-	at.WriteJSON(respw, http.StatusOK, userbelumterlayani)
+	respon.OK(respw, userbelumterlayani)
 }
 
 func GetTaskDone(respw http.ResponseWriter, req *http.Request) {
 	payload, err := watoken.Decode(config.PublicKeyWhatsAuth, at.GetLoginFromHeader(req))
 	if err != nil {
-		var respn model.Response
-		respn.Status = "Error : Token Tidak Valid"
-		respn.Info = at.GetSecretFromHeader(req)
-		respn.Location = "Decode Token Error"
-		respn.Response = err.Error()
-		at.WriteJSON(respw, http.StatusForbidden, respn)
+		respon.Error(respw, http.StatusForbidden, "Token Tidak Valid", err.Error())
 		return
 	}
 	//check eksistensi user
-	docuser, err := atdb.GetOneDoc[model.Userdomyikado](config.Mongoconn, "user", primitive.M{"phonenumber": payload.Id})
+	docuser, err := atdb.GetOneDoc[model.Userdomyikado](config.Mongoconn(), "user", primitive.M{"phonenumber": payload.Id})
 	if err != nil {
 		docuser.PhoneNumber = payload.Id
 		docuser.Name = payload.Alias
-		at.WriteJSON(respw, http.StatusNotFound, docuser)
+		respon.FailData(respw, http.StatusNotFound, respon.CodeNotFound, "User belum terdaftar", "", docuser)
 		return
 	}
 	docuser.Name = payload.Alias
-	taskdoing, err := atdb.GetOneLatestDoc[report.TaskList](config.Mongoconn, "taskdone", bson.M{"phonenumber": docuser.PhoneNumber})
+	taskdoing, err := atdb.GetOneLatestDoc[report.TaskList](config.Mongoconn(), "taskdone", bson.M{"phonenumber": docuser.PhoneNumber})
 	if err != nil {
-		at.WriteJSON(respw, http.StatusNotFound, taskdoing)
+		respon.Error(respw, http.StatusNotFound, "Task done tidak ditemukan", err.Error())
 		return
 	}
-	at.WriteJSON(respw, http.StatusOK, taskdoing)
+	respon.OK(respw, taskdoing)
 }
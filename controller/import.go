@@ -0,0 +1,104 @@
+package controller
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gocroot/config"
+	"github.com/gocroot/helper/impor"
+	"github.com/gocroot/helper/respon"
+)
+
+// readImportFile membaca file CSV/XLSX dari form field "file". Jika gagal, respon error sudah ditulis.
+func readImportFile(respw http.ResponseWriter, req *http.Request) (rows [][]string, ok bool) {
+	if err := req.ParseMultipartForm(10 << 20); err != nil {
+		respon.Error(respw, http.StatusBadRequest, "Form upload tidak valid", err.Error())
+		return nil, false
+	}
+	file, header, err := req.FormFile("file")
+	if err != nil {
+		respon.Error(respw, http.StatusBadRequest, "File tidak ada", err.Error())
+		return nil, false
+	}
+	defer file.Close()
+
+	rows, err = impor.ReadRows(file, impor.FormatOf(header.Filename))
+	if err != nil {
+		respon.Error(respw, http.StatusBadRequest, "File tidak bisa dibaca", err.Error())
+		return nil, false
+	}
+	return rows, true
+}
+
+// writeImportReport mengirim laporan validasi per baris
+func writeImportReport(respw http.ResponseWriter, report impor.Report, err error) {
+	if err != nil {
+		respon.Error(respw, http.StatusInternalServerError, "Impor gagal", err.Error())
+		return
+	}
+	message := "Impor selesai"
+	if report.DryRun {
+		message = "Validasi selesai, data belum disimpan"
+	}
+	respon.Success(respw, http.StatusOK, message, report)
+}
+
+// Fungsi untuk impor produk dari CSV/XLSX (kolom name, price, category, description, stock, cost). ?dry_run=true hanya validasi.
+func ImportProducts(respw http.ResponseWriter, req *http.Request) {
+	owner, ok := getOwner(respw, req)
+	if !ok {
+		return
+	}
+	rows, ok := readImportFile(respw, req)
+	if !ok {
+		return
+	}
+	report, err := impor.Products(config.Mongoconn(), owner, rows, req.URL.Query().Get("dry_run") == "true")
+	writeImportReport(respw, report, err)
+}
+
+// Fungsi untuk impor pelanggan dari CSV/XLSX (kolom name, email, phone, address). ?dry_run=true hanya validasi.
+func ImportCustomers(respw http.ResponseWriter, req *http.Request) {
+	owner, ok := getOwner(respw, req)
+	if !ok {
+		return
+	}
+	rows, ok := readImportFile(respw, req)
+	if !ok {
+		return
+	}
+	report, err := impor.Customers(config.Mongoconn(), owner, rows, req.URL.Query().Get("dry_run") == "true")
+	writeImportReport(respw, report, err)
+}
+
+// Fungsi untuk impor saldo awal akun dari CSV/XLSX (kolom account_code, debit, credit) sebagai satu jurnal.
+// Opsional ?date=YYYY-MM-DD (default hari ini), ?replace=true untuk mengganti saldo awal sebelumnya, ?dry_run=true.
+func ImportOpeningBalances(respw http.ResponseWriter, req *http.Request) {
+	owner, ok := getOwner(respw, req)
+	if !ok {
+		return
+	}
+	date := time.Now()
+	if s := req.URL.Query().Get("date"); s != "" {
+		parsed, err := time.Parse("2006-01-02", s)
+		if err != nil {
+			respon.Error(respw, http.StatusBadRequest, "Format date harus YYYY-MM-DD", err.Error())
+			return
+		}
+		date = parsed
+	}
+	if !periodOpen(respw, owner, date) {
+		return
+	}
+	rows, ok := readImportFile(respw, req)
+	if !ok {
+		return
+	}
+	q := req.URL.Query()
+	report, err := impor.OpeningBalances(config.Mongoconn(), owner, rows, date, q.Get("replace") == "true", q.Get("dry_run") == "true")
+	if err == impor.ErrOpeningExists {
+		respon.Error(respw, http.StatusConflict, err.Error(), "Gunakan ?replace=true untuk membalik saldo awal lama dan menggantinya")
+		return
+	}
+	writeImportReport(respw, report, err)
+}
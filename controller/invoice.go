@@ -0,0 +1,187 @@
+package controller
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gocroot/config"
+	"github.com/gocroot/helper/atdb"
+	"github.com/gocroot/helper/audit"
+	"github.com/gocroot/helper/invoice"
+	"github.com/gocroot/helper/pajak"
+	"github.com/gocroot/helper/respon"
+	"github.com/gocroot/helper/tagihan"
+	"github.com/gocroot/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Fungsi untuk mengunduh faktur PDF penjualan: ?id= dan opsional ?layout=58mm untuk struk thermal
+func GetSalesInvoice(respw http.ResponseWriter, req *http.Request) {
+	owner, ok := getOwner(respw, req)
+	if !ok {
+		return
+	}
+	objectID, err := primitive.ObjectIDFromHex(req.URL.Query().Get("id"))
+	if err != nil {
+		respon.Error(respw, http.StatusBadRequest, "ID Transaksi tidak valid", "")
+		return
+	}
+
+	transaction, err := atdb.GetOneDoc[model.SalesTransaction](config.Mongoconn(), invoice.SalesCollection, audit.Live(bson.M{"_id": objectID, "owner": owner}))
+	if err != nil {
+		respon.Error(respw, http.StatusNotFound, "Transaksi tidak ditemukan", "")
+		return
+	}
+	if err = invoice.Assign(config.Mongoconn(), &transaction); err != nil {
+		respon.Error(respw, http.StatusInternalServerError, "Gagal membuat nomor faktur", err.Error())
+		return
+	}
+
+	pdf, err := invoice.Render(transaction, invoice.Store(config.Mongoconn(), owner), req.URL.Query().Get("layout"))
+	if err != nil {
+		respon.Error(respw, http.StatusInternalServerError, "Gagal membuat PDF faktur", err.Error())
+		return
+	}
+
+	filename := strings.ReplaceAll(transaction.InvoiceNumber, "/", "-") + ".pdf"
+	respw.Header().Set("Content-Type", "application/pdf")
+	respw.Header().Set("Content-Disposition", "inline; filename=\""+filename+"\"")
+	respw.WriteHeader(http.StatusOK)
+	respw.Write(pdf)
+}
+
+// Fungsi untuk mendapatkan profil toko yang dicetak di faktur
+func GetStoreProfile(respw http.ResponseWriter, req *http.Request) {
+	owner, ok := getOwner(respw, req)
+	if !ok {
+		return
+	}
+	respon.OK(respw, invoice.Store(config.Mongoconn(), owner))
+}
+
+// Fungsi untuk menyimpan profil toko yang dicetak di faktur
+func UpdateStoreProfile(respw http.ResponseWriter, req *http.Request) {
+	owner, ok := getOwner(respw, req)
+	if !ok {
+		return
+	}
+	var store model.StoreProfile
+	if !respon.Decode(respw, req, &store) {
+		return
+	}
+	if strings.TrimSpace(store.Name) == "" {
+		respon.Error(respw, http.StatusBadRequest, "Nama toko wajib diisi", "")
+		return
+	}
+	if !pajak.ValidRate(store.PPNRate) {
+		respon.Error(respw, http.StatusBadRequest, "Tarif PPN harus antara 0 dan 100 persen", "")
+		return
+	}
+	if err := validSchedule(store); err != nil {
+		respon.Error(respw, http.StatusBadRequest, "Jadwal kerja tidak valid", err.Error())
+		return
+	}
+
+	updateData := bson.M{
+		"name":               store.Name,
+		"address":            store.Address,
+		"phone":              store.Phone,
+		"npwp":               store.NPWP,
+		"footer":             store.Footer,
+		"reminder_days":      store.ReminderDays,
+		"pkp":                store.PKP,
+		"ppn_rate":           store.PPNRate,
+		"price_includes_tax": store.PriceIncludesTax,
+		"shift_start":        store.ShiftStart,
+		"late_grace_minutes": store.LateGraceMinutes,
+		"work_days":          store.WorkDays,
+		"updatedAt":          time.Now(),
+	}
+	if _, err := atdb.UpdateOneDoc(config.Mongoconn(), invoice.StoreCollection, bson.M{"owner": owner}, updateData); err != nil {
+		respon.Error(respw, http.StatusInternalServerError, "Gagal menyimpan profil toko", err.Error())
+		return
+	}
+
+	respon.Success(respw, http.StatusOK, "Profil toko berhasil disimpan", updateData)
+}
+
+// Fungsi untuk mengirim faktur PDF ke WhatsApp pelanggan: ?id= dengan body opsional phone untuk nomor lain
+func SendSalesInvoice(respw http.ResponseWriter, req *http.Request) {
+	owner, ok := getOwner(respw, req)
+	if !ok {
+		return
+	}
+	objectID, err := primitive.ObjectIDFromHex(req.URL.Query().Get("id"))
+	if err != nil {
+		respon.Error(respw, http.StatusBadRequest, "ID Transaksi tidak valid", "")
+		return
+	}
+
+	var request struct {
+		Phone string `json:"phone"`
+	}
+	if req.ContentLength > 0 {
+		if !respon.Decode(respw, req, &request) {
+			return
+		}
+	}
+
+	transaction, err := atdb.GetOneDoc[model.SalesTransaction](config.Mongoconn(), invoice.SalesCollection, audit.Live(bson.M{"_id": objectID, "owner": owner}))
+	if err != nil {
+		respon.Error(respw, http.StatusNotFound, "Transaksi tidak ditemukan", "")
+		return
+	}
+
+	if err = tagihan.SendInvoice(config.Mongoconn(), &transaction, request.Phone); err != nil {
+		respon.Error(respw, http.StatusBadGateway, "Gagal mengirim faktur ke WhatsApp", err.Error())
+		return
+	}
+
+	respon.Success(respw, http.StatusOK, "Faktur "+transaction.InvoiceNumber+" berhasil dikirim", nil)
+}
+
+// Fungsi untuk melihat template pesan WhatsApp faktur dan pengingat piutang yang sedang dipakai
+func GetInvoiceTemplates(respw http.ResponseWriter, req *http.Request) {
+	owner, ok := getOwner(respw, req)
+	if !ok {
+		return
+	}
+	store := invoice.Store(config.Mongoconn(), owner)
+	data := map[string]string{
+		invoice.TemplateInvoice:  invoice.Template(config.Mongoconn(), invoice.TemplateInvoice, store),
+		invoice.TemplateReminder: invoice.Template(config.Mongoconn(), invoice.TemplateReminder, store),
+	}
+	respon.OK(respw, data)
+}
+
+// Fungsi untuk menyimpan template pesan toko: body key (kirimfaktur|pengingatpiutang) dan value.
+// Placeholder: ##NAMA##, ##NOFAKTUR##, ##TOKO##, ##TOTAL##, ##SISA##, ##TANGGAL##, ##JATUHTEMPO##
+func UpdateInvoiceTemplate(respw http.ResponseWriter, req *http.Request) {
+	owner, ok := getOwner(respw, req)
+	if !ok {
+		return
+	}
+	var request model.Prefill
+	if !respon.Decode(respw, req, &request) {
+		return
+	}
+	if !invoice.IsTemplateKey(request.Key) || strings.TrimSpace(request.Value) == "" {
+		respon.Error(respw, http.StatusBadRequest, "Key harus kirimfaktur atau pengingatpiutang dan value wajib diisi", "")
+		return
+	}
+
+	store := invoice.Store(config.Mongoconn(), owner)
+	if store.ID.IsZero() {
+		respon.Error(respw, http.StatusConflict, "Simpan profil toko terlebih dahulu", "Template pesan disimpan per toko melalui PUT /store-profile")
+		return
+	}
+	key := invoice.TemplateKey(request.Key, store)
+	if _, err := atdb.UpdateOneDoc(config.Mongoconn(), invoice.PrefillCollection, bson.M{"key": key}, bson.M{"key": key, "value": request.Value}); err != nil {
+		respon.Error(respw, http.StatusInternalServerError, "Gagal menyimpan template", err.Error())
+		return
+	}
+
+	respon.Success(respw, http.StatusOK, "Template berhasil disimpan", map[string]string{"key": key, "value": request.Value})
+}
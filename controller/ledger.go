@@ -0,0 +1,181 @@
+package controller
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gocroot/config"
+	"github.com/gocroot/helper/atdb"
+	"github.com/gocroot/helper/ledger"
+	"github.com/gocroot/helper/paging"
+	"github.com/gocroot/helper/respon"
+	"github.com/gocroot/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Fungsi untuk mendapatkan bagan akun
+func GetAccounts(respw http.ResponseWriter, req *http.Request) {
+	owner, ok := getOwner(respw, req)
+	if !ok {
+		return
+	}
+	data, err := atdb.GetAllDoc[[]model.Account](config.Mongoconn(), ledger.AccountCollection, bson.M{"owner": owner})
+	if err != nil {
+		respon.Error(respw, http.StatusInternalServerError, "Gagal mengambil data akun", err.Error())
+		return
+	}
+	respon.OK(respw, data)
+}
+
+// Fungsi untuk menambahkan akun baru ke bagan akun
+func CreateAccount(respw http.ResponseWriter, req *http.Request) {
+	owner, ok := getOwner(respw, req)
+	if !ok {
+		return
+	}
+	var account model.Account
+	if !respon.Decode(respw, req, &account) {
+		return
+	}
+
+	if _, err := atdb.GetOneDoc[model.Account](config.Mongoconn(), ledger.AccountCollection, bson.M{"code": account.Code, "owner": owner}); err == nil {
+		respon.Error(respw, http.StatusConflict, "Kode akun sudah digunakan", "")
+		return
+	}
+
+	account.ID = primitive.NewObjectID()
+	account.Owner = owner
+	account.CreatedAt = time.Now()
+	account.UpdatedAt = time.Now()
+	if _, err := atdb.InsertOneDoc(config.Mongoconn(), ledger.AccountCollection, account); err != nil {
+		respon.Error(respw, http.StatusInternalServerError, "Gagal Insert Database", err.Error())
+		return
+	}
+
+	respon.Success(respw, http.StatusCreated, "Akun berhasil ditambahkan", account)
+}
+
+// Fungsi untuk mengisi bagan akun dengan akun standar
+func InitDefaultAccounts(respw http.ResponseWriter, req *http.Request) {
+	owner, ok := getOwner(respw, req)
+	if !ok {
+		return
+	}
+	inserted, err := ledger.EnsureDefaultAccounts(config.Mongoconn(), owner)
+	if err != nil {
+		respon.Error(respw, http.StatusInternalServerError, "Gagal menyiapkan akun standar", err.Error())
+		return
+	}
+
+	respon.Success(respw, http.StatusOK, "Akun standar berhasil disiapkan", map[string]interface{}{"inserted": inserted})
+}
+
+// journalPaging adalah parameter list jurnal umum: ?q= keterangan, ?from=&to= pada tanggal, ?source_type=
+var journalPaging = paging.Options{
+	SearchFields: []string{"description"},
+	DateField:    "date",
+	Filters:      map[string]string{"source_type": "source_type"},
+	SortFields:   []string{"date", "createdAt"},
+}
+
+// Fungsi untuk mendapatkan jurnal umum, bisa difilter dengan source_id
+func GetJournalEntries(respw http.ResponseWriter, req *http.Request) {
+	owner, ok := getOwner(respw, req)
+	if !ok {
+		return
+	}
+	filter := bson.M{"owner": owner}
+	if sourceID := req.URL.Query().Get("source_id"); sourceID != "" {
+		objectID, err := primitive.ObjectIDFromHex(sourceID)
+		if err != nil {
+			respon.Error(respw, http.StatusBadRequest, "source_id tidak valid", "")
+			return
+		}
+		filter["source_id"] = objectID
+	}
+
+	q, err := paging.Parse(req.URL.Query(), filter, journalPaging)
+	if err != nil {
+		respon.Error(respw, http.StatusBadRequest, "Parameter query tidak valid", err.Error())
+		return
+	}
+	data, meta, err := paging.Find[model.JournalEntry](config.Mongoconn(), ledger.JournalCollection, q)
+	if err != nil {
+		respon.Error(respw, http.StatusInternalServerError, "Gagal mengambil data jurnal", err.Error())
+		return
+	}
+	respon.List(respw, data, meta)
+}
+
+// Fungsi untuk membuat jurnal manual (penyesuaian, modal awal, dll.)
+func CreateJournalEntry(respw http.ResponseWriter, req *http.Request) {
+	owner, ok := getOwner(respw, req)
+	if !ok {
+		return
+	}
+	var entry model.JournalEntry
+	if !respon.Decode(respw, req, &entry) {
+		return
+	}
+
+	if !periodOpen(respw, owner, entry.Date) {
+		return
+	}
+
+	entry.Owner = owner
+	entry.SourceType = ledger.SourceManual
+	entry.SourceID = primitive.NilObjectID
+	entry.Reversed = false
+	entry.ReversalOf = nil
+	for i, l := range entry.Lines {
+		if l.AccountName == "" {
+			entry.Lines[i].AccountName = ledger.AccountName(l.AccountCode)
+		}
+	}
+
+	id, err := ledger.Post(config.Mongoconn(), entry)
+	if err != nil {
+		respon.Error(respw, http.StatusBadRequest, "Jurnal tidak valid", err.Error())
+		return
+	}
+	entry.ID = id
+
+	respon.Success(respw, http.StatusCreated, "Jurnal berhasil diposting", entry)
+}
+
+// Fungsi untuk mendapatkan neraca saldo, opsional ?asof=YYYY-MM-DD
+func GetTrialBalance(respw http.ResponseWriter, req *http.Request) {
+	owner, ok := getOwner(respw, req)
+	if !ok {
+		return
+	}
+	var asOf time.Time
+	if s := req.URL.Query().Get("asof"); s != "" {
+		t, err := time.Parse("2006-01-02", s)
+		if err != nil {
+			respon.Error(respw, http.StatusBadRequest, "Invalid asof format. Use YYYY-MM-DD", err.Error())
+			return
+		}
+		asOf = t.Add(24*time.Hour - time.Nanosecond)
+	}
+
+	lines, err := ledger.TrialBalance(config.Mongoconn(), owner, asOf)
+	if err != nil {
+		respon.Error(respw, http.StatusInternalServerError, "Gagal menghitung neraca saldo", err.Error())
+		return
+	}
+
+	var totalDebit, totalCredit float64
+	for _, l := range lines {
+		totalDebit += l.Debit
+		totalCredit += l.Credit
+	}
+
+	respon.Success(respw, http.StatusOK, "Neraca saldo", map[string]interface{}{
+		"lines":        lines,
+		"total_debit":  ledger.Round2(totalDebit),
+		"total_credit": ledger.Round2(totalCredit),
+		"balanced":     ledger.Round2(totalDebit) == ledger.Round2(totalCredit),
+	})
+}
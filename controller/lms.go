@@ -4,30 +4,24 @@ import (
 	"net/http"
 
 	"github.com/gocroot/config"
-	"github.com/gocroot/helper/at"
 	"github.com/gocroot/helper/lms"
-	"github.com/gocroot/model"
+	"github.com/gocroot/helper/respon"
 )
 
 func GetCountDocUser(w http.ResponseWriter, r *http.Request) {
-	var resp model.Response
-	rkp, err := lms.GetRekapPendaftaranUsers(config.Mongoconn)
+	rkp, err := lms.GetRekapPendaftaranUsers(config.Mongoconn())
 	if err != nil {
-		resp.Response = err.Error()
-		at.WriteJSON(w, http.StatusConflict, resp)
+		respon.Error(w, http.StatusConflict, "Gagal mengambil rekap pendaftaran", err.Error())
 		return
 	}
-	at.WriteJSON(w, http.StatusOK, rkp)
+	respon.OK(w, rkp)
 }
 
 func RefreshLMSCookie(respw http.ResponseWriter, req *http.Request) {
-	var resp model.Response
-	err := lms.RefreshCookie(config.Mongoconn)
+	err := lms.RefreshCookie(config.Mongoconn())
 	if err != nil {
-		resp.Response = err.Error()
-		at.WriteJSON(respw, http.StatusBadRequest, resp)
+		respon.Error(respw, http.StatusBadRequest, "Gagal memperbarui cookie LMS", err.Error())
 		return
 	}
-	resp.Info = "ok"
-	at.WriteJSON(respw, http.StatusOK, resp)
+	respon.Success(respw, http.StatusOK, "Cookie LMS diperbarui", nil)
 }
@@ -0,0 +1,24 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/gocroot/config"
+	"github.com/gocroot/helper/metrik"
+	"github.com/gocroot/helper/respon"
+)
+
+// GetMetrics menampilkan metrik Prometheus, wajib Authorization: Bearer METRICS_TOKEN.
+// Jika METRICS_TOKEN kosong endpoint ditutup agar metrik tidak pernah terbuka tanpa token.
+func GetMetrics(respw http.ResponseWriter, req *http.Request) {
+	settings, _ := config.Load()
+	if settings.MetricsToken == "" {
+		respon.Error(respw, http.StatusForbidden, "Endpoint metrics belum diaktifkan", "isi "+config.EnvVarMetrics+" untuk mengaktifkan /metrics")
+		return
+	}
+	if !bearerMatches(req, settings.MetricsToken) {
+		respon.Error(respw, http.StatusUnauthorized, "Token metrics tidak valid", "")
+		return
+	}
+	metrik.Handler().ServeHTTP(respw, req)
+}
@@ -1,7 +1,6 @@
 package controller
 
 import (
-	"encoding/json"
 	"fmt"
 	"net/http"
 	"strconv"
@@ -12,6 +11,8 @@ import (
 	"github.com/gocroot/helper/atapi"
 	"github.com/gocroot/helper/atdb"
 	"github.com/gocroot/helper/jualin"
+	"github.com/gocroot/helper/logger"
+	"github.com/gocroot/helper/respon"
 	"github.com/gocroot/model"
 )
 
@@ -20,15 +21,13 @@ func HandleOrder(w http.ResponseWriter, r *http.Request) {
 	namalapak := at.GetParam(r)
 	var orderRequest jualin.PaymentRequest
 
-	// Decode JSON request ke struct
-	decoder := json.NewDecoder(r.Body)
-	if err := decoder.Decode(&orderRequest); err != nil {
-		http.Error(w, "Bad request", http.StatusBadRequest)
+	// Decode dan validasi JSON request ke struct
+	if !respon.Decode(w, r, &orderRequest) {
 		return
 	}
-	_, err := atdb.InsertOneDoc(config.Mongoconn, "order", orderRequest)
+	_, err := atdb.InsertOneDoc(config.Mongoconn(), "order", orderRequest)
 	if err != nil {
-		http.Error(w, "Insert Database Gagal", http.StatusBadRequest)
+		respon.Error(w, http.StatusInternalServerError, "Gagal menyimpan order", err.Error())
 		return
 	}
 
@@ -39,18 +38,17 @@ func HandleOrder(w http.ResponseWriter, r *http.Request) {
 		IsGroup:  false,
 		Messages: message,
 	}
-	_, _, err = atapi.PostStructWithToken[model.Response]("token", config.WAAPIToken, newmsg, config.WAAPIMessage)
+	log := logger.FromContext(r.Context())
+	log.Info("order diterima", "lapak", namalapak, "items", len(orderRequest.Orders), "total", orderRequest.Total, "payment", orderRequest.PaymentMethod)
+	_, _, err = atapi.PostStructWithTokenContext[model.Response](r.Context(), "token", config.WAAPIToken, newmsg, config.WAAPIMessage)
 	if err != nil {
-		http.Error(w, "Gagal Mengirim pesan", http.StatusBadRequest)
+		// Order sudah tersimpan, klien tidak boleh mengulang request agar order tidak ganda
+		log.Warn("pesan order ke penjual gagal dikirim", "lapak", namalapak, "error", err)
+		respon.Warn(w, http.StatusCreated, "Order received", "Pesan ke penjual gagal dikirim: "+err.Error(), nil)
 		return
 	}
-	// Cetak data order ke terminal (bisa diganti dengan logic lain, misal menyimpan ke database)
-	fmt.Printf("Received Order: %+v\n", orderRequest)
 
-	// Kirim response kembali ke client
-	w.Header().Set("Content-Type", "application/json")
-	response := map[string]string{"status": "success", "message": "Order received"}
-	json.NewEncoder(w).Encode(response)
+	respon.Success(w, http.StatusCreated, "Order received", nil)
 }
 
 // Fungsi untuk membuat pesan dari orders
@@ -0,0 +1,81 @@
+package controller
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gocroot/config"
+	"github.com/gocroot/helper/atdb"
+	"github.com/gocroot/helper/invoice"
+	"github.com/gocroot/helper/pajak"
+	"github.com/gocroot/helper/respon"
+	"github.com/gocroot/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// applySalesTax menghitung PPN penjualan sesuai profil toko, toko yang bukan PKP tidak memungut PPN
+func applySalesTax(owner string, trx *model.SalesTransaction) error {
+	store, err := atdb.GetOneDoc[model.StoreProfile](config.Mongoconn(), invoice.StoreCollection, bson.M{"owner": owner})
+	if err != nil && err != mongo.ErrNoDocuments {
+		return err
+	}
+	if !store.PKP {
+		pajak.Clear(trx)
+		return nil
+	}
+	rates, err := pajak.LoadRates(config.Mongoconn(), owner, store)
+	if err != nil {
+		return err
+	}
+	pajak.Apply(trx, rates, store.PriceIncludesTax)
+	return nil
+}
+
+// monthParam membaca parameter bulan YYYY-MM dari query, default bulan berjalan
+func monthParam(req *http.Request, name string) string {
+	if month := req.URL.Query().Get(name); month != "" {
+		return month
+	}
+	return time.Now().Format("2006-01")
+}
+
+// Handler untuk laporan PPN keluaran dan masukan bulanan: ?month=YYYY-MM
+func GetPPNReport(respw http.ResponseWriter, req *http.Request) {
+	owner, ok := getOwner(respw, req)
+	if !ok {
+		return
+	}
+	report, err := pajak.MonthlyReport(config.Mongoconn(), owner, monthParam(req, "month"))
+	if err != nil {
+		respon.Error(respw, http.StatusBadRequest, "Gagal menghitung laporan PPN", err.Error())
+		return
+	}
+
+	respon.Success(respw, http.StatusOK, "Laporan PPN "+report.Period, report)
+}
+
+// Handler untuk mengunduh CSV impor e-Faktur keluaran satu masa pajak: ?month=YYYY-MM
+func ExportEFaktur(respw http.ResponseWriter, req *http.Request) {
+	owner, ok := getOwner(respw, req)
+	if !ok {
+		return
+	}
+	month := monthParam(req, "month")
+	sales, err := pajak.TaxedSales(config.Mongoconn(), owner, month)
+	if err != nil {
+		respon.Error(respw, http.StatusBadRequest, "Gagal mengambil penjualan ber-PPN", err.Error())
+		return
+	}
+	buyers, err := pajak.Buyers(config.Mongoconn(), owner, sales)
+	if err != nil {
+		respon.Error(respw, http.StatusInternalServerError, "Gagal mengambil data pembeli", err.Error())
+		return
+	}
+
+	respw.Header().Set("Content-Disposition", "attachment; filename=efaktur-"+month+".csv")
+	respw.Header().Set("Content-Type", "text/csv")
+	if err = pajak.WriteEFaktur(respw, sales, buyers); err != nil {
+		respon.Error(respw, http.StatusInternalServerError, "Gagal menulis file e-Faktur", err.Error())
+	}
+}
@@ -0,0 +1,175 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/gocroot/config"
+	"github.com/gocroot/helper/atdb"
+	"github.com/gocroot/helper/audit"
+	"github.com/gocroot/helper/gaji"
+	"github.com/gocroot/helper/invoice"
+	"github.com/gocroot/helper/paging"
+	"github.com/gocroot/helper/respon"
+	"github.com/gocroot/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// payrollRequest adalah body pembuatan payroll bulanan
+type payrollRequest struct {
+	Period        string `json:"period"` // YYYY-MM
+	PaymentMethod string `json:"payment_method"`
+}
+
+// payrollPaging adalah parameter list payroll: ?sort=period|created_at
+var payrollPaging = paging.Options{
+	SortFields: []string{"period", "created_at"},
+}
+
+// Handler untuk menghitung payroll tanpa menyimpan: ?period=YYYY-MM
+func PreviewPayroll(respw http.ResponseWriter, req *http.Request) {
+	owner, ok := getOwner(respw, req)
+	if !ok {
+		return
+	}
+	run, err := gaji.Preview(config.Mongoconn(), owner, monthParam(req, "period"), "")
+	if err != nil {
+		respon.Error(respw, http.StatusBadRequest, "Gagal menghitung payroll", err.Error())
+		return
+	}
+	respon.OK(respw, run)
+}
+
+// Handler untuk membuat payroll bulanan dan mencatat pengeluaran gajinya
+func CreatePayrollRun(respw http.ResponseWriter, req *http.Request) {
+	owner, ok := getOwner(respw, req)
+	if !ok {
+		return
+	}
+	var request payrollRequest
+	if !respon.Decode(respw, req, &request) {
+		return
+	}
+
+	if !periodMonthOpen(respw, owner, request.Period) {
+		return
+	}
+
+	run, err := gaji.Run(config.Mongoconn(), auditActor(req, owner), request.Period, request.PaymentMethod)
+	if err == gaji.ErrRunExists {
+		respon.Error(respw, http.StatusConflict, "Payroll sudah ada", err.Error())
+		return
+	}
+	if err != nil {
+		respon.Error(respw, http.StatusBadRequest, "Gagal membuat payroll", err.Error())
+		return
+	}
+
+	respon.Success(respw, http.StatusCreated, "Payroll berhasil dibuat", run)
+}
+
+// Handler untuk daftar payroll
+func GetPayrollRuns(respw http.ResponseWriter, req *http.Request) {
+	owner, ok := getOwner(respw, req)
+	if !ok {
+		return
+	}
+	q, err := paging.Parse(req.URL.Query(), audit.Live(bson.M{"owner": owner}), payrollPaging)
+	if err != nil {
+		respon.Error(respw, http.StatusBadRequest, "Parameter query tidak valid", err.Error())
+		return
+	}
+	data, meta, err := paging.Find[model.PayrollRun](config.Mongoconn(), gaji.PayrollCollection, q)
+	if err != nil {
+		respon.Error(respw, http.StatusInternalServerError, "Gagal mengambil payroll", err.Error())
+		return
+	}
+	respon.List(respw, data, meta)
+}
+
+// Handler untuk detail payroll berdasarkan ?id=
+func GetPayrollRunByID(respw http.ResponseWriter, req *http.Request) {
+	owner, ok := getOwner(respw, req)
+	if !ok {
+		return
+	}
+	run, ok := findPayrollRun(respw, req, owner)
+	if !ok {
+		return
+	}
+	respon.OK(respw, run)
+}
+
+// Handler untuk menghapus payroll beserta pengeluaran gaji dan jurnalnya: ?id=
+func DeletePayrollRun(respw http.ResponseWriter, req *http.Request) {
+	owner, ok := getOwner(respw, req)
+	if !ok {
+		return
+	}
+	run, ok := findPayrollRun(respw, req, owner)
+	if !ok {
+		return
+	}
+	if !periodMonthOpen(respw, owner, run.Period) {
+		return
+	}
+	err := gaji.Delete(config.Mongoconn(), auditActor(req, owner), run.ID)
+	if err == mongo.ErrNoDocuments {
+		respon.Error(respw, http.StatusNotFound, "Payroll tidak ditemukan", "")
+		return
+	}
+	if err != nil {
+		respon.Error(respw, http.StatusInternalServerError, "Gagal menghapus payroll", err.Error())
+		return
+	}
+
+	respon.Success(respw, http.StatusOK, "Payroll berhasil dihapus", nil)
+}
+
+// Handler untuk mengunduh slip gaji PDF: ?id= payroll dan ?employee_id=
+func GetPayslipPDF(respw http.ResponseWriter, req *http.Request) {
+	owner, ok := getOwner(respw, req)
+	if !ok {
+		return
+	}
+	run, ok := findPayrollRun(respw, req, owner)
+	if !ok {
+		return
+	}
+	employeeID, err := primitive.ObjectIDFromHex(req.URL.Query().Get("employee_id"))
+	if err != nil {
+		respon.Error(respw, http.StatusBadRequest, "ID pegawai tidak valid", "")
+		return
+	}
+	slip, found := gaji.FindPayslip(run, employeeID)
+	if !found {
+		respon.Error(respw, http.StatusNotFound, "Slip gaji pegawai tidak ditemukan", "")
+		return
+	}
+
+	pdf, err := gaji.RenderPayslip(run, slip, invoice.Store(config.Mongoconn(), owner))
+	if err != nil {
+		respon.Error(respw, http.StatusInternalServerError, "Gagal membuat PDF slip gaji", err.Error())
+		return
+	}
+	respw.Header().Set("Content-Type", "application/pdf")
+	respw.Header().Set("Content-Disposition", "inline; filename=slip-gaji-"+run.Period+"-"+employeeID.Hex()+".pdf")
+	respw.WriteHeader(http.StatusOK)
+	respw.Write(pdf)
+}
+
+// findPayrollRun mengambil payroll milik owner dari ?id= dan menulis respon error jika gagal
+func findPayrollRun(respw http.ResponseWriter, req *http.Request, owner string) (run model.PayrollRun, ok bool) {
+	objectID, err := primitive.ObjectIDFromHex(req.URL.Query().Get("id"))
+	if err != nil {
+		respon.Error(respw, http.StatusBadRequest, "ID payroll tidak valid", "")
+		return
+	}
+	run, err = atdb.GetOneDoc[model.PayrollRun](config.Mongoconn(), gaji.PayrollCollection, audit.Live(bson.M{"_id": objectID, "owner": owner}))
+	if err != nil {
+		respon.Error(respw, http.StatusNotFound, "Payroll tidak ditemukan", "")
+		return
+	}
+	return run, true
+}
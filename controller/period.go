@@ -0,0 +1,114 @@
+package controller
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gocroot/config"
+	"github.com/gocroot/helper/audit"
+	"github.com/gocroot/helper/paging"
+	"github.com/gocroot/helper/respon"
+	"github.com/gocroot/helper/tutupbuku"
+	"github.com/gocroot/model"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// periodRequest adalah body tutup dan buka periode
+type periodRequest struct {
+	Period string `json:"period"` // YYYY-MM atau YYYY
+}
+
+// periodPaging adalah parameter list periode: ?status=closed|open, ?type=month|year, ?sort=period
+var periodPaging = paging.Options{
+	Filters:    map[string]string{"status": "status", "type": "type"},
+	SortFields: []string{"period", "closed_at"},
+}
+
+// periodOpen memastikan tanggal transaksi tidak berada di periode tertutup, jika tertutup respon error ditulis dan hasilnya false
+func periodOpen(respw http.ResponseWriter, owner string, dates ...time.Time) bool {
+	err := tutupbuku.Guard(config.Mongoconn(), owner, dates...)
+	if err == nil {
+		return true
+	}
+	if _, closed := err.(tutupbuku.ClosedError); closed {
+		respon.Fail(respw, http.StatusConflict, respon.CodePeriodClosed, "Periode akuntansi sudah ditutup", err.Error())
+		return false
+	}
+	respon.Error(respw, http.StatusInternalServerError, "Gagal memeriksa periode akuntansi", err.Error())
+	return false
+}
+
+// periodMonthOpen memastikan bulan YYYY-MM belum ditutup, format yang salah dibiarkan untuk divalidasi pemanggilnya
+func periodMonthOpen(respw http.ResponseWriter, owner, month string) bool {
+	_, _, end, err := tutupbuku.Parse(month)
+	if err != nil {
+		return true
+	}
+	return periodOpen(respw, owner, end)
+}
+
+// Handler untuk daftar periode akuntansi yang pernah ditutup beserta laba rugi saat ditutup
+func GetAccountingPeriods(respw http.ResponseWriter, req *http.Request) {
+	owner, ok := getOwner(respw, req)
+	if !ok {
+		return
+	}
+	q, err := paging.Parse(req.URL.Query(), bson.M{"owner": owner}, periodPaging)
+	if err != nil {
+		respon.Error(respw, http.StatusBadRequest, "Parameter query tidak valid", err.Error())
+		return
+	}
+	data, meta, err := paging.Find[model.AccountingPeriod](config.Mongoconn(), tutupbuku.PeriodCollection, q)
+	if err != nil {
+		respon.Error(respw, http.StatusInternalServerError, "Gagal mengambil periode akuntansi", err.Error())
+		return
+	}
+	respon.List(respw, data, meta)
+}
+
+// Handler untuk menutup bulan (YYYY-MM) atau tahun (YYYY), tahun ditutup dengan jurnal penutup ke Laba Ditahan
+func CloseAccountingPeriod(respw http.ResponseWriter, req *http.Request) {
+	changeAccountingPeriod(respw, req, audit.ActionClose)
+}
+
+// Handler untuk membuka kembali periode yang sudah ditutup
+func ReopenAccountingPeriod(respw http.ResponseWriter, req *http.Request) {
+	changeAccountingPeriod(respw, req, audit.ActionReopen)
+}
+
+// changeAccountingPeriod menutup atau membuka periode lalu mencatatnya di audit log
+func changeAccountingPeriod(respw http.ResponseWriter, req *http.Request, action string) {
+	owner, ok := getOwner(respw, req)
+	if !ok {
+		return
+	}
+	var request periodRequest
+	if !respon.Decode(respw, req, &request) {
+		return
+	}
+
+	actor := auditActor(req, owner)
+	change, message := tutupbuku.Close, "Periode "+request.Period+" berhasil ditutup"
+	if action == audit.ActionReopen {
+		change, message = tutupbuku.Reopen, "Periode "+request.Period+" berhasil dibuka kembali"
+	}
+	period, err := change(config.Mongoconn(), owner, request.Period, actor.User, time.Now())
+	if err != nil {
+		message := "Gagal mengubah status periode"
+		if _, closed := err.(tutupbuku.ClosedError); closed {
+			respon.Fail(respw, http.StatusConflict, respon.CodePeriodClosed, message, err.Error())
+		} else if err == tutupbuku.ErrAlreadyClosed || err == tutupbuku.ErrNotClosed {
+			respon.Error(respw, http.StatusConflict, message, err.Error())
+		} else if err == tutupbuku.ErrPeriodFormat || err == tutupbuku.ErrNotEnded {
+			respon.Error(respw, http.StatusBadRequest, message, err.Error())
+		} else {
+			respon.Error(respw, http.StatusInternalServerError, message, err.Error())
+		}
+		return
+	}
+	if !logAudit(respw, req, owner, audit.EntityPeriod, period.ID, action, nil, period) {
+		return
+	}
+
+	respon.Success(respw, http.StatusOK, message, period)
+}
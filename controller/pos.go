@@ -0,0 +1,217 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gocroot/config"
+	"github.com/gocroot/helper/atdb"
+	"github.com/gocroot/helper/audit"
+	"github.com/gocroot/helper/kasir"
+	"github.com/gocroot/helper/respon"
+	"github.com/gocroot/helper/tenant"
+	"github.com/gocroot/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// priceCart membaca keranjang dari body lalu menghitung transaksinya, respon error ditulis jika gagal
+func priceCart(respw http.ResponseWriter, req *http.Request, owner string) (trx model.SalesTransaction, promo *model.Promo, ok bool) {
+	var cart kasir.Cart
+	if !respon.Decode(respw, req, &cart) {
+		return
+	}
+	trx, promo, err := kasir.Checkout(config.Mongoconn(), owner, cart, time.Now())
+	if err != nil {
+		switch {
+		case errors.Is(err, kasir.ErrProductNotFound):
+			respon.Error(respw, http.StatusNotFound, "Produk tidak ditemukan", err.Error())
+		case errors.Is(err, kasir.ErrPromoUsedUp):
+			respon.Fail(respw, http.StatusConflict, respon.CodePromoUsedUp, "Kode promo sudah habis", err.Error())
+		case errors.Is(err, kasir.ErrTenderedShort):
+			respon.Error(respw, http.StatusBadRequest, "Uang yang diterima kurang", err.Error())
+		default:
+			respon.Error(respw, http.StatusBadRequest, "Keranjang tidak valid", err.Error())
+		}
+		return
+	}
+	return trx, promo, true
+}
+
+// Handler untuk menghitung total keranjang kasir tanpa menyimpan transaksi
+func QuoteCheckout(respw http.ResponseWriter, req *http.Request) {
+	owner, ok := getOwner(respw, req)
+	if !ok {
+		return
+	}
+	trx, _, ok := priceCart(respw, req, owner)
+	if !ok {
+		return
+	}
+	respon.OK(respw, trx)
+}
+
+// Handler checkout kasir: harga dari data produk, diskon, promo, PPN dan kembalian dihitung server,
+// lalu penjualan disimpan bersama pengurangan stok dan jurnalnya
+func Checkout(respw http.ResponseWriter, req *http.Request) {
+	owner, ok := getOwner(respw, req)
+	if !ok {
+		return
+	}
+	trx, promo, ok := priceCart(respw, req, owner)
+	if !ok {
+		return
+	}
+	trx.ID = primitive.NewObjectID()
+	trx.TransactionDate = time.Now()
+	if s, found := tenant.FromRequest(req); found {
+		trx.Cashier = s.Phone
+	}
+
+	if promo != nil {
+		if err := kasir.ClaimPromo(config.Mongoconn(), *promo); err != nil {
+			respon.Fail(respw, http.StatusConflict, respon.CodePromoUsedUp, "Kode promo sudah habis", err.Error())
+			return
+		}
+	}
+	if !saveSale(respw, req, &trx) {
+		// Penjualan tidak tersimpan atau sudah dibatalkan, kuota promo dikembalikan
+		if promo != nil {
+			kasir.ReleasePromo(config.Mongoconn(), *promo)
+		}
+		return
+	}
+	if !logAudit(respw, req, owner, audit.EntitySales, trx.ID, audit.ActionCreate, nil, trx) {
+		return
+	}
+
+	respon.Success(respw, http.StatusCreated, "Checkout berhasil", trx)
+}
+
+// Handler untuk daftar kode promo toko
+func GetPromos(respw http.ResponseWriter, req *http.Request) {
+	owner, ok := getOwner(respw, req)
+	if !ok {
+		return
+	}
+	promos, err := atdb.GetAllDoc[[]model.Promo](config.Mongoconn(), kasir.PromoCollection, bson.M{"owner": owner})
+	if err != nil {
+		respon.Error(respw, http.StatusInternalServerError, "Gagal mengambil kode promo", err.Error())
+		return
+	}
+	respon.OK(respw, promos)
+}
+
+// Handler untuk membuat kode promo
+func CreatePromo(respw http.ResponseWriter, req *http.Request) {
+	owner, ok := getOwner(respw, req)
+	if !ok {
+		return
+	}
+	var promo model.Promo
+	if !respon.Decode(respw, req, &promo) {
+		return
+	}
+	if err := kasir.ValidatePromo(&promo); err != nil {
+		respon.Error(respw, http.StatusBadRequest, "Kode promo tidak valid", err.Error())
+		return
+	}
+	count, err := atdb.GetCountDoc(config.Mongoconn(), kasir.PromoCollection, bson.M{"owner": owner, "code": promo.Code})
+	if err != nil {
+		respon.Error(respw, http.StatusInternalServerError, "Gagal memeriksa kode promo", err.Error())
+		return
+	}
+	if count > 0 {
+		respon.Error(respw, http.StatusConflict, "Kode promo sudah dipakai", "")
+		return
+	}
+
+	promo.ID = primitive.NewObjectID()
+	promo.Owner = owner
+	promo.UsedCount = 0
+	promo.CreatedAt = time.Now()
+	promo.UpdatedAt = promo.CreatedAt
+	if _, err = atdb.InsertOneDoc(config.Mongoconn(), kasir.PromoCollection, promo); err != nil {
+		respon.Error(respw, http.StatusInternalServerError, "Gagal menyimpan kode promo", err.Error())
+		return
+	}
+
+	respon.Success(respw, http.StatusCreated, "Kode promo berhasil ditambahkan", promo)
+}
+
+// Handler untuk mengubah kode promo: ?id=, jumlah pemakaian tidak ikut diubah
+func UpdatePromo(respw http.ResponseWriter, req *http.Request) {
+	owner, ok := getOwner(respw, req)
+	if !ok {
+		return
+	}
+	objectID, err := primitive.ObjectIDFromHex(req.URL.Query().Get("id"))
+	if err != nil {
+		respon.Error(respw, http.StatusBadRequest, "ID promo tidak valid", "")
+		return
+	}
+	var promo model.Promo
+	if !respon.Decode(respw, req, &promo) {
+		return
+	}
+	if err := kasir.ValidatePromo(&promo); err != nil {
+		respon.Error(respw, http.StatusBadRequest, "Kode promo tidak valid", err.Error())
+		return
+	}
+	count, err := atdb.GetCountDoc(config.Mongoconn(), kasir.PromoCollection, bson.M{"owner": owner, "code": promo.Code, "_id": bson.M{"$ne": objectID}})
+	if err == nil && count > 0 {
+		respon.Error(respw, http.StatusConflict, "Kode promo sudah dipakai", "")
+		return
+	}
+
+	update := bson.M{"$set": bson.M{
+		"code":         promo.Code,
+		"name":         promo.Name,
+		"type":         promo.Type,
+		"value":        promo.Value,
+		"min_purchase": promo.MinPurchase,
+		"max_discount": promo.MaxDiscount,
+		"start_at":     promo.StartAt,
+		"end_at":       promo.EndAt,
+		"usage_limit":  promo.UsageLimit,
+		"active":       promo.Active,
+		"updatedAt":    time.Now(),
+	}}
+	result, err := config.Mongoconn().Collection(kasir.PromoCollection).UpdateOne(context.TODO(), bson.M{"_id": objectID, "owner": owner}, update)
+	if err != nil {
+		respon.Error(respw, http.StatusInternalServerError, "Gagal mengubah kode promo", err.Error())
+		return
+	}
+	if result.MatchedCount == 0 {
+		respon.Error(respw, http.StatusNotFound, "Kode promo tidak ditemukan", "")
+		return
+	}
+
+	respon.Success(respw, http.StatusOK, "Kode promo berhasil diubah", update["$set"])
+}
+
+// Handler untuk menghapus kode promo: ?id=
+func DeletePromo(respw http.ResponseWriter, req *http.Request) {
+	owner, ok := getOwner(respw, req)
+	if !ok {
+		return
+	}
+	objectID, err := primitive.ObjectIDFromHex(req.URL.Query().Get("id"))
+	if err != nil {
+		respon.Error(respw, http.StatusBadRequest, "ID promo tidak valid", "")
+		return
+	}
+	result, err := atdb.DeleteOneDoc(config.Mongoconn(), kasir.PromoCollection, bson.M{"_id": objectID, "owner": owner})
+	if err != nil {
+		respon.Error(respw, http.StatusInternalServerError, "Gagal menghapus kode promo", err.Error())
+		return
+	}
+	if result.DeletedCount == 0 {
+		respon.Error(respw, http.StatusNotFound, "Kode promo tidak ditemukan", "")
+		return
+	}
+
+	respon.Success(respw, http.StatusOK, "Kode promo berhasil dihapus", nil)
+}
@@ -11,57 +11,41 @@ import (
 	"github.com/gocroot/helper/at"
 	"github.com/gocroot/helper/atdb"
 	"github.com/gocroot/helper/normalize"
+	"github.com/gocroot/helper/respon"
 	"github.com/gocroot/helper/watoken"
 )
 
 func PostDataProject(respw http.ResponseWriter, req *http.Request) {
 	payload, err := watoken.Decode(config.PublicKeyWhatsAuth, at.GetLoginFromHeader(req))
 	if err != nil {
-		var respn model.Response
-		respn.Status = "Error : Token Tidak Valid"
-		respn.Info = at.GetSecretFromHeader(req)
-		respn.Location = "Decode Token Error"
-		respn.Response = err.Error()
-		at.WriteJSON(respw, http.StatusForbidden, respn)
+		respon.Error(respw, http.StatusForbidden, "Token Tidak Valid", err.Error())
 		return
 	}
 	var prj model.Project
 	err = json.NewDecoder(req.Body).Decode(&prj)
 	if err != nil {
-		var respn model.Response
-		respn.Status = "Error : Body tidak valid"
-		respn.Response = err.Error()
-		at.WriteJSON(respw, http.StatusBadRequest, respn)
+		respon.Error(respw, http.StatusBadRequest, "Body tidak valid", err.Error())
 		return
 	}
-	docuser, err := atdb.GetOneDoc[model.Userdomyikado](config.Mongoconn, "user", primitive.M{"phonenumber": payload.Id})
+	docuser, err := atdb.GetOneDoc[model.Userdomyikado](config.Mongoconn(), "user", primitive.M{"phonenumber": payload.Id})
 	if err != nil {
-		var respn model.Response
-		respn.Status = "Error : Data user tidak di temukan"
-		respn.Response = err.Error()
-		at.WriteJSON(respw, http.StatusNotImplemented, respn)
+		respon.Error(respw, http.StatusNotImplemented, "Data user tidak di temukan", err.Error())
 		return
 	}
 	prj.Owner = docuser
 	prj.Secret = watoken.RandomString(48)
 	prj.Name = normalize.SetIntoID(prj.Name)
-	existingprj, err := atdb.GetOneDoc[model.Project](config.Mongoconn, "project", primitive.M{"name": prj.Name})
+	existingprj, err := atdb.GetOneDoc[model.Project](config.Mongoconn(), "project", primitive.M{"name": prj.Name})
 	if err != nil {
-		idprj, err := atdb.InsertOneDoc(config.Mongoconn, "project", prj)
+		idprj, err := atdb.InsertOneDoc(config.Mongoconn(), "project", prj)
 		if err != nil {
-			var respn model.Response
-			respn.Status = "Gagal Insert Database"
-			respn.Response = err.Error()
-			at.WriteJSON(respw, http.StatusNotModified, respn)
+			respon.Error(respw, http.StatusInternalServerError, "Gagal Insert Database", err.Error())
 			return
 		}
 		prj.ID = idprj
-		at.WriteJSON(respw, http.StatusOK, prj)
+		respon.OK(respw, prj)
 	} else {
-		var respn model.Response
-		respn.Status = "Error : Nama Project sudah ada"
-		respn.Response = existingprj.Name
-		at.WriteJSON(respw, http.StatusConflict, respn)
+		respon.Error(respw, http.StatusConflict, "Nama Project sudah ada", existingprj.Name)
 		return
 	}
 
@@ -70,50 +54,31 @@ func PostDataProject(respw http.ResponseWriter, req *http.Request) {
 func GetDataProject(respw http.ResponseWriter, req *http.Request) {
 	payload, err := watoken.Decode(config.PublicKeyWhatsAuth, at.GetLoginFromHeader(req))
 	if err != nil {
-		var respn model.Response
-		respn.Status = "Error : Token Tidak Valid"
-		respn.Info = at.GetSecretFromHeader(req)
-		respn.Location = "Decode Token Error"
-		respn.Response = err.Error()
-		at.WriteJSON(respw, http.StatusForbidden, respn)
+		respon.Error(respw, http.StatusForbidden, "Token Tidak Valid", err.Error())
 		return
 	}
-	docuser, err := atdb.GetOneDoc[model.Userdomyikado](config.Mongoconn, "user", primitive.M{"phonenumber": payload.Id})
+	docuser, err := atdb.GetOneDoc[model.Userdomyikado](config.Mongoconn(), "user", primitive.M{"phonenumber": payload.Id})
 	if err != nil {
-		var respn model.Response
-		respn.Status = "Error : Data user tidak di temukan"
-		respn.Response = err.Error()
-		at.WriteJSON(respw, http.StatusNotImplemented, respn)
+		respon.Error(respw, http.StatusNotImplemented, "Data user tidak di temukan", err.Error())
 		return
 	}
-	existingprjs, err := atdb.GetAllDoc[[]model.Project](config.Mongoconn, "project", primitive.M{"owner._id": docuser.ID})
+	existingprjs, err := atdb.GetAllDoc[[]model.Project](config.Mongoconn(), "project", primitive.M{"owner._id": docuser.ID})
 	if err != nil {
-		var respn model.Response
-		respn.Status = "Error : Data project tidak di temukan"
-		respn.Response = err.Error()
-		at.WriteJSON(respw, http.StatusNotFound, respn)
+		respon.Error(respw, http.StatusNotFound, "Data project tidak di temukan", err.Error())
 		return
 	}
 	if len(existingprjs) == 0 {
-		var respn model.Response
-		respn.Status = "Error : Data project tidak di temukan"
-		respn.Response = "Kakak belum input proyek, silahkan input dulu ya"
-		at.WriteJSON(respw, http.StatusNotFound, respn)
+		respon.Error(respw, http.StatusNotFound, "Data project tidak di temukan", "Kakak belum input proyek, silahkan input dulu ya")
 		return
 	}
-	at.WriteJSON(respw, http.StatusOK, existingprjs)
+	respon.OK(respw, existingprjs)
 }
 
 func PutDataProject(respw http.ResponseWriter, req *http.Request) {
 	// Decode token from header
 	payload, err := watoken.Decode(config.PublicKeyWhatsAuth, at.GetLoginFromHeader(req))
 	if err != nil {
-		var respn model.Response
-		respn.Status = "Error: Token Tidak Valid"
-		respn.Info = at.GetSecretFromHeader(req)
-		respn.Location = "Decode Token Error"
-		respn.Response = err.Error()
-		at.WriteJSON(respw, http.StatusForbidden, respn)
+		respon.Error(respw, http.StatusForbidden, "Token Tidak Valid", err.Error())
 		return
 	}
 
@@ -121,30 +86,21 @@ func PutDataProject(respw http.ResponseWriter, req *http.Request) {
 	var prj model.Project
 	err = json.NewDecoder(req.Body).Decode(&prj)
 	if err != nil {
-		var respn model.Response
-		respn.Status = "Error: Body tidak valid"
-		respn.Response = err.Error()
-		at.WriteJSON(respw, http.StatusBadRequest, respn)
+		respon.Error(respw, http.StatusBadRequest, "Body tidak valid", err.Error())
 		return
 	}
 
 	// Get user data from the database
-	docuser, err := atdb.GetOneDoc[model.Userdomyikado](config.Mongoconn, "user", primitive.M{"phonenumber": payload.Id})
+	docuser, err := atdb.GetOneDoc[model.Userdomyikado](config.Mongoconn(), "user", primitive.M{"phonenumber": payload.Id})
 	if err != nil {
-		var respn model.Response
-		respn.Status = "Error: Data user tidak ditemukan"
-		respn.Response = err.Error()
-		at.WriteJSON(respw, http.StatusNotImplemented, respn)
+		respon.Error(respw, http.StatusNotImplemented, "Data user tidak ditemukan", err.Error())
 		return
 	}
 
 	// Check if the project exists and belongs to the user
-	existingprj, err := atdb.GetOneDoc[model.Project](config.Mongoconn, "project", primitive.M{"_id": prj.ID, "owner._id": docuser.ID})
+	existingprj, err := atdb.GetOneDoc[model.Project](config.Mongoconn(), "project", primitive.M{"_id": prj.ID, "owner._id": docuser.ID})
 	if err != nil {
-		var respn model.Response
-		respn.Status = "Error: Project tidak ditemukan"
-		respn.Response = err.Error()
-		at.WriteJSON(respw, http.StatusNotFound, respn)
+		respon.Error(respw, http.StatusNotFound, "Project tidak ditemukan", err.Error())
 		return
 	}
 
@@ -157,29 +113,21 @@ func PutDataProject(respw http.ResponseWriter, req *http.Request) {
 	prj.WAGroupID = existingprj.WAGroupID
 
 	// Save the updated project back to the database using ReplaceOneDoc
-	_, err = atdb.ReplaceOneDoc(config.Mongoconn, "project", primitive.M{"_id": existingprj.ID}, prj)
+	_, err = atdb.ReplaceOneDoc(config.Mongoconn(), "project", primitive.M{"_id": existingprj.ID}, prj)
 	if err != nil {
-		var respn model.Response
-		respn.Status = "Error: Gagal memperbarui database"
-		respn.Response = err.Error()
-		at.WriteJSON(respw, http.StatusInternalServerError, respn)
+		respon.Error(respw, http.StatusInternalServerError, "Gagal memperbarui database", err.Error())
 		return
 	}
 
 	// Return the updated project
-	at.WriteJSON(respw, http.StatusOK, prj)
+	respon.OK(respw, prj)
 }
 
 func DeleteDataProject(respw http.ResponseWriter, req *http.Request) {
 	// Dekode token dari header permintaan
 	payload, err := watoken.Decode(config.PublicKeyWhatsAuth, at.GetLoginFromHeader(req))
 	if err != nil {
-		var respn model.Response
-		respn.Status = "Error : Token Tidak Valid"
-		respn.Info = at.GetSecretFromHeader(req)
-		respn.Location = "Decode Token Error"
-		respn.Response = err.Error()
-		at.WriteJSON(respw, http.StatusForbidden, respn)
+		respon.Error(respw, http.StatusForbidden, "Token Tidak Valid", err.Error())
 		return
 	}
 
@@ -189,144 +137,98 @@ func DeleteDataProject(respw http.ResponseWriter, req *http.Request) {
 	}
 	err = json.NewDecoder(req.Body).Decode(&reqBody)
 	if err != nil {
-		var respn model.Response
-		respn.Status = "Error : Body tidak valid"
-		respn.Response = err.Error()
-		at.WriteJSON(respw, http.StatusBadRequest, respn)
+		respon.Error(respw, http.StatusBadRequest, "Body tidak valid", err.Error())
 		return
 	}
 
 	// Dapatkan data pengguna berdasarkan ID dari payload token
-	docuser, err := atdb.GetOneDoc[model.Userdomyikado](config.Mongoconn, "user", primitive.M{"phonenumber": payload.Id})
+	docuser, err := atdb.GetOneDoc[model.Userdomyikado](config.Mongoconn(), "user", primitive.M{"phonenumber": payload.Id})
 	if err != nil {
-		var respn model.Response
-		respn.Status = "Error : Data user tidak di temukan"
-		respn.Response = err.Error()
-		at.WriteJSON(respw, http.StatusNotImplemented, respn)
+		respon.Error(respw, http.StatusNotImplemented, "Data user tidak di temukan", err.Error())
 		return
 	}
 
 	// Cek apakah proyek dengan nama yang diberikan ada dan dimiliki oleh pengguna
-	existingprj, err := atdb.GetOneDoc[model.Project](config.Mongoconn, "project", primitive.M{"name": reqBody.ProjectName, "owner._id": docuser.ID})
+	existingprj, err := atdb.GetOneDoc[model.Project](config.Mongoconn(), "project", primitive.M{"name": reqBody.ProjectName, "owner._id": docuser.ID})
 	if err != nil {
-		var respn model.Response
-		respn.Status = "Error : Data project tidak di temukan"
-		respn.Response = "Proyek dengan nama tersebut tidak ditemukan atau bukan milik Anda"
-		at.WriteJSON(respw, http.StatusNotFound, respn)
+		respon.Error(respw, http.StatusNotFound, "Data project tidak di temukan", "Proyek dengan nama tersebut tidak ditemukan atau bukan milik Anda")
 		return
 	}
 
 	// Hapus proyek dari koleksi "project" di MongoDB
-	_, err = atdb.DeleteOneDoc(config.Mongoconn, "project", primitive.M{"_id": existingprj.ID})
+	_, err = atdb.DeleteOneDoc(config.Mongoconn(), "project", primitive.M{"_id": existingprj.ID})
 	if err != nil {
-		var respn model.Response
-		respn.Status = "Error : Gagal menghapus project"
-		respn.Response = err.Error()
-		at.WriteJSON(respw, http.StatusExpectationFailed, respn)
+		respon.Error(respw, http.StatusExpectationFailed, "Gagal menghapus project", err.Error())
 		return
 	}
 
 	// Berhasil menghapus proyek
-	at.WriteJSON(respw, http.StatusOK, map[string]string{"status": "Project berhasil dihapus"})
+	respon.Success(respw, http.StatusOK, "Project berhasil dihapus", nil)
 }
 
 func GetDataMemberProject(respw http.ResponseWriter, req *http.Request) {
 	payload, err := watoken.Decode(config.PublicKeyWhatsAuth, at.GetLoginFromHeader(req))
 	if err != nil {
-		var respn model.Response
-		respn.Status = "Error : Token Tidak Valid"
-		respn.Info = at.GetSecretFromHeader(req)
-		respn.Location = "Decode Token Error"
-		respn.Response = err.Error()
-		at.WriteJSON(respw, http.StatusForbidden, respn)
+		respon.Error(respw, http.StatusForbidden, "Token Tidak Valid", err.Error())
 		return
 	}
-	docuser, err := atdb.GetOneDoc[model.Userdomyikado](config.Mongoconn, "user", primitive.M{"phonenumber": payload.Id})
+	docuser, err := atdb.GetOneDoc[model.Userdomyikado](config.Mongoconn(), "user", primitive.M{"phonenumber": payload.Id})
 	if err != nil {
-		var respn model.Response
-		respn.Status = "Error : Data user tidak di temukan"
-		respn.Response = err.Error()
-		at.WriteJSON(respw, http.StatusNotImplemented, respn)
+		respon.Error(respw, http.StatusNotImplemented, "Data user tidak di temukan", err.Error())
 		return
 	}
-	existingprjs, err := atdb.GetAllDoc[[]model.Project](config.Mongoconn, "project", primitive.M{"members._id": docuser.ID})
+	existingprjs, err := atdb.GetAllDoc[[]model.Project](config.Mongoconn(), "project", primitive.M{"members._id": docuser.ID})
 	if err != nil {
-		var respn model.Response
-		respn.Status = "Error : Data project tidak di temukan"
-		respn.Response = err.Error()
-		at.WriteJSON(respw, http.StatusNotFound, respn)
+		respon.Error(respw, http.StatusNotFound, "Data project tidak di temukan", err.Error())
 		return
 	}
 	if len(existingprjs) == 0 {
-		var respn model.Response
-		respn.Status = "Error : Data project tidak di temukan"
-		respn.Response = "Kakak belum menjadi anggota proyek manapun"
-		at.WriteJSON(respw, http.StatusNotFound, respn)
+		respon.Error(respw, http.StatusNotFound, "Data project tidak di temukan", "Kakak belum menjadi anggota proyek manapun")
 		return
 	}
-	at.WriteJSON(respw, http.StatusOK, existingprjs)
+	respon.OK(respw, existingprjs)
 }
 
 func PostDataMenuProject(respw http.ResponseWriter, req *http.Request) {
-	var respn model.Response
 	payload, err := watoken.Decode(config.PublicKeyWhatsAuth, at.GetLoginFromHeader(req))
 	if err != nil {
-		respn.Status = "Error : Token Tidak Valid"
-		respn.Info = at.GetSecretFromHeader(req)
-		respn.Location = "Decode Token Error"
-		respn.Response = err.Error()
-		at.WriteJSON(respw, http.StatusForbidden, respn)
+		respon.Error(respw, http.StatusForbidden, "Token Tidak Valid", err.Error())
 		return
 	}
 	var idprjuser model.MenuItem
 	err = json.NewDecoder(req.Body).Decode(&idprjuser)
 	if err != nil {
-		respn.Status = "Error : Body tidak valid"
-		respn.Response = err.Error()
-		at.WriteJSON(respw, http.StatusBadRequest, respn)
+		respon.Error(respw, http.StatusBadRequest, "Body tidak valid", err.Error())
 		return
 	}
-	docuserowner, err := atdb.GetOneDoc[model.Userdomyikado](config.Mongoconn, "user", primitive.M{"phonenumber": payload.Id})
+	docuserowner, err := atdb.GetOneDoc[model.Userdomyikado](config.Mongoconn(), "user", primitive.M{"phonenumber": payload.Id})
 	if err != nil {
-		respn.Status = "Error : Data owner tidak di temukan"
-		respn.Response = err.Error()
-		at.WriteJSON(respw, http.StatusNotImplemented, respn)
+		respon.Error(respw, http.StatusNotImplemented, "Data owner tidak di temukan", err.Error())
 		return
 	}
-	existingprj, err := atdb.GetOneDoc[model.Project](config.Mongoconn, "project", primitive.M{"_id": idprjuser.IDDatabase, "owner._id": docuserowner.ID})
+	existingprj, err := atdb.GetOneDoc[model.Project](config.Mongoconn(), "project", primitive.M{"_id": idprjuser.IDDatabase, "owner._id": docuserowner.ID})
 	if err != nil {
-		respn.Status = "Error : Data project tidak di temukan"
-		respn.Response = err.Error()
-		at.WriteJSON(respw, http.StatusNotFound, respn)
+		respon.Error(respw, http.StatusNotFound, "Data project tidak di temukan", err.Error())
 		return
 	}
 	//bikin insert menu
 	//idprjuser.IDDatabase = primitive.NilObjectID
-	rest, err := atdb.AddDocToArray[model.MenuItem](config.Mongoconn, "project", idprjuser.IDDatabase, "menu", idprjuser)
+	rest, err := atdb.AddDocToArray[model.MenuItem](config.Mongoconn(), "project", idprjuser.IDDatabase, "menu", idprjuser)
 	if err != nil {
-		respn.Status = "Error : Gagal menambahkan menu ke lapak"
-		respn.Response = err.Error()
-		at.WriteJSON(respw, http.StatusExpectationFailed, respn)
+		respon.Error(respw, http.StatusExpectationFailed, "Gagal menambahkan menu ke lapak", err.Error())
 		return
 	}
 	if rest.ModifiedCount == 0 {
-		respn.Status = "Error : Gagal menambahkan member ke project"
-		respn.Response = "Tidak ada perubahan pada dokumen proyek"
-		at.WriteJSON(respw, http.StatusExpectationFailed, respn)
+		respon.Error(respw, http.StatusExpectationFailed, "Gagal menambahkan member ke project", "Tidak ada perubahan pada dokumen proyek")
 		return
 	}
-	at.WriteJSON(respw, http.StatusOK, existingprj)
+	respon.OK(respw, existingprj)
 }
 
 func DeleteDataMenuProject(respw http.ResponseWriter, req *http.Request) {
-	var respn model.Response
 	payload, err := watoken.Decode(config.PublicKeyWhatsAuth, at.GetLoginFromHeader(req))
 	if err != nil {
-		respn.Status = "Error : Token Tidak Valid"
-		respn.Info = at.GetSecretFromHeader(req)
-		respn.Location = "Decode Token Error"
-		respn.Response = err.Error()
-		at.WriteJSON(respw, http.StatusForbidden, respn)
+		respon.Error(respw, http.StatusForbidden, "Token Tidak Valid", err.Error())
 		return
 	}
 
@@ -337,43 +239,33 @@ func DeleteDataMenuProject(respw http.ResponseWriter, req *http.Request) {
 
 	err = json.NewDecoder(req.Body).Decode(&requestPayload)
 	if err != nil {
-		respn.Status = "Error : Body tidak valid"
-		respn.Response = err.Error()
-		at.WriteJSON(respw, http.StatusBadRequest, respn)
+		respon.Error(respw, http.StatusBadRequest, "Body tidak valid", err.Error())
 		return
 	}
 
-	docuserowner, err := atdb.GetOneDoc[model.Userdomyikado](config.Mongoconn, "user", primitive.M{"phonenumber": payload.Id})
+	docuserowner, err := atdb.GetOneDoc[model.Userdomyikado](config.Mongoconn(), "user", primitive.M{"phonenumber": payload.Id})
 	if err != nil {
-		respn.Status = "Error : Data owner tidak ditemukan"
-		respn.Response = err.Error()
-		at.WriteJSON(respw, http.StatusNotImplemented, respn)
+		respon.Error(respw, http.StatusNotImplemented, "Data owner tidak ditemukan", err.Error())
 		return
 	}
 
-	existingprj, err := atdb.GetOneDoc[model.Project](config.Mongoconn, "project", primitive.M{"name": requestPayload.ProjectName, "owner._id": docuserowner.ID})
+	existingprj, err := atdb.GetOneDoc[model.Project](config.Mongoconn(), "project", primitive.M{"name": requestPayload.ProjectName, "owner._id": docuserowner.ID})
 	if err != nil {
-		respn.Status = "Error : Data project tidak ditemukan"
-		respn.Response = err.Error()
-		at.WriteJSON(respw, http.StatusNotFound, respn)
+		respon.Error(respw, http.StatusNotFound, "Data project tidak ditemukan", err.Error())
 		return
 	}
 
 	// Menghapus member dari project
 	menuToDelete := model.MenuItem{ID: requestPayload.MenuID}
-	rest, err := atdb.DeleteDocFromArray[model.MenuItem](config.Mongoconn, "project", existingprj.ID, "menu", menuToDelete)
+	rest, err := atdb.DeleteDocFromArray[model.MenuItem](config.Mongoconn(), "project", existingprj.ID, "menu", menuToDelete)
 	if err != nil {
-		respn.Status = "Error : Gagal menghapus menu dari lapak"
-		respn.Response = err.Error()
-		at.WriteJSON(respw, http.StatusExpectationFailed, respn)
+		respon.Error(respw, http.StatusExpectationFailed, "Gagal menghapus menu dari lapak", err.Error())
 		return
 	}
 	if rest.ModifiedCount == 0 {
-		respn.Status = "Error : Gagal menghapus menu dari lapak"
-		respn.Response = "Tidak ada perubahan pada dokumen proyek:" + menuToDelete.ID
-		at.WriteJSON(respw, http.StatusExpectationFailed, respn)
+		respon.Error(respw, http.StatusExpectationFailed, "Gagal menghapus menu dari lapak", "Tidak ada perubahan pada dokumen proyek:" + menuToDelete.ID)
 		return
 	}
 
-	at.WriteJSON(respw, http.StatusOK, existingprj)
+	respon.OK(respw, existingprj)
 }
\ No newline at end of file
@@ -0,0 +1,299 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gocroot/config"
+	"github.com/gocroot/helper/atdb"
+	"github.com/gocroot/helper/audit"
+	"github.com/gocroot/helper/pembelian"
+	"github.com/gocroot/helper/respon"
+	"github.com/gocroot/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Fungsi untuk membuat purchase order baru dengan status draft
+func CreatePurchaseOrder(respw http.ResponseWriter, req *http.Request) {
+	owner, ok := getOwner(respw, req)
+	if !ok {
+		return
+	}
+	var po model.PurchaseOrder
+	if !respon.Decode(respw, req, &po) {
+		return
+	}
+
+	supplier, err := atdb.GetOneDoc[model.Supplier](config.Mongoconn(), pembelian.SupplierCollection, bson.M{"_id": po.SupplierID, "owner": owner})
+	if err != nil {
+		respon.Error(respw, http.StatusBadRequest, "Supplier tidak ditemukan", "")
+		return
+	}
+	if err = fillPurchaseItems(req.Context(), &po, owner); err != nil {
+		respon.Error(respw, http.StatusBadRequest, "Item purchase order tidak valid", err.Error())
+		return
+	}
+
+	po.ID = primitive.NewObjectID()
+	po.Owner = owner
+	po.SupplierName = supplier.Name
+	po.Status = pembelian.StatusDraft
+	po.PayableID = primitive.NilObjectID
+	po.CreatedAt = time.Now()
+	po.UpdatedAt = time.Now()
+
+	if _, err = atdb.InsertOneDoc(config.Mongoconn(), pembelian.PurchaseOrderCollection, po); err != nil {
+		respon.Error(respw, http.StatusInternalServerError, "Gagal Insert Database", err.Error())
+		return
+	}
+
+	respon.Success(respw, http.StatusCreated, "Purchase order berhasil dibuat", po)
+}
+
+// fillPurchaseItems melengkapi nama produk dan menghitung subtotal serta total
+func fillPurchaseItems(ctx context.Context, po *model.PurchaseOrder, owner string) (err error) {
+	if err = pembelian.Normalize(po); err != nil {
+		return
+	}
+	for i, item := range po.Items {
+		var product model.Product
+		product, err = repos.Products.Get(ctx, audit.Live(bson.M{"_id": item.ProductID, "owner": owner}))
+		if err != nil {
+			return
+		}
+		po.Items[i].ProductName = product.Name
+	}
+	return
+}
+
+// Fungsi untuk mendapatkan semua purchase order, opsional ?status=
+func GetPurchaseOrders(respw http.ResponseWriter, req *http.Request) {
+	owner, ok := getOwner(respw, req)
+	if !ok {
+		return
+	}
+	filter := bson.M{"owner": owner}
+	if status := req.URL.Query().Get("status"); status != "" {
+		filter["status"] = status
+	}
+	data, err := atdb.GetAllDoc[[]model.PurchaseOrder](config.Mongoconn(), pembelian.PurchaseOrderCollection, filter)
+	if err != nil {
+		respon.Error(respw, http.StatusInternalServerError, "Gagal mengambil data purchase order", err.Error())
+		return
+	}
+	respon.OK(respw, data)
+}
+
+// Fungsi untuk mendapatkan purchase order berdasarkan ID
+func GetPurchaseOrderByID(respw http.ResponseWriter, req *http.Request) {
+	owner, ok := getOwner(respw, req)
+	if !ok {
+		return
+	}
+	objectID, err := primitive.ObjectIDFromHex(req.URL.Query().Get("id"))
+	if err != nil {
+		respon.Error(respw, http.StatusBadRequest, "ID Purchase order tidak valid", "")
+		return
+	}
+
+	po, err := atdb.GetOneDoc[model.PurchaseOrder](config.Mongoconn(), pembelian.PurchaseOrderCollection, bson.M{"_id": objectID, "owner": owner})
+	if err != nil {
+		respon.Error(respw, http.StatusNotFound, "Purchase order tidak ditemukan", "")
+		return
+	}
+
+	respon.Success(respw, http.StatusOK, "Purchase order ditemukan", po)
+}
+
+// Fungsi untuk mengubah item purchase order yang masih draft
+func UpdatePurchaseOrder(respw http.ResponseWriter, req *http.Request) {
+	owner, ok := getOwner(respw, req)
+	if !ok {
+		return
+	}
+	objectID, err := primitive.ObjectIDFromHex(req.URL.Query().Get("id"))
+	if err != nil {
+		respon.Error(respw, http.StatusBadRequest, "ID Purchase order tidak valid", "")
+		return
+	}
+
+	var po model.PurchaseOrder
+	if !respon.Decode(respw, req, &po) {
+		return
+	}
+	if err = fillPurchaseItems(req.Context(), &po, owner); err != nil {
+		respon.Error(respw, http.StatusBadRequest, "Item purchase order tidak valid", err.Error())
+		return
+	}
+
+	updateData := bson.M{
+		"items":        po.Items,
+		"total_amount": po.TotalAmount,
+		"tax_amount":   po.TaxAmount,
+		"notes":        po.Notes,
+		"updated_at":   time.Now(),
+	}
+	filter := bson.M{"_id": objectID, "owner": owner, "status": pembelian.StatusDraft}
+	result, err := config.Mongoconn().Collection(pembelian.PurchaseOrderCollection).UpdateOne(context.TODO(), filter, bson.M{"$set": updateData})
+	if err != nil {
+		respon.Error(respw, http.StatusInternalServerError, "Gagal mengupdate purchase order", err.Error())
+		return
+	}
+	if result.MatchedCount == 0 {
+		respon.Error(respw, http.StatusConflict, "Purchase order tidak ditemukan atau bukan draft", "")
+		return
+	}
+
+	respon.Success(respw, http.StatusOK, "Purchase order berhasil diupdate", updateData)
+}
+
+// Fungsi untuk memindahkan status purchase order: draft -> ordered -> received -> billed.
+// Status received menambah stok dan membuat utang usaha.
+func UpdatePurchaseOrderStatus(respw http.ResponseWriter, req *http.Request) {
+	owner, ok := getOwner(respw, req)
+	if !ok {
+		return
+	}
+	objectID, err := primitive.ObjectIDFromHex(req.URL.Query().Get("id"))
+	if err != nil {
+		respon.Error(respw, http.StatusBadRequest, "ID Purchase order tidak valid", "")
+		return
+	}
+
+	var request struct {
+		Status        string `json:"status"`
+		InvoiceNumber string `json:"invoice_number"`
+		DueDate       string `json:"due_date"`
+	}
+	if !respon.Decode(respw, req, &request) {
+		return
+	}
+	var dueDate time.Time
+	if request.DueDate != "" {
+		if dueDate, err = time.Parse("2006-01-02", request.DueDate); err != nil {
+			respon.Error(respw, http.StatusBadRequest, "Invalid due_date format. Use YYYY-MM-DD", "")
+			return
+		}
+	}
+
+	po, err := atdb.GetOneDoc[model.PurchaseOrder](config.Mongoconn(), pembelian.PurchaseOrderCollection, bson.M{"_id": objectID, "owner": owner})
+	if err != nil {
+		respon.Error(respw, http.StatusNotFound, "Purchase order tidak ditemukan", "")
+		return
+	}
+	if !pembelian.CanTransition(po.Status, request.Status) {
+		respon.Error(respw, http.StatusConflict, "Perubahan status tidak diizinkan", "Status "+po.Status+" tidak bisa menjadi "+request.Status)
+		return
+	}
+
+	// Kunci perpindahan status agar penerimaan barang tidak diproses dua kali
+	lock := bson.M{"_id": objectID, "owner": owner, "status": po.Status}
+	result, err := config.Mongoconn().Collection(pembelian.PurchaseOrderCollection).UpdateOne(context.TODO(), lock, bson.M{"$set": bson.M{"status": request.Status, "updated_at": time.Now()}})
+	if err != nil || result.MatchedCount == 0 {
+		respon.Error(respw, http.StatusConflict, "Purchase order sedang diproses atau statusnya sudah berubah", "")
+		return
+	}
+	po.Status = request.Status
+
+	switch request.Status {
+	case pembelian.StatusOrdered:
+		po.OrderedAt = time.Now()
+	case pembelian.StatusReceived:
+		if _, err = pembelian.Receive(config.Mongoconn(), &po); err != nil {
+			respon.Error(respw, http.StatusInternalServerError, "Gagal memproses penerimaan barang", err.Error())
+			return
+		}
+	case pembelian.StatusBilled:
+		if err = pembelian.Bill(config.Mongoconn(), &po, request.InvoiceNumber, dueDate); err != nil {
+			respon.Error(respw, http.StatusInternalServerError, "Gagal mencatat tagihan supplier", err.Error())
+			return
+		}
+	}
+
+	po.UpdatedAt = time.Now()
+	if _, err = atdb.ReplaceOneDoc(config.Mongoconn(), pembelian.PurchaseOrderCollection, bson.M{"_id": objectID, "owner": owner}, po); err != nil {
+		respon.Error(respw, http.StatusInternalServerError, "Gagal mengupdate purchase order", err.Error())
+		return
+	}
+
+	respon.Success(respw, http.StatusOK, "Status purchase order menjadi "+po.Status, po)
+}
+
+// Fungsi untuk menghapus purchase order yang masih draft
+func DeletePurchaseOrder(respw http.ResponseWriter, req *http.Request) {
+	owner, ok := getOwner(respw, req)
+	if !ok {
+		return
+	}
+	objectID, err := primitive.ObjectIDFromHex(req.URL.Query().Get("id"))
+	if err != nil {
+		respon.Error(respw, http.StatusBadRequest, "ID Purchase order tidak valid", "")
+		return
+	}
+
+	deleteResult, err := atdb.DeleteOneDoc(config.Mongoconn(), pembelian.PurchaseOrderCollection, bson.M{"_id": objectID, "owner": owner, "status": pembelian.StatusDraft})
+	if err != nil {
+		respon.Error(respw, http.StatusInternalServerError, "Gagal menghapus purchase order", err.Error())
+		return
+	}
+	if deleteResult.DeletedCount == 0 {
+		respon.Error(respw, http.StatusConflict, "Purchase order tidak ditemukan atau bukan draft", "")
+		return
+	}
+
+	respon.Success(respw, http.StatusOK, "Purchase order berhasil dihapus", nil)
+}
+
+// Fungsi untuk mendapatkan daftar utang usaha, opsional ?status=open|partial|paid
+func GetPayables(respw http.ResponseWriter, req *http.Request) {
+	owner, ok := getOwner(respw, req)
+	if !ok {
+		return
+	}
+	filter := bson.M{"owner": owner}
+	if status := req.URL.Query().Get("status"); status != "" {
+		filter["status"] = status
+	}
+	data, err := atdb.GetAllDoc[[]model.Payable](config.Mongoconn(), pembelian.PayableCollection, filter)
+	if err != nil {
+		respon.Error(respw, http.StatusInternalServerError, "Gagal mengambil data utang usaha", err.Error())
+		return
+	}
+	respon.OK(respw, data)
+}
+
+// Fungsi untuk membayar utang usaha: ?id= dengan body amount dan payment_method
+func PayPayable(respw http.ResponseWriter, req *http.Request) {
+	owner, ok := getOwner(respw, req)
+	if !ok {
+		return
+	}
+	objectID, err := primitive.ObjectIDFromHex(req.URL.Query().Get("id"))
+	if err != nil {
+		respon.Error(respw, http.StatusBadRequest, "ID Utang tidak valid", "")
+		return
+	}
+
+	var request struct {
+		Amount        float64 `json:"amount"`
+		PaymentMethod string  `json:"payment_method"`
+	}
+	if !respon.Decode(respw, req, &request) {
+		return
+	}
+
+	payable, err := atdb.GetOneDoc[model.Payable](config.Mongoconn(), pembelian.PayableCollection, bson.M{"_id": objectID, "owner": owner})
+	if err != nil {
+		respon.Error(respw, http.StatusNotFound, "Utang tidak ditemukan", "")
+		return
+	}
+
+	if err = pembelian.Pay(config.Mongoconn(), &payable, request.Amount, request.PaymentMethod); err != nil {
+		respon.Error(respw, http.StatusBadRequest, "Gagal mencatat pembayaran utang", err.Error())
+		return
+	}
+
+	respon.Success(respw, http.StatusCreated, "Pembayaran utang berhasil dicatat", payable)
+}
@@ -0,0 +1,111 @@
+package controller
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gocroot/config"
+	"github.com/gocroot/helper/atdb"
+	"github.com/gocroot/helper/audit"
+	"github.com/gocroot/helper/keuangan"
+	"github.com/gocroot/helper/piutang"
+	"github.com/gocroot/helper/respon"
+	"github.com/gocroot/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Fungsi untuk mencatat cicilan pembayaran penjualan kredit: ?id= dengan body amount, payment_method dan notes
+func CreateSalesPayment(respw http.ResponseWriter, req *http.Request) {
+	owner, ok := getOwner(respw, req)
+	if !ok {
+		return
+	}
+	objectID, err := primitive.ObjectIDFromHex(req.URL.Query().Get("id"))
+	if err != nil {
+		respon.Error(respw, http.StatusBadRequest, "ID Transaksi tidak valid", "")
+		return
+	}
+
+	var payment model.SalesPayment
+	if !respon.Decode(respw, req, &payment) {
+		return
+	}
+
+	transaction, err := atdb.GetOneDoc[model.SalesTransaction](config.Mongoconn(), piutang.SalesCollection, audit.Live(bson.M{"_id": objectID, "owner": owner}))
+	if err != nil {
+		respon.Error(respw, http.StatusNotFound, "Transaksi tidak ditemukan", "")
+		return
+	}
+
+	if !periodOpen(respw, owner, payment.PaidAt) {
+		return
+	}
+
+	if err = piutang.AddPayment(config.Mongoconn(), &transaction, payment); err != nil {
+		status := http.StatusBadRequest
+		if err == piutang.ErrConcurrentPayment {
+			status = http.StatusConflict
+		}
+		respon.Error(respw, status, "Gagal mencatat pembayaran", err.Error())
+		return
+	}
+
+	respon.Success(respw, http.StatusCreated, "Pembayaran berhasil dicatat", transaction)
+}
+
+// Fungsi untuk mendapatkan riwayat pembayaran satu penjualan: ?id=
+func GetSalesPayments(respw http.ResponseWriter, req *http.Request) {
+	owner, ok := getOwner(respw, req)
+	if !ok {
+		return
+	}
+	objectID, err := primitive.ObjectIDFromHex(req.URL.Query().Get("id"))
+	if err != nil {
+		respon.Error(respw, http.StatusBadRequest, "ID Transaksi tidak valid", "")
+		return
+	}
+
+	transaction, err := atdb.GetOneDoc[model.SalesTransaction](config.Mongoconn(), piutang.SalesCollection, audit.Live(bson.M{"_id": objectID, "owner": owner}))
+	if err != nil {
+		respon.Error(respw, http.StatusNotFound, "Transaksi tidak ditemukan", "")
+		return
+	}
+	if transaction.Payments == nil {
+		transaction.Payments = []model.SalesPayment{}
+	}
+
+	respon.Success(respw, http.StatusOK, "Riwayat pembayaran", map[string]interface{}{
+		"total_amount":   transaction.TotalAmount,
+		"paid_amount":    transaction.PaidAmount,
+		"outstanding":    piutang.Outstanding(transaction),
+		"payment_status": transaction.PaymentStatus,
+		"payments":       transaction.Payments,
+	})
+}
+
+// Handler untuk laporan umur piutang per pelanggan: ?asof=YYYY-MM-DD, default hari ini
+func GetReceivableAging(respw http.ResponseWriter, req *http.Request) {
+	owner, ok := getOwner(respw, req)
+	if !ok {
+		return
+	}
+	asOf := req.URL.Query().Get("asof")
+	if asOf == "" {
+		asOf = time.Now().Format("2006-01-02")
+	}
+	_, end, err := keuangan.ParsePeriod(asOf, asOf)
+	if err != nil {
+		respon.Error(respw, http.StatusBadRequest, "Invalid asof format. Use YYYY-MM-DD", "")
+		return
+	}
+
+	report, err := piutang.Aging(config.Mongoconn(), owner, end)
+	if err != nil {
+		respon.Error(respw, http.StatusInternalServerError, "Gagal menghitung umur piutang", err.Error())
+		return
+	}
+	report.AsOf = asOf
+
+	respon.Success(respw, http.StatusOK, "Umur piutang", report)
+}
@@ -0,0 +1,248 @@
+package controller
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/gocroot/config"
+	"github.com/gocroot/helper/atdb"
+	"github.com/gocroot/helper/berulang"
+	"github.com/gocroot/helper/paging"
+	"github.com/gocroot/helper/respon"
+	"github.com/gocroot/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// skipRequest adalah body untuk melewati satu jadwal pengeluaran rutin
+type skipRequest struct {
+	Date string `json:"date"` // YYYY-MM-DD
+}
+
+// recurringPaging adalah parameter list pengeluaran rutin: ?q= nama/kategori, ?frequency=, ?active=true|false, ?sort=next_date|expense_name|amount
+var recurringPaging = paging.Options{
+	SearchFields: []string{"expense_name", "category"},
+	Filters:      map[string]string{"frequency": "frequency"},
+	SortFields:   []string{"next_date", "expense_name", "amount"},
+}
+
+// Handler untuk daftar template pengeluaran rutin
+func GetRecurringExpenses(respw http.ResponseWriter, req *http.Request) {
+	owner, ok := getOwner(respw, req)
+	if !ok {
+		return
+	}
+	base := bson.M{"owner": owner}
+	if active, err := strconv.ParseBool(req.URL.Query().Get("active")); err == nil {
+		base["active"] = active
+	}
+	q, err := paging.Parse(req.URL.Query(), base, recurringPaging)
+	if err != nil {
+		respon.Error(respw, http.StatusBadRequest, "Parameter query tidak valid", err.Error())
+		return
+	}
+	data, meta, err := paging.Find[model.RecurringExpense](config.Mongoconn(), berulang.RecurringCollection, q)
+	if err != nil {
+		respon.Error(respw, http.StatusInternalServerError, "Gagal mengambil pengeluaran rutin", err.Error())
+		return
+	}
+	respon.List(respw, data, meta)
+}
+
+// Handler untuk membuat template pengeluaran rutin, jadwal pertama jatuh pada start_date
+func CreateRecurringExpense(respw http.ResponseWriter, req *http.Request) {
+	owner, ok := getOwner(respw, req)
+	if !ok {
+		return
+	}
+	var recurring model.RecurringExpense
+	if !respon.Decode(respw, req, &recurring) {
+		return
+	}
+	if err := berulang.Validate(recurring); err != nil {
+		respon.Error(respw, http.StatusBadRequest, "Pengeluaran rutin tidak valid", err.Error())
+		return
+	}
+
+	recurring.ID = primitive.NewObjectID()
+	recurring.Owner = owner
+	recurring.Active = true
+	recurring.SkipDates = nil
+	recurring.LastRunAt = time.Time{}
+	berulang.Reschedule(&recurring, recurring.StartDate)
+	recurring.CreatedAt = time.Now()
+	recurring.UpdatedAt = recurring.CreatedAt
+	if _, err := atdb.InsertOneDoc(config.Mongoconn(), berulang.RecurringCollection, recurring); err != nil {
+		respon.Error(respw, http.StatusInternalServerError, "Gagal menyimpan pengeluaran rutin", err.Error())
+		return
+	}
+
+	respon.Success(respw, http.StatusCreated, "Pengeluaran rutin berhasil ditambahkan", recurring)
+}
+
+// Handler untuk mengubah template pengeluaran rutin: ?id=.
+// Jadwal yang sudah dibuat tidak dibuat ulang, active=false menghentikan sementara template.
+func UpdateRecurringExpense(respw http.ResponseWriter, req *http.Request) {
+	owner, ok := getOwner(respw, req)
+	if !ok {
+		return
+	}
+	objectID, err := primitive.ObjectIDFromHex(req.URL.Query().Get("id"))
+	if err != nil {
+		respon.Error(respw, http.StatusBadRequest, "ID pengeluaran rutin tidak valid", "")
+		return
+	}
+	var recurring model.RecurringExpense
+	if !respon.Decode(respw, req, &recurring) {
+		return
+	}
+	if err := berulang.Validate(recurring); err != nil {
+		respon.Error(respw, http.StatusBadRequest, "Pengeluaran rutin tidak valid", err.Error())
+		return
+	}
+	filter := bson.M{"_id": objectID, "owner": owner}
+	existing, err := atdb.GetOneDoc[model.RecurringExpense](config.Mongoconn(), berulang.RecurringCollection, filter)
+	if err != nil {
+		respon.Error(respw, http.StatusNotFound, "Pengeluaran rutin tidak ditemukan", "")
+		return
+	}
+
+	// Jadwal dihitung ulang dari start_date baru tanpa mengulang jadwal yang sudah dibuat
+	from := recurring.StartDate
+	if existing.NextDate.After(from) {
+		from = existing.NextDate
+	}
+	berulang.Reschedule(&recurring, from)
+	update := bson.M{"$set": bson.M{
+		"expense_name":   recurring.ExpenseName,
+		"amount":         recurring.Amount,
+		"tax_amount":     recurring.TaxAmount,
+		"category":       recurring.Category,
+		"payment_method": recurring.PaymentMethod,
+		"notes":          recurring.Notes,
+		"frequency":      recurring.Frequency,
+		"start_date":     recurring.StartDate,
+		"end_date":       recurring.EndDate,
+		"next_date":      recurring.NextDate,
+		"occurrence":     recurring.Occurrence,
+		"notify_phone":   recurring.NotifyPhone,
+		"active":         recurring.Active,
+		"updatedAt":      time.Now(),
+	}}
+	result, err := config.Mongoconn().Collection(berulang.RecurringCollection).UpdateOne(context.TODO(), filter, update)
+	if err != nil {
+		respon.Error(respw, http.StatusInternalServerError, "Gagal mengubah pengeluaran rutin", err.Error())
+		return
+	}
+	if result.MatchedCount == 0 {
+		respon.Error(respw, http.StatusNotFound, "Pengeluaran rutin tidak ditemukan", "")
+		return
+	}
+
+	respon.Success(respw, http.StatusOK, "Pengeluaran rutin berhasil diubah", update["$set"])
+}
+
+// Handler untuk menghapus template pengeluaran rutin: ?id=, pengeluaran yang sudah dibuat tetap tersimpan
+func DeleteRecurringExpense(respw http.ResponseWriter, req *http.Request) {
+	owner, ok := getOwner(respw, req)
+	if !ok {
+		return
+	}
+	objectID, err := primitive.ObjectIDFromHex(req.URL.Query().Get("id"))
+	if err != nil {
+		respon.Error(respw, http.StatusBadRequest, "ID pengeluaran rutin tidak valid", "")
+		return
+	}
+	result, err := atdb.DeleteOneDoc(config.Mongoconn(), berulang.RecurringCollection, bson.M{"_id": objectID, "owner": owner})
+	if err != nil {
+		respon.Error(respw, http.StatusInternalServerError, "Gagal menghapus pengeluaran rutin", err.Error())
+		return
+	}
+	if result.DeletedCount == 0 {
+		respon.Error(respw, http.StatusNotFound, "Pengeluaran rutin tidak ditemukan", "")
+		return
+	}
+
+	respon.Success(respw, http.StatusOK, "Pengeluaran rutin berhasil dihapus", nil)
+}
+
+// Handler untuk pratinjau jadwal pengeluaran rutin yang akan dibuat: ?days= (default 30) dan opsional ?id=
+func GetUpcomingRecurringExpenses(respw http.ResponseWriter, req *http.Request) {
+	owner, ok := getOwner(respw, req)
+	if !ok {
+		return
+	}
+	days := berulang.DefaultPreviewDays
+	if s := req.URL.Query().Get("days"); s != "" {
+		parsed, err := strconv.Atoi(s)
+		if err != nil || parsed <= 0 {
+			respon.Error(respw, http.StatusBadRequest, "days harus bilangan bulat positif", "")
+			return
+		}
+		days = parsed
+	}
+	filter := bson.M{"owner": owner, "active": true}
+	if id := req.URL.Query().Get("id"); id != "" {
+		objectID, err := primitive.ObjectIDFromHex(id)
+		if err != nil {
+			respon.Error(respw, http.StatusBadRequest, "ID pengeluaran rutin tidak valid", "")
+			return
+		}
+		filter["_id"] = objectID
+	}
+	templates, err := atdb.GetAllDoc[[]model.RecurringExpense](config.Mongoconn(), berulang.RecurringCollection, filter)
+	if err != nil {
+		respon.Error(respw, http.StatusInternalServerError, "Gagal mengambil pengeluaran rutin", err.Error())
+		return
+	}
+
+	until := time.Now().AddDate(0, 0, days)
+	upcoming := []berulang.Upcoming{}
+	for _, r := range templates {
+		upcoming = append(upcoming, berulang.Preview(r, until)...)
+	}
+	sort.SliceStable(upcoming, func(i, j int) bool { return upcoming[i].Date.Before(upcoming[j].Date) })
+	respon.OK(respw, upcoming)
+}
+
+// Handler untuk melewati satu jadwal pengeluaran rutin yang akan datang: ?id= dengan body {date}
+func SkipRecurringExpense(respw http.ResponseWriter, req *http.Request) {
+	owner, ok := getOwner(respw, req)
+	if !ok {
+		return
+	}
+	objectID, err := primitive.ObjectIDFromHex(req.URL.Query().Get("id"))
+	if err != nil {
+		respon.Error(respw, http.StatusBadRequest, "ID pengeluaran rutin tidak valid", "")
+		return
+	}
+	var request skipRequest
+	if !respon.Decode(respw, req, &request) {
+		return
+	}
+	date, err := time.Parse("2006-01-02", request.Date)
+	if err != nil {
+		respon.Error(respw, http.StatusBadRequest, "Format date harus YYYY-MM-DD", err.Error())
+		return
+	}
+
+	recurring, err := berulang.Skip(config.Mongoconn(), owner, objectID, date)
+	if err == mongo.ErrNoDocuments {
+		respon.Error(respw, http.StatusNotFound, "Pengeluaran rutin tidak ditemukan", "")
+		return
+	}
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err == berulang.ErrNotScheduled {
+			status = http.StatusBadRequest
+		}
+		respon.Error(respw, status, "Gagal melewati jadwal pengeluaran rutin", err.Error())
+		return
+	}
+
+	respon.Success(respw, http.StatusOK, "Jadwal "+request.Date+" dilewati", recurring)
+}
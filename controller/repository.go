@@ -0,0 +1,9 @@
+package controller
+
+import (
+	"github.com/gocroot/config"
+	"github.com/gocroot/helper/repo"
+)
+
+// repos adalah repository data yang dipakai handler, pengujian bisa menggantinya dengan repo.NewFake()
+var repos = repo.Mongo(config.Mongoconn)
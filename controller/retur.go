@@ -0,0 +1,135 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/gocroot/config"
+	"github.com/gocroot/helper/atdb"
+	"github.com/gocroot/helper/audit"
+	"github.com/gocroot/helper/invoice"
+	"github.com/gocroot/helper/paging"
+	"github.com/gocroot/helper/respon"
+	"github.com/gocroot/helper/retur"
+	"github.com/gocroot/helper/tenant"
+	"github.com/gocroot/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// returnPaging adalah parameter list retur: ?q= pelanggan/nomor, ?from=&to= pada return_date, ?settlement=
+var returnPaging = paging.Options{
+	SearchFields: []string{"customer_name", "return_number", "invoice_number"},
+	DateField:    "return_date",
+	Filters:      map[string]string{"settlement": "settlement"},
+	SortFields:   []string{"return_date", "total_amount"},
+}
+
+// Handler untuk membuat retur penjualan dengan pengembalian uang atau nota kredit
+func CreateSalesReturn(respw http.ResponseWriter, req *http.Request) {
+	owner, ok := getOwner(respw, req)
+	if !ok {
+		return
+	}
+	var request retur.Request
+	if !respon.Decode(respw, req, &request) {
+		return
+	}
+	sale, err := atdb.GetOneDoc[model.SalesTransaction](config.Mongoconn(), retur.SalesCollection, audit.Live(bson.M{"_id": request.SalesID, "owner": owner}))
+	if err != nil {
+		respon.Error(respw, http.StatusNotFound, "Penjualan tidak ditemukan", "")
+		return
+	}
+
+	var createdBy string
+	if s, found := tenant.FromRequest(req); found {
+		createdBy = s.Phone
+	}
+	ret, err := retur.Create(config.Mongoconn(), sale, request, createdBy)
+	if err == retur.ErrConcurrentReturn {
+		respon.Error(respw, http.StatusConflict, "Penjualan sedang diubah", err.Error())
+		return
+	}
+	// Kesalahan sebelum retur diberi ID berasal dari validasi isi retur
+	if err != nil && ret.ID.IsZero() {
+		respon.Error(respw, http.StatusBadRequest, "Retur tidak valid", err.Error())
+		return
+	}
+	if err != nil {
+		respon.Error(respw, http.StatusInternalServerError, "Gagal menyimpan retur", err.Error())
+		return
+	}
+
+	respon.Success(respw, http.StatusCreated, "Retur penjualan berhasil dicatat", ret)
+}
+
+// Handler untuk daftar retur penjualan, ?sales_id= untuk retur satu penjualan
+func GetSalesReturns(respw http.ResponseWriter, req *http.Request) {
+	owner, ok := getOwner(respw, req)
+	if !ok {
+		return
+	}
+	base := bson.M{"owner": owner}
+	if salesID, err := primitive.ObjectIDFromHex(req.URL.Query().Get("sales_id")); err == nil {
+		base["sales_id"] = salesID
+	}
+	q, err := paging.Parse(req.URL.Query(), base, returnPaging)
+	if err != nil {
+		respon.Error(respw, http.StatusBadRequest, "Parameter query tidak valid", err.Error())
+		return
+	}
+	data, meta, err := paging.Find[model.SalesReturn](config.Mongoconn(), retur.ReturnCollection, q)
+	if err != nil {
+		respon.Error(respw, http.StatusInternalServerError, "Gagal mengambil retur", err.Error())
+		return
+	}
+	respon.List(respw, data, meta)
+}
+
+// Handler untuk detail retur berdasarkan ?id=
+func GetSalesReturnByID(respw http.ResponseWriter, req *http.Request) {
+	owner, ok := getOwner(respw, req)
+	if !ok {
+		return
+	}
+	ret, ok := findSalesReturn(respw, req, owner)
+	if !ok {
+		return
+	}
+	respon.OK(respw, ret)
+}
+
+// Handler untuk mengunduh PDF nota kredit atau nota retur: ?id=
+func GetReturnNotePDF(respw http.ResponseWriter, req *http.Request) {
+	owner, ok := getOwner(respw, req)
+	if !ok {
+		return
+	}
+	ret, ok := findSalesReturn(respw, req, owner)
+	if !ok {
+		return
+	}
+	pdf, err := retur.RenderNote(ret, invoice.Store(config.Mongoconn(), owner))
+	if err != nil {
+		respon.Error(respw, http.StatusInternalServerError, "Gagal membuat PDF nota retur", err.Error())
+		return
+	}
+	respw.Header().Set("Content-Type", "application/pdf")
+	respw.Header().Set("Content-Disposition", "inline; filename=nota-"+ret.ID.Hex()+".pdf")
+	respw.WriteHeader(http.StatusOK)
+	respw.Write(pdf)
+}
+
+// findSalesReturn mengambil retur milik owner dari ?id= dan menulis respon error jika gagal
+func findSalesReturn(respw http.ResponseWriter, req *http.Request, owner string) (ret model.SalesReturn, ok bool) {
+	objectID, err := primitive.ObjectIDFromHex(req.URL.Query().Get("id"))
+	if err != nil {
+		respon.Error(respw, http.StatusBadRequest, "ID retur tidak valid", "")
+		return
+	}
+	ret, err = atdb.GetOneDoc[model.SalesReturn](config.Mongoconn(), retur.ReturnCollection, bson.M{"_id": objectID, "owner": owner})
+	if err != nil {
+		respon.Error(respw, http.StatusNotFound, "Retur tidak ditemukan", "")
+		return
+	}
+	return ret, true
+}
@@ -1,171 +1,368 @@
 package controller
 
 import (
-	"encoding/json"
+	"context"
 	"net/http"
 
 	"github.com/gocroot/config"
-	"github.com/gocroot/helper/at"
-	"github.com/gocroot/helper/atdb"
+	"github.com/gocroot/helper/audit"
+	"github.com/gocroot/helper/ekspor"
+	"github.com/gocroot/helper/invoice"
+	"github.com/gocroot/helper/ledger"
+	"github.com/gocroot/helper/logger"
+	"github.com/gocroot/helper/paging"
+	"github.com/gocroot/helper/piutang"
+	"github.com/gocroot/helper/respon"
+	"github.com/gocroot/helper/stok"
 	"github.com/gocroot/model"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
 	"time"
 )
 
 // Fungsi untuk menambahkan transaksi penjualan baru
 func CreateSalesTransaction(respw http.ResponseWriter, req *http.Request) {
+	owner, ok := getOwner(respw, req)
+	if !ok {
+		return
+	}
 	var transaction model.SalesTransaction
-	if err := json.NewDecoder(req.Body).Decode(&transaction); err != nil {
-		var respn model.Response
-		respn.Status = "Error: Bad Request"
-		respn.Response = err.Error()
-		at.WriteJSON(respw, http.StatusBadRequest, respn)
+	if !respon.Decode(respw, req, &transaction) {
 		return
 	}
 
+	transaction.ID = primitive.NewObjectID()
+	transaction.Owner = owner
 	transaction.TransactionDate = time.Now()
+	transaction.Payments = nil
+	if err := applySalesTax(owner, &transaction); err != nil {
+		respon.Error(respw, http.StatusInternalServerError, "Gagal menghitung PPN", err.Error())
+		return
+	}
+	transaction.PaidAmount = transaction.TotalAmount
+	if ledger.IsCredit(transaction.PaymentStatus) {
+		transaction.PaidAmount = 0
+	}
+	if !saveSale(respw, req, &transaction) {
+		return
+	}
+	if !logAudit(respw, req, owner, audit.EntitySales, transaction.ID, audit.ActionCreate, nil, transaction) {
+		return
+	}
 
-	_, err := atdb.InsertOneDoc(config.Mongoconn, "transaksi_penjualan", transaction)
+	respon.Success(respw, http.StatusCreated, "Transaksi berhasil ditambahkan", transaction)
+}
+
+// saveSale mengurangi stok, memberi nomor faktur, menyimpan penjualan dan memposting jurnalnya.
+// Jika penyimpanan gagal stok dikembalikan dan nomor faktur dilepas, jika posting jurnal gagal penjualan dibatalkan.
+// Pada keduanya respon error ditulis dan hasilnya false.
+func saveSale(respw http.ResponseWriter, req *http.Request, transaction *model.SalesTransaction) bool {
+	// Kurangi stok semua item secara atomik sebelum transaksi disimpan
+	shortages, err := stok.ReserveSale(config.Mongoconn(), transaction)
+	if err == stok.ErrInsufficientStock {
+		respon.FailData(respw, http.StatusConflict, respon.CodeInsufficientStock, "Stok tidak mencukupi", "Kurangi jumlah item atau kirim allow_backorder=true", shortages)
+		return false
+	}
+	if err == mongo.ErrNoDocuments {
+		respon.Error(respw, http.StatusNotFound, "Produk pada transaksi tidak ditemukan", err.Error())
+		return false
+	}
 	if err != nil {
-		var respn model.Response
-		respn.Status = "Error: Gagal Insert Database"
-		respn.Response = err.Error()
-		at.WriteJSON(respw, http.StatusInternalServerError, respn)
-		return
+		respon.Error(respw, http.StatusInternalServerError, "Gagal mengurangi stok", err.Error())
+		return false
+	}
+
+	transaction.InvoiceNumber, err = invoice.NextNumber(config.Mongoconn(), transaction.Owner, transaction.TransactionDate)
+	if err == nil {
+		err = repos.Sales.Insert(req.Context(), *transaction)
+	}
+	if err != nil {
+		stok.RestockSale(config.Mongoconn(), *transaction, "Pembatalan: gagal menyimpan penjualan")
+		releaseInvoiceNumber(req, transaction)
+		respon.Error(respw, http.StatusInternalServerError, "Gagal Insert Database", err.Error())
+		return false
 	}
 
-	response := map[string]interface{}{
-		"status":  "success",
-		"message": "Transaksi berhasil ditambahkan",
-		"data":    transaction,
+	// Posting jurnal penjualan ke buku besar. Jika gagal penjualan dibatalkan lewat soft delete agar nomor fakturnya
+	// tetap tercatat, dan stok dikembalikan supaya permintaan ulang dari klien tidak menjual dua kali.
+	if _, err = ledger.Post(config.Mongoconn(), ledger.SalesEntry(*transaction)); err != nil {
+		audit.SoftDelete[model.SalesTransaction](config.Mongoconn(), auditActor(req, transaction.Owner), audit.EntitySales, transaction.ID)
+		stok.RestockSale(config.Mongoconn(), *transaction, "Pembatalan: gagal posting jurnal penjualan")
+		respon.Error(respw, http.StatusInternalServerError, "Gagal posting jurnal penjualan", err.Error())
+		return false
+	}
+	return true
+}
+
+// releaseInvoiceNumber mengembalikan nomor faktur penjualan yang gagal disimpan. Jika sudah terpakai nomor yang lebih baru,
+// nomor tersebut menjadi celah sehingga dicatat ke audit log sebagai void.
+func releaseInvoiceNumber(req *http.Request, transaction *model.SalesTransaction) {
+	if transaction.InvoiceNumber == "" {
+		return
+	}
+	released, err := invoice.ReleaseNumber(config.Mongoconn(), transaction.Owner, transaction.TransactionDate, transaction.InvoiceNumber)
+	if err != nil || !released {
+		logger.FromContext(req.Context()).Warn("nomor faktur terlewat", "invoice_number", transaction.InvoiceNumber, "sales_id", transaction.ID.Hex())
+		audit.Log(config.Mongoconn(), auditActor(req, transaction.Owner), audit.EntitySales, transaction.ID, audit.ActionVoid, nil, bson.M{"invoice_number": transaction.InvoiceNumber})
 	}
-	at.WriteJSON(respw, http.StatusOK, response)
+	transaction.InvoiceNumber = ""
+}
+
+// salesPaging adalah parameter list penjualan: ?q= pelanggan/nomor faktur, ?from=&to= pada transactionDate,
+// ?payment_method=, ?payment_status=
+var salesPaging = paging.Options{
+	SearchFields: []string{"customer_name", "invoice_number"},
+	DateField:    "transactionDate",
+	Filters:      map[string]string{"payment_method": "payment_method", "payment_status": "payment_status"},
+	SortFields:   []string{"transactionDate", "total_amount", "customer_name"},
 }
 
 // Fungsi untuk mendapatkan semua transaksi penjualan
 func GetSalesTransactions(respw http.ResponseWriter, req *http.Request) {
-	data, err := atdb.GetAllDoc[[]model.SalesTransaction](config.Mongoconn, "transaksi_penjualan", bson.M{})
+	owner, ok := getOwner(respw, req)
+	if !ok {
+		return
+	}
+	q, err := paging.Parse(req.URL.Query(), audit.Live(bson.M{"owner": owner}), salesPaging)
 	if err != nil {
-		var respn model.Response
-		respn.Status = "Error: Data transaksi tidak ditemukan"
-		respn.Response = err.Error()
-		at.WriteJSON(respw, http.StatusNotFound, respn)
+		respon.Error(respw, http.StatusBadRequest, "Parameter query tidak valid", err.Error())
+		return
+	}
+	data, meta, err := repos.Sales.Find(req.Context(), q)
+	if err != nil {
+		respon.Error(respw, http.StatusInternalServerError, "Gagal mengambil data transaksi", err.Error())
 		return
 	}
 
-	at.WriteJSON(respw, http.StatusOK, data)
+	respon.List(respw, data, meta)
 }
 
 // Fungsi untuk mendapatkan transaksi penjualan berdasarkan ID
 func GetSalesTransactionByID(respw http.ResponseWriter, req *http.Request) {
-	transactionID := req.URL.Query().Get("id")
+	owner, ok := getOwner(respw, req)
+	if !ok {
+		return
+	}
+	transactionID := idParam(req)
 	if transactionID == "" {
-		var respn model.Response
-		respn.Status = "Error: ID Transaksi tidak ditemukan"
-		at.WriteJSON(respw, http.StatusBadRequest, respn)
+		respon.Error(respw, http.StatusBadRequest, "ID Transaksi tidak ditemukan", "")
 		return
 	}
 
 	objectID, err := primitive.ObjectIDFromHex(transactionID)
 	if err != nil {
-		var respn model.Response
-		respn.Status = "Error: ID Transaksi tidak valid"
-		at.WriteJSON(respw, http.StatusBadRequest, respn)
+		respon.Error(respw, http.StatusBadRequest, "ID Transaksi tidak valid", "")
 		return
 	}
 
-	var transaction model.SalesTransaction
-	filter := bson.M{"_id": objectID}
-	_, err = atdb.GetOneDoc[model.SalesTransaction](config.Mongoconn, "transaksi_penjualan", filter)
+	filter := audit.Live(bson.M{"_id": objectID, "owner": owner})
+	transaction, err := repos.Sales.Get(req.Context(), filter)
 	if err != nil {
-		var respn model.Response
-		respn.Status = "Error: Transaksi tidak ditemukan"
-		at.WriteJSON(respw, http.StatusNotFound, respn)
+		respon.Error(respw, http.StatusNotFound, "Transaksi tidak ditemukan", "")
 		return
 	}
 
-	at.WriteJSON(respw, http.StatusOK, transaction)
+	respon.OK(respw, transaction)
 }
 
 // Fungsi untuk mengupdate transaksi penjualan berdasarkan ID
 func UpdateSalesTransaction(respw http.ResponseWriter, req *http.Request) {
-	transactionID := req.URL.Query().Get("id")
+	owner, ok := getOwner(respw, req)
+	if !ok {
+		return
+	}
+	transactionID := idParam(req)
 	if transactionID == "" {
-		var respn model.Response
-		respn.Status = "Error: ID Transaksi tidak ditemukan"
-		at.WriteJSON(respw, http.StatusBadRequest, respn)
+		respon.Error(respw, http.StatusBadRequest, "ID Transaksi tidak ditemukan", "")
 		return
 	}
 
 	objectID, err := primitive.ObjectIDFromHex(transactionID)
 	if err != nil {
-		var respn model.Response
-		respn.Status = "Error: ID Transaksi tidak valid"
-		at.WriteJSON(respw, http.StatusBadRequest, respn)
+		respon.Error(respw, http.StatusBadRequest, "ID Transaksi tidak valid", "")
 		return
 	}
 
 	var updatedTransaction model.SalesTransaction
-	if err := json.NewDecoder(req.Body).Decode(&updatedTransaction); err != nil {
-		var respn model.Response
-		respn.Status = "Error: Bad Request"
-		respn.Response = err.Error()
-		at.WriteJSON(respw, http.StatusBadRequest, respn)
+	if !respon.Decode(respw, req, &updatedTransaction) {
 		return
 	}
 
-	update := bson.M{"$set": updatedTransaction}
-	filter := bson.M{"_id": objectID}
-	_, err = atdb.UpdateOneDoc(config.Mongoconn, "transaksi_penjualan", filter, update)
+	filter := audit.Live(bson.M{"_id": objectID, "owner": owner})
+	existing, err := repos.Sales.Get(req.Context(), filter)
 	if err != nil {
-		var respn model.Response
-		respn.Status = "Error: Gagal mengupdate transaksi"
-		respn.Response = err.Error()
-		at.WriteJSON(respw, http.StatusInternalServerError, respn)
+		respon.Error(respw, http.StatusNotFound, "Transaksi tidak ditemukan", "")
+		return
+	}
+
+	if existing.ReturnedAmount > 0 {
+		respon.Error(respw, http.StatusConflict, "Penjualan yang sudah diretur tidak bisa diubah", "")
+		return
+	}
+	if !periodOpen(respw, owner, existing.TransactionDate) {
 		return
 	}
 
-	response := map[string]interface{}{
-		"status":  "success",
-		"message": "Transaksi berhasil diupdate",
-		"data":    updatedTransaction,
+	if err = applySalesTax(owner, &updatedTransaction); err != nil {
+		respon.Error(respw, http.StatusInternalServerError, "Gagal menghitung PPN", err.Error())
+		return
+	}
+
+	update := bson.M{
+		"customer_name":      updatedTransaction.CustomerName,
+		"total_amount":       updatedTransaction.TotalAmount,
+		"tax_amount":         updatedTransaction.TaxAmount,
+		"tax_lines":          updatedTransaction.TaxLines,
+		"price_includes_tax": updatedTransaction.PriceIncludesTax,
+		"payment_method":     updatedTransaction.PaymentMethod,
+		"payment_status":     updatedTransaction.PaymentStatus,
+	}
+	if !updatedTransaction.DueDate.IsZero() {
+		update["due_date"] = updatedTransaction.DueDate
+	}
+	if len(existing.Payments) > 0 {
+		// Status penjualan yang sudah dicicil mengikuti total cicilan, bukan input
+		if ledger.Round2(updatedTransaction.TotalAmount) < existing.PaidAmount {
+			respon.Error(respw, http.StatusBadRequest, "Total transaksi lebih kecil dari yang sudah dibayar", "")
+			return
+		}
+		update["payment_status"] = piutang.Status(updatedTransaction.TotalAmount, existing.PaidAmount)
+	} else if ledger.IsCredit(updatedTransaction.PaymentStatus) {
+		update["paid_amount"] = 0
+	} else {
+		update["paid_amount"] = updatedTransaction.TotalAmount
 	}
-	at.WriteJSON(respw, http.StatusOK, response)
+
+	// Pindahkan stok sebesar selisih kuantitas item lama dan baru
+	updatedTransaction.ID = objectID
+	updatedTransaction.Owner = owner
+	shortages, err := stok.AdjustSale(config.Mongoconn(), existing, &updatedTransaction)
+	if err == stok.ErrInsufficientStock {
+		respon.FailData(respw, http.StatusConflict, respon.CodeInsufficientStock, "Stok tidak mencukupi", "Kurangi jumlah item atau kirim allow_backorder=true", shortages)
+		return
+	}
+	if err != nil {
+		respon.Error(respw, http.StatusInternalServerError, "Gagal menyesuaikan stok", err.Error())
+		return
+	}
+	update["products"] = updatedTransaction.Products
+	if updatedTransaction.Backorder {
+		update["backorder"] = true
+	}
+	_, err = repos.Sales.Update(req.Context(), filter, bson.M{"$set": update})
+	if err != nil {
+		revertSaleStock(existing, updatedTransaction)
+		respon.Error(respw, http.StatusInternalServerError, "Gagal mengupdate transaksi", err.Error())
+		return
+	}
+
+	// Balik jurnal lama lalu posting ulang sesuai data terbaru
+	stored, err := repos.Sales.Get(req.Context(), filter)
+	if err == nil {
+		err = ledger.Repost(config.Mongoconn(), ledger.SalesEntry(stored))
+	}
+	if err != nil {
+		// Kembalikan isi penjualan, stok dan jurnal lama agar tetap sesuai satu sama lain
+		revertSale(req.Context(), filter, update, existing)
+		revertSaleStock(existing, updatedTransaction)
+		ledger.Repost(config.Mongoconn(), ledger.SalesEntry(existing))
+		respon.Error(respw, http.StatusInternalServerError, "Gagal posting ulang jurnal penjualan", err.Error())
+		return
+	}
+	if !logAudit(respw, req, owner, audit.EntitySales, objectID, audit.ActionUpdate, existing, stored) {
+		return
+	}
+	updatedTransaction = stored
+
+	respon.Success(respw, http.StatusOK, "Transaksi berhasil diupdate", updatedTransaction)
+}
+
+// revertSale mengembalikan field yang diubah update ke isi penjualan sebelum diubah
+func revertSale(ctx context.Context, filter, update bson.M, existing model.SalesTransaction) {
+	before := audit.Snapshot(existing)
+	set, unset := bson.M{}, bson.M{}
+	for field := range update {
+		if v, ok := before[field]; ok {
+			set[field] = v
+		} else {
+			unset[field] = ""
+		}
+	}
+	change := bson.M{"$set": set}
+	if len(unset) > 0 {
+		change["$unset"] = unset
+	}
+	repos.Sales.Update(ctx, filter, change)
+}
+
+// revertSaleStock memindahkan stok kembali dari item baru ke item penjualan sebelum diubah
+func revertSaleStock(existing, updated model.SalesTransaction) {
+	restore := existing
+	restore.AllowBackorder = true
+	stok.AdjustSale(config.Mongoconn(), updated, &restore)
 }
 
 // Fungsi untuk menghapus transaksi penjualan berdasarkan ID
 func DeleteSalesTransaction(respw http.ResponseWriter, req *http.Request) {
-	transactionID := req.URL.Query().Get("id")
+	owner, ok := getOwner(respw, req)
+	if !ok {
+		return
+	}
+	transactionID := idParam(req)
 	if transactionID == "" {
-		var respn model.Response
-		respn.Status = "Error: ID Transaksi tidak ditemukan"
-		at.WriteJSON(respw, http.StatusBadRequest, respn)
+		respon.Error(respw, http.StatusBadRequest, "ID Transaksi tidak ditemukan", "")
 		return
 	}
 
 	objectID, err := primitive.ObjectIDFromHex(transactionID)
 	if err != nil {
-		var respn model.Response
-		respn.Status = "Error: ID Transaksi tidak valid"
-		at.WriteJSON(respw, http.StatusBadRequest, respn)
+		respon.Error(respw, http.StatusBadRequest, "ID Transaksi tidak valid", "")
+		return
+	}
+
+	filter := audit.Live(bson.M{"_id": objectID, "owner": owner})
+	transaction, err := repos.Sales.Get(req.Context(), filter)
+	if err != nil {
+		respon.Error(respw, http.StatusNotFound, "Transaksi tidak ditemukan", "")
+		return
+	}
+	if transaction.ReturnedAmount > 0 {
+		respon.Error(respw, http.StatusConflict, "Penjualan yang sudah diretur tidak bisa dihapus", "")
+		return
+	}
+	if !periodOpen(respw, owner, transaction.TransactionDate) {
 		return
 	}
 
-	filter := bson.M{"_id": objectID}
-	deleteResult, err := atdb.DeleteOneDoc(config.Mongoconn, "transaksi_penjualan", filter)
+	// Tandai penjualan sebagai terhapus agar nomor faktur tetap tercatat, lalu balik jurnal dan stoknya
+	transaction, err = audit.SoftDelete[model.SalesTransaction](config.Mongoconn(), auditActor(req, owner), audit.EntitySales, objectID)
+	if err == mongo.ErrNoDocuments {
+		respon.Error(respw, http.StatusNotFound, "Transaksi tidak ditemukan", "")
+		return
+	}
 	if err != nil {
-		var respn model.Response
-		respn.Status = "Error: Gagal menghapus transaksi"
-		respn.Response = err.Error()
-		at.WriteJSON(respw, http.StatusInternalServerError, respn)
+		respon.Error(respw, http.StatusInternalServerError, "Gagal menghapus transaksi", err.Error())
 		return
 	}
 
-	response := map[string]interface{}{
-		"status":  "success",
-		"message": "Transaksi berhasil dihapus",
-		"data":    deleteResult,
+	err = ledger.ReverseSameDate(config.Mongoconn(), ledger.SourceSales, objectID)
+	if err == nil {
+		err = ledger.ReverseSameDate(config.Mongoconn(), ledger.SourceReceipt, objectID)
+	}
+	if err != nil {
+		respon.Error(respw, http.StatusInternalServerError, "Gagal membalik jurnal penjualan", err.Error())
+		return
 	}
-	at.WriteJSON(respw, http.StatusOK, response)
+	if err = stok.RestockSale(config.Mongoconn(), transaction, "Penjualan dihapus"); err != nil {
+		respon.Error(respw, http.StatusInternalServerError, "Gagal mengembalikan stok", err.Error())
+		return
+	}
+
+	respon.Success(respw, http.StatusOK, "Transaksi berhasil dihapus", transaction)
+}
+
+// Fungsi untuk mengekspor transaksi penjualan ke CSV, opsional ?from=&to= dan ?format=xlsx
+func ExportSalesToCSV(respw http.ResponseWriter, req *http.Request) {
+	exportEntity(respw, req, ekspor.Sales)
 }
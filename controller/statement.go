@@ -0,0 +1,127 @@
+package controller
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gocroot/config"
+	"github.com/gocroot/helper/atdb"
+	"github.com/gocroot/helper/keuangan"
+	"github.com/gocroot/helper/respon"
+	"github.com/gocroot/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Handler untuk neraca per tanggal: ?asof=YYYY-MM-DD, default hari ini
+func GetBalanceSheet(respw http.ResponseWriter, req *http.Request) {
+	owner, ok := getOwner(respw, req)
+	if !ok {
+		return
+	}
+	asOf := req.URL.Query().Get("asof")
+	if asOf == "" {
+		asOf = time.Now().Format("2006-01-02")
+	}
+
+	sheet, err := keuangan.BalanceSheetAsOf(config.Mongoconn(), owner, asOf)
+	if err != nil {
+		respon.Error(respw, http.StatusBadRequest, "Gagal menghitung neraca", err.Error())
+		return
+	}
+
+	respon.Success(respw, http.StatusOK, "Neraca", sheet)
+}
+
+// Handler untuk laporan arus kas: ?startDate=YYYY-MM-DD&endDate=YYYY-MM-DD
+func GetCashFlow(respw http.ResponseWriter, req *http.Request) {
+	owner, ok := getOwner(respw, req)
+	if !ok {
+		return
+	}
+	cf, err := keuangan.CashFlow(config.Mongoconn(), owner, req.URL.Query().Get("startDate"), req.URL.Query().Get("endDate"))
+	if err != nil {
+		respon.Error(respw, http.StatusBadRequest, "Gagal menghitung arus kas", err.Error())
+		return
+	}
+
+	respon.Success(respw, http.StatusOK, "Laporan arus kas", cf)
+}
+
+// Handler untuk menyimpan snapshot neraca atau arus kas
+func CreateStatementSnapshot(respw http.ResponseWriter, req *http.Request) {
+	owner, ok := getOwner(respw, req)
+	if !ok {
+		return
+	}
+	var request model.FinancialStatementSnapshot
+	if !respon.Decode(respw, req, &request) {
+		return
+	}
+
+	snap, err := keuangan.BuildSnapshot(config.Mongoconn(), owner, request.Type, request.StartDate, request.EndDate)
+	if err != nil {
+		respon.Error(respw, http.StatusBadRequest, "Gagal menghitung laporan", err.Error())
+		return
+	}
+	snap.ID = primitive.NewObjectID()
+	snap.CreatedAt = time.Now()
+
+	if _, err = atdb.InsertOneDoc(config.Mongoconn(), keuangan.SnapshotCollection, snap); err != nil {
+		respon.Error(respw, http.StatusInternalServerError, "Gagal Insert Database", err.Error())
+		return
+	}
+
+	respon.Success(respw, http.StatusCreated, "Snapshot laporan berhasil disimpan", snap)
+}
+
+// Handler untuk daftar snapshot, opsional ?type=balance_sheet|cash_flow
+func GetStatementSnapshots(respw http.ResponseWriter, req *http.Request) {
+	owner, ok := getOwner(respw, req)
+	if !ok {
+		return
+	}
+	filter := bson.M{"owner": owner}
+	if t := req.URL.Query().Get("type"); t != "" {
+		filter["type"] = t
+	}
+	data, err := atdb.GetAllDoc[[]model.FinancialStatementSnapshot](config.Mongoconn(), keuangan.SnapshotCollection, filter)
+	if err != nil {
+		respon.Error(respw, http.StatusInternalServerError, "Gagal mengambil data snapshot", err.Error())
+		return
+	}
+	respon.OK(respw, data)
+}
+
+// Handler untuk membandingkan snapshot tersimpan dengan hasil hitung ulang: ?id=
+func CompareStatementSnapshot(respw http.ResponseWriter, req *http.Request) {
+	owner, ok := getOwner(respw, req)
+	if !ok {
+		return
+	}
+	objectID, err := primitive.ObjectIDFromHex(req.URL.Query().Get("id"))
+	if err != nil {
+		respon.Error(respw, http.StatusBadRequest, "ID Snapshot tidak valid", "")
+		return
+	}
+
+	saved, err := atdb.GetOneDoc[model.FinancialStatementSnapshot](config.Mongoconn(), keuangan.SnapshotCollection, bson.M{"_id": objectID, "owner": owner})
+	if err != nil {
+		respon.Error(respw, http.StatusNotFound, "Snapshot tidak ditemukan", "")
+		return
+	}
+
+	current, err := keuangan.BuildSnapshot(config.Mongoconn(), owner, saved.Type, saved.StartDate, saved.EndDate)
+	if err != nil {
+		respon.Error(respw, http.StatusInternalServerError, "Gagal menghitung ulang laporan", err.Error())
+		return
+	}
+
+	diff := keuangan.CompareSnapshot(saved, current)
+	respon.Success(respw, http.StatusOK, "Perbandingan snapshot", map[string]interface{}{
+		"snapshot":    saved,
+		"current":     current,
+		"differences": diff,
+		"changed":     len(diff) > 0,
+	})
+}
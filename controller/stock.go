@@ -0,0 +1,102 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/gocroot/config"
+	"github.com/gocroot/helper/paging"
+	"github.com/gocroot/helper/respon"
+	"github.com/gocroot/helper/router"
+	"github.com/gocroot/helper/stok"
+	"github.com/gocroot/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Fungsi untuk mencatat mutasi stok manual (pembelian, penyesuaian, retur, stok awal)
+func CreateStockMovement(respw http.ResponseWriter, req *http.Request) {
+	owner, ok := getOwner(respw, req)
+	if !ok {
+		return
+	}
+	var request struct {
+		ProductID string `json:"product_id"`
+		Type      string `json:"type" validate:"required"`
+		Quantity  int    `json:"quantity" validate:"required"` // Positif menambah stok, negatif mengurangi
+		Notes     string `json:"notes"`
+	}
+	if !respon.Decode(respw, req, &request) {
+		return
+	}
+
+	// Produk bisa dari path /products/{id}/stock atau dari body
+	if id := router.Param(req, "id"); id != "" {
+		request.ProductID = id
+	}
+	objectID, err := primitive.ObjectIDFromHex(request.ProductID)
+	if err != nil {
+		respon.Error(respw, http.StatusBadRequest, "ID Produk tidak valid", "")
+		return
+	}
+	if request.Type == stok.MoveSale || !stok.IsValidType(request.Type) {
+		respon.Error(respw, http.StatusBadRequest, "Jenis mutasi tidak valid", "Gunakan purchase, adjustment, return atau opening. Mutasi sale dibuat dari transaksi penjualan")
+		return
+	}
+
+	movement, err := stok.Move(config.Mongoconn(), owner, objectID, request.Quantity, request.Type, "manual", primitive.NilObjectID, request.Notes)
+	if err != nil {
+		switch err {
+		case stok.ErrInsufficientStock:
+			respon.Fail(respw, http.StatusConflict, respon.CodeInsufficientStock, "Stok tidak mencukupi", err.Error())
+		case mongo.ErrNoDocuments:
+			respon.Error(respw, http.StatusNotFound, "Produk tidak ditemukan", err.Error())
+		default:
+			respon.Error(respw, http.StatusBadRequest, "Gagal mencatat mutasi stok", err.Error())
+		}
+		return
+	}
+
+	respon.Success(respw, http.StatusCreated, "Mutasi stok berhasil dicatat", movement)
+}
+
+// movementPaging adalah parameter list mutasi stok: ?q= nama produk/catatan, ?from=&to=, ?type=
+var movementPaging = paging.Options{
+	SearchFields: []string{"product_name", "notes"},
+	DateField:    "createdAt",
+	Filters:      map[string]string{"type": "type"},
+	SortFields:   []string{"createdAt", "quantity"},
+}
+
+// Fungsi untuk mendapatkan riwayat mutasi stok, opsional ?product_id= atau /products/{id}/stock
+func GetStockMovements(respw http.ResponseWriter, req *http.Request) {
+	owner, ok := getOwner(respw, req)
+	if !ok {
+		return
+	}
+	filter := bson.M{"owner": owner}
+	productID := req.URL.Query().Get("product_id")
+	if id := router.Param(req, "id"); id != "" {
+		productID = id
+	}
+	if productID != "" {
+		objectID, err := primitive.ObjectIDFromHex(productID)
+		if err != nil {
+			respon.Error(respw, http.StatusBadRequest, "ID Produk tidak valid", "")
+			return
+		}
+		filter["product_id"] = objectID
+	}
+
+	q, err := paging.Parse(req.URL.Query(), filter, movementPaging)
+	if err != nil {
+		respon.Error(respw, http.StatusBadRequest, "Parameter query tidak valid", err.Error())
+		return
+	}
+	data, meta, err := paging.Find[model.StockMovement](config.Mongoconn(), stok.MovementCollection, q)
+	if err != nil {
+		respon.Error(respw, http.StatusInternalServerError, "Gagal mengambil data mutasi stok", err.Error())
+		return
+	}
+	respon.List(respw, data, meta)
+}
@@ -0,0 +1,223 @@
+package controller
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gocroot/config"
+	"github.com/gocroot/helper/atdb"
+	"github.com/gocroot/helper/paging"
+	"github.com/gocroot/helper/pembelian"
+	"github.com/gocroot/helper/respon"
+	"github.com/gocroot/model"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// CreateSupplier handles creating a new supplier
+func CreateSupplier(w http.ResponseWriter, r *http.Request) {
+	owner, ok := getOwner(w, r)
+	if !ok {
+		return
+	}
+	var supplier model.Supplier
+
+	// Decode data supplier dari body permintaan
+	if !respon.Decode(w, r, &supplier) {
+		return
+	}
+
+	// Inisialisasi data supplier baru dengan ObjectID untuk ID
+	newSupplier := model.Supplier{
+		ID:            primitive.NewObjectID(),
+		Owner:         owner,
+		Name:          supplier.Name,
+		ContactPerson: supplier.ContactPerson,
+		Email:         supplier.Email,
+		Phone:         supplier.Phone,
+		Address:       supplier.Address,
+		CreatedAt:     time.Now(),
+		UpdatedAt:     time.Now(),
+	}
+
+	// Insert supplier ke dalam MongoDB
+	if err := repos.Suppliers.Insert(r.Context(), newSupplier); err != nil {
+		respon.Error(w, http.StatusInternalServerError, "Gagal Insert Database", err.Error())
+		return
+	}
+
+	// Kirim respon sukses
+	respon.Success(w, http.StatusCreated, "Supplier berhasil ditambahkan", newSupplier)
+}
+
+// supplierPaging adalah parameter list supplier: ?q= nama/kontak/telepon, ?sort=name|createdAt
+var supplierPaging = paging.Options{
+	SearchFields: []string{"name", "contact_person", "phone"},
+	SortFields:   []string{"name", "createdAt"},
+}
+
+// GetSuppliers handles retrieving all suppliers
+func GetSuppliers(w http.ResponseWriter, r *http.Request) {
+	owner, ok := getOwner(w, r)
+	if !ok {
+		return
+	}
+	q, err := paging.Parse(r.URL.Query(), bson.M{"owner": owner}, supplierPaging)
+	if err != nil {
+		respon.Error(w, http.StatusBadRequest, "Parameter query tidak valid", err.Error())
+		return
+	}
+	// Ambil satu halaman data supplier dari MongoDB
+	data, meta, err := repos.Suppliers.Find(r.Context(), q)
+	if err != nil {
+		respon.Error(w, http.StatusInternalServerError, "Gagal mengambil data supplier", err.Error())
+		return
+	}
+
+	// Kirim data supplier sebagai respon
+	respon.List(w, data, meta)
+}
+
+// GetSupplierByID handles retrieving a supplier by ID
+func GetSupplierByID(w http.ResponseWriter, r *http.Request) {
+	owner, ok := getOwner(w, r)
+	if !ok {
+		return
+	}
+	// Ambil parameter ID dari URL
+	supplierID := r.URL.Query().Get("id")
+	if supplierID == "" {
+		respon.Error(w, http.StatusBadRequest, "ID Supplier tidak ditemukan", "")
+		return
+	}
+
+	// Konversi ID dari string ke ObjectID MongoDB
+	objectID, err := primitive.ObjectIDFromHex(supplierID)
+	if err != nil {
+		respon.Error(w, http.StatusBadRequest, "ID Supplier tidak valid", "")
+		return
+	}
+
+	// Ambil data supplier dari MongoDB
+	supplier, err := repos.Suppliers.Get(r.Context(), bson.M{"_id": objectID, "owner": owner})
+	if err != nil {
+		respon.Error(w, http.StatusNotFound, "Supplier tidak ditemukan", "")
+		return
+	}
+
+	// Kirim data supplier sebagai respon
+	respon.Success(w, http.StatusOK, "Supplier ditemukan", supplier)
+}
+
+// UpdateSupplier handles updating a supplier by ID
+func UpdateSupplier(w http.ResponseWriter, r *http.Request) {
+	owner, ok := getOwner(w, r)
+	if !ok {
+		return
+	}
+	// Ambil parameter ID dari URL
+	supplierID := r.URL.Query().Get("id")
+	if supplierID == "" {
+		respon.Error(w, http.StatusBadRequest, "ID Supplier tidak ditemukan", "")
+		return
+	}
+
+	// Konversi ID dari string ke ObjectID MongoDB
+	objectID, err := primitive.ObjectIDFromHex(supplierID)
+	if err != nil {
+		respon.Error(w, http.StatusBadRequest, "ID Supplier tidak valid", "")
+		return
+	}
+
+	// Decode data supplier yang akan diupdate
+	var requestBody struct {
+		Name          string `json:"name"`
+		ContactPerson string `json:"contact_person"`
+		Email         string `json:"email"`
+		Phone         string `json:"phone"`
+		Address       string `json:"address"`
+	}
+	if !respon.Decode(w, r, &requestBody) {
+		return
+	}
+
+	// Siapkan data untuk update
+	updateData := bson.M{}
+	if requestBody.Name != "" {
+		updateData["name"] = requestBody.Name
+	}
+	if requestBody.ContactPerson != "" {
+		updateData["contact_person"] = requestBody.ContactPerson
+	}
+	if requestBody.Email != "" {
+		updateData["email"] = requestBody.Email
+	}
+	if requestBody.Phone != "" {
+		updateData["phone"] = requestBody.Phone
+	}
+	if requestBody.Address != "" {
+		updateData["address"] = requestBody.Address
+	}
+	updateData["updatedAt"] = time.Now()
+
+	// Update supplier di MongoDB
+	filter := bson.M{"_id": objectID, "owner": owner}
+	matched, err := repos.Suppliers.Update(r.Context(), filter, bson.M{"$set": updateData})
+	if err != nil {
+		respon.Error(w, http.StatusInternalServerError, "Gagal mengupdate supplier", err.Error())
+		return
+	}
+	if matched == 0 {
+		respon.Error(w, http.StatusNotFound, "Supplier tidak ditemukan", "")
+		return
+	}
+
+	// Kirim respon sukses
+	respon.Success(w, http.StatusOK, "Supplier berhasil diupdate", updateData)
+}
+
+// DeleteSupplier handles deleting a supplier by ID
+func DeleteSupplier(w http.ResponseWriter, r *http.Request) {
+	owner, ok := getOwner(w, r)
+	if !ok {
+		return
+	}
+	// Ambil parameter ID dari URL
+	supplierID := r.URL.Query().Get("id")
+	if supplierID == "" {
+		respon.Error(w, http.StatusBadRequest, "ID Supplier tidak ditemukan", "")
+		return
+	}
+
+	// Konversi supplierID dari string ke ObjectID MongoDB
+	objectID, err := primitive.ObjectIDFromHex(supplierID)
+	if err != nil {
+		respon.Error(w, http.StatusBadRequest, "ID Supplier tidak valid", "")
+		return
+	}
+
+	// Supplier yang masih memiliki purchase order tidak boleh dihapus
+	count, err := atdb.GetCountDoc(config.Mongoconn(), pembelian.PurchaseOrderCollection, bson.M{"supplier_id": objectID})
+	if err == nil && count > 0 {
+		respon.Error(w, http.StatusConflict, "Supplier masih memiliki purchase order", "")
+		return
+	}
+
+	// Hapus data supplier berdasarkan ID
+	filter := bson.M{"_id": objectID, "owner": owner}
+	deleted, err := repos.Suppliers.Delete(r.Context(), filter)
+	if err != nil {
+		respon.Error(w, http.StatusInternalServerError, "Gagal menghapus supplier", err.Error())
+		return
+	}
+
+	// Periksa apakah ada supplier yang dihapus
+	if deleted == 0 {
+		respon.Error(w, http.StatusNotFound, "Supplier tidak ditemukan", "")
+		return
+	}
+
+	// Kirim respon sukses
+	respon.Success(w, http.StatusOK, "Supplier berhasil dihapus", bson.M{"DeletedCount": deleted})
+}
@@ -0,0 +1,58 @@
+package controller
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/gocroot/helper/respon"
+	"github.com/gocroot/model"
+)
+
+func TestSupplierHandlers(t *testing.T) {
+	useFakeRepos(t)
+
+	rec := call(CreateSupplier, "62811", "POST", "/suppliers", `{"name":"CV Kopi Nusantara","contact_person":"Budi","phone":"62812"}`)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("create = %d %s", rec.Code, rec.Body)
+	}
+	var created struct {
+		Data model.Supplier `json:"data"`
+	}
+	json.Unmarshal(rec.Body.Bytes(), &created)
+	id := created.Data.ID.Hex()
+	call(CreateSupplier, "62811", "POST", "/suppliers", `{"name":"UD Gula Jaya"}`)
+	call(CreateSupplier, "62899", "POST", "/suppliers", `{"name":"Kopi toko lain"}`)
+
+	rec = call(GetSuppliers, "62811", "GET", "/suppliers?q=kopi", "")
+	var list struct {
+		Data []model.Supplier `json:"data"`
+		Meta respon.Meta      `json:"meta"`
+	}
+	json.Unmarshal(rec.Body.Bytes(), &list)
+	if rec.Code != http.StatusOK || len(list.Data) != 1 || list.Data[0].Name != "CV Kopi Nusantara" || list.Meta.Total == nil || *list.Meta.Total != 1 {
+		t.Errorf("list = %d %s", rec.Code, rec.Body)
+	}
+
+	if rec = call(CreateSupplier, "62811", "POST", "/suppliers", `{"name":"Tanpa email","email":"bukan email"}`); rec.Code != http.StatusBadRequest {
+		t.Errorf("email salah = %d %s", rec.Code, rec.Body)
+	}
+	if rec = call(GetSupplierByID, "62899", "GET", "/supplier?id="+id, ""); rec.Code != http.StatusNotFound {
+		t.Errorf("toko lain membaca supplier: %d", rec.Code)
+	}
+	if rec = call(UpdateSupplier, "62899", "PUT", "/supplier?id="+id, `{"name":"Diambil alih"}`); rec.Code != http.StatusNotFound {
+		t.Errorf("toko lain mengubah supplier: %d", rec.Code)
+	}
+
+	if rec = call(UpdateSupplier, "62811", "PUT", "/supplier?id="+id, `{"address":"Bandung"}`); rec.Code != http.StatusOK {
+		t.Fatalf("update = %d %s", rec.Code, rec.Body)
+	}
+	rec = call(GetSupplierByID, "62811", "GET", "/supplier?id="+id, "")
+	var got model.Supplier
+	json.Unmarshal(rec.Body.Bytes(), &struct {
+		Data *model.Supplier `json:"data"`
+	}{&got})
+	if got.Name != "CV Kopi Nusantara" || got.ContactPerson != "Budi" || got.Address != "Bandung" {
+		t.Errorf("setelah update = %+v", got)
+	}
+}
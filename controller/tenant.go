@@ -0,0 +1,156 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/gocroot/config"
+	"github.com/gocroot/helper/atdb"
+	"github.com/gocroot/helper/auth"
+	"github.com/gocroot/helper/respon"
+	"github.com/gocroot/helper/router"
+	"github.com/gocroot/helper/tenant"
+	"github.com/gocroot/helper/validasi"
+	"github.com/gocroot/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Authorize adalah middleware endpoint akuntansi: memverifikasi token dari LoginAkunPenjual,
+// memeriksa hak akses role lalu menyimpan session ke context request.
+func Authorize(respw http.ResponseWriter, req *http.Request) (*http.Request, bool) {
+	req, ok := authenticate(respw, req)
+	if !ok || !allowRole(respw, req) {
+		return req, false
+	}
+	return req, true
+}
+
+// RequireLogin adalah middleware route yang mewajibkan token login akun penjual lalu menyimpan session ke request
+func RequireLogin(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(respw http.ResponseWriter, req *http.Request) {
+		if req, ok := authenticate(respw, req); ok {
+			next.ServeHTTP(respw, req)
+		}
+	})
+}
+
+// RequireRole adalah middleware route yang membatasi akses role session terhadap method dan pola path route
+func RequireRole(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(respw http.ResponseWriter, req *http.Request) {
+		if allowRole(respw, req) {
+			next.ServeHTTP(respw, req)
+		}
+	})
+}
+
+// authenticate memverifikasi token login dan menyimpan session ke request, respon 401 ditulis jika gagal
+func authenticate(respw http.ResponseWriter, req *http.Request) (*http.Request, bool) {
+	s, err := tenant.Resolve(config.Mongoconn(), req, config.PUBLICKEY)
+	if err != nil {
+		respon.Error(respw, http.StatusUnauthorized, "Token Tidak Valid", "Sertakan header login dari endpoint login akun penjual: "+err.Error())
+		return req, false
+	}
+	return tenant.WithSession(req, s), true
+}
+
+// allowRole memeriksa hak akses role session, memakai pola path route jika ada agar /sales/{id} dinilai sama untuk semua ID
+func allowRole(respw http.ResponseWriter, req *http.Request) bool {
+	s, _ := tenant.FromRequest(req)
+	path := req.URL.Path
+	if route, found := router.Current(req); found {
+		path = route.Path
+	}
+	if !tenant.Allowed(s.Role, req.Method, path) {
+		respon.Error(respw, http.StatusForbidden, "Akses ditolak", "Role "+s.Role+" tidak diizinkan mengakses "+req.Method+" "+req.URL.Path)
+		return false
+	}
+	return true
+}
+
+// idParam mengambil ID dari parameter path {id}, atau dari ?id= untuk klien lama
+func idParam(req *http.Request) string {
+	if id := router.Param(req, "id"); id != "" {
+		return id
+	}
+	return req.URL.Query().Get("id")
+}
+
+// getOwner mengambil pemilik data dari session middleware. Jika belum ada session, token diverifikasi ulang
+// dan respon error sudah ditulis saat ok bernilai false.
+func getOwner(respw http.ResponseWriter, req *http.Request) (owner string, ok bool) {
+	if s, found := tenant.FromRequest(req); found {
+		return s.Owner, true
+	}
+	req, ok = Authorize(respw, req)
+	if !ok {
+		return "", false
+	}
+	s, _ := tenant.FromRequest(req)
+	return s.Owner, true
+}
+
+// Fungsi untuk membuat akun kasir atau akuntan yang terhubung ke toko pemilik yang login
+func CreateStaff(respw http.ResponseWriter, req *http.Request) {
+	owner, ok := getOwner(respw, req)
+	if !ok {
+		return
+	}
+	var request model.Userdomyikado
+	if !respon.Decode(respw, req, &request) {
+		return
+	}
+	if request.Role != tenant.RoleCashier && request.Role != tenant.RoleAccountant {
+		respon.Error(respw, http.StatusBadRequest, "Role tidak valid", "Gunakan cashier atau accountant")
+		return
+	}
+	if !validasi.Phone(request.PhoneNumber) || !validasi.Email(request.Email) || request.Password == "" {
+		respon.Error(respw, http.StatusBadRequest, "Nomor telepon, email dan password wajib diisi", "Nomor telepon diawali 62 dan email harus valid")
+		return
+	}
+	filter := bson.M{"$or": []bson.M{{"phonenumber": request.PhoneNumber}, {"email": request.Email}}}
+	if _, err := atdb.GetOneDoc[model.Userdomyikado](config.Mongoconn(), "user", filter); err == nil {
+		respon.Error(respw, http.StatusConflict, "Nomor telepon atau email sudah terdaftar", "")
+		return
+	}
+
+	hashedPassword, err := auth.HashPassword(request.Password)
+	if err != nil {
+		respon.Error(respw, http.StatusInternalServerError, "Gagal hash password", err.Error())
+		return
+	}
+	staff := model.Userdomyikado{
+		Name:        request.Name,
+		PhoneNumber: request.PhoneNumber,
+		Email:       request.Email,
+		Password:    hashedPassword,
+		Role:        request.Role,
+		Owner:       owner,
+	}
+	if staff.ID, err = atdb.InsertOneDoc(config.Mongoconn(), "user", staff); mongo.IsDuplicateKeyError(err) {
+		respon.Error(respw, http.StatusConflict, "Nomor telepon atau email sudah terdaftar", "")
+		return
+	} else if err != nil {
+		respon.Error(respw, http.StatusInternalServerError, "Gagal Insert Database", err.Error())
+		return
+	}
+
+	staff.Password = ""
+	respon.Success(respw, http.StatusCreated, "Akun "+staff.Role+" berhasil dibuat", staff)
+}
+
+// Fungsi untuk mendapatkan daftar akun kasir dan akuntan toko
+func GetStaff(respw http.ResponseWriter, req *http.Request) {
+	owner, ok := getOwner(respw, req)
+	if !ok {
+		return
+	}
+	data, err := atdb.GetAllDoc[[]model.Userdomyikado](config.Mongoconn(), "user", bson.M{"owner": owner})
+	if err != nil {
+		respon.Error(respw, http.StatusInternalServerError, "Gagal mengambil data akun", err.Error())
+		return
+	}
+	for i := range data {
+		data[i].Password = ""
+	}
+	respon.OK(respw, data)
+}
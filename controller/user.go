@@ -10,7 +10,6 @@ import (
 
 	"github.com/gocroot/config"
 	"github.com/gocroot/model"
-	"github.com/whatsauth/itmodel"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 
@@ -19,6 +18,7 @@ import (
 	"github.com/gocroot/helper/atdb"
 	"github.com/gocroot/helper/lms"
 	"github.com/gocroot/helper/report"
+	"github.com/gocroot/helper/respon"
 	"github.com/gocroot/helper/watoken"
 	"github.com/gocroot/helper/whatsauth"
 )
@@ -26,119 +26,96 @@ import (
 func GetDataUserFromApi(respw http.ResponseWriter, req *http.Request) {
 	payload, err := watoken.Decode(config.PublicKeyWhatsAuth, at.GetLoginFromHeader(req))
 	if err != nil {
-		var respn model.Response
-		respn.Status = "Error : Token Tidak Valid "
-		respn.Info = at.GetSecretFromHeader(req)
-		respn.Location = "Decode Token Error: " + at.GetLoginFromHeader(req)
-		respn.Response = err.Error()
-		at.WriteJSON(respw, http.StatusForbidden, respn)
+		respon.Error(respw, http.StatusForbidden, "Token Tidak Valid", err.Error())
 		return
 	}
 	userdt := lms.GetDataFromAPI(payload.Id)
 	if userdt.Data.Fullname == "" {
-		at.WriteJSON(respw, http.StatusNotFound, userdt)
+		respon.FailData(respw, http.StatusNotFound, respon.CodeNotFound, "Data user tidak ditemukan", "", userdt)
 		return
 	}
-	at.WriteJSON(respw, http.StatusOK, userdt)
+	respon.OK(respw, userdt)
 }
 
 func GetDataUser(respw http.ResponseWriter, req *http.Request) {
 	payload, err := watoken.Decode(config.PublicKeyWhatsAuth, at.GetLoginFromHeader(req))
 	if err != nil {
-		var respn model.Response
-		respn.Status = "Error : Token Tidak Valid "
-		respn.Info = config.PublicKeyWhatsAuth
-		respn.Location = "Decode Token Error: " + at.GetLoginFromHeader(req)
-		respn.Response = err.Error()
-		at.WriteJSON(respw, http.StatusForbidden, respn)
+		respon.Error(respw, http.StatusForbidden, "Token Tidak Valid", err.Error())
 		return
 	}
-	docuser, err := atdb.GetOneDoc[model.Userdomyikado](config.Mongoconn, "user", primitive.M{"phonenumber": payload.Id})
+	docuser, err := atdb.GetOneDoc[model.Userdomyikado](config.Mongoconn(), "user", primitive.M{"phonenumber": payload.Id})
 	if err != nil {
 		docuser.PhoneNumber = payload.Id
 		docuser.Name = payload.Alias
-		at.WriteJSON(respw, http.StatusNotFound, docuser)
+		respon.FailData(respw, http.StatusNotFound, respon.CodeNotFound, "User belum terdaftar", "", docuser)
 		return
 	}
 	docuser.Name = payload.Alias
-	at.WriteJSON(respw, http.StatusOK, docuser)
+	respon.OK(respw, docuser)
 }
 // melakukan pengecekan apakah suda link device klo ada generate token 5tahun
 func PutTokenDataUser(respw http.ResponseWriter, req *http.Request) {
 	payload, err := watoken.Decode(config.PublicKeyWhatsAuth, at.GetLoginFromHeader(req))
 	if err != nil {
-		var respn model.Response
-		respn.Status = "Error : Token Tidak Valid "
-		respn.Info = at.GetSecretFromHeader(req)
-		respn.Location = "Decode Token Error: " + at.GetLoginFromHeader(req)
-		respn.Response = err.Error()
-		at.WriteJSON(respw, http.StatusForbidden, respn)
+		respon.Error(respw, http.StatusForbidden, "Token Tidak Valid", err.Error())
 		return
 	}
-	docuser, err := atdb.GetOneDoc[model.Userdomyikado](config.Mongoconn, "user", primitive.M{"phonenumber": payload.Id})
+	docuser, err := atdb.GetOneDoc[model.Userdomyikado](config.Mongoconn(), "user", primitive.M{"phonenumber": payload.Id})
 	if err != nil {
 		docuser.PhoneNumber = payload.Id
 		docuser.Name = payload.Alias
-		at.WriteJSON(respw, http.StatusNotFound, docuser)
+		respon.FailData(respw, http.StatusNotFound, respon.CodeNotFound, "User belum terdaftar", "", docuser)
 		return
 	}
 	docuser.Name = payload.Alias
 	hcode, qrstat, err := atapi.Get[model.QRStatus](config.WAAPIGetDevice + at.GetLoginFromHeader(req))
 	if err != nil {
-		at.WriteJSON(respw, http.StatusMisdirectedRequest, docuser)
+		respon.FailData(respw, http.StatusMisdirectedRequest, respon.CodeFor(http.StatusMisdirectedRequest), "Gagal memeriksa status device", err.Error(), docuser)
 		return
 	}
 	if hcode == http.StatusOK && !qrstat.Status {
 		docuser.LinkedDevice, err = watoken.EncodeforHours(docuser.PhoneNumber, docuser.Name, config.PrivateKey, 43830)
 		if err != nil {
-			at.WriteJSON(respw, http.StatusFailedDependency, docuser)
+			respon.FailData(respw, http.StatusFailedDependency, respon.CodeFor(http.StatusFailedDependency), "Gagal membuat token device", err.Error(), docuser)
 			return
 		}
 	} else {
 		docuser.LinkedDevice = ""
 	}
-	_, err = atdb.ReplaceOneDoc(config.Mongoconn, "user", primitive.M{"phonenumber": payload.Id}, docuser)
+	_, err = atdb.ReplaceOneDoc(config.Mongoconn(), "user", primitive.M{"phonenumber": payload.Id}, docuser)
 	if err != nil {
-		at.WriteJSON(respw, http.StatusExpectationFailed, docuser)
+		respon.FailData(respw, http.StatusExpectationFailed, respon.CodeFor(http.StatusExpectationFailed), "Gagal menyimpan user", err.Error(), docuser)
 		return
 	}
-	at.WriteJSON(respw, http.StatusOK, docuser)
+	respon.OK(respw, docuser)
 }
 
 func PostDataUser(respw http.ResponseWriter, req *http.Request) {
 	payload, err := watoken.Decode(config.PublicKeyWhatsAuth, at.GetLoginFromHeader(req))
 	if err != nil {
-		var respn model.Response
-		respn.Status = "Error : Token Tidak Valid"
-		respn.Info = at.GetSecretFromHeader(req)
-		respn.Location = "Decode Token Error"
-		respn.Response = err.Error()
-		at.WriteJSON(respw, http.StatusForbidden, respn)
+		respon.Error(respw, http.StatusForbidden, "Token Tidak Valid", err.Error())
 		return
 	}
 	var usr model.Userdomyikado
 	err = json.NewDecoder(req.Body).Decode(&usr)
 	if err != nil {
-		var respn model.Response
-		respn.Status = "Error : Body tidak valid"
-		respn.Response = err.Error()
-		at.WriteJSON(respw, http.StatusBadRequest, respn)
+		respon.Error(respw, http.StatusBadRequest, "Body tidak valid", err.Error())
 		return
 	}
-	docuser, err := atdb.GetOneDoc[model.Userdomyikado](config.Mongoconn, "user", primitive.M{"phonenumber": payload.Id})
+	docuser, err := atdb.GetOneDoc[model.Userdomyikado](config.Mongoconn(), "user", primitive.M{"phonenumber": payload.Id})
 	if err != nil {
 		usr.PhoneNumber = payload.Id
 		usr.Name = payload.Alias
-		idusr, err := atdb.InsertOneDoc(config.Mongoconn, "user", usr)
+		// role dan toko hanya diatur oleh pemilik toko
+		usr.Role = ""
+		usr.Owner = ""
+		idusr, err := atdb.InsertOneDoc(config.Mongoconn(), "user", usr)
 		if err != nil {
-			var respn model.Response
-			respn.Status = "Gagal Insert Database"
-			respn.Response = err.Error()
-			at.WriteJSON(respw, http.StatusNotModified, respn)
+			respon.Error(respw, http.StatusInternalServerError, "Gagal Insert Database", err.Error())
 			return
 		}
 		usr.ID = idusr
-		at.WriteJSON(respw, http.StatusOK, usr)
+		respon.OK(respw, usr)
 		return
 	}
 	docuser.Name = payload.Alias
@@ -146,134 +123,107 @@ func PostDataUser(respw http.ResponseWriter, req *http.Request) {
 	docuser.GitHostUsername = usr.GitHostUsername
 	docuser.GitlabUsername = usr.GitlabUsername
 	docuser.GithubUsername = usr.GithubUsername
-	_, err = atdb.ReplaceOneDoc(config.Mongoconn, "user", primitive.M{"phonenumber": payload.Id}, docuser)
+	_, err = atdb.ReplaceOneDoc(config.Mongoconn(), "user", primitive.M{"phonenumber": payload.Id}, docuser)
 	if err != nil {
-		var respn model.Response
-		respn.Status = "Gagal replaceonedoc"
-		respn.Response = err.Error()
-		at.WriteJSON(respw, http.StatusConflict, respn)
+		respon.Error(respw, http.StatusConflict, "Gagal replaceonedoc", err.Error())
 		return
 	}
 	//melakukan update di seluruh member project
 	//ambil project yang member sebagai anggota
-	existingprjs, err := atdb.GetAllDoc[[]model.Project](config.Mongoconn, "project", primitive.M{"members._id": docuser.ID})
+	existingprjs, err := atdb.GetAllDoc[[]model.Project](config.Mongoconn(), "project", primitive.M{"members._id": docuser.ID})
 	if err != nil { //kalo belum jadi anggota project manapun aman langsung ok
-		at.WriteJSON(respw, http.StatusOK, docuser)
+		respon.OK(respw, docuser)
 		return
 	}
 	if len(existingprjs) == 0 { //kalo belum jadi anggota project manapun aman langsung ok
-		at.WriteJSON(respw, http.StatusOK, docuser)
+		respon.OK(respw, docuser)
 		return
 	}
 	//loop keanggotaan setiap project dan menggantinya dengan doc yang terupdate
 	for _, prj := range existingprjs {
 		memberToDelete := model.Userdomyikado{PhoneNumber: docuser.PhoneNumber}
-		_, err := atdb.DeleteDocFromArray[model.Userdomyikado](config.Mongoconn, "project", prj.ID, "members", memberToDelete)
+		_, err := atdb.DeleteDocFromArray[model.Userdomyikado](config.Mongoconn(), "project", prj.ID, "members", memberToDelete)
 		if err != nil {
-			var respn model.Response
-			respn.Status = "Error : Data project tidak di temukan"
-			respn.Response = err.Error()
-			at.WriteJSON(respw, http.StatusNotFound, respn)
+			respon.Error(respw, http.StatusNotFound, "Data project tidak di temukan", err.Error())
 			return
 		}
-		_, err = atdb.AddDocToArray[model.Userdomyikado](config.Mongoconn, "project", prj.ID, "members", docuser)
+		_, err = atdb.AddDocToArray[model.Userdomyikado](config.Mongoconn(), "project", prj.ID, "members", docuser)
 		if err != nil {
-			var respn model.Response
-			respn.Status = "Error : Gagal menambahkan member ke project"
-			respn.Response = err.Error()
-			at.WriteJSON(respw, http.StatusExpectationFailed, respn)
+			respon.Error(respw, http.StatusExpectationFailed, "Gagal menambahkan member ke project", err.Error())
 			return
 		}
 
 	}
 
-	at.WriteJSON(respw, http.StatusOK, docuser)
+	respon.OK(respw, docuser)
 }
 
 func PostDataUserFromWA(respw http.ResponseWriter, req *http.Request) {
-	var resp itmodel.Response
-	prof, err := whatsauth.GetAppProfile(at.GetParam(req), config.Mongoconn)
+	prof, err := whatsauth.GetAppProfile(at.GetParam(req), config.Mongoconn())
 	if err != nil {
-		resp.Response = err.Error()
-		at.WriteJSON(respw, http.StatusBadRequest, resp)
+		respon.Error(respw, http.StatusBadRequest, "Profil aplikasi tidak ditemukan", err.Error())
 		return
 	}
 	if at.GetSecretFromHeader(req) != prof.Secret {
-		resp.Response = "Salah secret: " + at.GetSecretFromHeader(req)
-		at.WriteJSON(respw, http.StatusUnauthorized, resp)
+		respon.Error(respw, http.StatusUnauthorized, "Salah secret", "")
 		return
 	}
 	var usr model.Userdomyikado
 	err = json.NewDecoder(req.Body).Decode(&usr)
 	if err != nil {
-		resp.Response = "Error : Body tidak valid"
-		resp.Info = err.Error()
-		at.WriteJSON(respw, http.StatusBadRequest, resp)
+		respon.Error(respw, http.StatusBadRequest, "Body tidak valid", err.Error())
 		return
 	}
-	docuser, err := atdb.GetOneDoc[model.Userdomyikado](config.Mongoconn, "user", primitive.M{"phonenumber": usr.PhoneNumber})
+	docuser, err := atdb.GetOneDoc[model.Userdomyikado](config.Mongoconn(), "user", primitive.M{"phonenumber": usr.PhoneNumber})
 	if err != nil {
-		idusr, err := atdb.InsertOneDoc(config.Mongoconn, "user", usr)
+		idusr, err := atdb.InsertOneDoc(config.Mongoconn(), "user", usr)
 		if err != nil {
-			resp.Response = "Gagal Insert Database"
-			resp.Info = err.Error()
-			at.WriteJSON(respw, http.StatusNotModified, resp)
+			respon.Error(respw, http.StatusInternalServerError, "Gagal Insert Database", err.Error())
 			return
 		}
-		resp.Info = idusr.Hex()
-		at.WriteJSON(respw, http.StatusOK, resp)
+		respon.Success(respw, http.StatusCreated, "User berhasil ditambahkan", bson.M{"id": idusr.Hex()})
 		return
 	}
 	docuser.Name = usr.Name
 	docuser.Email = usr.Email
-	_, err = atdb.ReplaceOneDoc(config.Mongoconn, "user", primitive.M{"phonenumber": usr.PhoneNumber}, docuser)
+	_, err = atdb.ReplaceOneDoc(config.Mongoconn(), "user", primitive.M{"phonenumber": usr.PhoneNumber}, docuser)
 	if err != nil {
-		resp.Response = "Gagal replaceonedoc"
-		resp.Info = err.Error()
-		at.WriteJSON(respw, http.StatusConflict, resp)
+		respon.Error(respw, http.StatusConflict, "Gagal replaceonedoc", err.Error())
 		return
 	}
 	//melakukan update di seluruh member project
 	//ambil project yang member sebagai anggota
-	existingprjs, err := atdb.GetAllDoc[[]model.Project](config.Mongoconn, "project", primitive.M{"members._id": docuser.ID})
+	existingprjs, err := atdb.GetAllDoc[[]model.Project](config.Mongoconn(), "project", primitive.M{"members._id": docuser.ID})
 	if err != nil { //kalo belum jadi anggota project manapun aman langsung ok
-		resp.Response = "belum terdaftar di project manapun"
-		at.WriteJSON(respw, http.StatusOK, resp)
+		respon.Success(respw, http.StatusOK, "belum terdaftar di project manapun", nil)
 		return
 	}
 	if len(existingprjs) == 0 { //kalo belum jadi anggota project manapun aman langsung ok
-		resp.Response = "belum terdaftar di project manapun"
-		at.WriteJSON(respw, http.StatusOK, resp)
+		respon.Success(respw, http.StatusOK, "belum terdaftar di project manapun", nil)
 		return
 	}
 	//loop keanggotaan setiap project dan menggantinya dengan doc yang terupdate
 	for _, prj := range existingprjs {
 		memberToDelete := model.Userdomyikado{PhoneNumber: docuser.PhoneNumber}
-		_, err := atdb.DeleteDocFromArray[model.Userdomyikado](config.Mongoconn, "project", prj.ID, "members", memberToDelete)
+		_, err := atdb.DeleteDocFromArray[model.Userdomyikado](config.Mongoconn(), "project", prj.ID, "members", memberToDelete)
 		if err != nil {
-			resp.Response = "Error : Data project tidak di temukan"
-			resp.Info = err.Error()
-			at.WriteJSON(respw, http.StatusNotFound, resp)
+			respon.Error(respw, http.StatusNotFound, "Data project tidak di temukan", err.Error())
 			return
 		}
-		_, err = atdb.AddDocToArray[model.Userdomyikado](config.Mongoconn, "project", prj.ID, "members", docuser)
+		_, err = atdb.AddDocToArray[model.Userdomyikado](config.Mongoconn(), "project", prj.ID, "members", docuser)
 		if err != nil {
-			resp.Response = "Error : Gagal menambahkan member ke project"
-			resp.Info = err.Error()
-			at.WriteJSON(respw, http.StatusExpectationFailed, resp)
+			respon.Error(respw, http.StatusExpectationFailed, "Gagal menambahkan member ke project", err.Error())
 			return
 		}
 
 	}
-	resp.Info = docuser.ID.Hex()
-	resp.Info = docuser.Email
-	at.WriteJSON(respw, http.StatusOK, resp)
+	respon.Success(respw, http.StatusOK, "User berhasil diperbarui", bson.M{"id": docuser.ID.Hex(), "email": docuser.Email})
 }
 
 func ApproveBimbinganbyPoin(w http.ResponseWriter, r *http.Request) {
 	noHp := r.Header.Get("nohp")
 	if noHp == "" {
-		http.Error(w, "No valid phone number found", http.StatusForbidden)
+		respon.Error(w, http.StatusForbidden, "Nomor telepon tidak ditemukan di header", "")
 		return
 	}
 
@@ -283,15 +233,15 @@ func ApproveBimbinganbyPoin(w http.ResponseWriter, r *http.Request) {
 	}
 	err := json.NewDecoder(r.Body).Decode(&requestData)
 	if err != nil || requestData.NIM == "" || requestData.Topik == "" {
-		http.Error(w, "Invalid request body or NIM/Topik not provided", http.StatusBadRequest)
+		respon.Error(w, http.StatusBadRequest, "Body tidak valid atau NIM/Topik tidak diisi", "")
 		return
 	}
 
 	// Get the API URL from the database
 	var conf model.Config
-	err = config.Mongoconn.Collection("config").FindOne(context.TODO(), bson.M{"phonenumber": "62895601060000"}).Decode(&conf)
+	err = config.Mongoconn().Collection("config").FindOne(context.TODO(), bson.M{"phonenumber": "62895601060000"}).Decode(&conf)
 	if err != nil {
-		http.Error(w, "Mohon maaf ada kesalahan dalam pengambilan config di database: "+err.Error(), http.StatusInternalServerError)
+		respon.Error(w, http.StatusInternalServerError, "Mohon maaf ada kesalahan dalam pengambilan config di database", err.Error())
 		return
 	}
 
@@ -301,7 +251,7 @@ func ApproveBimbinganbyPoin(w http.ResponseWriter, r *http.Request) {
 		"topik": requestData.Topik,
 	})
 	if err != nil {
-		http.Error(w, "Gagal membuat request body: "+err.Error(), http.StatusInternalServerError)
+		respon.Error(w, http.StatusInternalServerError, "Gagal membuat request body", err.Error())
 		return
 	}
 
@@ -309,7 +259,7 @@ func ApproveBimbinganbyPoin(w http.ResponseWriter, r *http.Request) {
 	client := &http.Client{Timeout: 10 * time.Second}
 	req, err := http.NewRequest("POST", conf.ApproveBimbinganURL, bytes.NewBuffer(requestBody))
 	if err != nil {
-		http.Error(w, "Gagal membuat request: "+err.Error(), http.StatusInternalServerError)
+		respon.Error(w, http.StatusInternalServerError, "Gagal membuat request", err.Error())
 		return
 	}
 	req.Header.Set("Content-Type", "application/json")
@@ -317,7 +267,7 @@ func ApproveBimbinganbyPoin(w http.ResponseWriter, r *http.Request) {
 
 	resp, err := client.Do(req)
 	if err != nil {
-		http.Error(w, "Gagal mengirim request: "+err.Error(), http.StatusInternalServerError)
+		respon.Error(w, http.StatusBadGateway, "Gagal mengirim request", err.Error())
 		return
 	}
 	defer resp.Body.Close()
@@ -325,11 +275,11 @@ func ApproveBimbinganbyPoin(w http.ResponseWriter, r *http.Request) {
 	if resp.StatusCode != http.StatusOK {
 		switch resp.StatusCode {
 		case http.StatusNotFound:
-			http.Error(w, "Token tidak ditemukan! Silahkan Login Kembali", http.StatusNotFound)
+			respon.Error(w, http.StatusNotFound, "Token tidak ditemukan! Silahkan Login Kembali", "")
 		case http.StatusForbidden:
-			http.Error(w, "Gagal, Bimbingan telah disetujui!", http.StatusForbidden)
+			respon.Error(w, http.StatusForbidden, "Gagal, Bimbingan telah disetujui!", "")
 		default:
-			http.Error(w, fmt.Sprintf("Gagal approve bimbingan, status code: %d", resp.StatusCode), http.StatusInternalServerError)
+			respon.Error(w, http.StatusInternalServerError, "Gagal approve bimbingan", fmt.Sprintf("status code: %d", resp.StatusCode))
 		}
 		return
 	}
@@ -337,29 +287,27 @@ func ApproveBimbinganbyPoin(w http.ResponseWriter, r *http.Request) {
 	var responseMap map[string]string
 	err = json.NewDecoder(resp.Body).Decode(&responseMap)
 	if err != nil {
-		http.Error(w, "Gagal memproses response: "+err.Error(), http.StatusInternalServerError)
+		respon.Error(w, http.StatusInternalServerError, "Gagal memproses response", err.Error())
 		return
 	}
 
 	// Kurangi poin berdasarkan nomor telepon yang ada di response
 	phonenumber := responseMap["no_hp"]
-	_, err = report.KurangPoinUserbyPhoneNumber(config.Mongoconn, phonenumber, 13.0)
+	_, err = report.KurangPoinUserbyPhoneNumber(config.Mongoconn(), phonenumber, 13.0)
 	if err != nil {
-		http.Error(w, "Gagal mengurangi poin: "+err.Error(), http.StatusInternalServerError)
+		respon.Error(w, http.StatusInternalServerError, "Gagal mengurangi poin", err.Error())
 		return
 	}
 
 	// Get updated user data to return the current points
-	usr, err := atdb.GetOneDoc[model.Userdomyikado](config.Mongoconn, "user", bson.M{"phonenumber": phonenumber})
+	usr, err := atdb.GetOneDoc[model.Userdomyikado](config.Mongoconn(), "user", bson.M{"phonenumber": phonenumber})
 	if err != nil {
-		http.Error(w, "Gagal mengambil data pengguna: "+err.Error(), http.StatusInternalServerError)
+		respon.Error(w, http.StatusInternalServerError, "Gagal mengambil data pengguna", err.Error())
 		return
 	}
 
 	// Add the current points to the response
-	responseMap["message"] = "Bimbingan berhasil di approve!"
-	responseMap["status"] = "success"
 	responseMap["poin_mahasiswa"] = fmt.Sprintf("Poin mahasiswa telah berkurang menjadi: %f", usr.Poin)
 
-	at.WriteJSON(w, http.StatusOK, responseMap)
+	respon.Success(w, http.StatusOK, "Bimbingan berhasil di approve!", responseMap)
 }
\ No newline at end of file
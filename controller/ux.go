@@ -4,7 +4,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
-	"strconv"
 	"strings"
 
 	"github.com/gocroot/config"
@@ -16,6 +15,7 @@ import (
 	"github.com/gocroot/helper/lms"
 	"github.com/gocroot/helper/phone"
 	"github.com/gocroot/helper/report"
+	"github.com/gocroot/helper/respon"
 	"github.com/gocroot/helper/tiket"
 	"github.com/gocroot/helper/watoken"
 	"github.com/gocroot/helper/whatsauth"
@@ -26,35 +26,29 @@ import (
 )
 
 func PostTaskList(w http.ResponseWriter, r *http.Request) {
-	var resp itmodel.Response
-	prof, err := whatsauth.GetAppProfile(at.GetParam(r), config.Mongoconn)
+	prof, err := whatsauth.GetAppProfile(at.GetParam(r), config.Mongoconn())
 	if err != nil {
-		resp.Response = err.Error()
-		at.WriteJSON(w, http.StatusBadRequest, resp)
+		respon.Error(w, http.StatusBadRequest, "Profil aplikasi tidak ditemukan", err.Error())
 		return
 	}
 	if at.GetSecretFromHeader(r) != prof.Secret {
-		resp.Response = "Salah secret: " + at.GetSecretFromHeader(r)
-		at.WriteJSON(w, http.StatusUnauthorized, resp)
+		respon.Error(w, http.StatusUnauthorized, "Salah secret", "")
 		return
 	}
 	var tasklists []report.TaskList
 	err = json.NewDecoder(r.Body).Decode(&tasklists)
 	if err != nil {
-		resp.Response = err.Error()
-		at.WriteJSON(w, http.StatusBadRequest, resp)
+		respon.Error(w, http.StatusBadRequest, "Body tidak valid", err.Error())
 		return
 	}
-	docusr, err := atdb.GetOneLatestDoc[model.Userdomyikado](config.Mongoconn, "user", primitive.M{"phonenumber": tasklists[0].PhoneNumber})
+	docusr, err := atdb.GetOneLatestDoc[model.Userdomyikado](config.Mongoconn(), "user", primitive.M{"phonenumber": tasklists[0].PhoneNumber})
 	if err != nil {
-		resp.Response = "Error : user tidak di temukan " + err.Error()
-		at.WriteJSON(w, http.StatusForbidden, resp)
+		respon.Error(w, http.StatusForbidden, "user tidak di temukan", err.Error())
 		return
 	}
-	lapuser, err := atdb.GetOneLatestDoc[model.Laporan](config.Mongoconn, "uxlaporan", primitive.M{"_id": tasklists[0].LaporanID})
+	lapuser, err := atdb.GetOneLatestDoc[model.Laporan](config.Mongoconn(), "uxlaporan", primitive.M{"_id": tasklists[0].LaporanID})
 	if err != nil {
-		resp.Response = "Error : user tidak di temukan " + err.Error()
-		at.WriteJSON(w, http.StatusForbidden, resp)
+		respon.Error(w, http.StatusForbidden, "user tidak di temukan", err.Error())
 		return
 	}
 	for _, task := range tasklists {
@@ -66,86 +60,64 @@ func PostTaskList(w http.ResponseWriter, r *http.Request) {
 		task.MeetGoal = lapuser.MeetEvent.Summary
 		task.MeetDate = lapuser.MeetEvent.Date
 		task.ProjectWAGroupID = lapuser.Project.WAGroupID
-		_, err = atdb.InsertOneDoc(config.Mongoconn, "tasklist", task)
+		_, err = atdb.InsertOneDoc(config.Mongoconn(), "tasklist", task)
 		if err != nil {
-			resp.Info = "Kakak sudah melaporkan tasklist sebelumnya"
-			resp.Response = "Error : tidak bisa insert ke database " + err.Error()
-			at.WriteJSON(w, http.StatusForbidden, resp)
+			respon.Error(w, http.StatusForbidden, "Kakak sudah melaporkan tasklist sebelumnya", err.Error())
 			return
 		}
 	}
-	res, err := report.TambahPoinTasklistbyPhoneNumber(config.Mongoconn, docusr.PhoneNumber, lapuser.Project, float64(len(tasklists)), "tasklist")
+	res, err := report.TambahPoinTasklistbyPhoneNumber(config.Mongoconn(), docusr.PhoneNumber, lapuser.Project, float64(len(tasklists)), "tasklist")
 	if err != nil {
-		resp.Info = "Tambah Poin Tasklist gagal"
-		resp.Response = err.Error()
-		at.WriteJSON(w, http.StatusExpectationFailed, resp)
+		respon.Error(w, http.StatusExpectationFailed, "Gagal menambah poin", err.Error())
 		return
 	}
-	resp.Response = strconv.Itoa(int(res.ModifiedCount))
-	resp.Info = docusr.Name
-	at.WriteJSON(w, http.StatusOK, resp)
+	respon.Success(w, http.StatusOK, "Poin berhasil ditambahkan", primitive.M{"modified": res.ModifiedCount, "nama": docusr.Name})
 }
 
 func PostPresensi(respw http.ResponseWriter, req *http.Request) {
-	var resp itmodel.Response
-	prof, err := whatsauth.GetAppProfile(at.GetParam(req), config.Mongoconn)
+	prof, err := whatsauth.GetAppProfile(at.GetParam(req), config.Mongoconn())
 	if err != nil {
-		resp.Response = err.Error()
-		at.WriteJSON(respw, http.StatusBadRequest, resp)
+		respon.Error(respw, http.StatusBadRequest, "Profil aplikasi tidak ditemukan", err.Error())
 		return
 	}
 	if at.GetSecretFromHeader(req) != prof.Secret {
-		resp.Response = "Salah secret: " + at.GetSecretFromHeader(req)
-		at.WriteJSON(respw, http.StatusUnauthorized, resp)
+		respon.Error(respw, http.StatusUnauthorized, "Salah secret", "")
 		return
 	}
 	var presensi report.PresensiDomyikado
 	err = json.NewDecoder(req.Body).Decode(&presensi)
 	if err != nil {
-		resp.Response = err.Error()
-		at.WriteJSON(respw, http.StatusBadRequest, resp)
+		respon.Error(respw, http.StatusBadRequest, "Body tidak valid", err.Error())
 		return
 	}
-	docusr, err := atdb.GetOneLatestDoc[model.Userdomyikado](config.Mongoconn, "user", primitive.M{"phonenumber": presensi.PhoneNumber})
+	docusr, err := atdb.GetOneLatestDoc[model.Userdomyikado](config.Mongoconn(), "user", primitive.M{"phonenumber": presensi.PhoneNumber})
 	if err != nil {
-		resp.Response = "Error : user tidak di temukan " + err.Error()
-		at.WriteJSON(respw, http.StatusForbidden, resp)
+		respon.Error(respw, http.StatusForbidden, "user tidak di temukan", err.Error())
 		return
 	}
-	_, err = atdb.InsertOneDoc(config.Mongoconn, "presensi", presensi)
+	_, err = atdb.InsertOneDoc(config.Mongoconn(), "presensi", presensi)
 	if err != nil {
-		resp.Info = "Kakak sudah melaporkan presensi sebelumnya"
-		resp.Response = "Error : tidak bisa insert ke database " + err.Error()
-		at.WriteJSON(respw, http.StatusForbidden, resp)
+		respon.Error(respw, http.StatusForbidden, "Kakak sudah melaporkan presensi sebelumnya", err.Error())
 		return
 	}
-	res, err := report.TambahPoinPresensibyPhoneNumber(config.Mongoconn, presensi.PhoneNumber, presensi.Lokasi, presensi.Skor, config.WAAPIToken, config.WAAPIMessage, "presensi")
+	res, err := report.TambahPoinPresensibyPhoneNumber(config.Mongoconn(), presensi.PhoneNumber, presensi.Lokasi, presensi.Skor, config.WAAPIToken, config.WAAPIMessage, "presensi")
 	if err != nil {
-		resp.Info = "Tambah Poin Presensi gagal"
-		resp.Response = err.Error()
-		at.WriteJSON(respw, http.StatusExpectationFailed, resp)
+		respon.Error(respw, http.StatusExpectationFailed, "Gagal menambah poin", err.Error())
 		return
 	}
-	resp.Response = strconv.Itoa(int(res.ModifiedCount))
-	resp.Info = docusr.Name
-	at.WriteJSON(respw, http.StatusOK, resp)
+	respon.Success(respw, http.StatusOK, "Poin berhasil ditambahkan", primitive.M{"modified": res.ModifiedCount, "nama": docusr.Name})
 }
 
 // testimoni dari useng lms pamong
 func PostTestimoni(respw http.ResponseWriter, req *http.Request) {
 	payload, err := watoken.Decode(config.PublicKeyWhatsAuth, at.GetLoginFromHeader(req))
 	if err != nil {
-		var respn model.Response
-		respn.Status = "Error : Token Tidak Valid "
-		respn.Info = at.GetSecretFromHeader(req)
-		respn.Location = "Decode Token Error: " + at.GetLoginFromHeader(req)
-		respn.Response = err.Error()
-		at.WriteJSON(respw, http.StatusForbidden, respn)
+		respon.Error(respw, http.StatusForbidden, "Token Tidak Valid", err.Error())
 		return
 	}
 	userdt := lms.GetDataFromAPI(payload.Id)
 	if userdt.Data.Fullname == "" {
-		at.WriteJSON(respw, http.StatusNotFound, userdt)
+		respon.FailData(respw, http.StatusNotFound, respon.CodeNotFound, "Data user tidak ditemukan", "", userdt)
 		return
 	}
 	//pindah ke struck user
@@ -158,36 +130,26 @@ func PostTestimoni(respw http.ResponseWriter, req *http.Request) {
 	usersub.Provinsi = userdt.Data.Province
 
 	var rating report.Rating
-	var respn model.Response
 	err = json.NewDecoder(req.Body).Decode(&rating)
 	if err != nil {
-		respn.Status = "Error : Body tidak valid"
-		respn.Response = err.Error()
-		at.WriteJSON(respw, http.StatusBadRequest, respn)
+		respon.Error(respw, http.StatusBadRequest, "Body tidak valid", err.Error())
 		return
 	}
 	usersub.Rating = rating.Rating
 	usersub.Komentar = rating.Komentar
-	res, err := atdb.InsertOneDoc(config.Mongoconn, "unsubs", usersub)
+	res, err := atdb.InsertOneDoc(config.Mongoconn(), "unsubs", usersub)
 	if err != nil {
-		respn.Status = "Error : Data laporan tidak berhasil di update data rating"
-		respn.Response = err.Error()
-		at.WriteJSON(respw, http.StatusNotImplemented, respn)
+		respon.Error(respw, http.StatusNotImplemented, "Data laporan tidak berhasil di update data rating", err.Error())
 		return
 	}
-	respn.Response = res.Hex()
-	respn.Info = usersub.Fullname
-	at.WriteJSON(respw, http.StatusOK, respn)
+	respon.Success(respw, http.StatusCreated, "Rating berhasil disimpan", primitive.M{"id": res.Hex(), "nama": usersub.Fullname})
 }
 
 // mendapatkan random testi 4 buah untuk halaman depan
 func GetRandomTesti4(respw http.ResponseWriter, req *http.Request) {
-	var respn model.Response
-	lstpeserta, err := atdb.GetRandomDoc[model.Peserta](config.Mongoconn, "unsubs", 4)
+	lstpeserta, err := atdb.GetRandomDoc[model.Peserta](config.Mongoconn(), "unsubs", 4)
 	if err != nil {
-		respn.Status = "Error : Data laporan tidak berhasil di update data rating"
-		respn.Response = err.Error()
-		at.WriteJSON(respw, http.StatusNotImplemented, respn)
+		respon.Error(respw, http.StatusNotImplemented, "Data laporan tidak berhasil di update data rating", err.Error())
 		return
 	}
 	var listtesti []model.Testi
@@ -202,48 +164,35 @@ func GetRandomTesti4(respw http.ResponseWriter, req *http.Request) {
 	testidepan := model.Depan{
 		List: listtesti,
 	}
-	at.WriteJSON(respw, http.StatusOK, testidepan)
+	respon.OK(respw, testidepan)
 }
 
 // feedback dan meeting jadi satu disini
 func PostUnsubscribe(respw http.ResponseWriter, req *http.Request) {
 	var rating report.Rating
-	var respn model.Response
 	err := json.NewDecoder(req.Body).Decode(&rating)
 	if err != nil {
-		respn.Status = "Error : Body tidak valid"
-		respn.Response = err.Error()
-		at.WriteJSON(respw, http.StatusBadRequest, respn)
+		respon.Error(respw, http.StatusBadRequest, "Body tidak valid", err.Error())
 		return
 	}
 	objectId, err := primitive.ObjectIDFromHex(rating.ID)
 	if err != nil {
-		respn.Status = "Error : ObjectID Tidak Valid"
-		respn.Info = at.GetSecretFromHeader(req)
-		respn.Location = "Encode Object ID Error"
-		respn.Response = err.Error()
-		at.WriteJSON(respw, http.StatusBadRequest, respn)
+		respon.Error(respw, http.StatusBadRequest, "ObjectID Tidak Valid", err.Error())
 		return
 	}
-	hasil, err := atdb.GetOneLatestDoc[model.Peserta](config.Mongoconn, "sent", primitive.M{"_id": objectId})
+	hasil, err := atdb.GetOneLatestDoc[model.Peserta](config.Mongoconn(), "sent", primitive.M{"_id": objectId})
 	if err != nil {
-		respn.Status = "Error : Data laporan tidak di temukan"
-		respn.Response = err.Error()
-		at.WriteJSON(respw, http.StatusNotImplemented, respn)
+		respon.Error(respw, http.StatusNotImplemented, "Data laporan tidak di temukan", err.Error())
 		return
 	}
 	hasil.Rating = rating.Rating
 	hasil.Komentar = rating.Komentar
-	res, err := atdb.InsertOneDoc(config.Mongoconn, "unsubs", hasil)
+	res, err := atdb.InsertOneDoc(config.Mongoconn(), "unsubs", hasil)
 	if err != nil {
-		respn.Status = "Error : Data laporan tidak berhasil di update data rating"
-		respn.Response = err.Error()
-		at.WriteJSON(respw, http.StatusNotImplemented, respn)
+		respon.Error(respw, http.StatusNotImplemented, "Data laporan tidak berhasil di update data rating", err.Error())
 		return
 	}
-	respn.Response = res.Hex()
-	respn.Info = hasil.Fullname
-	at.WriteJSON(respw, http.StatusOK, respn)
+	respon.Success(respw, http.StatusCreated, "Rating berhasil disimpan", primitive.M{"id": res.Hex(), "nama": hasil.Fullname})
 }
 
 // mendapatkan data FAQ
@@ -251,23 +200,15 @@ func GetFAQ(respw http.ResponseWriter, req *http.Request) {
 	id := at.GetParam(req)
 	objectId, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
-		var respn model.Response
-		respn.Status = "Error : ObjectID Tidak Valid"
-		respn.Info = at.GetSecretFromHeader(req)
-		respn.Location = "Encode Object ID Error"
-		respn.Response = err.Error()
-		at.WriteJSON(respw, http.StatusBadRequest, respn)
+		respon.Error(respw, http.StatusBadRequest, "ObjectID Tidak Valid", err.Error())
 		return
 	}
-	hasil, err := atdb.GetOneLatestDoc[kimseok.Datasets](config.Mongoconn, "faq", primitive.M{"_id": objectId})
+	hasil, err := atdb.GetOneLatestDoc[kimseok.Datasets](config.Mongoconn(), "faq", primitive.M{"_id": objectId})
 	if err != nil {
-		var respn model.Response
-		respn.Status = "Error : Data profile user sent tidak di temukan"
-		respn.Response = err.Error()
-		at.WriteJSON(respw, http.StatusNotImplemented, respn)
+		respon.Error(respw, http.StatusNotImplemented, "Data profile user sent tidak di temukan", err.Error())
 		return
 	}
-	at.WriteJSON(respw, http.StatusOK, hasil)
+	respon.OK(respw, hasil)
 }
 
 // mendapatkan user yang sent dan mau unnsubscribe
@@ -275,24 +216,16 @@ func GetSentItem(respw http.ResponseWriter, req *http.Request) {
 	id := at.GetParam(req)
 	objectId, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
-		var respn model.Response
-		respn.Status = "Error : ObjectID Tidak Valid"
-		respn.Info = at.GetSecretFromHeader(req)
-		respn.Location = "Encode Object ID Error"
-		respn.Response = err.Error()
-		at.WriteJSON(respw, http.StatusBadRequest, respn)
+		respon.Error(respw, http.StatusBadRequest, "ObjectID Tidak Valid", err.Error())
 		return
 	}
-	hasil, err := atdb.GetOneLatestDoc[model.Peserta](config.Mongoconn, "sent", primitive.M{"_id": objectId})
+	hasil, err := atdb.GetOneLatestDoc[model.Peserta](config.Mongoconn(), "sent", primitive.M{"_id": objectId})
 	if err != nil {
-		var respn model.Response
-		respn.Status = "Error : Data profile user sent tidak di temukan"
-		respn.Response = err.Error()
-		at.WriteJSON(respw, http.StatusNotImplemented, respn)
+		respon.Error(respw, http.StatusNotImplemented, "Data profile user sent tidak di temukan", err.Error())
 		return
 	}
 	hasil.PhoneNumber = ""
-	at.WriteJSON(respw, http.StatusOK, hasil)
+	respon.OK(respw, hasil)
 }
 
 // mendapatkan tiket yang sudah closed Profile, err := atdb.GetOneDoc[itmodel.Profile](Mongoconn, "profile", primitive.M{"phonenumber": PhoneNumber})
@@ -300,34 +233,23 @@ func GetClosedTicket(respw http.ResponseWriter, req *http.Request) {
 	id := at.GetParam(req)
 	objectId, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
-		var respn model.Response
-		respn.Status = "Error : ObjectID Tidak Valid"
-		respn.Info = at.GetSecretFromHeader(req)
-		respn.Location = "Encode Object ID Error"
-		respn.Response = err.Error()
-		at.WriteJSON(respw, http.StatusBadRequest, respn)
+		respon.Error(respw, http.StatusBadRequest, "ObjectID Tidak Valid", err.Error())
 		return
 	}
-	hasil, err := atdb.GetOneLatestDoc[tiket.Bantuan](config.Mongoconn, "tiket", primitive.M{"_id": objectId})
+	hasil, err := atdb.GetOneLatestDoc[tiket.Bantuan](config.Mongoconn(), "tiket", primitive.M{"_id": objectId})
 	if err != nil {
-		var respn model.Response
-		respn.Status = "Error : Data tiket tidak di temukan"
-		respn.Response = err.Error()
-		at.WriteJSON(respw, http.StatusNotImplemented, respn)
+		respon.Error(respw, http.StatusNotImplemented, "Data tiket tidak di temukan", err.Error())
 		return
 	}
 
-	at.WriteJSON(respw, http.StatusOK, hasil)
+	respon.OK(respw, hasil)
 }
 
 // mendapatkan semua list bot yang aktif
 func GetBotList(respw http.ResponseWriter, req *http.Request) {
-	Profiles, err := atdb.GetAllDoc[[]itmodel.Profile](config.Mongoconn, "profile", primitive.M{})
+	Profiles, err := atdb.GetAllDoc[[]itmodel.Profile](config.Mongoconn(), "profile", primitive.M{})
 	if err != nil {
-		var respn model.Response
-		respn.Status = "Error : Data tiket tidak di temukan"
-		respn.Response = err.Error()
-		at.WriteJSON(respw, http.StatusNotImplemented, respn)
+		respon.Error(respw, http.StatusNotImplemented, "Data tiket tidak di temukan", err.Error())
 		return
 	}
 	var phonelist []string
@@ -337,46 +259,35 @@ func GetBotList(respw http.ResponseWriter, req *http.Request) {
 	}
 	hasil := model.PhoneList{PhoneList: phonelist}
 
-	at.WriteJSON(respw, http.StatusOK, hasil)
+	respon.OK(respw, hasil)
 }
 
 // feedback dari tiket yang sudah tertutup
 func PostMasukanTiket(respw http.ResponseWriter, req *http.Request) {
 	var rating report.Rating
-	var respn model.Response
 	err := json.NewDecoder(req.Body).Decode(&rating)
 	if err != nil {
-		respn.Status = "Error : Body tidak valid"
-		respn.Response = err.Error()
-		at.WriteJSON(respw, http.StatusBadRequest, respn)
+		respon.Error(respw, http.StatusBadRequest, "Body tidak valid", err.Error())
 		return
 	}
 	objectId, err := primitive.ObjectIDFromHex(rating.ID)
 	if err != nil {
-		respn.Status = "Error : ObjectID Tidak Valid"
-		respn.Info = at.GetSecretFromHeader(req)
-		respn.Location = "Encode Object ID Error"
-		respn.Response = err.Error()
-		at.WriteJSON(respw, http.StatusBadRequest, respn)
+		respon.Error(respw, http.StatusBadRequest, "ObjectID Tidak Valid", err.Error())
 		return
 	}
 	updatefields := primitive.M{
 		"ratelayanan": rating.Rating,
 		"masukan":     rating.Komentar,
 	}
-	res, err := atdb.UpdateOneDoc(config.Mongoconn, "tiket", primitive.M{"_id": objectId}, updatefields)
+	res, err := atdb.UpdateOneDoc(config.Mongoconn(), "tiket", primitive.M{"_id": objectId}, updatefields)
 	if err != nil {
-		respn.Status = "Error : Data laporan tidak berhasil di update data rating"
-		respn.Response = err.Error()
-		at.WriteJSON(respw, http.StatusNotImplemented, respn)
+		respon.Error(respw, http.StatusNotImplemented, "Data laporan tidak berhasil di update data rating", err.Error())
 		return
 	}
 	//mendapatkan document untuk informasi ke admin
-	hasil, err := atdb.GetOneLatestDoc[tiket.Bantuan](config.Mongoconn, "tiket", primitive.M{"_id": objectId})
+	hasil, err := atdb.GetOneLatestDoc[tiket.Bantuan](config.Mongoconn(), "tiket", primitive.M{"_id": objectId})
 	if err != nil {
-		respn.Status = "Error : Data laporan tidak di temukan"
-		respn.Response = err.Error()
-		at.WriteJSON(respw, http.StatusNotImplemented, respn)
+		respon.Error(respw, http.StatusNotImplemented, "Data laporan tidak di temukan", err.Error())
 		return
 	}
 
@@ -385,10 +296,8 @@ func PostMasukanTiket(respw http.ResponseWriter, req *http.Request) {
 		nama = phone.MaskPhoneNumber(hasil.UserPhone)
 	}
 
-	respn.Response = strconv.Itoa(int(res.ModifiedCount))
-	respn.Info = nama
 	//info ke admin
-	message := helpdesk.GetPrefillMessage("adminnotiffeedback", config.Mongoconn)
+	message := helpdesk.GetPrefillMessage("adminnotiffeedback", config.Mongoconn())
 	message = fmt.Sprintf(message, rating.Rating, nama, rating.Komentar)
 	dt := &whatsauth.TextMessage{
 		To:       hasil.AdminPhone,
@@ -397,42 +306,31 @@ func PostMasukanTiket(respw http.ResponseWriter, req *http.Request) {
 	}
 	go atapi.PostStructWithToken[model.Response]("Token", config.WAAPIToken, dt, config.WAAPIMessage)
 
-	at.WriteJSON(respw, http.StatusOK, respn)
+	respon.Success(respw, http.StatusOK, "Masukan berhasil disimpan", primitive.M{"modified": res.ModifiedCount, "nama": nama})
 }
 
 func PostMeeting(w http.ResponseWriter, r *http.Request) {
-	var respn model.Response
 	//otorisasi dan validasi inputan
 	payload, err := watoken.Decode(config.PublicKeyWhatsAuth, at.GetLoginFromHeader(r))
 	if err != nil {
-		respn.Status = "Error : Token Tidak Valid"
-		respn.Info = at.GetSecretFromHeader(r)
-		respn.Location = "Decode Token Error"
-		respn.Response = err.Error()
-		at.WriteJSON(w, http.StatusForbidden, respn)
+		respon.Error(w, http.StatusForbidden, "Token Tidak Valid", err.Error())
 		return
 	}
 	var event gcallapi.SimpleEvent
 	err = json.NewDecoder(r.Body).Decode(&event)
 	if err != nil {
-		respn.Status = "Error : Body tidak valid"
-		respn.Response = err.Error()
-		at.WriteJSON(w, http.StatusBadRequest, respn)
+		respon.Error(w, http.StatusBadRequest, "Body tidak valid", err.Error())
 		return
 	}
 	//check validasi user
-	docuser, err := atdb.GetOneDoc[model.Userdomyikado](config.Mongoconn, "user", primitive.M{"phonenumber": payload.Id})
+	docuser, err := atdb.GetOneDoc[model.Userdomyikado](config.Mongoconn(), "user", primitive.M{"phonenumber": payload.Id})
 	if err != nil {
-		respn.Status = "Error : Data user tidak di temukan: " + payload.Id
-		respn.Response = err.Error()
-		at.WriteJSON(w, http.StatusNotImplemented, respn)
+		respon.Error(w, http.StatusNotImplemented, "Data user tidak di temukan: "+payload.Id, err.Error())
 		return
 	}
-	prjuser, err := atdb.GetOneDoc[model.Project](config.Mongoconn, "project", primitive.M{"_id": event.ProjectID})
+	prjuser, err := atdb.GetOneDoc[model.Project](config.Mongoconn(), "project", primitive.M{"_id": event.ProjectID})
 	if err != nil {
-		respn.Status = "Error : Data project tidak di temukan: " + event.ProjectID.Hex()
-		respn.Response = err.Error()
-		at.WriteJSON(w, http.StatusNotImplemented, respn)
+		respon.Error(w, http.StatusNotImplemented, "Data project tidak di temukan: "+event.ProjectID.Hex(), err.Error())
 		return
 	}
 	//lojik inputan post
@@ -451,43 +349,32 @@ func PostMeeting(w http.ResponseWriter, r *http.Request) {
 	}
 	event.Attendees = attendees
 
-	gevt, err := gcallapi.HandlerCalendar(config.Mongoconn, event)
+	gevt, err := gcallapi.HandlerCalendar(config.Mongoconn(), event)
 	if err != nil {
-		respn.Status = "Gagal Membuat Google Calendar"
-		respn.Response = err.Error()
-		at.WriteJSON(w, http.StatusNotModified, respn)
+		respon.Error(w, http.StatusInternalServerError, "Gagal Membuat Google Calendar", err.Error())
 		return
 	}
-	_, err = atdb.InsertOneDoc(config.Mongoconn, "meetinglog", gevt)
+	_, err = atdb.InsertOneDoc(config.Mongoconn(), "meetinglog", gevt)
 	if err != nil {
-		respn.Status = "Gagal Insert Database meetinglog"
-		respn.Response = err.Error()
-		at.WriteJSON(w, http.StatusNotModified, respn)
+		respon.Error(w, http.StatusInternalServerError, "Gagal Insert Database meetinglog", err.Error())
 		return
 	}
-	event.ID, err = atdb.InsertOneDoc(config.Mongoconn, "meeting", event)
+	event.ID, err = atdb.InsertOneDoc(config.Mongoconn(), "meeting", event)
 	if err != nil {
-		respn.Status = "Gagal Insert Database meeting"
-		respn.Response = err.Error()
-		at.WriteJSON(w, http.StatusNotModified, respn)
+		respon.Error(w, http.StatusInternalServerError, "Gagal Insert Database meeting", err.Error())
 		return
 	}
 	lap.MeetID = event.ID
 	lap.MeetEvent = event
 	lap.Kode = gevt.HtmlLink
-	lap.ID, err = atdb.InsertOneDoc(config.Mongoconn, "uxlaporan", lap)
+	lap.ID, err = atdb.InsertOneDoc(config.Mongoconn(), "uxlaporan", lap)
 	if err != nil {
-		respn.Status = "Gagal Insert Database"
-		respn.Response = err.Error()
-		at.WriteJSON(w, http.StatusNotModified, respn)
+		respon.Error(w, http.StatusInternalServerError, "Gagal Insert Database", err.Error())
 		return
 	}
-	_, err = report.TambahPoinLaporanbyPhoneNumber(config.Mongoconn, prjuser, docuser.PhoneNumber, 1, "meeting")
+	_, err = report.TambahPoinLaporanbyPhoneNumber(config.Mongoconn(), prjuser, docuser.PhoneNumber, 1, "meeting")
 	if err != nil {
-		var resp model.Response
-		resp.Info = "TambahPoinLaporanbyPhoneNumber gagal"
-		resp.Response = err.Error()
-		at.WriteJSON(w, http.StatusExpectationFailed, resp)
+		respon.Error(w, http.StatusExpectationFailed, "Gagal menambah poin", err.Error())
 		return
 	}
 
@@ -497,70 +384,46 @@ func PostMeeting(w http.ResponseWriter, r *http.Request) {
 		IsGroup:  true,
 		Messages: message,
 	}
-	_, resp, err := atapi.PostStructWithToken[model.Response]("Token", config.WAAPIToken, dt, config.WAAPIMessage)
+	_, _, err = atapi.PostStructWithToken[model.Response]("Token", config.WAAPIToken, dt, config.WAAPIMessage)
 	if err != nil {
-		resp.Info = "Tidak berhak"
-		resp.Response = err.Error()
-		at.WriteJSON(w, http.StatusUnauthorized, resp)
+		respon.Error(w, http.StatusUnauthorized, "Gagal mengirim pesan WhatsApp", err.Error())
 		return
 	}
-	at.WriteJSON(w, http.StatusOK, lap)
+	respon.OK(w, lap)
 }
 
 func PostLaporan(respw http.ResponseWriter, req *http.Request) {
 	//otorisasi dan validasi inputan
 	payload, err := watoken.Decode(config.PublicKeyWhatsAuth, at.GetLoginFromHeader(req))
 	if err != nil {
-		var respn model.Response
-		respn.Status = "Error : Token Tidak Valid"
-		respn.Info = at.GetSecretFromHeader(req)
-		respn.Location = "Decode Token Error"
-		respn.Response = err.Error()
-		at.WriteJSON(respw, http.StatusForbidden, respn)
+		respon.Error(respw, http.StatusForbidden, "Token Tidak Valid", err.Error())
 		return
 	}
 	var lap model.Laporan
 	err = json.NewDecoder(req.Body).Decode(&lap)
 	if err != nil {
-		var respn model.Response
-		respn.Status = "Error : Body tidak valid"
-		respn.Response = err.Error()
-		at.WriteJSON(respw, http.StatusBadRequest, respn)
+		respon.Error(respw, http.StatusBadRequest, "Body tidak valid", err.Error())
 		return
 	}
 	if lap.Solusi == "" {
-		var respn model.Response
-		respn.Status = "Error : Telepon atau nama atau solusi tidak diisi"
-		respn.Response = "Isi lebih lengkap dahulu"
-		at.WriteJSON(respw, http.StatusBadRequest, respn)
+		respon.Error(respw, http.StatusBadRequest, "Telepon atau nama atau solusi tidak diisi", "Isi lebih lengkap dahulu")
 		return
 	}
 	//check validasi user
-	docuser, err := atdb.GetOneDoc[model.Userdomyikado](config.Mongoconn, "user", primitive.M{"phonenumber": payload.Id})
+	docuser, err := atdb.GetOneDoc[model.Userdomyikado](config.Mongoconn(), "user", primitive.M{"phonenumber": payload.Id})
 	if err != nil {
-		var respn model.Response
-		respn.Status = "Error : Data user tidak di temukan: " + payload.Id
-		respn.Response = err.Error()
-		at.WriteJSON(respw, http.StatusNotImplemented, respn)
+		respon.Error(respw, http.StatusNotImplemented, "Data user tidak di temukan: "+payload.Id, err.Error())
 		return
 	}
 	//ambil data project
 	prjobjectId, err := primitive.ObjectIDFromHex(lap.Kode)
 	if err != nil {
-		var respn model.Response
-		respn.Status = "Error : ObjectID Tidak Valid"
-		respn.Info = lap.Kode
-		respn.Location = "Encode Object ID Error"
-		respn.Response = err.Error()
-		at.WriteJSON(respw, http.StatusBadRequest, respn)
+		respon.Error(respw, http.StatusBadRequest, "ObjectID Tidak Valid", err.Error())
 		return
 	}
-	prjuser, err := atdb.GetOneDoc[model.Project](config.Mongoconn, "project", primitive.M{"_id": prjobjectId})
+	prjuser, err := atdb.GetOneDoc[model.Project](config.Mongoconn(), "project", primitive.M{"_id": prjobjectId})
 	if err != nil {
-		var respn model.Response
-		respn.Status = "Error : Data project tidak di temukan: " + lap.Kode
-		respn.Response = err.Error()
-		at.WriteJSON(respw, http.StatusNotImplemented, respn)
+		respon.Error(respw, http.StatusNotImplemented, "Data project tidak di temukan: "+lap.Kode, err.Error())
 		return
 	}
 	//lojik inputan post
@@ -571,20 +434,14 @@ func PostLaporan(respw http.ResponseWriter, req *http.Request) {
 	lap.Petugas = docuser.Name
 	lap.NoPetugas = docuser.PhoneNumber
 
-	idlap, err := atdb.InsertOneDoc(config.Mongoconn, "uxlaporan", lap)
+	idlap, err := atdb.InsertOneDoc(config.Mongoconn(), "uxlaporan", lap)
 	if err != nil {
-		var respn model.Response
-		respn.Status = "Gagal Insert Database"
-		respn.Response = err.Error()
-		at.WriteJSON(respw, http.StatusNotModified, respn)
+		respon.Error(respw, http.StatusInternalServerError, "Gagal Insert Database", err.Error())
 		return
 	}
-	_, err = report.TambahPoinLaporanbyPhoneNumber(config.Mongoconn, prjuser, docuser.PhoneNumber, 1, "laporan")
+	_, err = report.TambahPoinLaporanbyPhoneNumber(config.Mongoconn(), prjuser, docuser.PhoneNumber, 1, "laporan")
 	if err != nil {
-		var resp model.Response
-		resp.Info = "TambahPoinPushRepobyGithubUsername gagal"
-		resp.Response = err.Error()
-		at.WriteJSON(respw, http.StatusExpectationFailed, resp)
+		respon.Error(respw, http.StatusExpectationFailed, "Gagal menambah poin", err.Error())
 		return
 	}
 	message := "*Permintaan Feedback Pekerjaan*\n" + "Petugas : " + docuser.Name + "\nDeskripsi:" + lap.Solusi + "\n Beri Nilai: " + "https://www.do.my.id/rate/#" + idlap.Hex()
@@ -593,14 +450,12 @@ func PostLaporan(respw http.ResponseWriter, req *http.Request) {
 		IsGroup:  false,
 		Messages: message,
 	}
-	_, resp, err := atapi.PostStructWithToken[model.Response]("Token", config.WAAPIToken, dt, config.WAAPIMessage)
+	_, _, err = atapi.PostStructWithToken[model.Response]("Token", config.WAAPIToken, dt, config.WAAPIMessage)
 	if err != nil {
-		resp.Info = "Tidak berhak"
-		resp.Response = err.Error()
-		at.WriteJSON(respw, http.StatusUnauthorized, resp)
+		respon.Error(respw, http.StatusUnauthorized, "Gagal mengirim pesan WhatsApp", err.Error())
 		return
 	}
-	at.WriteJSON(respw, http.StatusOK, lap)
+	respon.OK(respw, lap)
 
 }
 
@@ -608,56 +463,34 @@ func PostFeedback(respw http.ResponseWriter, req *http.Request) {
 	//otorisasi dan validasi inputan
 	payload, err := watoken.Decode(config.PublicKeyWhatsAuth, at.GetLoginFromHeader(req))
 	if err != nil {
-		var respn model.Response
-		respn.Status = "Error : Token Tidak Valid"
-		respn.Info = at.GetSecretFromHeader(req)
-		respn.Location = "Decode Token Error"
-		respn.Response = err.Error()
-		at.WriteJSON(respw, http.StatusForbidden, respn)
+		respon.Error(respw, http.StatusForbidden, "Token Tidak Valid", err.Error())
 		return
 	}
 	var lap model.Laporan
 	err = json.NewDecoder(req.Body).Decode(&lap)
 	if err != nil {
-		var respn model.Response
-		respn.Status = "Error : Body tidak valid"
-		respn.Response = err.Error()
-		at.WriteJSON(respw, http.StatusBadRequest, respn)
+		respon.Error(respw, http.StatusBadRequest, "Body tidak valid", err.Error())
 		return
 	}
 	if lap.Phone == "" || lap.Nama == "" || lap.Solusi == "" {
-		var respn model.Response
-		respn.Status = "Error : Telepon atau nama atau solusi tidak diisi"
-		respn.Response = "Isi lebih lengkap dahulu"
-		at.WriteJSON(respw, http.StatusBadRequest, respn)
+		respon.Error(respw, http.StatusBadRequest, "Telepon atau nama atau solusi tidak diisi", "Isi lebih lengkap dahulu")
 		return
 	}
 	//validasi eksistensi user di db
-	docuser, err := atdb.GetOneDoc[model.Userdomyikado](config.Mongoconn, "user", primitive.M{"phonenumber": payload.Id})
+	docuser, err := atdb.GetOneDoc[model.Userdomyikado](config.Mongoconn(), "user", primitive.M{"phonenumber": payload.Id})
 	if err != nil {
-		var respn model.Response
-		respn.Status = "Error : Data user tidak di temukan"
-		respn.Response = err.Error()
-		at.WriteJSON(respw, http.StatusNotImplemented, respn)
+		respon.Error(respw, http.StatusNotImplemented, "Data user tidak di temukan", err.Error())
 		return
 	}
 	//ambil data project
 	prjobjectId, err := primitive.ObjectIDFromHex(lap.Kode)
 	if err != nil {
-		var respn model.Response
-		respn.Status = "Error : ObjectID Tidak Valid"
-		respn.Info = lap.Kode
-		respn.Location = "Encode Object ID Error"
-		respn.Response = err.Error()
-		at.WriteJSON(respw, http.StatusBadRequest, respn)
+		respon.Error(respw, http.StatusBadRequest, "ObjectID Tidak Valid", err.Error())
 		return
 	}
-	prjuser, err := atdb.GetOneDoc[model.Project](config.Mongoconn, "project", primitive.M{"_id": prjobjectId})
+	prjuser, err := atdb.GetOneDoc[model.Project](config.Mongoconn(), "project", primitive.M{"_id": prjobjectId})
 	if err != nil {
-		var respn model.Response
-		respn.Status = "Error : Data project tidak di temukan: " + lap.Kode
-		respn.Response = err.Error()
-		at.WriteJSON(respw, http.StatusNotImplemented, respn)
+		respon.Error(respw, http.StatusNotImplemented, "Data project tidak di temukan: "+lap.Kode, err.Error())
 		return
 	}
 	//lojik inputan post
@@ -667,20 +500,14 @@ func PostFeedback(respw http.ResponseWriter, req *http.Request) {
 	lap.Petugas = docuser.Name
 	lap.NoPetugas = docuser.PhoneNumber
 
-	idlap, err := atdb.InsertOneDoc(config.Mongoconn, "uxlaporan", lap)
+	idlap, err := atdb.InsertOneDoc(config.Mongoconn(), "uxlaporan", lap)
 	if err != nil {
-		var respn model.Response
-		respn.Status = "Gagal Insert Database"
-		respn.Response = err.Error()
-		at.WriteJSON(respw, http.StatusNotModified, respn)
+		respon.Error(respw, http.StatusInternalServerError, "Gagal Insert Database", err.Error())
 		return
 	}
-	_, err = report.TambahPoinLaporanbyPhoneNumber(config.Mongoconn, prjuser, docuser.PhoneNumber, 1, "feedback")
+	_, err = report.TambahPoinLaporanbyPhoneNumber(config.Mongoconn(), prjuser, docuser.PhoneNumber, 1, "feedback")
 	if err != nil {
-		var resp model.Response
-		resp.Info = "TambahPoinLaporanbyPhoneNumber gagal"
-		resp.Response = err.Error()
-		at.WriteJSON(respw, http.StatusExpectationFailed, resp)
+		respon.Error(respw, http.StatusExpectationFailed, "Gagal menambah poin", err.Error())
 		return
 	}
 	message := "*Permintaan Feedback*\n" + "Petugas : " + docuser.Name + "\nDeskripsi:" + lap.Solusi + "\n Beri Nilai: " + "https://www.do.my.id/rate/#" + idlap.Hex()
@@ -689,14 +516,12 @@ func PostFeedback(respw http.ResponseWriter, req *http.Request) {
 		IsGroup:  false,
 		Messages: message,
 	}
-	_, resp, err := atapi.PostStructWithToken[model.Response]("Token", config.WAAPIToken, dt, config.WAAPIMessage)
+	_, _, err = atapi.PostStructWithToken[model.Response]("Token", config.WAAPIToken, dt, config.WAAPIMessage)
 	if err != nil {
-		resp.Info = "Tidak berhak"
-		resp.Response = err.Error()
-		at.WriteJSON(respw, http.StatusUnauthorized, resp)
+		respon.Error(respw, http.StatusUnauthorized, "Gagal mengirim pesan WhatsApp", err.Error())
 		return
 	}
-	at.WriteJSON(respw, http.StatusOK, lap)
+	respon.OK(respw, lap)
 
 }
 
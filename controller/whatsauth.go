@@ -2,13 +2,18 @@ package controller
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 	"sync"
 
 	"github.com/gocroot/config"
 	"github.com/gocroot/helper/at"
 	"github.com/gocroot/helper/atdb"
+	"github.com/gocroot/helper/berulang"
 	"github.com/gocroot/helper/report"
+	"github.com/gocroot/helper/respon"
+	"github.com/gocroot/helper/tagihan"
 	"github.com/gocroot/helper/whatsauth"
 	"github.com/gocroot/model"
 	"github.com/whatsauth/itmodel"
@@ -16,9 +21,7 @@ import (
 )
 
 func GetHome(respw http.ResponseWriter, req *http.Request) {
-	var resp model.Response
-	resp.Response = at.GetIPaddress()
-	at.WriteJSON(respw, http.StatusOK, resp)
+	respon.OK(respw, bson.M{"ip": at.GetIPaddress()})
 }
 
 func PostInboxNomor(respw http.ResponseWriter, req *http.Request) {
@@ -27,7 +30,7 @@ func PostInboxNomor(respw http.ResponseWriter, req *http.Request) {
 	httpstatus := http.StatusUnauthorized
 	resp.Response = "Wrong Secret"
 	waphonenumber := at.GetParam(req)
-	prof, err := whatsauth.GetAppProfile(waphonenumber, config.Mongoconn)
+	prof, err := whatsauth.GetAppProfile(waphonenumber, config.Mongoconn())
 	if err != nil {
 		resp.Response = err.Error()
 		httpstatus = http.StatusServiceUnavailable
@@ -37,7 +40,7 @@ func PostInboxNomor(respw http.ResponseWriter, req *http.Request) {
 		if err != nil {
 			resp.Response = err.Error()
 		} else {
-			resp, err = whatsauth.WebHook(prof, msg, config.Mongoconn)
+			resp, err = whatsauth.WebHook(req.Context(), prof, msg, config.Mongoconn())
 			if err != nil {
 				resp.Response = err.Error()
 			}
@@ -46,87 +49,70 @@ func PostInboxNomor(respw http.ResponseWriter, req *http.Request) {
 	at.WriteJSON(respw, httpstatus, resp)
 }
 
-// jalan setiap jam 3 pagi
+// GetNewToken menjalankan job harian dari scheduler setiap jam 3 pagi: refresh token WhatsApp, rekap,
+// pengingat piutang dan pengeluaran rutin. Semua job tetap dijalankan walau ada yang gagal.
 func GetNewToken(respw http.ResponseWriter, req *http.Request) {
-	var resp model.Response
-	httpstatus := http.StatusServiceUnavailable
-
-	var wg sync.WaitGroup
-	wg.Add(3) // Menambahkan jumlah goroutine yang akan dijalankan
-
-	// Mutex untuk mengamankan akses ke variabel resp dan httpstatus
-	var mu sync.Mutex
-	// Variabel untuk menyimpan kesalahan terakhir
-	var lastErr error
+	var (
+		wg        sync.WaitGroup
+		mu        sync.Mutex
+		errs      []error
+		refreshed int64
+	)
+	fail := func(job string, err error) {
+		mu.Lock()
+		errs = append(errs, fmt.Errorf("%s: %w", job, err))
+		mu.Unlock()
+	}
+	run := func(job string, fn func() error) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := fn(); err != nil {
+				fail(job, err)
+			}
+		}()
+	}
 
-	// 1. Refresh token
-	go func() {
-		defer wg.Done() // Memanggil wg.Done() setelah fungsi selesai
-		profs, err := atdb.GetAllDoc[[]model.Profile](config.Mongoconn, "profile", bson.M{})
+	// 1. Refresh token, profil yang gagal tidak menghentikan profil lain
+	run("refresh token", func() error {
+		profs, err := atdb.GetAllDoc[[]model.Profile](config.Mongoconn(), "profile", bson.M{})
 		if err != nil {
-			mu.Lock()
-			lastErr = err
-			resp.Response = err.Error()
-			mu.Unlock()
-			return
+			return err
 		}
 		for _, prof := range profs {
 			dt := &itmodel.WebHook{
 				URL:    prof.URL,
 				Secret: prof.Secret,
 			}
-			res, err := whatsauth.RefreshToken(dt, prof.Phonenumber, config.WAAPIGetToken, config.Mongoconn)
+			res, err := whatsauth.RefreshToken(dt, prof.Phonenumber, config.WAAPIGetToken, config.Mongoconn())
 			if err != nil {
-				mu.Lock()
-				lastErr = err
-				resp.Response = err.Error()
-				httpstatus = http.StatusInternalServerError
-				mu.Unlock()
-				continue // Lanjutkan ke iterasi berikutnya
+				fail("refresh token "+prof.Phonenumber, err)
+				continue
 			}
 			mu.Lock()
-			resp.Response = at.Jsonstr(res.ModifiedCount)
-			httpstatus = http.StatusOK
-			mu.Unlock()
-		}
-	}()
-
-	// 2. Menjalankan fungsi RekapMeetingKemarin dalam goroutine
-	go func() {
-		defer wg.Done() // Memanggil wg.Done() setelah fungsi selesai
-		if err := report.RekapMeetingKemarin(config.Mongoconn); err != nil {
-			mu.Lock()
-			lastErr = err
-			resp.Response = err.Error()
-			httpstatus = http.StatusInternalServerError
-			mu.Unlock()
-		}
-	}()
-
-	// 3. Menjalankan fungsi RekapPagiHari dalam goroutine
-	go func() {
-		defer wg.Done() // Memanggil wg.Done() setelah fungsi selesai
-		if err := report.RekapPagiHari(config.Mongoconn); err != nil {
-			mu.Lock()
-			lastErr = err
-			resp.Response = err.Error()
-			httpstatus = http.StatusInternalServerError
+			refreshed += res.ModifiedCount
 			mu.Unlock()
 		}
-	}()
-
-	wg.Wait() // Menunggu sampai semua goroutine selesai
+		return nil
+	})
+	// 2. Rekap meeting kemarin dan 3. rekap pagi hari
+	run("rekap meeting", func() error { return report.RekapMeetingKemarin(config.Mongoconn()) })
+	run("rekap pagi", func() error { return report.RekapPagiHari(config.Mongoconn()) })
+	// 4. Mengirim pengingat piutang penjualan kredit ke WhatsApp pelanggan
+	run("pengingat piutang", func() error { return tagihan.KirimPengingatPiutang(config.Mongoconn()) })
+	// 5. Membuat pengeluaran rutin yang jatuh tempo dan mengirim notifikasinya ke WhatsApp owner
+	run("pengeluaran rutin", func() error {
+		return berulang.BuatPengeluaranRutin(config.Mongoconn(), config.WAAPIToken, config.WAAPIMessage)
+	})
+	wg.Wait()
 
-	// Menggunakan status yang benar dari kesalahan terakhir jika ada
-	if lastErr != nil {
-		at.WriteJSON(respw, httpstatus, resp)
-	} else {
-		at.WriteJSON(respw, http.StatusOK, resp)
+	if err := errors.Join(errs...); err != nil {
+		respon.Error(respw, http.StatusInternalServerError, "Sebagian job harian gagal", err.Error())
+		return
 	}
+	respon.Success(respw, http.StatusOK, "Job harian selesai", bson.M{"token_refreshed": refreshed})
 }
 
 func NotFound(respw http.ResponseWriter, req *http.Request) {
-	var resp model.Response
-	resp.Response = "Not Found"
-	at.WriteJSON(respw, http.StatusNotFound, resp)
+	respon.Error(respw, http.StatusNotFound, "Endpoint tidak ditemukan", req.Method+" "+req.URL.Path)
 }
@@ -0,0 +1,172 @@
+package absensi
+
+import (
+	"math"
+	"time"
+
+	"github.com/gocroot/helper/atdb"
+	"github.com/gocroot/helper/audit"
+	"github.com/gocroot/helper/invoice"
+	"github.com/gocroot/mod/presensi"
+	"github.com/gocroot/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// ParseMonth mengubah YYYY-MM menjadi awal dan akhir bulan dalam WIB
+func ParseMonth(month string) (start, end time.Time, err error) {
+	start, err = time.ParseInLocation("2006-01", month, WIB)
+	if err != nil {
+		return
+	}
+	end = start.AddDate(0, 1, 0).Add(-time.Nanosecond)
+	return
+}
+
+// ValidShiftStart memeriksa format jam masuk HH:MM, kosong dianggap valid
+func ValidShiftStart(s string) error {
+	if s == "" {
+		return nil
+	}
+	if _, err := time.Parse("15:04", s); err != nil {
+		return ErrShiftStart
+	}
+	return nil
+}
+
+// ScheduleOf menyusun jadwal kerja pegawai dari profil toko, jam masuk pegawai menimpa jam masuk toko
+func ScheduleOf(store model.StoreProfile, emp model.Employee) Schedule {
+	sched := Schedule{ShiftStart: store.ShiftStart, Grace: store.LateGraceMinutes, WorkDays: store.WorkDays}
+	if emp.ShiftStart != "" {
+		sched.ShiftStart = emp.ShiftStart
+	}
+	if ValidShiftStart(sched.ShiftStart) != nil || sched.ShiftStart == "" {
+		sched.ShiftStart = DefaultShiftStart
+	}
+	if len(sched.WorkDays) == 0 {
+		sched.WorkDays = DefaultWorkDays
+	}
+	return sched
+}
+
+// Summarize merekap presensi satu pegawai dalam satu bulan.
+// Hari kerja yang belum lewat tidak dihitung tidak hadir, presensi hari ini tetap dihitung hadir.
+// Jam kerja dihitung dari presensi masuk pertama sampai presensi pulang terakhir.
+func Summarize(emp model.Employee, sched Schedule, records []presensi.PresensiLokasi, month string, now time.Time) (summary model.AttendanceSummary) {
+	summary = model.AttendanceSummary{EmployeeID: emp.ID, Name: emp.Name, Month: month}
+	start, end, err := ParseMonth(month)
+	if err != nil {
+		return
+	}
+	days := make(map[string]*model.AttendanceDay)
+	for _, r := range records {
+		at := r.CreatedAt.In(WIB)
+		date := at.Format("2006-01-02")
+		day, ok := days[date]
+		if !ok {
+			day = &model.AttendanceDay{Date: date}
+			days[date] = day
+		}
+		if r.IsMasuk {
+			if day.CheckIn == nil || at.Before(*day.CheckIn) {
+				day.CheckIn = &at
+				day.Location = r.Lokasi.Nama
+			}
+		} else if day.CheckOut == nil || at.After(*day.CheckOut) {
+			day.CheckOut = &at
+		}
+	}
+
+	shift, _ := time.Parse("15:04", sched.ShiftStart)
+	workday := make(map[time.Weekday]bool)
+	for _, d := range sched.WorkDays {
+		workday[time.Weekday(d)] = true
+	}
+	today := now.In(WIB).Format("2006-01-02")
+	for d := start; !d.After(end); d = d.AddDate(0, 0, 1) {
+		date := d.Format("2006-01-02")
+		if date > today {
+			break
+		}
+		day, present := days[date]
+		if present && day.CheckIn == nil {
+			// hanya presensi pulang tanpa masuk tidak dihitung hadir
+			present = false
+		}
+		scheduled := workday[d.Weekday()]
+		if !present {
+			if scheduled && date < today {
+				summary.WorkDays++
+				summary.Absent++
+				summary.Days = append(summary.Days, model.AttendanceDay{Date: date, Absent: true})
+			}
+			continue
+		}
+		if scheduled {
+			summary.WorkDays++
+			due := time.Date(d.Year(), d.Month(), d.Day(), shift.Hour(), shift.Minute(), 0, 0, WIB)
+			if late := day.CheckIn.Sub(due); late > time.Duration(sched.Grace)*time.Minute {
+				day.Late = true
+				day.LateMinutes = int(late.Minutes())
+				summary.Late++
+				summary.LateMinutes += day.LateMinutes
+			}
+		}
+		if day.CheckOut != nil && day.CheckOut.After(*day.CheckIn) {
+			day.WorkedHours = math.Round(day.CheckOut.Sub(*day.CheckIn).Hours()*100) / 100
+			summary.WorkedHours += day.WorkedHours
+		}
+		summary.Present++
+		summary.Days = append(summary.Days, *day)
+	}
+	summary.WorkedHours = math.Round(summary.WorkedHours*100) / 100
+	return
+}
+
+// Monthly merekap kehadiran semua pegawai toko dalam satu bulan, employeeID kosong berarti semua pegawai
+func Monthly(db *mongo.Database, owner, month string, employeeID primitive.ObjectID) (summaries []model.AttendanceSummary, err error) {
+	start, end, err := ParseMonth(month)
+	if err != nil {
+		return
+	}
+	empFilter := audit.Live(bson.M{"owner": owner})
+	recFilter := bson.M{"owner": owner, "createdAt": bson.M{"$gte": start, "$lte": end}}
+	if !employeeID.IsZero() {
+		empFilter["_id"] = employeeID
+		recFilter["employee_id"] = employeeID
+	}
+	employees, err := atdb.GetAllDoc[[]model.Employee](db, EmployeeCollection, empFilter)
+	if err != nil {
+		return
+	}
+	records, err := atdb.GetAllDoc[[]presensi.PresensiLokasi](db, PresensiCollection, recFilter)
+	if err != nil {
+		return
+	}
+	byEmployee := make(map[primitive.ObjectID][]presensi.PresensiLokasi)
+	for _, r := range records {
+		byEmployee[r.EmployeeID] = append(byEmployee[r.EmployeeID], r)
+	}
+	store := invoice.Store(db, owner)
+	now := time.Now()
+	summaries = []model.AttendanceSummary{}
+	for _, emp := range employees {
+		summaries = append(summaries, Summarize(emp, ScheduleOf(store, emp), byEmployee[emp.ID], month, now))
+	}
+	return
+}
+
+// ByEmployee merekap kehadiran sebulan dan mengelompokkannya per pegawai untuk payroll
+func ByEmployee(db *mongo.Database, owner, month string) (map[primitive.ObjectID]model.AttendanceSummary, error) {
+	summaries, err := Monthly(db, owner, month, primitive.NilObjectID)
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[primitive.ObjectID]model.AttendanceSummary)
+	for _, s := range summaries {
+		s.Days = nil
+		result[s.EmployeeID] = s
+	}
+	return result, nil
+}
@@ -0,0 +1,55 @@
+package absensi
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gocroot/mod/presensi"
+	"github.com/gocroot/model"
+)
+
+func record(masuk bool, s string) presensi.PresensiLokasi {
+	at, _ := time.ParseInLocation("2006-01-02 15:04", s, WIB)
+	return presensi.PresensiLokasi{IsMasuk: masuk, CreatedAt: at.UTC(), Lokasi: presensi.Lokasi{Nama: "Toko"}}
+}
+
+func TestSummarize(t *testing.T) {
+	sched := ScheduleOf(model.StoreProfile{LateGraceMinutes: 10}, model.Employee{})
+	records := []presensi.PresensiLokasi{
+		record(true, "2026-03-02 07:55"), // Senin, tepat waktu
+		record(false, "2026-03-02 16:55"),
+		record(true, "2026-03-03 08:30"), // Selasa, terlambat 30 menit
+		record(false, "2026-03-03 17:00"),
+		record(true, "2026-03-04 08:05"), // Rabu, masih dalam toleransi
+		record(true, "2026-03-08 09:00"), // Minggu, hadir di luar hari kerja
+	}
+	now, _ := time.ParseInLocation("2006-01-02 15:04", "2026-03-09 07:00", WIB)
+	s := Summarize(model.Employee{Name: "Sari"}, sched, records, "2026-03", now)
+
+	// 1-8 Maret: hari kerja 2,3,4,5,6,7 Maret, hari ini 9 Maret belum presensi
+	if s.WorkDays != 6 || s.Present != 4 || s.Absent != 3 {
+		t.Errorf("summary = %+v", s)
+	}
+	if s.Late != 1 || s.LateMinutes != 30 {
+		t.Errorf("late = %d (%d menit)", s.Late, s.LateMinutes)
+	}
+	if s.WorkedHours != 17.5 {
+		t.Errorf("worked hours = %v", s.WorkedHours)
+	}
+	if len(s.Days) != 7 || s.Days[0].Location != "Toko" {
+		t.Errorf("days = %+v", s.Days)
+	}
+}
+
+func TestScheduleOf(t *testing.T) {
+	sched := ScheduleOf(model.StoreProfile{ShiftStart: "09:00", WorkDays: []int{1, 2, 3, 4, 5}}, model.Employee{ShiftStart: "14:00"})
+	if sched.ShiftStart != "14:00" || len(sched.WorkDays) != 5 {
+		t.Errorf("schedule = %+v", sched)
+	}
+	if ScheduleOf(model.StoreProfile{ShiftStart: "jam 8"}, model.Employee{}).ShiftStart != DefaultShiftStart {
+		t.Error("jam masuk tidak valid harus memakai default")
+	}
+	if ValidShiftStart("25:00") == nil {
+		t.Error("jam 25:00 harus ditolak")
+	}
+}
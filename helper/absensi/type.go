@@ -0,0 +1,33 @@
+package absensi
+
+import (
+	"errors"
+	"time"
+
+	"github.com/gocroot/helper/repo"
+)
+
+// Nama koleksi yang dipakai rekap kehadiran
+const (
+	PresensiCollection = "presensi"
+	LokasiCollection   = "lokasi"
+	EmployeeCollection = repo.EmployeeCollection
+)
+
+// DefaultShiftStart adalah jam masuk jika profil toko dan pegawai tidak mengaturnya
+const DefaultShiftStart = "08:00"
+
+// DefaultWorkDays adalah hari kerja Senin sampai Sabtu
+var DefaultWorkDays = []int{1, 2, 3, 4, 5, 6}
+
+// WIB adalah zona waktu jadwal kerja dan tanggal presensi
+var WIB = time.FixedZone("WIB", 7*3600)
+
+// Schedule adalah jadwal kerja yang dipakai menilai terlambat dan tidak hadir
+type Schedule struct {
+	ShiftStart string // HH:MM WIB
+	Grace      int    // Toleransi terlambat dalam menit
+	WorkDays   []int  // 0=Minggu sampai 6=Sabtu
+}
+
+var ErrShiftStart = errors.New("shift_start harus berformat HH:MM")
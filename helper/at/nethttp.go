@@ -3,7 +3,7 @@ package at
 import (
 	"encoding/json"
 	"fmt"
-	"log"
+	"log/slog"
 	"net/http"
 )
 
@@ -28,7 +28,7 @@ func GetLoginFromHeader(r *http.Request) (secret string) {
 func Jsonstr(strc interface{}) string {
 	jsonData, err := json.Marshal(strc)
 	if err != nil {
-		log.Fatal(err)
+		slog.Error("gagal mengubah data ke JSON", "error", err)
 	}
 	return string(jsonData)
 }
@@ -3,7 +3,7 @@ package at
 import (
 	"errors"
 	"io"
-	"log"
+	"log/slog"
 	"net"
 	"net/http"
 	"os"
@@ -75,7 +75,8 @@ func GetIPaddress() string {
 	resp, err := http.Get("https://icanhazip.com/")
 
 	if err != nil {
-		log.Fatal(err)
+		slog.Error("gagal mengambil IP publik", "error", err)
+		return ""
 	}
 
 	defer resp.Body.Close()
@@ -83,7 +84,8 @@ func GetIPaddress() string {
 	body, err := io.ReadAll(resp.Body)
 
 	if err != nil {
-		log.Fatal(err)
+		slog.Error("gagal membaca IP publik", "error", err)
+		return ""
 	}
 	return string(body)
 }
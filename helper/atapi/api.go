@@ -2,21 +2,45 @@ package atapi
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"io"
 	"net/http"
+	"time"
+
+	"github.com/gocroot/helper/logger"
+	"github.com/gocroot/helper/metrik"
 )
 
 func PostStructWithToken[T any](tokenkey string, tokenvalue string, structname interface{}, urltarget string) (statusCode int, result T, err error) {
+	return PostStructWithTokenContext[T](context.Background(), tokenkey, tokenvalue, structname, urltarget)
+}
+
+// PostStructWithTokenContext sama dengan PostStructWithToken, ID request dari ctx diteruskan lewat header X-Request-ID
+// dan hasil setiap POST dicatat ke log dan metrik
+func PostStructWithTokenContext[T any](ctx context.Context, tokenkey string, tokenvalue string, structname interface{}, urltarget string) (statusCode int, result T, err error) {
+	start := time.Now()
+	defer func() {
+		metrik.ObservePost(urltarget, statusCode, err, time.Since(start))
+		log := logger.FromContext(ctx).With("target", urltarget, "status", statusCode, "duration", time.Since(start))
+		if err != nil || statusCode < 200 || statusCode > 299 {
+			log.Warn("atapi post gagal", "error", err)
+		} else {
+			log.Debug("atapi post")
+		}
+	}()
 	client := http.Client{}
 	mJson, _ := json.Marshal(structname)
-	req, err := http.NewRequest("POST", urltarget, bytes.NewBuffer(mJson))
+	req, err := http.NewRequestWithContext(ctx, "POST", urltarget, bytes.NewBuffer(mJson))
 	if err != nil {
 		return
 	}
 	req.Header.Add("Content-Type", "application/json")
 	req.Header.Add(tokenkey, tokenvalue)
+	if id := logger.RequestID(ctx); id != "" {
+		req.Header.Set(logger.HeaderRequestID, id)
+	}
 	resp, err := client.Do(req)
 	if err != nil {
 		return
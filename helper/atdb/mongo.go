@@ -8,17 +8,19 @@ import (
 	"strings"
 	"time"
 
+	"github.com/gocroot/helper/metrik"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
+// MongoConnect membuka koneksi, lama setiap perintah dicatat ke metrik mongo_command_duration_seconds
 func MongoConnect(mconn DBInfo) (db *mongo.Database, err error) {
-	client, err := mongo.Connect(context.TODO(), options.Client().ApplyURI(mconn.DBString))
+	client, err := mongo.Connect(context.TODO(), options.Client().ApplyURI(mconn.DBString).SetMonitor(metrik.MongoMonitor()))
 	if err != nil {
 		mconn.DBString = SRVLookup(mconn.DBString)
-		client, err = mongo.Connect(context.TODO(), options.Client().ApplyURI(mconn.DBString))
+		client, err = mongo.Connect(context.TODO(), options.Client().ApplyURI(mconn.DBString).SetMonitor(metrik.MongoMonitor()))
 		if err != nil {
 			return
 		}
@@ -27,6 +29,33 @@ func MongoConnect(mconn DBInfo) (db *mongo.Database, err error) {
 	return
 }
 
+// EnsureUniqueIndex membuat index unik pada field, dokumen yang tidak punya field tersebut tidak ikut diperiksa
+func EnsureUniqueIndex(db *mongo.Database, collection, field string) (err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	_, err = db.Collection(collection).Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: field, Value: 1}},
+		Options: options.Index().SetUnique(true).
+			SetPartialFilterExpression(bson.M{field: bson.M{"$type": "string"}}),
+	})
+	return
+}
+
+// EnsureCompoundUniqueIndex membuat index unik gabungan beberapa field, field yang tidak ada dianggap null
+func EnsureCompoundUniqueIndex(db *mongo.Database, collection string, fields ...string) (err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	keys := bson.D{}
+	for _, field := range fields {
+		keys = append(keys, bson.E{Key: field, Value: 1})
+	}
+	_, err = db.Collection(collection).Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    keys,
+		Options: options.Index().SetUnique(true),
+	})
+	return
+}
+
 func SRVLookup(srvuri string) (mongouri string) {
 	atsplits := strings.Split(srvuri, "@")
 	userpass := strings.Split(atsplits[0], "//")[1]
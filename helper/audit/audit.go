@@ -0,0 +1,142 @@
+package audit
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/gocroot/helper/at"
+	"github.com/gocroot/helper/atdb"
+	"github.com/gocroot/helper/tenant"
+	"github.com/gocroot/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// FromRequest mengambil pelaku perubahan dari sesi login dan IP klien
+func FromRequest(req *http.Request) (actor Actor) {
+	if s, ok := tenant.FromRequest(req); ok {
+		actor = Actor{Owner: s.Owner, User: s.Phone, Role: s.Role}
+	}
+	actor.IP, _ = at.GetClientIP(req)
+	return
+}
+
+// Live menambahkan syarat data belum dihapus ke filter
+func Live(filter bson.M) bson.M {
+	if filter == nil {
+		filter = bson.M{}
+	}
+	filter["deleted_at"] = nil
+	return filter
+}
+
+// Deleted menambahkan syarat data sudah dihapus ke filter
+func Deleted(filter bson.M) bson.M {
+	if filter == nil {
+		filter = bson.M{}
+	}
+	filter["deleted_at"] = bson.M{"$ne": nil}
+	return filter
+}
+
+// Snapshot mengubah dokumen menjadi map field bson, nil tetap nil
+func Snapshot(doc interface{}) bson.M {
+	if doc == nil {
+		return nil
+	}
+	raw, err := bson.Marshal(doc)
+	if err != nil {
+		return nil
+	}
+	var m bson.M
+	if err = bson.Unmarshal(raw, &m); err != nil {
+		return nil
+	}
+	return m
+}
+
+// Diff membandingkan dua snapshot dan mengembalikan field yang berubah, urut nama field
+func Diff(before, after bson.M) (changes []model.FieldChange) {
+	fields := map[string]bool{}
+	for k := range before {
+		fields[k] = true
+	}
+	for k := range after {
+		fields[k] = true
+	}
+	for k := range fields {
+		if ignored[k] || reflect.DeepEqual(before[k], after[k]) {
+			continue
+		}
+		changes = append(changes, model.FieldChange{Field: k, Before: before[k], After: after[k]})
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Field < changes[j].Field })
+	return
+}
+
+// Log mencatat satu perubahan entitas. Update tanpa perubahan field tidak dicatat.
+func Log(db *mongo.Database, actor Actor, entity string, id primitive.ObjectID, action string, before, after interface{}) (err error) {
+	entry := model.AuditLog{
+		Owner:     actor.Owner,
+		Entity:    entity,
+		EntityID:  id,
+		Action:    action,
+		Before:    Snapshot(before),
+		After:     Snapshot(after),
+		User:      actor.User,
+		Role:      actor.Role,
+		IP:        actor.IP,
+		CreatedAt: time.Now(),
+	}
+	if action == ActionUpdate {
+		entry.Changes = Diff(entry.Before, entry.After)
+		if len(entry.Changes) == 0 {
+			return nil
+		}
+	}
+	_, err = atdb.InsertOneDoc(db, Collection, entry)
+	return
+}
+
+// SoftDelete menandai data milik owner sebagai terhapus lalu mencatatnya di audit log.
+// Data yang dikembalikan adalah isi sebelum dihapus; mongo.ErrNoDocuments jika tidak ada atau sudah terhapus.
+func SoftDelete[T any](db *mongo.Database, actor Actor, entity string, id primitive.ObjectID) (doc T, err error) {
+	coll, ok := Collections[entity]
+	if !ok {
+		return doc, ErrUnknownEntity
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	filter := Live(bson.M{"_id": id, "owner": actor.Owner})
+	update := bson.M{"$set": bson.M{"deleted_at": time.Now(), "deleted_by": actor.User}}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.Before)
+	if err = db.Collection(coll).FindOneAndUpdate(ctx, filter, update, opts).Decode(&doc); err != nil {
+		return
+	}
+	err = Log(db, actor, entity, id, ActionDelete, doc, nil)
+	return
+}
+
+// Restore mengembalikan data yang terhapus lalu mencatatnya di audit log.
+// mongo.ErrNoDocuments jika data tidak ada atau tidak sedang terhapus.
+func Restore[T any](db *mongo.Database, actor Actor, entity string, id primitive.ObjectID) (doc T, err error) {
+	coll, ok := Collections[entity]
+	if !ok {
+		return doc, ErrUnknownEntity
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	filter := Deleted(bson.M{"_id": id, "owner": actor.Owner})
+	update := bson.M{"$unset": bson.M{"deleted_at": "", "deleted_by": ""}}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	if err = db.Collection(coll).FindOneAndUpdate(ctx, filter, update, opts).Decode(&doc); err != nil {
+		return
+	}
+	err = Log(db, actor, entity, id, ActionRestore, nil, doc)
+	return
+}
@@ -0,0 +1,44 @@
+package audit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gocroot/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestDiff(t *testing.T) {
+	id := primitive.NewObjectID()
+	before := model.Product{ID: id, Owner: "62811", Name: "Kopi", Price: 10000, Stock: 5, UpdatedAt: time.Now()}
+	after := before
+	after.Price = 12000
+	after.UpdatedAt = time.Now().Add(time.Hour)
+
+	changes := Diff(Snapshot(before), Snapshot(after))
+	if len(changes) != 1 || changes[0].Field != "price" || changes[0].Before != 10000.0 || changes[0].After != 12000.0 {
+		t.Errorf("changes = %+v", changes)
+	}
+	if len(Diff(Snapshot(before), Snapshot(before))) != 0 {
+		t.Error("dokumen yang sama tidak boleh punya perubahan")
+	}
+
+	deleted := Diff(Snapshot(before), nil)
+	if len(deleted) != 6 {
+		t.Errorf("hapus harus mencatat semua field kecuali _id, owner dan updatedAt: %+v", deleted)
+	}
+}
+
+func TestLiveFilter(t *testing.T) {
+	filter := Live(bson.M{"owner": "62811"})
+	if v, ok := filter["deleted_at"]; !ok || v != nil {
+		t.Errorf("filter = %v", filter)
+	}
+	if _, ok := Deleted(nil)["deleted_at"].(bson.M); !ok {
+		t.Error("filter terhapus harus memakai $ne")
+	}
+	if Snapshot(nil) != nil {
+		t.Error("snapshot nil harus nil")
+	}
+}
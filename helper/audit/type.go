@@ -0,0 +1,59 @@
+package audit
+
+import (
+	"errors"
+
+	"github.com/gocroot/helper/repo"
+)
+
+// Collection adalah koleksi jejak audit
+const Collection = "audit_log"
+
+// Jenis perubahan yang dicatat
+const (
+	ActionCreate  = "create"
+	ActionUpdate  = "update"
+	ActionDelete  = "delete"
+	ActionRestore = "restore"
+	ActionClose   = "close"
+	ActionReopen  = "reopen"
+	ActionVoid    = "void" // Nomor faktur terpakai tetapi penjualannya gagal disimpan
+)
+
+// Nama entitas yang diaudit
+const (
+	EntityProduct  = "product"
+	EntitySales    = "sales"
+	EntityExpense  = "expense"
+	EntityCustomer = "customer"
+	EntityEmployee = "employee"
+	EntityReport   = "report"
+	EntityPayroll  = "payroll"
+	EntityPeriod   = "period" // Tutup dan buka periode akuntansi, tidak bisa dihapus atau dipulihkan
+)
+
+// Collections memetakan entitas ke koleksi datanya
+var Collections = map[string]string{
+	EntityProduct:  repo.ProductCollection,
+	EntitySales:    repo.SalesCollection,
+	EntityExpense:  repo.ExpenseCollection,
+	EntityCustomer: repo.CustomerCollection,
+	EntityPayroll:  repo.PayrollCollection,
+	EntityEmployee: repo.EmployeeCollection,
+	EntityReport:   repo.ReportCollection,
+}
+
+// ignored adalah field yang tidak dibandingkan saat menghitung perubahan
+var ignored = map[string]bool{
+	"_id": true, "owner": true, "updatedAt": true, "updated_at": true,
+}
+
+// Actor adalah pelaku perubahan, diambil dari token login dan IP klien
+type Actor struct {
+	Owner string
+	User  string
+	Role  string
+	IP    string
+}
+
+var ErrUnknownEntity = errors.New("entitas tidak dikenal, gunakan product, sales, expense, customer, employee atau report")
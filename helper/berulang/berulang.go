@@ -0,0 +1,257 @@
+package berulang
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/gocroot/helper/atapi"
+	"github.com/gocroot/helper/atdb"
+	"github.com/gocroot/helper/audit"
+	"github.com/gocroot/helper/invoice"
+	"github.com/gocroot/helper/ledger"
+	"github.com/gocroot/helper/tutupbuku"
+	"github.com/gocroot/model"
+	"github.com/whatsauth/itmodel"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Validate memeriksa isi template pengeluaran rutin
+func Validate(r model.RecurringExpense) error {
+	switch {
+	case strings.TrimSpace(r.ExpenseName) == "":
+		return ErrName
+	case r.Amount <= 0:
+		return ErrAmount
+	case r.TaxAmount < 0 || r.TaxAmount > r.Amount:
+		return ErrTax
+	case r.StartDate.IsZero():
+		return ErrStartDate
+	case !r.EndDate.IsZero() && day(r.EndDate) < day(r.StartDate):
+		return ErrEndDate
+	}
+	switch r.Frequency {
+	case FrequencyDaily, FrequencyWeekly, FrequencyMonthly, FrequencyYearly:
+		return nil
+	}
+	return ErrFrequency
+}
+
+// Occurrence menghitung jadwal ke-n dari StartDate. Jadwal bulanan dan tahunan memakai tanggal terakhir bulan
+// jika tanggal mulainya tidak ada di bulan tersebut, misalnya 31 Januari menjadi 28 Februari lalu 31 Maret.
+func Occurrence(r model.RecurringExpense, n int) time.Time {
+	switch r.Frequency {
+	case FrequencyDaily:
+		return r.StartDate.AddDate(0, 0, n)
+	case FrequencyWeekly:
+		return r.StartDate.AddDate(0, 0, 7*n)
+	case FrequencyYearly:
+		return addMonths(r.StartDate, 12*n)
+	}
+	return addMonths(r.StartDate, n)
+}
+
+// Ended memeriksa apakah tanggal sudah melewati end_date template
+func Ended(r model.RecurringExpense, date time.Time) bool {
+	return !r.EndDate.IsZero() && day(date) > day(r.EndDate)
+}
+
+// Skipped memeriksa apakah jadwal pada tanggal tersebut dilewati
+func Skipped(r model.RecurringExpense, date time.Time) bool {
+	for _, s := range r.SkipDates {
+		if day(s) == day(date) {
+			return true
+		}
+	}
+	return false
+}
+
+// Reschedule mengatur jadwal berikutnya ke jadwal pertama yang jatuh pada atau setelah tanggal from
+func Reschedule(r *model.RecurringExpense, from time.Time) {
+	n := 0
+	for day(Occurrence(*r, n)) < day(from) {
+		n++
+	}
+	r.Occurrence, r.NextDate = n, Occurrence(*r, n)
+}
+
+// Find mencari urutan jadwal yang akan datang pada tanggal tersebut
+func Find(r model.RecurringExpense, date time.Time) (n int, ok bool) {
+	for n = r.Occurrence; ; n++ {
+		d := Occurrence(r, n)
+		if Ended(r, d) || day(d) > day(date) {
+			return 0, false
+		}
+		if day(d) == day(date) {
+			return n, true
+		}
+	}
+}
+
+// Preview mengembalikan jadwal yang belum dibuat sampai tanggal until, termasuk yang dilewati
+func Preview(r model.RecurringExpense, until time.Time) (upcoming []Upcoming) {
+	if !r.Active {
+		return
+	}
+	for n := r.Occurrence; len(upcoming) < maxPreview; n++ {
+		d := Occurrence(r, n)
+		if Ended(r, d) || day(d) > day(until) {
+			break
+		}
+		upcoming = append(upcoming, Upcoming{
+			RecurringID:   r.ID,
+			ExpenseName:   r.ExpenseName,
+			Amount:        r.Amount,
+			Category:      r.Category,
+			PaymentMethod: r.PaymentMethod,
+			Date:          d,
+			Skipped:       Skipped(r, d),
+		})
+	}
+	return
+}
+
+// Skip menandai satu jadwal yang akan datang agar tidak dibuat oleh cron
+func Skip(db *mongo.Database, owner string, id primitive.ObjectID, date time.Time) (r model.RecurringExpense, err error) {
+	filter := bson.M{"_id": id, "owner": owner}
+	r, err = atdb.GetOneDoc[model.RecurringExpense](db, RecurringCollection, filter)
+	if err != nil {
+		return
+	}
+	n, ok := Find(r, date)
+	if !ok {
+		return r, ErrNotScheduled
+	}
+	update := bson.M{"$addToSet": bson.M{"skip_dates": Occurrence(r, n)}, "$set": bson.M{"updatedAt": time.Now()}}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	err = db.Collection(RecurringCollection).FindOneAndUpdate(context.TODO(), filter, update, opts).Decode(&r)
+	return
+}
+
+// Instance membuat transaksi pengeluaran dari template untuk jadwal pada tanggal tersebut
+func Instance(r model.RecurringExpense, date, now time.Time) model.ExpenseTransaction {
+	id := r.ID
+	return model.ExpenseTransaction{
+		ID:            primitive.NewObjectID(),
+		Owner:         r.Owner,
+		ExpenseName:   r.ExpenseName,
+		Amount:        r.Amount,
+		TaxAmount:     r.TaxAmount,
+		Category:      r.Category,
+		PaymentMethod: r.PaymentMethod,
+		ExpenseDate:   date,
+		Notes:         r.Notes,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+		RecurringID:   &id,
+	}
+}
+
+// Generate membuat pengeluaran dan jurnalnya untuk semua jadwal template yang sudah jatuh tempo sampai now.
+// Jadwal yang dilewati atau berada di periode akuntansi tertutup tidak dibuat.
+// Template r ikut diperbarui ke jadwal berikutnya.
+func Generate(db *mongo.Database, r *model.RecurringExpense, now time.Time) (created []model.ExpenseTransaction, err error) {
+	for r.Active && !Ended(*r, r.NextDate) && !r.NextDate.After(now) {
+		date, n := r.NextDate, r.Occurrence
+		next := Occurrence(*r, n+1)
+		set := bson.M{"occurrence": n + 1, "next_date": next, "last_run_at": now}
+		if Ended(*r, next) {
+			set["active"] = false
+		}
+		// Klaim jadwal lebih dulu agar cron yang berjalan bersamaan tidak membuat pengeluaran ganda
+		var result *mongo.UpdateResult
+		result, err = db.Collection(RecurringCollection).UpdateOne(context.TODO(), bson.M{"_id": r.ID, "occurrence": n}, bson.M{"$set": set})
+		if err != nil || result.MatchedCount == 0 {
+			return
+		}
+		r.Occurrence, r.NextDate, r.Active = n+1, next, !Ended(*r, next)
+
+		if Skipped(*r, date) {
+			continue
+		}
+		if err = tutupbuku.Guard(db, r.Owner, date); err != nil {
+			if _, closed := err.(tutupbuku.ClosedError); closed {
+				err = nil
+				continue
+			}
+			return
+		}
+		expense := Instance(*r, date, now)
+		if _, err = atdb.InsertOneDoc(db, ExpenseCollection, expense); err != nil {
+			return
+		}
+		if _, err = ledger.Post(db, ledger.ExpenseEntry(expense)); err != nil {
+			return
+		}
+		if err = audit.Log(db, audit.Actor{Owner: r.Owner, User: CronUser}, audit.EntityExpense, expense.ID, audit.ActionCreate, nil, expense); err != nil {
+			return
+		}
+		created = append(created, expense)
+	}
+	return
+}
+
+// Message membuat isi notifikasi WhatsApp untuk pengeluaran rutin yang baru dibuat
+func Message(r model.RecurringExpense, expense model.ExpenseTransaction) string {
+	msg := "Pengeluaran rutin *" + expense.ExpenseName + "* sebesar " + invoice.Rupiah(expense.Amount) +
+		" tanggal " + expense.ExpenseDate.Format("02-01-2006") + " sudah dicatat otomatis."
+	if r.Active && !Ended(r, r.NextDate) {
+		msg += " Jadwal berikutnya " + r.NextDate.Format("02-01-2006") + "."
+	}
+	return msg
+}
+
+// Notify mengirim notifikasi WhatsApp ke notify_phone template atau ke nomor owner memakai token dan URL API WhatsApp
+func Notify(r model.RecurringExpense, expense model.ExpenseTransaction, token, apiURL string) (err error) {
+	phone := r.NotifyPhone
+	if phone == "" {
+		phone = r.Owner
+	}
+	dt := &itmodel.TextMessage{
+		To:       invoice.NormalizePhone(phone),
+		IsGroup:  false,
+		Messages: Message(r, expense),
+	}
+	_, _, err = atapi.PostStructWithToken[model.Response]("Token", token, dt, apiURL)
+	return
+}
+
+// BuatPengeluaranRutin dijalankan oleh cron untuk membuat pengeluaran dari semua template yang jatuh tempo
+// lalu mengirim notifikasi WhatsApp untuk setiap pengeluaran yang dibuat.
+func BuatPengeluaranRutin(db *mongo.Database, token, apiURL string) (err error) {
+	now := time.Now()
+	templates, err := atdb.GetAllDoc[[]model.RecurringExpense](db, RecurringCollection, bson.M{"active": true, "next_date": bson.M{"$lte": now}})
+	if err != nil {
+		return
+	}
+	for _, r := range templates {
+		created, errGen := Generate(db, &r, now)
+		if errGen != nil {
+			err = errGen
+		}
+		for _, expense := range created {
+			if errNotify := Notify(r, expense, token, apiURL); errNotify != nil {
+				err = errNotify
+			}
+		}
+	}
+	return
+}
+
+// addMonths menambah bulan tanpa meluap ke bulan berikutnya, tanggal dipotong ke akhir bulan
+func addMonths(t time.Time, months int) time.Time {
+	first := time.Date(t.Year(), t.Month()+time.Month(months), 1, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+	d := t.Day()
+	if last := first.AddDate(0, 1, -1).Day(); d > last {
+		d = last
+	}
+	return first.AddDate(0, 0, d-1)
+}
+
+// day adalah kunci tanggal YYYY-MM-DD untuk membandingkan jadwal tanpa jam
+func day(t time.Time) string {
+	return t.Format("2006-01-02")
+}
@@ -0,0 +1,107 @@
+package berulang
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gocroot/model"
+)
+
+func date(s string) time.Time {
+	t, _ := time.Parse("2006-01-02", s)
+	return t
+}
+
+func TestOccurrenceMonthEnd(t *testing.T) {
+	r := model.RecurringExpense{Frequency: FrequencyMonthly, StartDate: date("2024-01-31")}
+	want := []string{"2024-01-31", "2024-02-29", "2024-03-31", "2024-04-30"}
+	for n, w := range want {
+		if got := Occurrence(r, n).Format("2006-01-02"); got != w {
+			t.Errorf("Occurrence(%d) = %s, want %s", n, got, w)
+		}
+	}
+	r = model.RecurringExpense{Frequency: FrequencyYearly, StartDate: date("2024-02-29")}
+	if got := Occurrence(r, 1).Format("2006-01-02"); got != "2025-02-28" {
+		t.Errorf("tahunan dari 29 Februari = %s", got)
+	}
+	r = model.RecurringExpense{Frequency: FrequencyWeekly, StartDate: date("2024-01-01")}
+	if got := Occurrence(r, 2).Format("2006-01-02"); got != "2024-01-15" {
+		t.Errorf("mingguan = %s", got)
+	}
+}
+
+func TestPreviewSkipAndEndDate(t *testing.T) {
+	r := model.RecurringExpense{
+		ExpenseName: "Sewa",
+		Amount:      1500000,
+		Frequency:   FrequencyMonthly,
+		StartDate:   date("2026-01-05"),
+		EndDate:     date("2026-04-05"),
+		SkipDates:   []time.Time{date("2026-03-05")},
+		Active:      true,
+	}
+	Reschedule(&r, date("2026-02-01"))
+	if r.Occurrence != 1 || !r.NextDate.Equal(date("2026-02-05")) {
+		t.Fatalf("Reschedule = %d %s", r.Occurrence, r.NextDate)
+	}
+
+	upcoming := Preview(r, date("2026-12-31"))
+	if len(upcoming) != 3 {
+		t.Fatalf("Preview = %d jadwal, want 3 sampai end_date", len(upcoming))
+	}
+	if upcoming[0].Skipped || !upcoming[1].Skipped || upcoming[2].Skipped {
+		t.Errorf("hanya jadwal 2026-03-05 yang dilewati: %+v", upcoming)
+	}
+	if len(Preview(r, date("2026-02-28"))) != 1 {
+		t.Error("Preview harus berhenti di tanggal until")
+	}
+
+	if n, ok := Find(r, date("2026-04-05")); !ok || n != 3 {
+		t.Errorf("Find = %d %v", n, ok)
+	}
+	if _, ok := Find(r, date("2026-04-06")); ok {
+		t.Error("tanggal di luar jadwal tidak boleh ditemukan")
+	}
+	if _, ok := Find(r, date("2026-05-05")); ok {
+		t.Error("jadwal setelah end_date tidak boleh ditemukan")
+	}
+	if !Ended(r, date("2026-05-05")) || Ended(r, date("2026-04-05")) {
+		t.Error("Ended harus membandingkan tanggal dengan end_date")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	r := model.RecurringExpense{ExpenseName: "Listrik", Amount: 500000, Frequency: FrequencyMonthly, StartDate: date("2026-01-01")}
+	if err := Validate(r); err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+	cases := map[error]func(*model.RecurringExpense){
+		ErrName:      func(r *model.RecurringExpense) { r.ExpenseName = " " },
+		ErrAmount:    func(r *model.RecurringExpense) { r.Amount = 0 },
+		ErrTax:       func(r *model.RecurringExpense) { r.TaxAmount = 600000 },
+		ErrFrequency: func(r *model.RecurringExpense) { r.Frequency = "hourly" },
+		ErrStartDate: func(r *model.RecurringExpense) { r.StartDate = time.Time{} },
+		ErrEndDate:   func(r *model.RecurringExpense) { r.EndDate = date("2025-12-31") },
+	}
+	for want, mutate := range cases {
+		c := r
+		mutate(&c)
+		if err := Validate(c); err != want {
+			t.Errorf("Validate = %v, want %v", err, want)
+		}
+	}
+}
+
+func TestInstanceAndMessage(t *testing.T) {
+	r := model.RecurringExpense{ExpenseName: "Sewa", Amount: 1500000, Category: "operasional", Frequency: FrequencyMonthly, StartDate: date("2026-01-05"), Active: true}
+	Reschedule(&r, date("2026-02-01"))
+	expense := Instance(r, date("2026-01-05"), time.Now())
+	if expense.RecurringID == nil || *expense.RecurringID != r.ID || !expense.ExpenseDate.Equal(date("2026-01-05")) {
+		t.Errorf("Instance = %+v", expense)
+	}
+	msg := Message(r, expense)
+	if !strings.Contains(msg, "Rp 1.500.000") || !strings.Contains(msg, "05-01-2026") || !strings.Contains(msg, "05-02-2026") {
+		t.Errorf("Message = %q", msg)
+	}
+}
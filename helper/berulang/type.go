@@ -0,0 +1,53 @@
+package berulang
+
+import (
+	"errors"
+	"time"
+
+	"github.com/gocroot/helper/repo"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Nama koleksi yang dipakai pengeluaran rutin
+const (
+	RecurringCollection = "recurring_expenses"
+	ExpenseCollection   = repo.ExpenseCollection
+)
+
+// Frekuensi jadwal pengeluaran rutin
+const (
+	FrequencyDaily   = "daily"
+	FrequencyWeekly  = "weekly"
+	FrequencyMonthly = "monthly"
+	FrequencyYearly  = "yearly"
+)
+
+// CronUser adalah pelaku di audit log untuk pengeluaran yang dibuat otomatis
+const CronUser = "cron"
+
+// DefaultPreviewDays adalah rentang hari pratinjau jadwal jika ?days= kosong
+const DefaultPreviewDays = 30
+
+// maxPreview membatasi jumlah jadwal per template pada pratinjau agar template harian tidak membanjiri respon
+const maxPreview = 366
+
+// Upcoming adalah satu jadwal pengeluaran rutin yang belum dibuat
+type Upcoming struct {
+	RecurringID   primitive.ObjectID `json:"recurring_id"`
+	ExpenseName   string             `json:"expense_name"`
+	Amount        float64            `json:"amount"`
+	Category      string             `json:"category"`
+	PaymentMethod string             `json:"payment_method"`
+	Date          time.Time          `json:"date"`
+	Skipped       bool               `json:"skipped"`
+}
+
+var (
+	ErrName         = errors.New("nama pengeluaran wajib diisi")
+	ErrAmount       = errors.New("jumlah pengeluaran harus lebih dari 0")
+	ErrTax          = errors.New("PPN masukan harus antara 0 dan jumlah pengeluaran")
+	ErrFrequency    = errors.New("frequency harus daily, weekly, monthly atau yearly")
+	ErrStartDate    = errors.New("start_date wajib diisi")
+	ErrEndDate      = errors.New("end_date tidak boleh sebelum start_date")
+	ErrNotScheduled = errors.New("tanggal tersebut bukan jadwal pengeluaran rutin yang akan datang")
+)
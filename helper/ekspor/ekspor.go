@@ -0,0 +1,168 @@
+package ekspor
+
+import (
+	"context"
+	"time"
+
+	"github.com/gocroot/helper/audit"
+	"github.com/gocroot/helper/piutang"
+	"github.com/gocroot/helper/repo"
+	"github.com/gocroot/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Column adalah satu kolom file ekspor beserta cara mengambil nilainya
+type Column[T any] struct {
+	Header string
+	Value  func(T) interface{}
+}
+
+// Entity menjelaskan koleksi yang diekspor, field tanggal untuk filter from/to dan kolomnya
+type Entity[T any] struct {
+	Name       string
+	Collection string
+	DateField  string
+	Columns    []Column[T]
+}
+
+// Exporter adalah entitas yang bisa diekspor tanpa mengetahui tipe datanya
+type Exporter interface {
+	FileName() string
+	Export(db *mongo.Database, owner string, from, to time.Time, rw RowWriter) (rows int, err error)
+}
+
+// FileName adalah nama file unduhan tanpa ekstensi
+func (e Entity[T]) FileName() string {
+	return e.Name
+}
+
+// Export membaca data milik owner lewat cursor dan menulisnya baris per baris sehingga data besar tidak dimuat sekaligus.
+// from dan to bersifat opsional, to inklusif sampai akhir hari.
+func (e Entity[T]) Export(db *mongo.Database, owner string, from, to time.Time, rw RowWriter) (rows int, err error) {
+	headers := make([]interface{}, len(e.Columns))
+	for i, c := range e.Columns {
+		headers[i] = c.Header
+	}
+	if err = rw.WriteRow(headers); err != nil {
+		return
+	}
+
+	filter := audit.Live(bson.M{"owner": owner})
+	if e.DateField != "" && (!from.IsZero() || !to.IsZero()) {
+		rng := bson.M{}
+		if !from.IsZero() {
+			rng["$gte"] = from
+		}
+		if !to.IsZero() {
+			rng["$lt"] = to.AddDate(0, 0, 1)
+		}
+		filter[e.DateField] = rng
+	}
+	sort := bson.D{{Key: "_id", Value: 1}}
+	if e.DateField != "" {
+		sort = bson.D{{Key: e.DateField, Value: 1}, {Key: "_id", Value: 1}}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+	cur, err := db.Collection(e.Collection).Find(ctx, filter, options.Find().SetSort(sort).SetBatchSize(500))
+	if err != nil {
+		return
+	}
+	defer cur.Close(ctx)
+	for cur.Next(ctx) {
+		var doc T
+		if err = cur.Decode(&doc); err != nil {
+			return
+		}
+		values := make([]interface{}, len(e.Columns))
+		for i, c := range e.Columns {
+			values[i] = c.Value(doc)
+		}
+		if err = rw.WriteRow(values); err != nil {
+			return
+		}
+		rows++
+	}
+	err = cur.Err()
+	return
+}
+
+// Products adalah kolom ekspor produk
+var Products = Entity[model.Product]{
+	Name:       "products",
+	Collection: repo.ProductCollection,
+	Columns: []Column[model.Product]{
+		{"ID", func(p model.Product) interface{} { return p.ID.Hex() }},
+		{"Name", func(p model.Product) interface{} { return p.Name }},
+		{"Price", func(p model.Product) interface{} { return p.Price }},
+		{"Category", func(p model.Product) interface{} { return p.Category }},
+		{"Description", func(p model.Product) interface{} { return p.Description }},
+		{"Stock", func(p model.Product) interface{} { return p.Stock }},
+		{"Cost", func(p model.Product) interface{} { return p.Cost }},
+		{"Created At", func(p model.Product) interface{} { return p.CreatedAt }},
+		{"Updated At", func(p model.Product) interface{} { return p.UpdatedAt }},
+	},
+}
+
+// Customers adalah kolom ekspor pelanggan
+var Customers = Entity[model.Customer]{
+	Name:       "customers",
+	Collection: repo.CustomerCollection,
+	Columns: []Column[model.Customer]{
+		{"ID", func(c model.Customer) interface{} { return c.ID.Hex() }},
+		{"Name", func(c model.Customer) interface{} { return c.Name }},
+		{"Email", func(c model.Customer) interface{} { return c.Email }},
+		{"Phone", func(c model.Customer) interface{} { return c.Phone }},
+		{"Address", func(c model.Customer) interface{} { return c.Address }},
+		{"NPWP", func(c model.Customer) interface{} { return c.NPWP }},
+		{"Created At", func(c model.Customer) interface{} { return c.CreatedAt }},
+	},
+}
+
+// Expenses adalah kolom ekspor pengeluaran, difilter dengan expense_date
+var Expenses = Entity[model.ExpenseTransaction]{
+	Name:       "expenses",
+	Collection: repo.ExpenseCollection,
+	DateField:  "expense_date",
+	Columns: []Column[model.ExpenseTransaction]{
+		{"ID", func(e model.ExpenseTransaction) interface{} { return e.ID.Hex() }},
+		{"Expense Date", func(e model.ExpenseTransaction) interface{} { return e.ExpenseDate }},
+		{"Expense Name", func(e model.ExpenseTransaction) interface{} { return e.ExpenseName }},
+		{"Category", func(e model.ExpenseTransaction) interface{} { return e.Category }},
+		{"Amount", func(e model.ExpenseTransaction) interface{} { return e.Amount }},
+		{"Payment Method", func(e model.ExpenseTransaction) interface{} { return e.PaymentMethod }},
+		{"Notes", func(e model.ExpenseTransaction) interface{} { return e.Notes }},
+	},
+}
+
+// Sales adalah kolom ekspor penjualan per faktur, difilter dengan transactionDate
+var Sales = Entity[model.SalesTransaction]{
+	Name:       "sales",
+	Collection: repo.SalesCollection,
+	DateField:  "transactionDate",
+	Columns: []Column[model.SalesTransaction]{
+		{"ID", func(s model.SalesTransaction) interface{} { return s.ID.Hex() }},
+		{"Invoice Number", func(s model.SalesTransaction) interface{} { return s.InvoiceNumber }},
+		{"Transaction Date", func(s model.SalesTransaction) interface{} { return s.TransactionDate }},
+		{"Customer", func(s model.SalesTransaction) interface{} { return s.CustomerName }},
+		{"Items", func(s model.SalesTransaction) interface{} { return len(s.Products) }},
+		{"Total Amount", func(s model.SalesTransaction) interface{} { return s.TotalAmount }},
+		{"Tax Amount", func(s model.SalesTransaction) interface{} { return s.TaxAmount }},
+		{"Paid Amount", func(s model.SalesTransaction) interface{} { return s.PaidAmount }},
+		{"Outstanding", func(s model.SalesTransaction) interface{} { return piutang.Outstanding(s) }},
+		{"Payment Method", func(s model.SalesTransaction) interface{} { return s.PaymentMethod }},
+		{"Payment Status", func(s model.SalesTransaction) interface{} { return s.PaymentStatus }},
+		{"Due Date", func(s model.SalesTransaction) interface{} { return s.DueDate }},
+	},
+}
+
+// Entities adalah entitas yang bisa diekspor lewat ?entity=
+var Entities = map[string]Exporter{
+	Products.Name:  Products,
+	Customers.Name: Customers,
+	Expenses.Name:  Expenses,
+	Sales.Name:     Sales,
+}
@@ -0,0 +1,154 @@
+package gaji
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gocroot/helper/absensi"
+	"github.com/gocroot/helper/atdb"
+	"github.com/gocroot/helper/audit"
+	"github.com/gocroot/helper/ledger"
+	"github.com/gocroot/helper/pajak"
+	"github.com/gocroot/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Preview menghitung payroll satu bulan (YYYY-MM) untuk semua pegawai bergaji tanpa menyimpan apa pun,
+// termasuk potongan tidak hadir dan terlambat dari rekap presensi. PPh 21 Desember dihitung ulang
+// dari payroll Januari sampai November tahun yang sama.
+func Preview(db *mongo.Database, owner, period, paymentMethod string) (run model.PayrollRun, err error) {
+	start, _, err := pajak.ParseMonth(period)
+	if err != nil {
+		return
+	}
+	employees, err := atdb.GetAllDoc[[]model.Employee](db, EmployeeCollection, audit.Live(bson.M{"owner": owner, "base_salary": bson.M{"$gt": 0}}))
+	if err != nil {
+		return
+	}
+	if len(employees) == 0 {
+		err = ErrNoEmployees
+		return
+	}
+	attendance, err := absensi.ByEmployee(db, owner, period)
+	if err != nil {
+		return
+	}
+	run = model.PayrollRun{Owner: owner, Period: period, PaymentMethod: paymentMethod}
+	for _, emp := range employees {
+		if att, ok := attendance[emp.ID]; ok {
+			run.Payslips = append(run.Payslips, CalculateWithAttendance(emp, att))
+			continue
+		}
+		run.Payslips = append(run.Payslips, Calculate(emp))
+	}
+	if start.Month() == time.December {
+		if err = reconcileYear(db, owner, start.Year(), run.Payslips); err != nil {
+			return
+		}
+	}
+	Summarize(&run)
+	return
+}
+
+// reconcileYear mengambil slip Januari sampai November milik setiap pegawai lalu menghitung ulang PPh 21 Desembernya
+func reconcileYear(db *mongo.Database, owner string, year int, slips []model.Payslip) (err error) {
+	prefix := fmt.Sprintf("%d-", year)
+	runs, err := atdb.GetAllDoc[[]model.PayrollRun](db, PayrollCollection, audit.Live(bson.M{
+		"owner":  owner,
+		"period": bson.M{"$gte": prefix + "01", "$lte": prefix + "11"},
+	}))
+	if err != nil {
+		return
+	}
+	prior := map[primitive.ObjectID][]model.Payslip{}
+	for _, r := range runs {
+		for _, s := range r.Payslips {
+			prior[s.EmployeeID] = append(prior[s.EmployeeID], s)
+		}
+	}
+	for i := range slips {
+		ReconcileDecember(&slips[i], prior[slips[i].EmployeeID])
+	}
+	return
+}
+
+// Run membuat payroll bulanan lalu mencatat total biaya gaji sebagai satu pengeluaran kategori gaji.
+// Pengeluaran dijurnal ke beban gaji pada kas/bank, termasuk PPh 21 dan iuran BPJS yang disetor toko.
+// Payroll disimpan lebih dulu agar index unik owner dan period menolak run ganda sebelum ada pengeluaran;
+// jika pengeluaran atau jurnalnya gagal, payroll dan pengeluarannya ditandai terhapus.
+func Run(db *mongo.Database, actor audit.Actor, period, paymentMethod string) (run model.PayrollRun, err error) {
+	owner := actor.Owner
+	count, err := atdb.GetCountDoc(db, PayrollCollection, audit.Live(bson.M{"owner": owner, "period": period}))
+	if err != nil {
+		return
+	}
+	if count > 0 {
+		err = ErrRunExists
+		return
+	}
+	if run, err = Preview(db, owner, period, paymentMethod); err != nil {
+		return
+	}
+	_, end, _ := pajak.ParseMonth(period)
+
+	run.ID = primitive.NewObjectID()
+	run.CreatedAt = time.Now()
+	expense := model.ExpenseTransaction{
+		ID:            primitive.NewObjectID(),
+		Owner:         owner,
+		ExpenseName:   "Gaji " + period,
+		Amount:        run.TotalCost,
+		Category:      ExpenseCategory,
+		PaymentMethod: paymentMethod,
+		ExpenseDate:   end,
+		Notes:         fmt.Sprintf("Payroll %d pegawai, gaji bersih %.2f, PPh 21 %.2f", len(run.Payslips), run.TotalNet, run.TotalPPh21),
+		CreatedAt:     run.CreatedAt,
+		UpdatedAt:     run.CreatedAt,
+	}
+	run.ExpenseID = expense.ID
+	if _, err = atdb.InsertOneDoc(db, PayrollCollection, run); mongo.IsDuplicateKeyError(err) {
+		err = ErrRunExists
+	}
+	if err != nil {
+		return
+	}
+	if _, err = atdb.InsertOneDoc(db, ExpenseCollection, expense); err != nil {
+		audit.SoftDelete[model.PayrollRun](db, actor, audit.EntityPayroll, run.ID)
+		return
+	}
+	if _, err = ledger.Post(db, ledger.ExpenseEntry(expense)); err != nil {
+		audit.SoftDelete[model.ExpenseTransaction](db, actor, audit.EntityExpense, expense.ID)
+		audit.SoftDelete[model.PayrollRun](db, actor, audit.EntityPayroll, run.ID)
+	}
+	return
+}
+
+// Delete menandai payroll dan pengeluaran gajinya sebagai terhapus, mencatat isi lamanya di audit log
+// lalu membalik jurnal gaji seperti penghapusan pengeluaran biasa
+func Delete(db *mongo.Database, actor audit.Actor, id primitive.ObjectID) (err error) {
+	run, err := audit.SoftDelete[model.PayrollRun](db, actor, audit.EntityPayroll, id)
+	if err != nil || run.ExpenseID.IsZero() {
+		return
+	}
+	_, err = audit.SoftDelete[model.ExpenseTransaction](db, actor, audit.EntityExpense, run.ExpenseID)
+	if err == mongo.ErrNoDocuments {
+		// Pengeluaran gaji sudah dihapus sendiri, jurnalnya sudah dibalik saat itu
+		return nil
+	}
+	if err != nil {
+		return
+	}
+	return ledger.ReverseSameDate(db, ledger.SourceExpense, run.ExpenseID)
+}
+
+// FindPayslip mengambil slip gaji satu pegawai dari payroll
+func FindPayslip(run model.PayrollRun, employeeID primitive.ObjectID) (slip model.Payslip, ok bool) {
+	for _, s := range run.Payslips {
+		if s.EmployeeID == employeeID {
+			return s, true
+		}
+	}
+	return
+}
@@ -0,0 +1,115 @@
+package gaji
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/gocroot/model"
+)
+
+func TestCalculate(t *testing.T) {
+	emp := model.Employee{
+		Name:                "Sari",
+		BaseSalary:          9000000,
+		Allowances:          []model.PayComponent{{Name: "Transport", Amount: 1000000}},
+		Deductions:          []model.PayComponent{{Name: "Kasbon", Amount: 500000}},
+		TaxStatus:           "TK/0",
+		BPJSKesehatan:       true,
+		BPJSKetenagakerjaan: true,
+	}
+	slip := Calculate(emp)
+	if slip.Gross != 10000000 {
+		t.Errorf("gross = %v", slip.Gross)
+	}
+	// Pegawai: Kesehatan 100.000, JHT 200.000, JP 100.000
+	if sum(slip.BPJSEmployee) != 400000 {
+		t.Errorf("bpjs pegawai = %+v", slip.BPJSEmployee)
+	}
+	// Toko: Kesehatan 400.000, JHT 370.000, JKK 24.000, JKM 30.000, JP 200.000
+	if sum(slip.BPJSEmployer) != 1024000 || slip.Cost != 11024000 {
+		t.Errorf("bpjs toko = %+v cost = %v", slip.BPJSEmployer, slip.Cost)
+	}
+	// Bruto PPh 21 10.454.000 (gaji ditambah Kesehatan, JKK dan JKM dari toko), TER A 2,5%
+	if slip.TaxableGross != 10454000 || slip.Pension != 300000 || slip.TaxMethod != "TER A" || slip.PPh21 != 261350 {
+		t.Fatalf("pph21 = %v %s dari bruto %v", slip.PPh21, slip.TaxMethod, slip.TaxableGross)
+	}
+	if slip.NetPay != 10000000-500000-400000-slip.PPh21 {
+		t.Errorf("net = %v", slip.NetPay)
+	}
+}
+
+func TestReconcileDecember(t *testing.T) {
+	emp := model.Employee{BaseSalary: 10000000, TaxStatus: "TK/0"}
+	var prior []model.Payslip
+	for i := 0; i < 11; i++ {
+		prior = append(prior, Calculate(emp))
+	}
+	dec := Calculate(emp)
+	ReconcileDecember(&dec, prior)
+	// Setahun 120 juta: PKP 120jt - 6jt - 54jt = 60jt, PPh 3.000.000 dikurangi TER 11 x 200.000
+	if dec.PPh21 != 800000 || dec.TaxMethod != "Pasal 17" || dec.NetPay != 10000000-800000 {
+		t.Errorf("desember = %v %s net %v", dec.PPh21, dec.TaxMethod, dec.NetPay)
+	}
+
+	// Pegawai baru mulai Desember: hanya penghasilan sebulan yang dihitung
+	low := Calculate(model.Employee{BaseSalary: 4000000, TaxStatus: "K/1"})
+	ReconcileDecember(&low, nil)
+	if low.PPh21 != 0 || low.NetPay != 4000000 {
+		t.Errorf("di bawah PTKP = %v net %v", low.PPh21, low.NetPay)
+	}
+}
+
+func TestCalculateWageCap(t *testing.T) {
+	slip := Calculate(model.Employee{BaseSalary: 20000000, BPJSKesehatan: true})
+	if slip.BPJSEmployee[0].Amount != 120000 || slip.BPJSEmployer[0].Amount != 480000 {
+		t.Errorf("iuran kesehatan harus dibatasi upah 12 juta: %+v %+v", slip.BPJSEmployee, slip.BPJSEmployer)
+	}
+}
+
+func TestSummarizeAndValidate(t *testing.T) {
+	run := model.PayrollRun{Payslips: []model.Payslip{
+		Calculate(model.Employee{BaseSalary: 3000000}),
+		Calculate(model.Employee{BaseSalary: 4000000, Allowances: []model.PayComponent{{Name: "Makan", Amount: 500000}}}),
+	}}
+	Summarize(&run)
+	if run.TotalGross != 7500000 || run.TotalCost != 7500000 || run.TotalNet != 7500000 || run.TotalPPh21 != 0 {
+		t.Errorf("run = %+v", run)
+	}
+
+	if Validate(model.Employee{BaseSalary: -1}) == nil {
+		t.Error("gaji pokok negatif harus ditolak")
+	}
+	if Validate(model.Employee{Allowances: []model.PayComponent{{Amount: 1}}}) == nil {
+		t.Error("komponen tanpa nama harus ditolak")
+	}
+	if Validate(model.Employee{TaxStatus: "K/4"}) == nil {
+		t.Error("status PTKP tidak dikenal harus ditolak")
+	}
+}
+
+func TestRenderPayslip(t *testing.T) {
+	slip := Calculate(model.Employee{Name: "Sari", BaseSalary: 5000000, BPJSKesehatan: true})
+	pdf, err := RenderPayslip(model.PayrollRun{Period: "2026-01"}, slip, model.StoreProfile{Name: "Toko"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.HasPrefix(pdf, []byte("%PDF")) {
+		t.Error("output bukan PDF")
+	}
+}
+
+func TestCalculateWithAttendance(t *testing.T) {
+	emp := model.Employee{BaseSalary: 3000000, AbsentDeduction: 100000, LateDeduction: 25000, Deductions: []model.PayComponent{{Name: "Kasbon", Amount: 200000}}}
+	slip := CalculateWithAttendance(emp, model.AttendanceSummary{WorkDays: 24, Present: 22, Absent: 2, Late: 3})
+	if slip.Gross != 2725000 || slip.Cost != 2725000 {
+		t.Errorf("gross = %v cost = %v", slip.Gross, slip.Cost)
+	}
+	if slip.NetPay != 2525000 || len(slip.Deductions) != 3 || slip.Attendance == nil {
+		t.Errorf("slip = %+v", slip)
+	}
+
+	slip = CalculateWithAttendance(model.Employee{BaseSalary: 3000000}, model.AttendanceSummary{Absent: 2})
+	if slip.NetPay != 3000000 || len(slip.Deductions) != 0 {
+		t.Errorf("tanpa tarif potongan gaji tidak boleh berkurang: %+v", slip)
+	}
+}
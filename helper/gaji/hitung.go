@@ -0,0 +1,174 @@
+package gaji
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/gocroot/helper/absensi"
+	"github.com/gocroot/helper/ledger"
+	"github.com/gocroot/helper/pajak"
+	"github.com/gocroot/model"
+)
+
+// Calculate menyusun slip gaji sebulan: iuran BPJS, PPh 21 dan gaji bersih.
+// Iuran BPJS Kesehatan, JKK dan JKM yang ditanggung toko menambah penghasilan bruto untuk PPh 21,
+// iuran JHT dan JP pegawai mengurangi penghasilan neto pada perhitungan Pasal 17 di bulan Desember.
+// PPh 21 dihitung dengan TER sesuai PP 58/2023, slip Desember dihitung ulang dengan ReconcileDecember.
+func Calculate(emp model.Employee) model.Payslip {
+	return calculate(emp, nil)
+}
+
+// CalculateWithAttendance menyusun slip gaji dengan potongan tidak hadir dan terlambat dari rekap kehadiran.
+// Potongan kehadiran mengurangi penghasilan bruto, iuran BPJS tetap dihitung dari upah tetap.
+func CalculateWithAttendance(emp model.Employee, att model.AttendanceSummary) model.Payslip {
+	return calculate(emp, &att)
+}
+
+func calculate(emp model.Employee, att *model.AttendanceSummary) (slip model.Payslip) {
+	slip = model.Payslip{
+		EmployeeID: emp.ID,
+		Name:       emp.Name,
+		Position:   emp.Position,
+		TaxStatus:  emp.TaxStatus,
+		BaseSalary: ledger.Round2(emp.BaseSalary),
+		Allowances: emp.Allowances,
+		Deductions: emp.Deductions,
+		Attendance: att,
+	}
+	if slip.TaxStatus == "" {
+		slip.TaxStatus = "TK/0"
+	}
+	wage := emp.BaseSalary + sum(emp.Allowances)
+	earned := wage
+	if att != nil {
+		cuts := attendanceCuts(emp, *att)
+		if len(cuts) > 0 {
+			earned = math.Max(wage-sum(cuts), 0)
+			slip.Deductions = append(cuts, emp.Deductions...)
+		}
+	}
+	slip.Gross = ledger.Round2(earned)
+
+	var taxableBenefit, pension float64
+	if emp.BPJSKesehatan {
+		base := math.Min(wage, kesehatanWageCap)
+		employer := percent(base, kesehatanEmployer)
+		slip.BPJSEmployer = append(slip.BPJSEmployer, model.PayComponent{Name: "BPJS Kesehatan", Amount: employer})
+		slip.BPJSEmployee = append(slip.BPJSEmployee, model.PayComponent{Name: "BPJS Kesehatan", Amount: percent(base, kesehatanEmployee)})
+		taxableBenefit += employer
+	}
+	if emp.BPJSKetenagakerjaan {
+		jkk, jkm := percent(wage, jkkEmployer), percent(wage, jkmEmployer)
+		jpBase := math.Min(wage, jpWageCap)
+		slip.BPJSEmployer = append(slip.BPJSEmployer,
+			model.PayComponent{Name: "JHT", Amount: percent(wage, jhtEmployer)},
+			model.PayComponent{Name: "JKK", Amount: jkk},
+			model.PayComponent{Name: "JKM", Amount: jkm},
+			model.PayComponent{Name: "JP", Amount: percent(jpBase, jpEmployer)},
+		)
+		jht, jp := percent(wage, jhtEmployee), percent(jpBase, jpEmployee)
+		slip.BPJSEmployee = append(slip.BPJSEmployee,
+			model.PayComponent{Name: "JHT", Amount: jht},
+			model.PayComponent{Name: "JP", Amount: jp},
+		)
+		taxableBenefit += jkk + jkm
+		pension += jht + jp
+	}
+
+	// Januari sampai November memakai TER, masa Desember dihitung ulang oleh ReconcileDecember
+	slip.TaxableGross = ledger.Round2(earned + taxableBenefit)
+	slip.Pension = ledger.Round2(pension)
+	slip.TaxMethod = "TER " + pajak.TERCategory(slip.TaxStatus)
+	slip.PPh21 = pajak.PPh21TER(slip.TaxableGross, slip.TaxStatus)
+	slip.NetPay = ledger.Round2(earned - sum(emp.Deductions) - sum(slip.BPJSEmployee) - slip.PPh21)
+	slip.Cost = ledger.Round2(earned + sum(slip.BPJSEmployer))
+	return
+}
+
+// ReconcileDecember menghitung ulang PPh 21 slip masa Desember dengan tarif Pasal 17 atas penghasilan setahun,
+// dikurangi PPh 21 yang sudah dipotong pada slip Januari sampai November tahun yang sama (prior).
+// Kelebihan potong membuat PPh 21 negatif dan menambah gaji bersih.
+func ReconcileDecember(slip *model.Payslip, prior []model.Payslip) {
+	gross, pension, withheld := slip.TaxableGross, slip.Pension, 0.0
+	for _, p := range prior {
+		taxable := p.TaxableGross
+		if taxable == 0 {
+			// Slip lama sebelum taxable_gross disimpan
+			taxable = p.Gross
+		}
+		gross += taxable
+		pension += p.Pension
+		withheld += p.PPh21
+	}
+	pph := pajak.PPh21December(gross, pension, len(prior)+1, slip.TaxStatus, withheld)
+	slip.NetPay = ledger.Round2(slip.NetPay + slip.PPh21 - pph)
+	slip.PPh21 = ledger.Round2(pph)
+	slip.TaxMethod = "Pasal 17"
+}
+
+// attendanceCuts menghitung potongan tidak hadir dan terlambat sesuai tarif potongan pegawai
+func attendanceCuts(emp model.Employee, att model.AttendanceSummary) (cuts []model.PayComponent) {
+	if emp.AbsentDeduction > 0 && att.Absent > 0 {
+		cuts = append(cuts, model.PayComponent{
+			Name:   fmt.Sprintf("Potongan tidak hadir (%d hari)", att.Absent),
+			Amount: ledger.Round2(emp.AbsentDeduction * float64(att.Absent)),
+		})
+	}
+	if emp.LateDeduction > 0 && att.Late > 0 {
+		cuts = append(cuts, model.PayComponent{
+			Name:   fmt.Sprintf("Potongan terlambat (%d kali)", att.Late),
+			Amount: ledger.Round2(emp.LateDeduction * float64(att.Late)),
+		})
+	}
+	return
+}
+
+// Summarize menjumlahkan slip gaji ke total payroll
+func Summarize(run *model.PayrollRun) {
+	run.TotalGross, run.TotalPPh21, run.TotalNet, run.TotalCost = 0, 0, 0, 0
+	for _, slip := range run.Payslips {
+		run.TotalGross += slip.Gross
+		run.TotalPPh21 += slip.PPh21
+		run.TotalNet += slip.NetPay
+		run.TotalCost += slip.Cost
+	}
+	run.TotalGross = ledger.Round2(run.TotalGross)
+	run.TotalPPh21 = ledger.Round2(run.TotalPPh21)
+	run.TotalNet = ledger.Round2(run.TotalNet)
+	run.TotalCost = ledger.Round2(run.TotalCost)
+}
+
+func percent(v, rate float64) float64 {
+	return math.Round(v * rate / 100)
+}
+
+func sum(components []model.PayComponent) (total float64) {
+	for _, c := range components {
+		total += c.Amount
+	}
+	return
+}
+
+// Validate memeriksa komponen gaji pegawai
+func Validate(emp model.Employee) error {
+	if emp.BaseSalary < 0 {
+		return errors.New("base_salary tidak boleh negatif")
+	}
+	for _, c := range append(append([]model.PayComponent{}, emp.Allowances...), emp.Deductions...) {
+		if strings.TrimSpace(c.Name) == "" || c.Amount < 0 {
+			return errors.New("komponen gaji wajib memiliki nama dan nominal tidak negatif")
+		}
+	}
+	if emp.AbsentDeduction < 0 || emp.LateDeduction < 0 {
+		return errors.New("potongan kehadiran tidak boleh negatif")
+	}
+	if absensi.ValidShiftStart(emp.ShiftStart) != nil {
+		return absensi.ErrShiftStart
+	}
+	if !pajak.ValidTaxStatus(emp.TaxStatus) {
+		return errors.New("tax_status harus TK/0 sampai TK/3 atau K/0 sampai K/3")
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package gaji
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/gocroot/helper/invoice"
+	"github.com/gocroot/model"
+	"github.com/raykov/gofpdf"
+)
+
+// RenderPayslip membuat PDF slip gaji A5 satu pegawai
+func RenderPayslip(run model.PayrollRun, slip model.Payslip, store model.StoreProfile) ([]byte, error) {
+	pdf := gofpdf.New("P", "mm", "A5", "")
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+	pdf.AddPage()
+	width, _ := pdf.GetPageSize()
+	left, _, right, _ := pdf.GetMargins()
+	width -= left + right
+
+	pdf.SetFont("Arial", "B", 13)
+	pdf.CellFormat(width, 7, tr(store.Name), "", 1, "L", false, 0, "")
+	pdf.SetFont("Arial", "", 9)
+	if store.Address != "" {
+		pdf.CellFormat(width, 4, tr(store.Address), "", 1, "L", false, 0, "")
+	}
+	pdf.Ln(3)
+	pdf.SetFont("Arial", "B", 12)
+	pdf.CellFormat(width, 7, "SLIP GAJI "+run.Period, "", 1, "C", false, 0, "")
+	pdf.Ln(2)
+
+	pdf.SetFont("Arial", "", 9)
+	rows := [][2]string{{"Nama", slip.Name}, {"Jabatan", slip.Position}, {"Status PTKP", slip.TaxStatus}}
+	if att := slip.Attendance; att != nil {
+		rows = append(rows, [2]string{"Kehadiran", fmt.Sprintf("%d dari %d hari kerja, %d kali terlambat", att.Present, att.WorkDays, att.Late)})
+	}
+	for _, row := range rows {
+		pdf.CellFormat(30, 5, row[0], "", 0, "L", false, 0, "")
+		pdf.CellFormat(width-30, 5, ": "+tr(row[1]), "", 1, "L", false, 0, "")
+	}
+	pdf.Ln(3)
+
+	section := func(title string, rows []model.PayComponent) {
+		pdf.SetFont("Arial", "B", 10)
+		pdf.SetFillColor(230, 230, 230)
+		pdf.CellFormat(width, 6, title, "", 1, "L", true, 0, "")
+		pdf.SetFont("Arial", "", 9)
+		for _, c := range rows {
+			pdf.CellFormat(width-40, 5, tr(c.Name), "", 0, "L", false, 0, "")
+			pdf.CellFormat(40, 5, invoice.Rupiah(c.Amount), "", 1, "R", false, 0, "")
+		}
+		pdf.Ln(1)
+	}
+	earnings := append([]model.PayComponent{{Name: "Gaji pokok", Amount: slip.BaseSalary}}, slip.Allowances...)
+	if slip.PPh21 < 0 {
+		earnings = append(earnings, model.PayComponent{Name: "Kelebihan potong PPh 21", Amount: -slip.PPh21})
+	}
+	section("Pendapatan", earnings)
+	deductions := append([]model.PayComponent{}, slip.BPJSEmployee...)
+	if slip.PPh21 > 0 {
+		deductions = append(deductions, model.PayComponent{Name: "PPh 21 (" + slip.TaxMethod + ")", Amount: slip.PPh21})
+	}
+	section("Potongan", append(deductions, slip.Deductions...))
+	if len(slip.BPJSEmployer) > 0 {
+		section("Iuran BPJS ditanggung perusahaan", slip.BPJSEmployer)
+	}
+
+	pdf.SetFont("Arial", "B", 11)
+	pdf.CellFormat(width-40, 7, "Gaji bersih", "T", 0, "L", false, 0, "")
+	pdf.CellFormat(40, 7, invoice.Rupiah(slip.NetPay), "T", 1, "R", false, 0, "")
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,40 @@
+package gaji
+
+import (
+	"errors"
+
+	"github.com/gocroot/helper/repo"
+)
+
+// Nama koleksi yang dipakai payroll
+const (
+	PayrollCollection  = repo.PayrollCollection
+	EmployeeCollection = repo.EmployeeCollection
+	ExpenseCollection  = repo.ExpenseCollection
+)
+
+// ExpenseCategory adalah kategori pengeluaran gaji, dijurnal ke akun beban gaji
+const ExpenseCategory = "gaji"
+
+// Iuran BPJS dalam persen dari upah (gaji pokok ditambah tunjangan tetap)
+const (
+	kesehatanEmployer = 4
+	kesehatanEmployee = 1
+	jhtEmployer       = 3.7
+	jhtEmployee       = 2
+	jkkEmployer       = 0.24 // Kelompok risiko sangat rendah
+	jkmEmployer       = 0.3
+	jpEmployer        = 2
+	jpEmployee        = 1
+)
+
+// Batas upah tertinggi dasar iuran BPJS Kesehatan dan Jaminan Pensiun
+const (
+	kesehatanWageCap = 12000000
+	jpWageCap        = 10547400
+)
+
+var (
+	ErrRunExists   = errors.New("payroll untuk periode ini sudah dibuat")
+	ErrNoEmployees = errors.New("belum ada pegawai dengan gaji pokok")
+)
@@ -13,7 +13,10 @@ import (
 	"go.mongodb.org/mongo-driver/mongo"
 )
 
-func HubHandler(Profile itmodel.Profile, msg itmodel.IteungMessage, db *mongo.Database) string {
+// HubHandler meneruskan pesan antara user dan admin helpdesk jika session hub aktif
+func HubHandler(ctx context.Context, Profile itmodel.Profile, msg itmodel.IteungMessage, db *mongo.Database) string {
+	// Pengiriman berjalan di goroutine, jadi tidak ikut dibatalkan saat request selesai
+	sendCtx := context.WithoutCancel(ctx)
 	//check apakah ada sesssion hub
 	var shub SessionHub
 	shub, res, err := CheckHubSessionUser(msg.Phone_number, db)
@@ -32,7 +35,7 @@ func HubHandler(Profile itmodel.Profile, msg itmodel.IteungMessage, db *mongo.Da
 				IsGroup:  false,
 				Messages: msgstr,
 			}
-			go atapi.PostStructWithToken[itmodel.Response]("Token", Profile.Token, dt, Profile.URLAPIText)
+			go atapi.PostStructWithTokenContext[itmodel.Response](sendCtx, "Token", Profile.Token, dt, Profile.URLAPIText)
 			err = tiket.UpdateAdminMsgInTiket(msg.Phone_number, msg.Message, db)
 			if err != nil {
 				return "tiket tidak ditemukan atau sudah di tutup : " + err.Error()
@@ -53,7 +56,7 @@ func HubHandler(Profile itmodel.Profile, msg itmodel.IteungMessage, db *mongo.Da
 		IsGroup:  false,
 		Messages: msgstr,
 	}
-	go atapi.PostStructWithToken[itmodel.Response]("Token", Profile.Token, dt, Profile.URLAPIText)
+	go atapi.PostStructWithTokenContext[itmodel.Response](sendCtx, "Token", Profile.Token, dt, Profile.URLAPIText)
 	err = tiket.UpdateUserMsgInTiket(msg.Phone_number, msg.Message, db)
 	if err != nil {
 		return "tiket tidak ditemukan atau sudah di tutup : " + err.Error()
@@ -0,0 +1,212 @@
+package impor
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/gocroot/helper/atdb"
+	"github.com/gocroot/helper/audit"
+	"github.com/gocroot/helper/ledger"
+	"github.com/gocroot/helper/repo"
+	"github.com/gocroot/helper/stok"
+	"github.com/gocroot/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// ErrOpeningExists dikembalikan jika saldo awal sudah pernah diimpor dan replace tidak diminta
+var ErrOpeningExists = errors.New("saldo awal sudah pernah diimpor")
+
+// newReport menghitung baris data yang tidak kosong dan mengisi kesalahan validasi
+func newReport(rows [][]string, errs []RowError, dryRun bool) Report {
+	r := Report{DryRun: dryRun, Errors: errs}
+	for _, row := range rows[1:] {
+		if !blank(row) {
+			r.Total++
+		}
+	}
+	if r.Errors == nil {
+		r.Errors = []RowError{}
+	}
+	return r
+}
+
+// finish menghitung baris yang dilewati dari jumlah baris yang tidak diimpor
+func (r *Report) finish() {
+	r.Skipped = r.Total - r.Imported
+}
+
+func key(s string) string {
+	return strings.ToLower(strings.TrimSpace(s))
+}
+
+// Products mengimpor produk milik owner. Baris yang tidak valid atau namanya sudah ada dilewati,
+// stok awal dicatat sebagai mutasi opening. Jika dryRun, data hanya divalidasi.
+func Products(db *mongo.Database, owner string, rows [][]string, dryRun bool) (report Report, err error) {
+	items, errs := ParseProducts(rows)
+	report = newReport(rows, errs, dryRun)
+	defer report.finish()
+
+	names, err := atdb.GetAllDistinct[string](db, audit.Live(bson.M{"owner": owner}), "name", stok.ProductCollection)
+	if err != nil {
+		return
+	}
+	seen := map[string]bool{}
+	for _, n := range names {
+		seen[key(n)] = true
+	}
+	for _, item := range items {
+		p := item.Value
+		if seen[key(p.Name)] {
+			report.Errors = append(report.Errors, RowError{Row: item.Line, Column: "name", Message: "produk dengan nama ini sudah ada"})
+			continue
+		}
+		seen[key(p.Name)] = true
+		if dryRun {
+			report.Imported++
+			continue
+		}
+		p.ID = primitive.NewObjectID()
+		p.Owner = owner
+		p.CreatedAt = time.Now()
+		p.UpdatedAt = p.CreatedAt
+		if _, err = atdb.InsertOneDoc(db, stok.ProductCollection, p); err != nil {
+			return
+		}
+		if err = stok.RecordSet(db, model.Product{}, p, "Stok awal impor"); err != nil {
+			return
+		}
+		report.Imported++
+	}
+	return
+}
+
+// Customers mengimpor pelanggan milik owner. Pelanggan dengan email atau telepon yang sudah terdaftar dilewati.
+func Customers(db *mongo.Database, owner string, rows [][]string, dryRun bool) (report Report, err error) {
+	items, errs := ParseCustomers(rows)
+	report = newReport(rows, errs, dryRun)
+	defer report.finish()
+
+	existing, err := atdb.GetAllDoc[[]model.Customer](db, repo.CustomerCollection, audit.Live(bson.M{"owner": owner}))
+	if err != nil {
+		return
+	}
+	seen := map[string]bool{}
+	for _, c := range existing {
+		if c.Email != "" {
+			seen["email:"+key(c.Email)] = true
+		}
+		if c.Phone != "" {
+			seen["phone:"+key(c.Phone)] = true
+		}
+	}
+	for _, item := range items {
+		c := item.Value
+		if c.Email != "" && seen["email:"+key(c.Email)] {
+			report.Errors = append(report.Errors, RowError{Row: item.Line, Column: "email", Message: "email sudah terdaftar"})
+			continue
+		}
+		if c.Phone != "" && seen["phone:"+key(c.Phone)] {
+			report.Errors = append(report.Errors, RowError{Row: item.Line, Column: "phone", Message: "telepon sudah terdaftar"})
+			continue
+		}
+		if c.Email != "" {
+			seen["email:"+key(c.Email)] = true
+		}
+		if c.Phone != "" {
+			seen["phone:"+key(c.Phone)] = true
+		}
+		if dryRun {
+			report.Imported++
+			continue
+		}
+		c.ID = primitive.NewObjectID()
+		c.Owner = owner
+		c.CreatedAt = time.Now()
+		c.UpdatedAt = c.CreatedAt
+		if _, err = atdb.InsertOneDoc(db, repo.CustomerCollection, c); err != nil {
+			return
+		}
+		report.Imported++
+	}
+	return
+}
+
+// OpeningBalances mengimpor saldo awal akun sebagai satu jurnal seimbang per tanggal date.
+// Impor bersifat semua-atau-tidak-sama-sekali: satu baris salah membatalkan seluruh jurnal.
+// Jika replace bernilai true, jurnal saldo awal sebelumnya dibalik terlebih dahulu.
+func OpeningBalances(db *mongo.Database, owner string, rows [][]string, date time.Time, replace, dryRun bool) (report Report, err error) {
+	items, errs := ParseOpening(rows)
+	report = newReport(rows, errs, dryRun)
+	defer report.finish()
+
+	types, err := ledger.AccountTypes(db, owner)
+	if err != nil {
+		return
+	}
+	accounts, err := atdb.GetAllDoc[[]model.Account](db, ledger.AccountCollection, bson.M{"owner": owner})
+	if err != nil {
+		return
+	}
+	names := map[string]string{}
+	for _, acc := range accounts {
+		names[acc.Code] = acc.Name
+	}
+
+	entry := model.JournalEntry{
+		Owner:       owner,
+		Date:        date,
+		Description: "Saldo awal",
+		SourceType:  ledger.SourceOpening,
+		SourceID:    primitive.NewObjectID(),
+	}
+	for _, item := range items {
+		l := item.Value
+		if _, ok := types[l.AccountCode]; !ok {
+			report.Errors = append(report.Errors, RowError{Row: item.Line, Column: "account_code", Message: "kode akun tidak ada di bagan akun"})
+			continue
+		}
+		name := names[l.AccountCode]
+		if name == "" {
+			name = ledger.AccountName(l.AccountCode)
+		}
+		entry.Lines = append(entry.Lines, model.JournalLine{
+			AccountCode: l.AccountCode,
+			AccountName: name,
+			Debit:       l.Debit,
+			Credit:      l.Credit,
+		})
+	}
+	if len(report.Errors) > 0 {
+		return
+	}
+	if err := ledger.Validate(entry); err != nil {
+		report.Errors = append(report.Errors, RowError{Message: err.Error()})
+		return report, nil
+	}
+
+	previous, err := atdb.GetAllDoc[[]model.JournalEntry](db, ledger.JournalCollection,
+		bson.M{"owner": owner, "source_type": ledger.SourceOpening, "reversed": bson.M{"$ne": true}})
+	if err != nil {
+		return
+	}
+	if len(previous) > 0 && !replace {
+		return report, ErrOpeningExists
+	}
+	if dryRun {
+		report.Imported = report.Total
+		return
+	}
+	for _, p := range previous {
+		if err = ledger.ReverseSameDate(db, ledger.SourceOpening, p.SourceID); err != nil {
+			return
+		}
+	}
+	if _, err = ledger.Post(db, entry); err != nil {
+		return
+	}
+	report.Imported = report.Total
+	return
+}
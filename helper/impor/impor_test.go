@@ -0,0 +1,130 @@
+package impor
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/gocroot/helper/ekspor"
+)
+
+func TestParseNumber(t *testing.T) {
+	cases := map[string]float64{
+		"":             0,
+		"1500":         1500,
+		"1.500":        1500,
+		"1.500.000":    1500000,
+		"1,500,000":    1500000,
+		"1.500.000,50": 1500000.5,
+		"1,500,000.50": 1500000.5,
+		"12,5":         12.5,
+		"12.5":         12.5,
+		"Rp 15.000":    15000,
+	}
+	for in, want := range cases {
+		got, err := ParseNumber(in)
+		if err != nil || got != want {
+			t.Errorf("ParseNumber(%q) = %v, %v; want %v", in, got, err, want)
+		}
+	}
+	if _, err := ParseNumber("abc"); err == nil {
+		t.Error("ParseNumber(abc) harus error")
+	}
+}
+
+func TestParseProducts(t *testing.T) {
+	rows := [][]string{
+		{"Nama", "Harga", "Kategori", "Stok"},
+		{"Kopi", "15.000", "Minuman", "10"},
+		{"", "", "", ""},
+		{"", "5000", "", ""},
+		{"Teh", "abc", "", "1,5"},
+	}
+	items, errs := ParseProducts(rows)
+	if len(items) != 1 || items[0].Value.Name != "Kopi" || items[0].Value.Price != 15000 || items[0].Value.Stock != 10 {
+		t.Fatalf("items = %+v", items)
+	}
+	if items[0].Line != 2 {
+		t.Errorf("line = %d, want 2", items[0].Line)
+	}
+	want := map[string]int{"name": 4, "price": 5, "stock": 5}
+	if len(errs) != 3 {
+		t.Fatalf("errs = %+v", errs)
+	}
+	for _, e := range errs {
+		if want[e.Column] != e.Row {
+			t.Errorf("error %+v tidak diharapkan", e)
+		}
+	}
+}
+
+func TestParseMissingHeader(t *testing.T) {
+	_, errs := ParseCustomers([][]string{{"email", "phone"}})
+	if len(errs) != 1 || errs[0].Row != 1 || errs[0].Column != "name" {
+		t.Errorf("errs = %+v", errs)
+	}
+}
+
+func TestParseOpening(t *testing.T) {
+	rows := [][]string{
+		{"kode_akun", "debet", "kredit"},
+		{"1101", "1.000.000", ""},
+		{"3101", "", "1.000.000"},
+		{"1201", "500", "500"},
+	}
+	items, errs := ParseOpening(rows)
+	if len(items) != 2 || items[1].Value.Credit != 1000000 {
+		t.Errorf("items = %+v", items)
+	}
+	if len(errs) != 1 || errs[0].Row != 4 {
+		t.Errorf("errs = %+v", errs)
+	}
+}
+
+func TestReadRowsCSVSemicolon(t *testing.T) {
+	rows, err := ReadRows(strings.NewReader("\ufeffnama;harga\nKopi;15.000\n"), FormatOf("produk.CSV"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 2 || rows[1][1] != "15.000" {
+		t.Errorf("rows = %v", rows)
+	}
+}
+
+func TestReadRowsXLSX(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := ekspor.NewWriter(ekspor.FormatXLSX, &buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, row := range [][]interface{}{{"name", "price", "cost", "stock"}, {"Kopi", 15000.5, 1.125, 10}, {"Teh", "1.500", "", 1}} {
+		if err = w.WriteRow(row); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err = w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := ReadRows(&buf, FormatOf("produk.xlsx"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	items, errs := ParseProducts(rows)
+	if len(errs) != 0 || len(items) != 2 || items[0].Value.Price != 15000.5 || items[0].Value.Cost != 1.125 || items[0].Value.Stock != 10 {
+		t.Errorf("items = %+v, errs = %+v", items, errs)
+	}
+	// Teks "1.500" tetap dibaca dengan format Indonesia
+	if items[1].Value.Price != 1500 {
+		t.Errorf("items = %+v, errs = %+v", items, errs)
+	}
+}
+
+func TestReadRowsInvalid(t *testing.T) {
+	if _, err := ReadRows(strings.NewReader("a"), FormatOf("produk.txt")); err == nil {
+		t.Error("format txt harus ditolak")
+	}
+	if _, err := ReadRows(strings.NewReader(""), ekspor.FormatCSV); err == nil {
+		t.Error("file kosong harus ditolak")
+	}
+}
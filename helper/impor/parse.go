@@ -0,0 +1,211 @@
+package impor
+
+import (
+	"errors"
+	"net/mail"
+	"strconv"
+	"strings"
+
+	"github.com/gocroot/model"
+)
+
+// sheet adalah baris data dengan indeks kolom dari header
+type sheet struct {
+	index map[string]int
+	rows  [][]string
+}
+
+func newSheet(rows [][]string) sheet {
+	s := sheet{index: map[string]int{}, rows: rows[1:]}
+	for i, h := range rows[0] {
+		key := strings.ToLower(strings.TrimSpace(strings.TrimPrefix(h, "\ufeff")))
+		key = strings.Join(strings.Fields(key), "_")
+		if alias, ok := headerAliases[key]; ok {
+			key = alias
+		}
+		if _, dup := s.index[key]; !dup {
+			s.index[key] = i
+		}
+	}
+	return s
+}
+
+// require memeriksa kolom wajib pada header
+func (s sheet) require(fields ...string) (errs []RowError) {
+	for _, f := range fields {
+		if _, ok := s.index[f]; !ok {
+			errs = append(errs, RowError{Row: 1, Column: f, Message: "kolom wajib tidak ditemukan di header"})
+		}
+	}
+	return
+}
+
+func (s sheet) get(row []string, field string) string {
+	i, ok := s.index[field]
+	if !ok || i >= len(row) {
+		return ""
+	}
+	return strings.TrimSpace(row[i])
+}
+
+func blank(row []string) bool {
+	for _, c := range row {
+		if strings.TrimSpace(c) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// ParseNumber membaca angka dengan format Indonesia maupun Inggris: 1.500.000, 1,500,000, 1500000,50, Rp 15.000
+func ParseNumber(s string) (float64, error) {
+	s = strings.TrimSpace(s)
+	s = strings.TrimPrefix(strings.TrimPrefix(s, "Rp"), "rp")
+	s = strings.ReplaceAll(strings.TrimSpace(s), " ", "")
+	if s == "" {
+		return 0, nil
+	}
+	dot, comma := strings.LastIndex(s, "."), strings.LastIndex(s, ",")
+	switch {
+	case dot >= 0 && comma >= 0:
+		// Pemisah yang paling belakang adalah desimal
+		if comma > dot {
+			s = strings.ReplaceAll(s, ".", "")
+			s = strings.Replace(s, ",", ".", 1)
+		} else {
+			s = strings.ReplaceAll(s, ",", "")
+		}
+	case comma >= 0:
+		if strings.Count(s, ",") == 1 && len(s)-comma-1 != 3 {
+			s = strings.Replace(s, ",", ".", 1)
+		} else {
+			s = strings.ReplaceAll(s, ",", "")
+		}
+	case dot >= 0:
+		if strings.Count(s, ".") > 1 || len(s)-dot-1 == 3 {
+			s = strings.ReplaceAll(s, ".", "")
+		}
+	}
+	n, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, errors.New("bukan angka")
+	}
+	return n, nil
+}
+
+// number membaca kolom angka dan mencatat kesalahan baris jika tidak valid atau negatif
+func (s sheet) number(row []string, line int, field string, errs *[]RowError) float64 {
+	n, err := ParseNumber(s.get(row, field))
+	if err == nil && n < 0 {
+		err = errors.New("tidak boleh negatif")
+	}
+	if err != nil {
+		*errs = append(*errs, RowError{Row: line, Column: field, Message: err.Error()})
+	}
+	return n
+}
+
+// ParseProducts memvalidasi baris produk: name dan price wajib, stock harus bilangan bulat
+func ParseProducts(rows [][]string) (items []Row[model.Product], errs []RowError) {
+	s := newSheet(rows)
+	if errs = s.require("name", "price"); errs != nil {
+		return
+	}
+	for i, row := range s.rows {
+		line := i + 2
+		if blank(row) {
+			continue
+		}
+		var rowErrs []RowError
+		p := model.Product{
+			Name:        s.get(row, "name"),
+			Category:    s.get(row, "category"),
+			Description: s.get(row, "description"),
+		}
+		if p.Name == "" {
+			rowErrs = append(rowErrs, RowError{Row: line, Column: "name", Message: "wajib diisi"})
+		}
+		if s.get(row, "price") == "" {
+			rowErrs = append(rowErrs, RowError{Row: line, Column: "price", Message: "wajib diisi"})
+		}
+		p.Price = s.number(row, line, "price", &rowErrs)
+		p.Cost = s.number(row, line, "cost", &rowErrs)
+		stock := s.number(row, line, "stock", &rowErrs)
+		if stock != float64(int(stock)) {
+			rowErrs = append(rowErrs, RowError{Row: line, Column: "stock", Message: "harus bilangan bulat"})
+		}
+		p.Stock = int(stock)
+		if rowErrs != nil {
+			errs = append(errs, rowErrs...)
+			continue
+		}
+		items = append(items, Row[model.Product]{Line: line, Value: p})
+	}
+	return
+}
+
+// ParseCustomers memvalidasi baris pelanggan: name wajib, email harus valid jika diisi
+func ParseCustomers(rows [][]string) (items []Row[model.Customer], errs []RowError) {
+	s := newSheet(rows)
+	if errs = s.require("name"); errs != nil {
+		return
+	}
+	for i, row := range s.rows {
+		line := i + 2
+		if blank(row) {
+			continue
+		}
+		c := model.Customer{
+			Name:    s.get(row, "name"),
+			Email:   s.get(row, "email"),
+			Phone:   s.get(row, "phone"),
+			Address: s.get(row, "address"),
+			NPWP:    s.get(row, "npwp"),
+		}
+		if c.Name == "" {
+			errs = append(errs, RowError{Row: line, Column: "name", Message: "wajib diisi"})
+			continue
+		}
+		if c.Email != "" {
+			if _, err := mail.ParseAddress(c.Email); err != nil {
+				errs = append(errs, RowError{Row: line, Column: "email", Message: "format email tidak valid"})
+				continue
+			}
+		}
+		items = append(items, Row[model.Customer]{Line: line, Value: c})
+	}
+	return
+}
+
+// ParseOpening memvalidasi baris saldo awal: account_code wajib dan tepat satu dari debit atau credit diisi
+func ParseOpening(rows [][]string) (items []Row[OpeningLine], errs []RowError) {
+	s := newSheet(rows)
+	if errs = s.require("account_code", "debit", "credit"); errs != nil {
+		return
+	}
+	for i, row := range s.rows {
+		line := i + 2
+		if blank(row) {
+			continue
+		}
+		var rowErrs []RowError
+		l := OpeningLine{
+			AccountCode: s.get(row, "account_code"),
+			Description: s.get(row, "description"),
+		}
+		if l.AccountCode == "" {
+			rowErrs = append(rowErrs, RowError{Row: line, Column: "account_code", Message: "wajib diisi"})
+		}
+		l.Debit = s.number(row, line, "debit", &rowErrs)
+		l.Credit = s.number(row, line, "credit", &rowErrs)
+		if rowErrs == nil && (l.Debit > 0) == (l.Credit > 0) {
+			rowErrs = append(rowErrs, RowError{Row: line, Message: "isi salah satu dari debit atau credit"})
+		}
+		if rowErrs != nil {
+			errs = append(errs, rowErrs...)
+			continue
+		}
+		items = append(items, Row[OpeningLine]{Line: line, Value: l})
+	}
+	return
+}
@@ -0,0 +1,89 @@
+package impor
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/gocroot/helper/ekspor"
+	"github.com/xuri/excelize/v2"
+)
+
+// FormatOf menentukan format file dari ekstensi nama file
+func FormatOf(filename string) string {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".xlsx":
+		return ekspor.FormatXLSX
+	case ".csv":
+		return ekspor.FormatCSV
+	}
+	return ""
+}
+
+// ReadRows membaca seluruh baris dari file CSV atau sheet pertama XLSX. Baris pertama adalah header.
+func ReadRows(r io.Reader, format string) (rows [][]string, err error) {
+	switch format {
+	case ekspor.FormatCSV:
+		var data []byte
+		if data, err = io.ReadAll(r); err != nil {
+			return
+		}
+		// Excel versi Indonesia sering menyimpan CSV dengan pemisah titik koma
+		cr := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))))
+		cr.FieldsPerRecord = -1
+		if first, _, _ := bytes.Cut(data, []byte("\n")); bytes.Count(first, []byte(";")) > bytes.Count(first, []byte(",")) {
+			cr.Comma = ';'
+		}
+		rows, err = cr.ReadAll()
+	case ekspor.FormatXLSX:
+		var f *excelize.File
+		if f, err = excelize.OpenReader(r, excelize.Options{RawCellValue: true}); err != nil {
+			return
+		}
+		defer f.Close()
+		sheets := f.GetSheetList()
+		if len(sheets) == 0 {
+			return nil, errors.New("file xlsx tidak memiliki sheet")
+		}
+		if rows, err = f.GetRows(sheets[0], excelize.Options{RawCellValue: true}); err != nil {
+			return
+		}
+		err = plainDecimals(f, sheets[0], rows)
+	default:
+		err = errors.New("format file harus .csv atau .xlsx")
+	}
+	if err != nil {
+		return
+	}
+	if len(rows) == 0 {
+		return nil, errors.New("file kosong")
+	}
+	if len(rows)-1 > MaxRows {
+		return nil, errors.New("jumlah baris melebihi batas impor")
+	}
+	return
+}
+
+// plainDecimals menambah nol di belakang angka sel numerik dengan tepat tiga desimal (misalnya 1.125 menjadi 1.1250)
+// agar ParseNumber tidak membacanya sebagai pemisah ribuan 1.125 = 1125
+func plainDecimals(f *excelize.File, sheet string, rows [][]string) error {
+	for r, row := range rows {
+		for c, v := range row {
+			dot := strings.IndexByte(v, '.')
+			if dot < 0 || len(v)-dot-1 != 3 {
+				continue
+			}
+			cell, err := excelize.CoordinatesToCellName(c+1, r+1)
+			if err != nil {
+				return err
+			}
+			if t, err := f.GetCellType(sheet, cell); err == nil && t != excelize.CellTypeSharedString && t != excelize.CellTypeInlineString {
+				rows[r][c] = v + "0"
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package impor
+
+// MaxRows adalah jumlah baris data maksimal dalam satu file impor
+const MaxRows = 5000
+
+// RowError adalah kesalahan validasi pada satu baris file. Row dihitung seperti di spreadsheet (header = baris 1).
+type RowError struct {
+	Row     int    `json:"row"`
+	Column  string `json:"column,omitempty"`
+	Message string `json:"message"`
+}
+
+// Report adalah hasil impor per baris
+type Report struct {
+	Total    int        `json:"total"`
+	Imported int        `json:"imported"`
+	Skipped  int        `json:"skipped"`
+	DryRun   bool       `json:"dry_run,omitempty"`
+	Errors   []RowError `json:"errors"`
+}
+
+// Row adalah data hasil parsing beserta nomor barisnya
+type Row[T any] struct {
+	Line  int
+	Value T
+}
+
+// OpeningLine adalah satu baris saldo awal akun
+type OpeningLine struct {
+	AccountCode string
+	Description string
+	Debit       float64
+	Credit      float64
+}
+
+// headerAliases memetakan judul kolom (bahasa Indonesia atau Inggris) ke nama field
+var headerAliases = map[string]string{
+	"nama": "name", "nama_produk": "name", "nama_pelanggan": "name",
+	"harga": "price", "harga_jual": "price",
+	"kategori":  "category",
+	"deskripsi": "description", "keterangan": "description",
+	"stok": "stock", "stok_awal": "stock",
+	"harga_pokok": "cost", "hpp": "cost",
+	"telepon": "phone", "no_hp": "phone", "nomor_telepon": "phone", "phone_number": "phone",
+	"alamat":    "address",
+	"kode_akun": "account_code", "kode": "account_code", "account": "account_code",
+	"debet":  "debit",
+	"kredit": "credit",
+}
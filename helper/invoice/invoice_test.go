@@ -0,0 +1,96 @@
+package invoice
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/gocroot/model"
+)
+
+func TestRupiah(t *testing.T) {
+	cases := map[float64]string{
+		0:        "Rp 0",
+		999:      "Rp 999",
+		1250000:  "Rp 1.250.000",
+		-45000.4: "-Rp 45.000",
+	}
+	for v, want := range cases {
+		if got := Rupiah(v); got != want {
+			t.Errorf("Rupiah(%v) = %s, want %s", v, got, want)
+		}
+	}
+}
+
+func TestFormatNumber(t *testing.T) {
+	if got := FormatNumber(2024, 42); got != "INV/2024/000042" {
+		t.Errorf("FormatNumber = %s", got)
+	}
+}
+
+func TestRenderLayouts(t *testing.T) {
+	trx := model.SalesTransaction{
+		InvoiceNumber:   "INV/2024/000001",
+		TransactionDate: time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC),
+		CustomerName:    "Budi",
+		Products:        []model.Product{{Name: "Kopi", Price: 15000, Quantity: 2}, {Name: "Roti", Price: 10000}},
+		TotalAmount:     40000,
+		PaymentStatus:   "bon",
+		PaidAmount:      10000,
+	}
+	for _, layout := range []string{LayoutA4, LayoutThermal} {
+		data, err := Render(trx, model.StoreProfile{Name: "Toko Maju", Footer: "Terima kasih"}, layout)
+		if err != nil {
+			t.Fatalf("%s: %v", layout, err)
+		}
+		if !bytes.HasPrefix(data, []byte("%PDF")) {
+			t.Errorf("%s: output bukan PDF", layout)
+		}
+	}
+}
+
+func TestNormalizePhone(t *testing.T) {
+	cases := map[string]string{
+		"0812-3456-7890":  "6281234567890",
+		"+62 812 3456789": "628123456789",
+		"6281234567":      "6281234567",
+	}
+	for in, want := range cases {
+		if got := NormalizePhone(in); got != want {
+			t.Errorf("NormalizePhone(%q) = %s, want %s", in, got, want)
+		}
+	}
+}
+
+func TestFillTemplate(t *testing.T) {
+	trx := model.SalesTransaction{
+		InvoiceNumber:   "INV/2024/000007",
+		TransactionDate: time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC),
+		CustomerName:    "Sari",
+		TotalAmount:     150000,
+		PaidAmount:      50000,
+		PaymentStatus:   "partial",
+	}
+	got := FillTemplate(defaultTemplates[TemplateReminder], trx, model.StoreProfile{Name: "Toko Maju"})
+	want := "Halo kak Sari, kami mengingatkan faktur INV/2024/000007 dari Toko Maju masih ada sisa Rp 100.000 sejak 01-05-2024. Mohon segera diselesaikan ya kak, terima kasih."
+	if got != want {
+		t.Errorf("FillTemplate = %q", got)
+	}
+}
+
+func TestDueForReminder(t *testing.T) {
+	now := time.Date(2024, 6, 30, 0, 0, 0, 0, time.UTC)
+	trx := model.SalesTransaction{TotalAmount: 100000, PaymentStatus: "bon", TransactionDate: now.AddDate(0, 0, -10)}
+	if !DueForReminder(trx, 7, now) {
+		t.Error("piutang 10 hari seharusnya diingatkan")
+	}
+	trx.RemindedAt = now.AddDate(0, 0, -2)
+	if DueForReminder(trx, 7, now) {
+		t.Error("piutang yang baru diingatkan tidak perlu diingatkan lagi")
+	}
+	trx.RemindedAt = time.Time{}
+	trx.PaymentStatus = "lunas"
+	if DueForReminder(trx, 7, now) {
+		t.Error("penjualan lunas tidak perlu diingatkan")
+	}
+}
@@ -0,0 +1,81 @@
+package invoice
+
+import (
+	"strings"
+	"time"
+
+	"github.com/gocroot/helper/atdb"
+	"github.com/gocroot/helper/piutang"
+	"github.com/gocroot/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// NormalizePhone mengubah nomor 08xx atau +628xx menjadi format 628xx yang dipakai API WhatsApp
+func NormalizePhone(phone string) string {
+	phone = strings.NewReplacer(" ", "", "-", "", "+", "").Replace(strings.TrimSpace(phone))
+	if strings.HasPrefix(phone, "0") {
+		phone = "62" + phone[1:]
+	}
+	return phone
+}
+
+// Template mengambil template pesan dari koleksi prefill seperti helpdesk.GetPrefillMessage.
+// Template khusus toko didahulukan, lalu template umum, lalu template bawaan.
+func Template(db *mongo.Database, key string, store model.StoreProfile) string {
+	keys := []string{key}
+	if !store.ID.IsZero() {
+		keys = []string{TemplateKey(key, store), key}
+	}
+	for _, k := range keys {
+		templ, err := atdb.GetOneDoc[model.Prefill](db, PrefillCollection, bson.M{"key": k})
+		if err == nil && templ.Value != "" {
+			return templ.Value
+		}
+	}
+	return defaultTemplates[key]
+}
+
+// TemplateKey membentuk kunci prefill template khusus toko
+func TemplateKey(key string, store model.StoreProfile) string {
+	if store.ID.IsZero() {
+		return key
+	}
+	return key + "_" + store.ID.Hex()
+}
+
+// IsTemplateKey memeriksa kunci template pesan faktur yang dikenal
+func IsTemplateKey(key string) bool {
+	_, ok := defaultTemplates[key]
+	return ok
+}
+
+// FillTemplate mengganti placeholder ##NAMA##, ##NOFAKTUR##, ##TOKO##, ##TOTAL##, ##SISA##, ##TANGGAL## dan ##JATUHTEMPO##
+func FillTemplate(templ string, trx model.SalesTransaction, store model.StoreProfile) string {
+	dueDate := "-"
+	if !trx.DueDate.IsZero() {
+		dueDate = trx.DueDate.Format("02-01-2006")
+	}
+	return strings.NewReplacer(
+		"##NAMA##", trx.CustomerName,
+		"##NOFAKTUR##", trx.InvoiceNumber,
+		"##TOKO##", store.Name,
+		"##TOTAL##", Rupiah(trx.TotalAmount),
+		"##SISA##", Rupiah(piutang.Outstanding(trx)),
+		"##TANGGAL##", trx.TransactionDate.Format("02-01-2006"),
+		"##JATUHTEMPO##", dueDate,
+	).Replace(templ)
+}
+
+// DueForReminder bernilai true jika piutang sudah lebih dari days hari dan belum diingatkan dalam days hari terakhir
+func DueForReminder(trx model.SalesTransaction, days int, now time.Time) bool {
+	if piutang.Outstanding(trx) <= 0 {
+		return false
+	}
+	since := trx.TransactionDate
+	if !trx.DueDate.IsZero() {
+		since = trx.DueDate
+	}
+	limit := now.AddDate(0, 0, -days)
+	return since.Before(limit) && (trx.RemindedAt.IsZero() || trx.RemindedAt.Before(limit))
+}
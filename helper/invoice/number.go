@@ -0,0 +1,108 @@
+package invoice
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gocroot/helper/atdb"
+	"github.com/gocroot/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// FormatNumber membentuk nomor faktur INV/<tahun>/<urut 6 digit>
+func FormatNumber(year, seq int) string {
+	return fmt.Sprintf("INV/%d/%06d", year, seq)
+}
+
+// FormatReturnNumber membentuk nomor retur RTR/<tahun>/<urut 6 digit>
+func FormatReturnNumber(year, seq int) string {
+	return fmt.Sprintf("RTR/%d/%06d", year, seq)
+}
+
+// NextNumber mengambil nomor faktur berikutnya milik owner secara atomik. Urutan dimulai ulang setiap tahun.
+func NextNumber(db *mongo.Database, owner string, date time.Time) (number string, err error) {
+	seq, err := nextSequence(db, "invoice-"+owner+"-"+strconv.Itoa(date.Year()))
+	if err != nil {
+		return
+	}
+	return FormatNumber(date.Year(), seq), nil
+}
+
+// NextReturnNumber mengambil nomor retur penjualan berikutnya milik owner, urutannya terpisah dari nomor faktur
+func NextReturnNumber(db *mongo.Database, owner string, date time.Time) (number string, err error) {
+	seq, err := nextSequence(db, "return-"+owner+"-"+strconv.Itoa(date.Year()))
+	if err != nil {
+		return
+	}
+	return FormatReturnNumber(date.Year(), seq), nil
+}
+
+// ReleaseNumber mengembalikan nomor faktur yang gagal dipakai ke urutan owner agar tidak ada nomor yang terlewat.
+// Nomor hanya bisa dikembalikan jika belum ada nomor yang lebih baru, selain itu released bernilai false.
+func ReleaseNumber(db *mongo.Database, owner string, date time.Time, number string) (released bool, err error) {
+	seq, err := strconv.Atoi(number[strings.LastIndex(number, "/")+1:])
+	if err != nil {
+		return
+	}
+	key := "invoice-" + owner + "-" + strconv.Itoa(date.Year())
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	res, err := db.Collection(CounterCollection).UpdateOne(ctx, bson.M{"_id": key, "value": seq}, bson.M{"$inc": bson.M{"value": -1}})
+	if err != nil {
+		return
+	}
+	return res.ModifiedCount == 1, nil
+}
+
+func nextSequence(db *mongo.Database, key string) (seq int, err error) {
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var c counter
+	err = db.Collection(CounterCollection).FindOneAndUpdate(ctx, bson.M{"_id": key}, bson.M{"$inc": bson.M{"value": 1}}, opts).Decode(&c)
+	return c.Value, err
+}
+
+// Assign memberi nomor faktur pada penjualan yang belum punya nomor, misalnya penjualan lama.
+// Nomor hanya diset jika belum ada sehingga satu penjualan tidak pernah punya dua nomor.
+func Assign(db *mongo.Database, trx *model.SalesTransaction) (err error) {
+	if trx.InvoiceNumber != "" {
+		return
+	}
+	date := trx.TransactionDate
+	if date.IsZero() {
+		date = time.Now()
+	}
+	number, err := NextNumber(db, trx.Owner, date)
+	if err != nil {
+		return
+	}
+	filter := bson.M{"_id": trx.ID, "invoice_number": bson.M{"$in": bson.A{nil, ""}}}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if _, err = db.Collection(SalesCollection).UpdateOne(ctx, filter, bson.M{"$set": bson.M{"invoice_number": number}}); err != nil {
+		return
+	}
+	// Ambil ulang karena permintaan lain mungkin sudah lebih dulu memberi nomor
+	stored, err := atdb.GetOneDoc[model.SalesTransaction](db, SalesCollection, bson.M{"_id": trx.ID})
+	if err != nil {
+		return
+	}
+	trx.InvoiceNumber = stored.InvoiceNumber
+	return
+}
+
+// Store mengambil profil toko milik owner, nama default dipakai jika profil belum diisi
+func Store(db *mongo.Database, owner string) model.StoreProfile {
+	store, err := atdb.GetOneDoc[model.StoreProfile](db, StoreCollection, bson.M{"owner": owner})
+	if err != nil || strings.TrimSpace(store.Name) == "" {
+		store.Name = "Toko"
+	}
+	return store
+}
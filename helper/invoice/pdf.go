@@ -0,0 +1,255 @@
+package invoice
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/gocroot/helper/ledger"
+	"github.com/gocroot/helper/piutang"
+	"github.com/gocroot/model"
+	"github.com/raykov/gofpdf"
+)
+
+// Rupiah memformat nominal dengan pemisah ribuan titik, misalnya Rp 1.250.000
+func Rupiah(v float64) string {
+	sign := ""
+	if v < 0 {
+		sign = "-"
+		v = -v
+	}
+	s := strconv.FormatInt(int64(math.Round(v)), 10)
+	var b strings.Builder
+	for i, c := range s {
+		if i > 0 && (len(s)-i)%3 == 0 {
+			b.WriteByte('.')
+		}
+		b.WriteRune(c)
+	}
+	return sign + "Rp " + b.String()
+}
+
+// itemQty mengikuti aturan stok dan jurnal: kuantitas kosong dihitung satu
+func itemQty(p model.Product) int {
+	if p.Quantity <= 0 {
+		return 1
+	}
+	return p.Quantity
+}
+
+// Subtotal menjumlahkan harga kali kuantitas semua item
+func Subtotal(trx model.SalesTransaction) (subtotal float64) {
+	for _, p := range trx.Products {
+		subtotal += p.Price * float64(itemQty(p))
+	}
+	return ledger.Round2(subtotal)
+}
+
+// Discount menjumlahkan diskon semua item, termasuk bagian diskon keranjang dan promo
+func Discount(trx model.SalesTransaction) (discount float64) {
+	for _, p := range trx.Products {
+		discount += p.Discount
+	}
+	return ledger.Round2(discount)
+}
+
+// paymentLabel menampilkan status pembayaran yang mudah dibaca pembeli
+func paymentLabel(trx model.SalesTransaction) string {
+	if piutang.Outstanding(trx) > 0 {
+		if trx.PaidAmount > 0 {
+			return "DIBAYAR SEBAGIAN"
+		}
+		return "BELUM LUNAS"
+	}
+	return "LUNAS"
+}
+
+// Render membuat PDF faktur penjualan, layout "58mm" untuk struk printer thermal dan selain itu A4
+func Render(trx model.SalesTransaction, store model.StoreProfile, layout string) ([]byte, error) {
+	var pdf *gofpdf.Fpdf
+	if layout == LayoutThermal {
+		pdf = renderThermal(trx, store)
+	} else {
+		pdf = renderA4(trx, store)
+	}
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func renderA4(trx model.SalesTransaction, store model.StoreProfile) *gofpdf.Fpdf {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+	pdf.AddPage()
+
+	// Kepala faktur: identitas toko di kiri, judul dan nomor di kanan
+	pdf.SetFont("Arial", "B", 16)
+	pdf.CellFormat(110, 8, tr(store.Name), "", 0, "L", false, 0, "")
+	pdf.CellFormat(80, 8, "FAKTUR", "", 1, "R", false, 0, "")
+	pdf.SetFont("Arial", "", 10)
+	pdf.CellFormat(110, 5, tr(store.Address), "", 0, "L", false, 0, "")
+	pdf.CellFormat(80, 5, "No: "+trx.InvoiceNumber, "", 1, "R", false, 0, "")
+	pdf.CellFormat(110, 5, tr(store.Phone), "", 0, "L", false, 0, "")
+	pdf.CellFormat(80, 5, "Tanggal: "+trx.TransactionDate.Format("02-01-2006"), "", 1, "R", false, 0, "")
+	if store.NPWP != "" {
+		pdf.CellFormat(110, 5, "NPWP: "+store.NPWP, "", 1, "L", false, 0, "")
+	}
+	pdf.Ln(6)
+
+	pdf.SetFont("Arial", "B", 11)
+	pdf.CellFormat(0, 6, "Kepada: "+tr(trx.CustomerName), "", 1, "L", false, 0, "")
+	pdf.Ln(2)
+
+	// Tabel item
+	widths := []float64{10, 85, 20, 35, 40}
+	pdf.SetFont("Arial", "B", 10)
+	pdf.SetFillColor(230, 230, 230)
+	for i, h := range []string{"No", "Produk", "Qty", "Harga", "Subtotal"} {
+		align := "L"
+		if i >= 2 {
+			align = "R"
+		}
+		pdf.CellFormat(widths[i], 7, h, "1", 0, align, true, 0, "")
+	}
+	pdf.Ln(-1)
+	pdf.SetFont("Arial", "", 10)
+	for i, p := range trx.Products {
+		qty := itemQty(p)
+		pdf.CellFormat(widths[0], 7, strconv.Itoa(i+1), "1", 0, "L", false, 0, "")
+		pdf.CellFormat(widths[1], 7, tr(p.Name), "1", 0, "L", false, 0, "")
+		pdf.CellFormat(widths[2], 7, strconv.Itoa(qty), "1", 0, "R", false, 0, "")
+		pdf.CellFormat(widths[3], 7, Rupiah(p.Price), "1", 0, "R", false, 0, "")
+		pdf.CellFormat(widths[4], 7, Rupiah(p.Price*float64(qty)), "1", 1, "R", false, 0, "")
+	}
+	pdf.Ln(2)
+
+	// Ringkasan total
+	for _, row := range summaryRows(trx) {
+		pdf.SetFont("Arial", row.style, 10)
+		pdf.CellFormat(150, 6, row.label, "", 0, "R", false, 0, "")
+		pdf.CellFormat(40, 6, row.value, "", 1, "R", false, 0, "")
+	}
+	pdf.Ln(4)
+	pdf.SetFont("Arial", "B", 12)
+	pdf.CellFormat(0, 8, "Status: "+paymentLabel(trx), "", 1, "L", false, 0, "")
+	if trx.PaymentMethod != "" {
+		pdf.SetFont("Arial", "", 10)
+		pdf.CellFormat(0, 5, "Metode pembayaran: "+tr(trx.PaymentMethod), "", 1, "L", false, 0, "")
+	}
+	if !trx.DueDate.IsZero() && piutang.Outstanding(trx) > 0 {
+		pdf.SetFont("Arial", "", 10)
+		pdf.CellFormat(0, 5, "Jatuh tempo: "+trx.DueDate.Format("02-01-2006"), "", 1, "L", false, 0, "")
+	}
+	if store.Footer != "" {
+		pdf.Ln(6)
+		pdf.SetFont("Arial", "I", 9)
+		pdf.MultiCell(0, 5, tr(store.Footer), "", "L", false)
+	}
+	return pdf
+}
+
+func renderThermal(trx model.SalesTransaction, store model.StoreProfile) *gofpdf.Fpdf {
+	// Tinggi struk mengikuti jumlah item agar tidak ada kertas kosong
+	height := 75 + float64(len(trx.Products))*8
+	if Discount(trx) > 0 {
+		height += 3.5
+	}
+	if trx.Tendered > 0 {
+		height += 7
+	}
+	if store.Footer != "" {
+		height += 10
+	}
+	pdf := gofpdf.NewCustom(&gofpdf.InitType{
+		OrientationStr: "P",
+		UnitStr:        "mm",
+		Size:           gofpdf.SizeType{Wd: thermalWidth, Ht: height},
+	})
+	pdf.SetMargins(thermalMargin, thermalMargin, thermalMargin)
+	pdf.SetAutoPageBreak(false, 0)
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+	pdf.AddPage()
+	width := thermalWidth - 2*thermalMargin
+
+	pdf.SetFont("Courier", "B", 9)
+	pdf.MultiCell(width, 4, tr(store.Name), "", "C", false)
+	pdf.SetFont("Courier", "", 7)
+	if store.Address != "" {
+		pdf.MultiCell(width, 3, tr(store.Address), "", "C", false)
+	}
+	if store.Phone != "" {
+		pdf.CellFormat(width, 3, tr(store.Phone), "", 1, "C", false, 0, "")
+	}
+	separator(pdf, width)
+	pdf.CellFormat(width, 3, trx.InvoiceNumber, "", 1, "L", false, 0, "")
+	pdf.CellFormat(width, 3, trx.TransactionDate.Format("02-01-2006 15:04"), "", 1, "L", false, 0, "")
+	pdf.CellFormat(width, 3, tr(trx.CustomerName), "", 1, "L", false, 0, "")
+	separator(pdf, width)
+
+	for _, p := range trx.Products {
+		qty := itemQty(p)
+		pdf.CellFormat(width, 3.5, tr(p.Name), "", 1, "L", false, 0, "")
+		pdf.CellFormat(width/2, 3.5, fmt.Sprintf("%d x %s", qty, Rupiah(p.Price)), "", 0, "L", false, 0, "")
+		pdf.CellFormat(width/2, 3.5, Rupiah(p.Price*float64(qty)), "", 1, "R", false, 0, "")
+	}
+	separator(pdf, width)
+
+	for _, row := range summaryRows(trx) {
+		pdf.SetFont("Courier", row.style, 7)
+		pdf.CellFormat(width/2, 3.5, row.label, "", 0, "L", false, 0, "")
+		pdf.CellFormat(width/2, 3.5, row.value, "", 1, "R", false, 0, "")
+	}
+	separator(pdf, width)
+	pdf.SetFont("Courier", "B", 8)
+	pdf.CellFormat(width, 4, paymentLabel(trx), "", 1, "C", false, 0, "")
+	if store.Footer != "" {
+		pdf.SetFont("Courier", "", 6)
+		pdf.MultiCell(width, 3, tr(store.Footer), "", "C", false)
+	}
+	return pdf
+}
+
+func separator(pdf *gofpdf.Fpdf, width float64) {
+	x, y := pdf.GetXY()
+	pdf.SetDashPattern([]float64{0.8, 0.8}, 0)
+	pdf.Line(x, y+1, x+width, y+1)
+	pdf.SetDashPattern([]float64{}, 0)
+	pdf.Ln(2)
+}
+
+type summaryRow struct {
+	label, value, style string
+}
+
+// summaryRows menyusun baris subtotal, pajak, total dan sisa tagihan
+func summaryRows(trx model.SalesTransaction) []summaryRow {
+	rows := []summaryRow{{"Subtotal", Rupiah(Subtotal(trx)), ""}}
+	if discount := Discount(trx); discount > 0 {
+		label := "Diskon"
+		if trx.PromoCode != "" {
+			label += " (" + trx.PromoCode + ")"
+		}
+		rows = append(rows, summaryRow{label, Rupiah(-discount), ""})
+	}
+	if trx.TaxAmount > 0 {
+		rows = append(rows, summaryRow{"Pajak", Rupiah(trx.TaxAmount), ""})
+	}
+	rows = append(rows, summaryRow{"Total", Rupiah(trx.TotalAmount), "B"})
+	if trx.Tendered > 0 {
+		rows = append(rows,
+			summaryRow{"Tunai", Rupiah(trx.Tendered), ""},
+			summaryRow{"Kembali", Rupiah(trx.Change), ""},
+		)
+	}
+	if outstanding := piutang.Outstanding(trx); outstanding > 0 {
+		rows = append(rows,
+			summaryRow{"Dibayar", Rupiah(trx.PaidAmount), ""},
+			summaryRow{"Sisa", Rupiah(outstanding), "B"},
+		)
+	}
+	return rows
+}
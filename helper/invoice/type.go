@@ -0,0 +1,43 @@
+package invoice
+
+import "github.com/gocroot/helper/repo"
+
+// Nama koleksi yang dipakai faktur
+const (
+	SalesCollection   = repo.SalesCollection
+	CounterCollection = "counters"
+	StoreCollection   = repo.StoreCollection
+	PrefillCollection = "prefill"
+)
+
+// Kunci template pesan WhatsApp di koleksi prefill. Template khusus toko memakai kunci <kunci>_<id toko>.
+const (
+	TemplateInvoice  = "kirimfaktur"
+	TemplateReminder = "pengingatpiutang"
+)
+
+// DefaultReminderDays dipakai jika profil toko belum mengatur reminder_days
+const DefaultReminderDays = 7
+
+// Template bawaan jika belum ada di koleksi prefill
+var defaultTemplates = map[string]string{
+	TemplateInvoice:  "Halo kak ##NAMA##, berikut faktur ##NOFAKTUR## dari ##TOKO## sebesar ##TOTAL##. Terima kasih sudah berbelanja.",
+	TemplateReminder: "Halo kak ##NAMA##, kami mengingatkan faktur ##NOFAKTUR## dari ##TOKO## masih ada sisa ##SISA## sejak ##TANGGAL##. Mohon segera diselesaikan ya kak, terima kasih.",
+}
+
+// Tata letak PDF faktur
+const (
+	LayoutA4      = "a4"
+	LayoutThermal = "58mm"
+)
+
+// Lebar kertas dan margin struk printer thermal dalam milimeter
+const (
+	thermalWidth  = 58.0
+	thermalMargin = 3.0
+)
+
+type counter struct {
+	Key   string `bson:"_id"`
+	Value int    `bson:"value"`
+}
@@ -1,23 +1,23 @@
 package jualin
 
 type Order struct {
-	Name     string `json:"name" bson:"name"`
-	Quantity int    `json:"quantity" bson:"quantity"`
-	Price    int    `json:"price" bson:"price"`
+	Name     string `json:"name" bson:"name" validate:"required"`
+	Quantity int    `json:"quantity" bson:"quantity" validate:"positive"`
+	Price    int    `json:"price" bson:"price" validate:"positive"`
 }
 
 type User struct {
-	Name     string `json:"name" bson:"name"`
-	Whatsapp string `json:"whatsapp" bson:"whatsapp"`
-	Address  string `json:"address" bson:"address"`
+	Name     string `json:"name" bson:"name" validate:"required"`
+	Whatsapp string `json:"whatsapp" bson:"whatsapp" validate:"required,phone"`
+	Address  string `json:"address" bson:"address" validate:"required"`
 }
 
 type PaymentRequest struct {
-	Orders        []Order `json:"orders" bson:"orders"`
-	Total         int     `json:"total" bson:"total"`
+	Orders        []Order `json:"orders" bson:"orders" validate:"required"`
+	Total         int     `json:"total" bson:"total" validate:"positive"`
 	User          User    `json:"user" bson:"user"`
 	Payment       string  `json:"payment" bson:"payment"`
-	PaymentMethod string  `json:"paymentMethod" bson:"paymentMethod"`
+	PaymentMethod string  `json:"paymentMethod" bson:"paymentMethod" validate:"required"`
 }
 
 type MenuItem struct {
@@ -0,0 +1,81 @@
+package kasir
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/gocroot/helper/atdb"
+	"github.com/gocroot/helper/audit"
+	"github.com/gocroot/helper/invoice"
+	"github.com/gocroot/helper/pajak"
+	"github.com/gocroot/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Checkout mengambil produk, kode promo dan profil toko milik owner lalu menghitung transaksi dari keranjang.
+// Transaksi belum disimpan dan stok belum dikurangi.
+func Checkout(db *mongo.Database, owner string, cart Cart, now time.Time) (trx model.SalesTransaction, promo *model.Promo, err error) {
+	ids := make([]primitive.ObjectID, 0, len(cart.Items))
+	for _, item := range cart.Items {
+		ids = append(ids, item.ProductID)
+	}
+	list, err := atdb.GetAllDoc[[]model.Product](db, ProductCollection, audit.Live(bson.M{"owner": owner, "_id": bson.M{"$in": ids}}))
+	if err != nil {
+		return
+	}
+	products := make(map[primitive.ObjectID]model.Product, len(list))
+	for _, p := range list {
+		products[p.ID] = p
+	}
+
+	if code := strings.ToUpper(strings.TrimSpace(cart.PromoCode)); code != "" {
+		var found model.Promo
+		found, err = atdb.GetOneDoc[model.Promo](db, PromoCollection, bson.M{"owner": owner, "code": code})
+		if err == mongo.ErrNoDocuments {
+			err = ErrPromoInvalid
+		}
+		if err != nil {
+			return
+		}
+		promo = &found
+	}
+
+	store, err := atdb.GetOneDoc[model.StoreProfile](db, invoice.StoreCollection, bson.M{"owner": owner})
+	if err != nil && err != mongo.ErrNoDocuments {
+		return
+	}
+	var rates pajak.Rates
+	if store.PKP {
+		if rates, err = pajak.LoadRates(db, owner, store); err != nil {
+			return
+		}
+	}
+	trx, err = Price(cart, products, promo, rates, store, now)
+	trx.Owner = owner
+	return
+}
+
+// ClaimPromo menambah pemakaian kode promo secara atomik, ditolak jika kuota sudah habis
+func ClaimPromo(db *mongo.Database, promo model.Promo) error {
+	filter := bson.M{"_id": promo.ID, "owner": promo.Owner, "active": true}
+	if promo.UsageLimit > 0 {
+		filter["used_count"] = bson.M{"$lt": promo.UsageLimit}
+	}
+	result, err := db.Collection(PromoCollection).UpdateOne(context.Background(), filter, bson.M{"$inc": bson.M{"used_count": 1}})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return ErrPromoUsedUp
+	}
+	return nil
+}
+
+// ReleasePromo mengembalikan kuota kode promo saat checkout gagal disimpan
+func ReleasePromo(db *mongo.Database, promo model.Promo) error {
+	_, err := db.Collection(PromoCollection).UpdateOne(context.Background(), bson.M{"_id": promo.ID, "owner": promo.Owner}, bson.M{"$inc": bson.M{"used_count": -1}})
+	return err
+}
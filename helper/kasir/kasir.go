@@ -0,0 +1,216 @@
+package kasir
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gocroot/helper/ledger"
+	"github.com/gocroot/helper/pajak"
+	"github.com/gocroot/helper/piutang"
+	"github.com/gocroot/model"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Price menyusun transaksi penjualan dari keranjang: harga produk saat ini, diskon item, diskon keranjang dan promo,
+// PPN sesuai profil toko, serta kembalian. Diskon keranjang dibagi ke item sebanding nilai item agar DPP per item tepat.
+func Price(cart Cart, products map[primitive.ObjectID]model.Product, promo *model.Promo, rates pajak.Rates, store model.StoreProfile, now time.Time) (trx model.SalesTransaction, err error) {
+	if len(cart.Items) == 0 {
+		err = ErrEmptyCart
+		return
+	}
+	trx = model.SalesTransaction{
+		CustomerID:     cart.CustomerID,
+		CustomerName:   strings.TrimSpace(cart.CustomerName),
+		PaymentMethod:  cart.PaymentMethod,
+		PaymentStatus:  cart.PaymentStatus,
+		DueDate:        cart.DueDate,
+		AllowBackorder: cart.AllowBackorder,
+	}
+	if trx.CustomerName == "" {
+		trx.CustomerName = "Umum"
+	}
+
+	var net float64
+	for _, item := range cart.Items {
+		if item.Quantity <= 0 {
+			err = errors.New("quantity item harus lebih dari nol")
+			return
+		}
+		product, ok := products[item.ProductID]
+		if !ok {
+			err = fmt.Errorf("%w: %s", ErrProductNotFound, item.ProductID.Hex())
+			return
+		}
+		line := model.Product{
+			ID:       product.ID,
+			Name:     product.Name,
+			Price:    product.Price,
+			Category: product.Category,
+			Cost:     product.Cost,
+			TaxRate:  product.TaxRate,
+			Quantity: item.Quantity,
+		}
+		gross := ledger.Round2(line.Price * float64(line.Quantity))
+		if line.Discount, err = discount(gross, item.Discount, item.DiscountPercent); err != nil {
+			err = fmt.Errorf("%s: %w", product.Name, err)
+			return
+		}
+		trx.Subtotal += gross
+		net += gross - line.Discount
+		trx.Products = append(trx.Products, line)
+	}
+	trx.Subtotal = ledger.Round2(trx.Subtotal)
+	net = ledger.Round2(net)
+
+	cartDiscount, err := discount(net, cart.Discount, cart.DiscountPercent)
+	if err != nil {
+		return
+	}
+	if promo != nil {
+		var promoDiscount float64
+		if promoDiscount, err = PromoDiscount(*promo, net, now); err != nil {
+			return
+		}
+		trx.PromoCode = promo.Code
+		cartDiscount += promoDiscount
+	}
+	if cartDiscount > net {
+		cartDiscount = net
+	}
+	allocate(trx.Products, cartDiscount)
+	for _, p := range trx.Products {
+		trx.DiscountAmount += p.Discount
+	}
+	trx.DiscountAmount = ledger.Round2(trx.DiscountAmount)
+
+	trx.TotalAmount = ledger.Round2(trx.Subtotal - trx.DiscountAmount)
+	if store.PKP {
+		pajak.Apply(&trx, rates, store.PriceIncludesTax)
+	} else {
+		pajak.Clear(&trx)
+	}
+
+	err = settle(&trx, cart.Tendered)
+	return
+}
+
+// discount menghitung diskon rupiah atau persen terhadap nilai, diskon tidak boleh melebihi nilai
+func discount(value, amount, percent float64) (float64, error) {
+	switch {
+	case amount < 0 || percent < 0 || percent > 100:
+		return 0, errors.New("diskon harus antara 0 dan nilai belanja, persen antara 0 dan 100")
+	case amount > 0 && percent > 0:
+		return 0, errors.New("pilih diskon rupiah atau persen, tidak keduanya")
+	case percent > 0:
+		return ledger.Round2(value * percent / 100), nil
+	case amount > value:
+		return 0, errors.New("diskon melebihi nilai belanja")
+	}
+	return ledger.Round2(amount), nil
+}
+
+// allocate membagi diskon keranjang ke item sebanding nilai setelah diskon item, sisa pembulatan ke item terakhir
+func allocate(items []model.Product, total float64) {
+	if total <= 0 {
+		return
+	}
+	var base float64
+	last := -1
+	for i, p := range items {
+		if v := p.Price*float64(p.Quantity) - p.Discount; v > 0 {
+			base += v
+			last = i
+		}
+	}
+	if last < 0 {
+		return
+	}
+	remaining := total
+	for i := range items {
+		v := items[i].Price*float64(items[i].Quantity) - items[i].Discount
+		if v <= 0 {
+			continue
+		}
+		share := ledger.Round2(total * v / base)
+		if i == last || share > remaining {
+			share = remaining
+		}
+		items[i].Discount = ledger.Round2(items[i].Discount + share)
+		remaining = ledger.Round2(remaining - share)
+	}
+}
+
+// settle mengisi status pembayaran, uang diterima dan kembalian.
+// Penjualan kredit tidak menerima uang, pembayaran tunai wajib menyertakan uang diterima,
+// pembayaran nontunai dianggap pas sebesar total.
+func settle(trx *model.SalesTransaction, tendered float64) error {
+	if ledger.IsCredit(trx.PaymentStatus) {
+		trx.PaidAmount = 0
+		return nil
+	}
+	trx.PaymentStatus = piutang.StatusPaid
+	trx.PaidAmount = trx.TotalAmount
+	if ledger.CashAccount(trx.PaymentMethod) != ledger.AkunKas {
+		trx.Tendered = trx.TotalAmount
+		return nil
+	}
+	if ledger.Round2(tendered) < trx.TotalAmount {
+		return ErrTenderedShort
+	}
+	trx.Tendered = ledger.Round2(tendered)
+	trx.Change = ledger.Round2(tendered - trx.TotalAmount)
+	return nil
+}
+
+// PromoDiscount menghitung diskon kode promo untuk nilai belanja setelah diskon item
+func PromoDiscount(promo model.Promo, value float64, now time.Time) (float64, error) {
+	if !promo.Active || (!promo.StartAt.IsZero() && now.Before(promo.StartAt)) || (!promo.EndAt.IsZero() && now.After(promo.EndAt)) {
+		return 0, ErrPromoInvalid
+	}
+	if promo.UsageLimit > 0 && promo.UsedCount >= promo.UsageLimit {
+		return 0, ErrPromoUsedUp
+	}
+	if value < promo.MinPurchase {
+		return 0, fmt.Errorf("%w: minimal belanja %.0f", ErrPromoInvalid, promo.MinPurchase)
+	}
+	amount := promo.Value
+	if promo.Type == PromoPercent {
+		amount = value * promo.Value / 100
+		if promo.MaxDiscount > 0 && amount > promo.MaxDiscount {
+			amount = promo.MaxDiscount
+		}
+	}
+	if amount > value {
+		amount = value
+	}
+	return ledger.Round2(amount), nil
+}
+
+// ValidatePromo memeriksa data kode promo sebelum disimpan dan menormalkan kodenya
+func ValidatePromo(promo *model.Promo) error {
+	promo.Code = strings.ToUpper(strings.TrimSpace(promo.Code))
+	if promo.Code == "" || strings.ContainsAny(promo.Code, " \t") {
+		return errors.New("kode promo wajib diisi tanpa spasi")
+	}
+	switch promo.Type {
+	case PromoPercent:
+		if promo.Value <= 0 || promo.Value > 100 {
+			return errors.New("promo persen harus antara 0 dan 100")
+		}
+	case PromoAmount:
+		if promo.Value <= 0 {
+			return errors.New("nilai promo harus lebih dari nol")
+		}
+	default:
+		return errors.New("type promo harus percent atau amount")
+	}
+	if promo.MinPurchase < 0 || promo.MaxDiscount < 0 || promo.UsageLimit < 0 {
+		return errors.New("min_purchase, max_discount dan usage_limit tidak boleh negatif")
+	}
+	if !promo.StartAt.IsZero() && !promo.EndAt.IsZero() && promo.EndAt.Before(promo.StartAt) {
+		return errors.New("end_at harus setelah start_at")
+	}
+	return nil
+}
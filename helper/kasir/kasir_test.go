@@ -0,0 +1,110 @@
+package kasir
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gocroot/helper/pajak"
+	"github.com/gocroot/helper/piutang"
+	"github.com/gocroot/model"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func catalog() (map[primitive.ObjectID]model.Product, primitive.ObjectID, primitive.ObjectID) {
+	kopi, roti := primitive.NewObjectID(), primitive.NewObjectID()
+	return map[primitive.ObjectID]model.Product{
+		kopi: {ID: kopi, Name: "Kopi", Price: 20000, Cost: 8000, Stock: 10},
+		roti: {ID: roti, Name: "Roti", Price: 10000, Cost: 4000, Stock: 10},
+	}, kopi, roti
+}
+
+func TestPriceUsesServerPricesAndDiscounts(t *testing.T) {
+	products, kopi, roti := catalog()
+	cart := Cart{
+		Items: []CartItem{
+			{ProductID: kopi, Quantity: 2, DiscountPercent: 10}, // 40.000 - 4.000
+			{ProductID: roti, Quantity: 3},                      // 30.000
+		},
+		Discount:      6600, // dibagi 36.000 : 30.000
+		PaymentMethod: "tunai",
+		Tendered:      100000,
+	}
+	trx, err := Price(cart, products, nil, pajak.Rates{}, model.StoreProfile{}, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if trx.Subtotal != 70000 || trx.DiscountAmount != 10600 || trx.TotalAmount != 59400 {
+		t.Errorf("subtotal %v diskon %v total %v", trx.Subtotal, trx.DiscountAmount, trx.TotalAmount)
+	}
+	if trx.Products[0].Discount != 7600 || trx.Products[1].Discount != 3000 {
+		t.Errorf("alokasi diskon = %v, %v", trx.Products[0].Discount, trx.Products[1].Discount)
+	}
+	if trx.Change != 40600 || trx.PaidAmount != 59400 || trx.PaymentStatus != piutang.StatusPaid {
+		t.Errorf("pembayaran = %+v", trx)
+	}
+	if trx.Products[0].Cost != 8000 || trx.CustomerName != "Umum" {
+		t.Errorf("item = %+v", trx.Products[0])
+	}
+}
+
+func TestPriceWithPromoAndTax(t *testing.T) {
+	products, kopi, _ := catalog()
+	promo := &model.Promo{Code: "HEMAT", Type: PromoPercent, Value: 50, MaxDiscount: 5000, Active: true}
+	store := model.StoreProfile{PKP: true}
+	cart := Cart{Items: []CartItem{{ProductID: kopi, Quantity: 1}}, PaymentMethod: "qris"}
+	trx, err := Price(cart, products, promo, pajak.Rates{Default: 11}, store, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 20.000 - 5.000 promo, PPN 11% di luar harga
+	if trx.PromoCode != "HEMAT" || trx.TaxAmount != 1650 || trx.TotalAmount != 16650 {
+		t.Errorf("trx = %+v", trx)
+	}
+	if trx.Tendered != trx.TotalAmount || trx.Change != 0 {
+		t.Errorf("nontunai harus pas: tendered %v change %v", trx.Tendered, trx.Change)
+	}
+}
+
+func TestPriceRejects(t *testing.T) {
+	products, kopi, _ := catalog()
+	now := time.Now()
+	cases := []struct {
+		name  string
+		cart  Cart
+		promo *model.Promo
+		want  error
+	}{
+		{"kosong", Cart{}, nil, ErrEmptyCart},
+		{"produk lain", Cart{Items: []CartItem{{ProductID: primitive.NewObjectID(), Quantity: 1}}}, nil, ErrProductNotFound},
+		{"uang kurang", Cart{Items: []CartItem{{ProductID: kopi, Quantity: 1}}, Tendered: 10000}, nil, ErrTenderedShort},
+		{"promo kedaluwarsa", Cart{Items: []CartItem{{ProductID: kopi, Quantity: 1}}, Tendered: 20000},
+			&model.Promo{Type: PromoAmount, Value: 1000, Active: true, EndAt: now.Add(-time.Hour)}, ErrPromoInvalid},
+		{"promo habis", Cart{Items: []CartItem{{ProductID: kopi, Quantity: 1}}, Tendered: 20000},
+			&model.Promo{Type: PromoAmount, Value: 1000, Active: true, UsageLimit: 1, UsedCount: 1}, ErrPromoUsedUp},
+	}
+	for _, c := range cases {
+		if _, err := Price(c.cart, products, c.promo, pajak.Rates{}, model.StoreProfile{}, now); !errors.Is(err, c.want) {
+			t.Errorf("%s: err = %v, want %v", c.name, err, c.want)
+		}
+	}
+	if _, err := Price(Cart{Items: []CartItem{{ProductID: kopi, Quantity: 1, Discount: 25000}}, Tendered: 20000}, products, nil, pajak.Rates{}, model.StoreProfile{}, now); err == nil {
+		t.Error("diskon melebihi harga item harus ditolak")
+	}
+}
+
+func TestCreditSaleAndValidatePromo(t *testing.T) {
+	products, kopi, _ := catalog()
+	trx, err := Price(Cart{Items: []CartItem{{ProductID: kopi, Quantity: 1}}, PaymentStatus: "unpaid"}, products, nil, pajak.Rates{}, model.StoreProfile{}, time.Now())
+	if err != nil || trx.PaidAmount != 0 || trx.Tendered != 0 {
+		t.Errorf("kredit: %+v %v", trx, err)
+	}
+
+	promo := model.Promo{Code: " hemat10 ", Type: PromoPercent, Value: 10}
+	if err := ValidatePromo(&promo); err != nil || promo.Code != "HEMAT10" {
+		t.Errorf("promo = %+v err = %v", promo, err)
+	}
+	if ValidatePromo(&model.Promo{Code: "X", Type: PromoPercent, Value: 150}) == nil {
+		t.Error("promo lebih dari 100 persen harus ditolak")
+	}
+}
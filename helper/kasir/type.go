@@ -0,0 +1,52 @@
+package kasir
+
+import (
+	"errors"
+	"time"
+
+	"github.com/gocroot/helper/repo"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Nama koleksi yang dipakai checkout kasir
+const (
+	ProductCollection = repo.ProductCollection
+	PromoCollection   = "promos"
+)
+
+// Jenis diskon kode promo
+const (
+	PromoPercent = "percent"
+	PromoAmount  = "amount"
+)
+
+// Cart adalah permintaan checkout kasir. Harga, nama dan harga pokok item selalu diambil dari data produk di server.
+type Cart struct {
+	CustomerID      primitive.ObjectID `json:"customer_id,omitempty"`
+	CustomerName    string             `json:"customer_name"`
+	Items           []CartItem         `json:"items" validate:"required"`
+	Discount        float64            `json:"discount,omitempty" validate:"nonnegative"`                 // Diskon keranjang dalam rupiah
+	DiscountPercent float64            `json:"discount_percent,omitempty" validate:"nonnegative,max=100"` // Diskon keranjang dalam persen
+	PromoCode       string             `json:"promo_code,omitempty"`
+	PaymentMethod   string             `json:"payment_method"`
+	PaymentStatus   string             `json:"payment_status,omitempty"`                  // Kosong berarti dibayar lunas saat checkout
+	DueDate         time.Time          `json:"due_date,omitempty"`                        // Jatuh tempo jika penjualan kredit
+	Tendered        float64            `json:"tendered,omitempty" validate:"nonnegative"` // Uang yang diterima, wajib untuk pembayaran tunai
+	AllowBackorder  bool               `json:"allow_backorder,omitempty"`
+}
+
+// CartItem adalah satu produk di keranjang
+type CartItem struct {
+	ProductID       primitive.ObjectID `json:"product_id"`
+	Quantity        int                `json:"quantity" validate:"positive"`
+	Discount        float64            `json:"discount,omitempty" validate:"nonnegative"`                 // Diskon item dalam rupiah untuk seluruh kuantitas
+	DiscountPercent float64            `json:"discount_percent,omitempty" validate:"nonnegative,max=100"` // Diskon item dalam persen
+}
+
+var (
+	ErrEmptyCart       = errors.New("keranjang belanja kosong")
+	ErrProductNotFound = errors.New("produk tidak ditemukan")
+	ErrPromoInvalid    = errors.New("kode promo tidak berlaku")
+	ErrPromoUsedUp     = errors.New("kuota kode promo sudah habis")
+	ErrTenderedShort   = errors.New("uang yang diterima kurang dari total belanja")
+)
@@ -0,0 +1,247 @@
+package keuangan
+
+import (
+	"strings"
+	"time"
+
+	"github.com/gocroot/helper/atdb"
+	"github.com/gocroot/helper/audit"
+	"github.com/gocroot/helper/ledger"
+	"github.com/gocroot/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Kategori pengeluaran yang bukan aktivitas operasi
+var (
+	investingCategories = []string{"aset", "aset tetap", "peralatan", "kendaraan", "investasi"}
+	financingCategories = []string{"prive", "dividen", "pinjaman", "cicilan pinjaman", "bunga pinjaman"}
+)
+
+// ExpenseActivity menentukan aktivitas arus kas dari kategori pengeluaran: operating, investing atau financing
+func ExpenseActivity(category string) string {
+	c := strings.ToLower(strings.TrimSpace(category))
+	for _, v := range investingCategories {
+		if c == v {
+			return "investing"
+		}
+	}
+	for _, v := range financingCategories {
+		if c == v {
+			return "financing"
+		}
+	}
+	return "operating"
+}
+
+type paymentTotal struct {
+	Method string  `bson:"_id"`
+	Amount float64 `bson:"amount"`
+}
+
+type categoryPaymentTotal struct {
+	ID struct {
+		Category string `bson:"category"`
+		Method   string `bson:"method"`
+	} `bson:"_id"`
+	Amount float64 `bson:"amount"`
+}
+
+// CashFlow menyusun laporan arus kas metode langsung dari penjualan lunas, pengeluaran dan jurnal manual yang menyentuh kas
+func CashFlow(db *mongo.Database, owner, startDate, endDate string) (cf model.CashFlowStatement, err error) {
+	start, end, err := ParsePeriod(startDate, endDate)
+	if err != nil {
+		return
+	}
+	cf.StartDate = startDate
+	cf.EndDate = endDate
+	cf.Operating = emptySection()
+	cf.Investing = emptySection()
+	cf.Financing = emptySection()
+
+	// Penerimaan dari penjualan per metode pembayaran, penjualan kredit dan yang dilunasi dengan cicilan tidak dihitung di sini
+	var sales []paymentTotal
+	err = aggregate(db, SalesCollection, mongo.Pipeline{
+		{{Key: "$match", Value: audit.Live(bson.M{
+			"owner":           owner,
+			"transactionDate": bson.M{"$gte": start, "$lte": end},
+			"payment_status":  bson.M{"$nin": ledger.CreditStatuses},
+			"payments.0":      bson.M{"$exists": false},
+		})}},
+		{{Key: "$group", Value: bson.M{"_id": "$payment_method", "amount": bson.M{"$sum": "$total_amount"}}}},
+		{{Key: "$sort", Value: bson.M{"_id": 1}}},
+	}, &sales)
+	if err != nil {
+		return
+	}
+	for _, s := range sales {
+		cf.Operating.Inflows = append(cf.Operating.Inflows, model.CashFlowLine{
+			Description:   "Penerimaan dari pelanggan",
+			PaymentMethod: s.Method,
+			Amount:        ledger.Round2(s.Amount),
+		})
+	}
+
+	// Cicilan piutang yang diterima pada periode ini
+	var receipts []paymentTotal
+	err = aggregate(db, SalesCollection, mongo.Pipeline{
+		{{Key: "$match", Value: audit.Live(bson.M{"owner": owner, "payments.paid_at": bson.M{"$gte": start, "$lte": end}})}},
+		{{Key: "$unwind", Value: "$payments"}},
+		{{Key: "$match", Value: bson.M{"payments.paid_at": bson.M{"$gte": start, "$lte": end}}}},
+		{{Key: "$group", Value: bson.M{"_id": "$payments.payment_method", "amount": bson.M{"$sum": "$payments.amount"}}}},
+		{{Key: "$sort", Value: bson.M{"_id": 1}}},
+	}, &receipts)
+	if err != nil {
+		return
+	}
+	for _, r := range receipts {
+		cf.Operating.Inflows = append(cf.Operating.Inflows, model.CashFlowLine{
+			Description:   "Penerimaan piutang pelanggan",
+			PaymentMethod: r.Method,
+			Amount:        ledger.Round2(r.Amount),
+		})
+	}
+
+	// Pengembalian uang retur penjualan
+	var refunds []paymentTotal
+	err = aggregate(db, ReturnCollection, mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"owner": owner, "settlement": "refund", "return_date": bson.M{"$gte": start, "$lte": end}}}},
+		{{Key: "$group", Value: bson.M{"_id": "$payment_method", "amount": bson.M{"$sum": "$total_amount"}}}},
+		{{Key: "$sort", Value: bson.M{"_id": 1}}},
+	}, &refunds)
+	if err != nil {
+		return
+	}
+	for _, r := range refunds {
+		cf.Operating.Outflows = append(cf.Operating.Outflows, model.CashFlowLine{
+			Description:   "Pengembalian uang retur penjualan",
+			PaymentMethod: r.Method,
+			Amount:        ledger.Round2(r.Amount),
+		})
+	}
+
+	// Pembayaran pengeluaran per kategori dan metode pembayaran
+	var expenses []categoryPaymentTotal
+	err = aggregate(db, ExpenseCollection, mongo.Pipeline{
+		{{Key: "$match", Value: audit.Live(bson.M{"owner": owner, "expense_date": bson.M{"$gte": start, "$lte": end}})}},
+		{{Key: "$group", Value: bson.M{
+			"_id":    bson.M{"category": "$category", "method": "$payment_method"},
+			"amount": bson.M{"$sum": "$amount"},
+		}}},
+		{{Key: "$sort", Value: bson.M{"_id.category": 1}}},
+	}, &expenses)
+	if err != nil {
+		return
+	}
+	for _, e := range expenses {
+		item := model.CashFlowLine{
+			Description:   "Pembayaran " + e.ID.Category,
+			PaymentMethod: e.ID.Method,
+			Amount:        ledger.Round2(e.Amount),
+		}
+		section := sectionOf(&cf, ExpenseActivity(e.ID.Category))
+		section.Outflows = append(section.Outflows, item)
+	}
+
+	// Jurnal manual yang menyentuh kas atau bank, misalnya setoran modal
+	manual, err := atdb.GetAllDoc[[]model.JournalEntry](db, ledger.JournalCollection, bson.M{
+		"owner":       owner,
+		"source_type": ledger.SourceManual,
+		"date":        bson.M{"$gte": start, "$lte": end},
+	})
+	if err != nil {
+		return
+	}
+	types, err := ledger.AccountTypes(db, owner)
+	if err != nil {
+		return
+	}
+	for _, entry := range manual {
+		addManualEntry(&cf, entry, types)
+	}
+
+	for _, section := range []*model.CashFlowSection{&cf.Operating, &cf.Investing, &cf.Financing} {
+		var net float64
+		for _, l := range section.Inflows {
+			net += l.Amount
+		}
+		for _, l := range section.Outflows {
+			net -= l.Amount
+		}
+		section.Net = ledger.Round2(net)
+	}
+	cf.NetChange = ledger.Round2(cf.Operating.Net + cf.Investing.Net + cf.Financing.Net)
+
+	// Saldo kas awal dan akhir diambil dari buku besar
+	cf.OpeningCash, err = cashBalance(db, owner, start.Add(-time.Nanosecond))
+	if err != nil {
+		return
+	}
+	cf.ClosingCash = ledger.Round2(cf.OpeningCash + cf.NetChange)
+	return
+}
+
+// addManualEntry mengklasifikasikan jurnal manual dari akun lawan kas: ekuitas/kewajiban ke pendanaan, aset lain ke investasi
+func addManualEntry(cf *model.CashFlowStatement, entry model.JournalEntry, types map[string]string) {
+	var cash float64
+	activity := "operating"
+	for _, l := range entry.Lines {
+		if ledger.IsCashAccount(l.AccountCode) {
+			cash += l.Debit - l.Credit
+			continue
+		}
+		accType, ok := types[l.AccountCode]
+		if !ok {
+			accType = ledger.TypeFromCode(l.AccountCode)
+		}
+		switch accType {
+		case ledger.TypeEquity, ledger.TypeLiability:
+			activity = "financing"
+		case ledger.TypeAsset:
+			if activity == "operating" && l.AccountCode != ledger.AkunPiutangUsaha && l.AccountCode != ledger.AkunPersediaan {
+				activity = "investing"
+			}
+		}
+	}
+	cash = ledger.Round2(cash)
+	if cash == 0 {
+		return
+	}
+	section := sectionOf(cf, activity)
+	item := model.CashFlowLine{Description: entry.Description, PaymentMethod: "jurnal"}
+	if cash > 0 {
+		item.Amount = cash
+		section.Inflows = append(section.Inflows, item)
+	} else {
+		item.Amount = -cash
+		section.Outflows = append(section.Outflows, item)
+	}
+}
+
+func sectionOf(cf *model.CashFlowStatement, activity string) *model.CashFlowSection {
+	switch activity {
+	case "investing":
+		return &cf.Investing
+	case "financing":
+		return &cf.Financing
+	default:
+		return &cf.Operating
+	}
+}
+
+func emptySection() model.CashFlowSection {
+	return model.CashFlowSection{Inflows: []model.CashFlowLine{}, Outflows: []model.CashFlowLine{}}
+}
+
+func cashBalance(db *mongo.Database, owner string, asOf time.Time) (balance float64, err error) {
+	lines, err := ledger.TrialBalance(db, owner, asOf)
+	if err != nil {
+		return
+	}
+	for _, l := range lines {
+		if ledger.IsCashAccount(l.AccountCode) {
+			balance += l.Balance
+		}
+	}
+	return ledger.Round2(balance), nil
+}
@@ -0,0 +1,31 @@
+package keuangan
+
+import (
+	"testing"
+
+	"github.com/gocroot/helper/ledger"
+	"github.com/gocroot/model"
+)
+
+func TestBuildBalanceSheetBalanced(t *testing.T) {
+	lines := []model.TrialBalanceLine{
+		{AccountCode: ledger.AkunKas, Debit: 1500000, Credit: 300000},
+		{AccountCode: ledger.AkunModalPemilik, Credit: 1000000},
+		{AccountCode: ledger.AkunPendapatan, Credit: 500000},
+		{AccountCode: ledger.AkunBebanSewa, Debit: 300000},
+	}
+	sheet := BuildBalanceSheet(lines, map[string]string{})
+	if !sheet.Balanced {
+		t.Fatalf("neraca tidak seimbang: %+v", sheet)
+	}
+	if sheet.TotalAssets != 1200000 || sheet.TotalEquity != 1200000 {
+		t.Errorf("total tidak sesuai: aset %.2f ekuitas %.2f", sheet.TotalAssets, sheet.TotalEquity)
+	}
+}
+
+func TestBuildProfitLoss(t *testing.T) {
+	report := BuildProfitLoss(1000000, 400000, []model.CategoryAmount{{Category: "sewa", Amount: 250000}})
+	if report.GrossProfit != 600000 || report.Profit != 350000 || report.Expenses != 250000 {
+		t.Errorf("laba rugi tidak sesuai: %+v", report)
+	}
+}
@@ -0,0 +1,155 @@
+package keuangan
+
+import (
+	"context"
+	"time"
+
+	"github.com/gocroot/helper/audit"
+	"github.com/gocroot/helper/ledger"
+	"github.com/gocroot/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// ParsePeriod mengubah tanggal YYYY-MM-DD menjadi rentang waktu inklusif sampai akhir hari endDate
+func ParsePeriod(startDate, endDate string) (start, end time.Time, err error) {
+	start, err = time.Parse("2006-01-02", startDate)
+	if err != nil {
+		return
+	}
+	end, err = time.Parse("2006-01-02", endDate)
+	if err != nil {
+		return
+	}
+	end = end.Add(24*time.Hour - time.Nanosecond)
+	return
+}
+
+// SummarizeSales menghitung total pendapatan tanpa PPN dan HPP penjualan pada rentang waktu
+func SummarizeSales(db *mongo.Database, owner string, start, end time.Time) (sum salesSummary, err error) {
+	cogsPerSale := bson.M{"$reduce": bson.M{
+		"input":        bson.M{"$ifNull": bson.A{"$products", bson.A{}}},
+		"initialValue": 0,
+		"in": bson.M{"$add": bson.A{"$$value", bson.M{"$multiply": bson.A{
+			bson.M{"$ifNull": bson.A{"$$this.cost", 0}},
+			bson.M{"$max": bson.A{bson.M{"$ifNull": bson.A{"$$this.quantity", 1}}, 1}},
+		}}}},
+	}}
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: audit.Live(bson.M{"owner": owner, "transactionDate": bson.M{"$gte": start, "$lte": end}})}},
+		{{Key: "$group", Value: bson.M{
+			"_id":     nil,
+			"revenue": bson.M{"$sum": bson.M{"$subtract": bson.A{"$total_amount", bson.M{"$ifNull": bson.A{"$tax_amount", 0}}}}},
+			"cogs":    bson.M{"$sum": cogsPerSale},
+			"count":   bson.M{"$sum": 1},
+		}}},
+	}
+	var rows []salesSummary
+	if err = aggregate(db, SalesCollection, pipeline, &rows); err != nil {
+		return
+	}
+	if len(rows) > 0 {
+		sum = rows[0]
+	}
+	return
+}
+
+// SummarizeReturns menghitung retur penjualan tanpa PPN dan harga pokok barang yang kembali ke stok pada rentang waktu
+func SummarizeReturns(db *mongo.Database, owner string, start, end time.Time) (sum returnSummary, err error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{"owner": owner, "return_date": bson.M{"$gte": start, "$lte": end}}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":     nil,
+			"revenue": bson.M{"$sum": bson.M{"$subtract": bson.A{"$total_amount", bson.M{"$ifNull": bson.A{"$tax_amount", 0}}}}},
+			"cost":    bson.M{"$sum": bson.M{"$ifNull": bson.A{"$cost", 0}}},
+		}}},
+	}
+	var rows []returnSummary
+	if err = aggregate(db, ReturnCollection, pipeline, &rows); err != nil {
+		return
+	}
+	if len(rows) > 0 {
+		sum = rows[0]
+	}
+	return
+}
+
+// ExpensesByCategory menghitung total pengeluaran tanpa PPN masukan per kategori pada rentang waktu
+func ExpensesByCategory(db *mongo.Database, owner string, start, end time.Time) (rows []model.CategoryAmount, err error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: audit.Live(bson.M{"owner": owner, "expense_date": bson.M{"$gte": start, "$lte": end}})}},
+		{{Key: "$group", Value: bson.M{
+			"_id":    "$category",
+			"amount": bson.M{"$sum": bson.M{"$subtract": bson.A{"$amount", bson.M{"$ifNull": bson.A{"$tax_amount", 0}}}}},
+			"count":  bson.M{"$sum": 1},
+		}}},
+		{{Key: "$sort", Value: bson.M{"amount": -1}}},
+	}
+	if err = aggregate(db, ExpenseCollection, pipeline, &rows); err != nil {
+		return
+	}
+	for i := range rows {
+		if rows[i].Category == "" {
+			rows[i].Category = "Tanpa Kategori"
+		}
+		rows[i].Amount = ledger.Round2(rows[i].Amount)
+	}
+	return
+}
+
+// ProfitLoss menyusun laporan laba rugi dari transaksi penjualan, retur dan pengeluaran yang tersimpan
+func ProfitLoss(db *mongo.Database, owner, startDate, endDate string) (report model.LaporanAkuntan, err error) {
+	start, end, err := ParsePeriod(startDate, endDate)
+	if err != nil {
+		return
+	}
+	sales, err := SummarizeSales(db, owner, start, end)
+	if err != nil {
+		return
+	}
+	returns, err := SummarizeReturns(db, owner, start, end)
+	if err != nil {
+		return
+	}
+	expenses, err := ExpensesByCategory(db, owner, start, end)
+	if err != nil {
+		return
+	}
+	// Retur mengurangi pendapatan, barang yang kembali ke stok mengurangi HPP
+	report = BuildProfitLoss(sales.Revenue-returns.Revenue, sales.COGS-returns.Cost, expenses)
+	report.Returns = ledger.Round2(returns.Revenue)
+	report.StartDate = startDate
+	report.EndDate = endDate
+	report.StartDateTime = start
+	report.EndDateTime = end
+	return
+}
+
+// BuildProfitLoss menghitung laba kotor dan laba bersih dari komponen laporan
+func BuildProfitLoss(revenue, cogs float64, expenses []model.CategoryAmount) (report model.LaporanAkuntan) {
+	var totalExpenses float64
+	for _, e := range expenses {
+		totalExpenses += e.Amount
+	}
+	if expenses == nil {
+		expenses = []model.CategoryAmount{}
+	}
+	report.Income = ledger.Round2(revenue)
+	report.COGS = ledger.Round2(cogs)
+	report.GrossProfit = ledger.Round2(revenue - cogs)
+	report.Expenses = ledger.Round2(totalExpenses)
+	report.Profit = ledger.Round2(report.GrossProfit - totalExpenses)
+	report.ExpenseByCategory = expenses
+	return
+}
+
+func aggregate(db *mongo.Database, collection string, pipeline mongo.Pipeline, result interface{}) (err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	cursor, err := db.Collection(collection).Aggregate(ctx, pipeline)
+	if err != nil {
+		return
+	}
+	defer cursor.Close(ctx)
+	return cursor.All(ctx, result)
+}
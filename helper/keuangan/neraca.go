@@ -0,0 +1,72 @@
+package keuangan
+
+import (
+	"time"
+
+	"github.com/gocroot/helper/ledger"
+	"github.com/gocroot/model"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// BalanceSheetAsOf menyusun neraca dari jurnal umum sampai akhir hari asOfDate (YYYY-MM-DD)
+func BalanceSheetAsOf(db *mongo.Database, owner, asOfDate string) (sheet model.BalanceSheet, err error) {
+	asOf, err := time.Parse("2006-01-02", asOfDate)
+	if err != nil {
+		return
+	}
+	asOf = asOf.Add(24*time.Hour - time.Nanosecond)
+	lines, err := ledger.TrialBalance(db, owner, asOf)
+	if err != nil {
+		return
+	}
+	types, err := ledger.AccountTypes(db, owner)
+	if err != nil {
+		return
+	}
+	sheet = BuildBalanceSheet(lines, types)
+	sheet.AsOf = asOf
+	return
+}
+
+// BuildBalanceSheet mengelompokkan saldo akun menjadi aset, kewajiban dan ekuitas.
+// Saldo pendapatan dikurangi beban yang belum ditutup masuk ke ekuitas sebagai laba tahun berjalan.
+func BuildBalanceSheet(lines []model.TrialBalanceLine, types map[string]string) (sheet model.BalanceSheet) {
+	sheet.Assets = []model.StatementLine{}
+	sheet.Liabilities = []model.StatementLine{}
+	sheet.Equity = []model.StatementLine{}
+	var currentEarnings float64
+	for _, l := range lines {
+		accType, ok := types[l.AccountCode]
+		if !ok {
+			accType = ledger.TypeFromCode(l.AccountCode)
+		}
+		debitBalance := l.Debit - l.Credit
+		item := model.StatementLine{AccountCode: l.AccountCode, AccountName: l.AccountName}
+		switch accType {
+		case ledger.TypeAsset:
+			item.Amount = ledger.Round2(debitBalance)
+			sheet.Assets = append(sheet.Assets, item)
+			sheet.TotalAssets += item.Amount
+		case ledger.TypeLiability:
+			item.Amount = ledger.Round2(-debitBalance)
+			sheet.Liabilities = append(sheet.Liabilities, item)
+			sheet.TotalLiabilities += item.Amount
+		case ledger.TypeEquity:
+			item.Amount = ledger.Round2(-debitBalance)
+			sheet.Equity = append(sheet.Equity, item)
+			sheet.TotalEquity += item.Amount
+		default:
+			currentEarnings -= debitBalance
+		}
+	}
+	if ledger.Round2(currentEarnings) != 0 {
+		item := model.StatementLine{AccountName: "Laba Tahun Berjalan", Amount: ledger.Round2(currentEarnings)}
+		sheet.Equity = append(sheet.Equity, item)
+		sheet.TotalEquity += item.Amount
+	}
+	sheet.TotalAssets = ledger.Round2(sheet.TotalAssets)
+	sheet.TotalLiabilities = ledger.Round2(sheet.TotalLiabilities)
+	sheet.TotalEquity = ledger.Round2(sheet.TotalEquity)
+	sheet.Balanced = sheet.TotalAssets == ledger.Round2(sheet.TotalLiabilities+sheet.TotalEquity)
+	return
+}
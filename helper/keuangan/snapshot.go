@@ -0,0 +1,61 @@
+package keuangan
+
+import (
+	"errors"
+
+	"github.com/gocroot/helper/ledger"
+	"github.com/gocroot/model"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// BuildSnapshot menghitung neraca atau arus kas sesuai jenis snapshot
+func BuildSnapshot(db *mongo.Database, owner, snapType, startDate, endDate string) (snap model.FinancialStatementSnapshot, err error) {
+	snap.Owner = owner
+	snap.Type = snapType
+	snap.EndDate = endDate
+	switch snapType {
+	case SnapshotBalanceSheet:
+		var sheet model.BalanceSheet
+		sheet, err = BalanceSheetAsOf(db, owner, endDate)
+		snap.BalanceSheet = &sheet
+	case SnapshotCashFlow:
+		var cf model.CashFlowStatement
+		cf, err = CashFlow(db, owner, startDate, endDate)
+		snap.StartDate = startDate
+		snap.CashFlow = &cf
+	default:
+		err = errors.New("jenis snapshot harus balance_sheet atau cash_flow")
+	}
+	return
+}
+
+// SnapshotTotals mengambil angka-angka utama dari snapshot untuk dibandingkan
+func SnapshotTotals(snap model.FinancialStatementSnapshot) map[string]float64 {
+	totals := map[string]float64{}
+	if snap.BalanceSheet != nil {
+		totals["totalAssets"] = snap.BalanceSheet.TotalAssets
+		totals["totalLiabilities"] = snap.BalanceSheet.TotalLiabilities
+		totals["totalEquity"] = snap.BalanceSheet.TotalEquity
+	}
+	if snap.CashFlow != nil {
+		totals["openingCash"] = snap.CashFlow.OpeningCash
+		totals["operating"] = snap.CashFlow.Operating.Net
+		totals["investing"] = snap.CashFlow.Investing.Net
+		totals["financing"] = snap.CashFlow.Financing.Net
+		totals["netChange"] = snap.CashFlow.NetChange
+		totals["closingCash"] = snap.CashFlow.ClosingCash
+	}
+	return totals
+}
+
+// CompareSnapshot menghitung selisih angka utama antara snapshot tersimpan dan hasil hitung ulang
+func CompareSnapshot(saved, current model.FinancialStatementSnapshot) (diff map[string]float64) {
+	diff = map[string]float64{}
+	now := SnapshotTotals(current)
+	for key, before := range SnapshotTotals(saved) {
+		if d := ledger.Round2(now[key] - before); d != 0 {
+			diff[key] = d
+		}
+	}
+	return
+}
@@ -0,0 +1,29 @@
+package keuangan
+
+import "github.com/gocroot/helper/repo"
+
+// Nama koleksi transaksi yang menjadi sumber laporan keuangan
+const (
+	SalesCollection    = repo.SalesCollection
+	ReturnCollection   = repo.ReturnCollection
+	ExpenseCollection  = repo.ExpenseCollection
+	ReportCollection   = repo.ReportCollection
+	SnapshotCollection = repo.SnapshotCollection
+)
+
+// Jenis snapshot laporan keuangan
+const (
+	SnapshotBalanceSheet = "balance_sheet"
+	SnapshotCashFlow     = "cash_flow"
+)
+
+type salesSummary struct {
+	Revenue float64 `bson:"revenue"`
+	COGS    float64 `bson:"cogs"`
+	Count   int     `bson:"count"`
+}
+
+type returnSummary struct {
+	Revenue float64 `bson:"revenue"` // Nilai retur tanpa PPN
+	Cost    float64 `bson:"cost"`    // Harga pokok barang yang kembali ke stok
+}
@@ -2,8 +2,7 @@ package kimseok
 
 import (
 	"context"
-	"fmt"
-	"log"
+	"log/slog"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
@@ -15,7 +14,8 @@ func StammingQuestioninDB(mongostring, db string) {
 	clientOptions := options.Client().ApplyURI(mongostring)
 	client, err := mongo.Connect(context.TODO(), clientOptions)
 	if err != nil {
-		log.Fatal(err)
+		slog.Error("stemming qna gagal terhubung ke mongo", "error", err)
+		return
 	}
 	defer client.Disconnect(context.TODO())
 
@@ -24,7 +24,8 @@ func StammingQuestioninDB(mongostring, db string) {
 	// Find all documents in the collection
 	cursor, err := collection.Find(context.TODO(), bson.D{})
 	if err != nil {
-		log.Fatal(err)
+		slog.Error("stemming qna gagal membaca qna", "error", err)
+		return
 	}
 	defer cursor.Close(context.TODO())
 
@@ -32,7 +33,8 @@ func StammingQuestioninDB(mongostring, db string) {
 	for cursor.Next(context.TODO()) {
 		var doc bson.M
 		if err := cursor.Decode(&doc); err != nil {
-			log.Fatal(err)
+			slog.Error("stemming qna gagal membaca dokumen", "error", err)
+			return
 		}
 
 		// Get the origin question field
@@ -64,15 +66,17 @@ func StammingQuestioninDB(mongostring, db string) {
 
 		_, err := collection.UpdateOne(context.TODO(), filter, update)
 		if err != nil {
-			log.Fatal(err)
+			slog.Error("stemming qna gagal menyimpan pertanyaan", "id", doc["_id"], "error", err)
+			return
 		}
 
-		fmt.Printf("Updated question to: %s\n", stemmedQuestion)
+		slog.Debug("pertanyaan qna distemming", "id", doc["_id"], "question", stemmedQuestion)
 	}
 
 	if err := cursor.Err(); err != nil {
-		log.Fatal(err)
+		slog.Error("stemming qna berhenti di tengah", "error", err)
+		return
 	}
 
-	fmt.Println("Documents updated successfully")
+	slog.Info("stemming qna selesai")
 }
@@ -3,7 +3,7 @@ package kimseok
 import (
 	"context"
 	"errors"
-	"fmt"
+	"log/slog"
 	"os"
 	"regexp"
 	"strings"
@@ -39,7 +39,8 @@ func InsertUserdata(MongoConn *mongo.Database, username, email, password, passwo
 func InsertOneDoc(db *mongo.Database, collection string, doc interface{}) (insertedID interface{}) {
 	insertResult, err := db.Collection(collection).InsertOne(context.TODO(), doc)
 	if err != nil {
-		fmt.Printf("InsertOneDoc: %v\n", err)
+		slog.Error("kimseok gagal menyimpan dokumen", "collection", collection, "error", err)
+		return nil
 	}
 	return insertResult.InsertedID
 }
@@ -0,0 +1,429 @@
+package ledger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/gocroot/helper/atdb"
+	"github.com/gocroot/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Round2 membulatkan nominal ke dua angka desimal (sen)
+func Round2(v float64) float64 {
+	return math.Round(v*100) / 100
+}
+
+func AccountName(code string) string {
+	for _, acc := range DefaultAccounts {
+		if acc.Code == code {
+			return acc.Name
+		}
+	}
+	return code
+}
+
+func line(code string, debit, credit float64) model.JournalLine {
+	return model.JournalLine{
+		AccountCode: code,
+		AccountName: AccountName(code),
+		Debit:       Round2(debit),
+		Credit:      Round2(credit),
+	}
+}
+
+// CashAccount menentukan akun kas atau bank dari metode pembayaran
+func CashAccount(paymentMethod string) string {
+	switch strings.ToLower(strings.TrimSpace(paymentMethod)) {
+	case "", "tunai", "cash", "kas":
+		return AkunKas
+	default:
+		return AkunBank
+	}
+}
+
+// ExpenseAccount menentukan akun beban dari kategori pengeluaran
+func ExpenseAccount(category string) string {
+	if code, ok := expenseCategoryAccount[strings.ToLower(strings.TrimSpace(category))]; ok {
+		return code
+	}
+	return AkunBebanOperasional
+}
+
+// IsCredit bernilai true jika penjualan belum dibayar sehingga dicatat sebagai piutang
+func IsCredit(paymentStatus string) bool {
+	status := strings.ToLower(strings.TrimSpace(paymentStatus))
+	for _, s := range CreditStatuses {
+		if status == s {
+			return true
+		}
+	}
+	return false
+}
+
+// SalesEntry membuat jurnal penjualan: kas/bank/piutang pada pendapatan dan PPN keluaran, serta HPP pada persediaan jika harga pokok diketahui.
+// Penjualan yang sudah memiliki cicilan tetap dicatat sebagai piutang karena pelunasannya dijurnal terpisah.
+func SalesEntry(trx model.SalesTransaction) model.JournalEntry {
+	debitAccount := CashAccount(trx.PaymentMethod)
+	if IsCredit(trx.PaymentStatus) || len(trx.Payments) > 0 {
+		debitAccount = AkunPiutangUsaha
+	}
+	lines := []model.JournalLine{
+		line(debitAccount, trx.TotalAmount, 0),
+		line(AkunPendapatan, 0, trx.TotalAmount-trx.TaxAmount),
+	}
+	if Round2(trx.TaxAmount) > 0 {
+		lines = append(lines, line(AkunPPNKeluaran, 0, trx.TaxAmount))
+	}
+	var cogs float64
+	for _, p := range trx.Products {
+		qty := p.Quantity
+		if qty <= 0 {
+			qty = 1
+		}
+		cogs += p.Cost * float64(qty)
+	}
+	if Round2(cogs) > 0 {
+		lines = append(lines, line(AkunHPP, cogs, 0), line(AkunPersediaan, 0, cogs))
+	}
+	return model.JournalEntry{
+		Owner:       trx.Owner,
+		Date:        trx.TransactionDate,
+		Description: "Penjualan kepada " + trx.CustomerName,
+		SourceType:  SourceSales,
+		SourceID:    trx.ID,
+		Lines:       lines,
+	}
+}
+
+// ExpenseEntry membuat jurnal pengeluaran: beban dan PPN masukan pada kas/bank
+func ExpenseEntry(exp model.ExpenseTransaction) model.JournalEntry {
+	date := exp.ExpenseDate
+	if date.IsZero() {
+		date = exp.CreatedAt
+	}
+	lines := []model.JournalLine{line(ExpenseAccount(exp.Category), exp.Amount-exp.TaxAmount, 0)}
+	if Round2(exp.TaxAmount) > 0 {
+		lines = append(lines, line(AkunPPNMasukan, exp.TaxAmount, 0))
+	}
+	return model.JournalEntry{
+		Owner:       exp.Owner,
+		Date:        date,
+		Description: "Pengeluaran " + exp.ExpenseName,
+		SourceType:  SourceExpense,
+		SourceID:    exp.ID,
+		Lines:       append(lines, line(CashAccount(exp.PaymentMethod), 0, exp.Amount)),
+	}
+}
+
+// ReceiptEntry membuat jurnal penerimaan cicilan piutang: kas/bank pada piutang usaha
+func ReceiptEntry(trx model.SalesTransaction, payment model.SalesPayment) model.JournalEntry {
+	return model.JournalEntry{
+		Owner:       trx.Owner,
+		Date:        payment.PaidAt,
+		Description: "Pembayaran piutang dari " + trx.CustomerName,
+		SourceType:  SourceReceipt,
+		SourceID:    trx.ID,
+		Lines: []model.JournalLine{
+			line(CashAccount(payment.PaymentMethod), payment.Amount, 0),
+			line(AkunPiutangUsaha, 0, payment.Amount),
+		},
+	}
+}
+
+// ReturnEntry membuat jurnal retur penjualan: retur penjualan dan PPN keluaran pada kas/bank untuk pengembalian uang
+// atau pada piutang untuk nota kredit, serta persediaan pada HPP untuk barang yang kembali ke stok
+func ReturnEntry(ret model.SalesReturn, refund bool) model.JournalEntry {
+	creditAccount := AkunPiutangUsaha
+	if refund {
+		creditAccount = CashAccount(ret.PaymentMethod)
+	}
+	lines := []model.JournalLine{line(AkunReturPenjualan, ret.TotalAmount-ret.TaxAmount, 0)}
+	if Round2(ret.TaxAmount) > 0 {
+		lines = append(lines, line(AkunPPNKeluaran, ret.TaxAmount, 0))
+	}
+	lines = append(lines, line(creditAccount, 0, ret.TotalAmount))
+	if Round2(ret.Cost) > 0 {
+		lines = append(lines, line(AkunPersediaan, ret.Cost, 0), line(AkunHPP, 0, ret.Cost))
+	}
+	return model.JournalEntry{
+		Owner:       ret.Owner,
+		Date:        ret.ReturnDate,
+		Description: "Retur penjualan " + ret.InvoiceNumber + " dari " + ret.CustomerName,
+		SourceType:  SourceReturn,
+		SourceID:    ret.ID,
+		Lines:       lines,
+	}
+}
+
+// PurchaseEntry membuat jurnal penerimaan barang dari supplier: persediaan dan PPN masukan pada utang usaha
+func PurchaseEntry(po model.PurchaseOrder) model.JournalEntry {
+	lines := []model.JournalLine{line(AkunPersediaan, po.TotalAmount-po.TaxAmount, 0)}
+	if Round2(po.TaxAmount) > 0 {
+		lines = append(lines, line(AkunPPNMasukan, po.TaxAmount, 0))
+	}
+	return model.JournalEntry{
+		Owner:       po.Owner,
+		Date:        po.ReceivedAt,
+		Description: "Penerimaan barang dari " + po.SupplierName,
+		SourceType:  SourcePurchase,
+		SourceID:    po.ID,
+		Lines:       append(lines, line(AkunUtangUsaha, 0, po.TotalAmount)),
+	}
+}
+
+// PayablePaymentEntry membuat jurnal pembayaran utang usaha: utang usaha pada kas/bank
+func PayablePaymentEntry(payable model.Payable, amount float64, paymentMethod string) model.JournalEntry {
+	return model.JournalEntry{
+		Owner:       payable.Owner,
+		Date:        time.Now(),
+		Description: "Pembayaran utang kepada " + payable.SupplierName,
+		SourceType:  SourcePayable,
+		SourceID:    payable.ID,
+		Lines: []model.JournalLine{
+			line(AkunUtangUsaha, amount, 0),
+			line(CashAccount(paymentMethod), 0, amount),
+		},
+	}
+}
+
+// ClosingEntry menyusun jurnal penutup tahun: saldo akun pendapatan dan beban dinolkan dan selisihnya dipindah ke Laba Ditahan.
+// netIncome adalah laba bersih yang dipindah, entry tanpa baris berarti tidak ada saldo yang perlu ditutup.
+func ClosingEntry(owner string, date time.Time, lines []model.TrialBalanceLine, types map[string]string) (entry model.JournalEntry, netIncome float64) {
+	entry = model.JournalEntry{
+		Owner:       owner,
+		Date:        date,
+		Description: "Jurnal penutup tahun " + date.Format("2006"),
+		SourceType:  SourceClosing,
+	}
+	for _, l := range lines {
+		accType, ok := types[l.AccountCode]
+		if !ok {
+			accType = TypeFromCode(l.AccountCode)
+		}
+		if accType != TypeRevenue && accType != TypeExpense {
+			continue
+		}
+		balance := Round2(l.Debit - l.Credit)
+		if balance == 0 {
+			continue
+		}
+		closing := model.JournalLine{AccountCode: l.AccountCode, AccountName: l.AccountName}
+		if balance > 0 {
+			closing.Credit = balance
+		} else {
+			closing.Debit = -balance
+		}
+		entry.Lines = append(entry.Lines, closing)
+		netIncome -= balance
+	}
+	netIncome = Round2(netIncome)
+	switch {
+	case netIncome > 0:
+		entry.Lines = append(entry.Lines, line(AkunLabaDitahan, 0, netIncome))
+	case netIncome < 0:
+		entry.Lines = append(entry.Lines, line(AkunLabaDitahan, -netIncome, 0))
+	}
+	return
+}
+
+// Validate memastikan jurnal memiliki minimal dua baris dan total debit sama dengan total kredit
+func Validate(entry model.JournalEntry) error {
+	if len(entry.Lines) < 2 {
+		return errors.New("jurnal minimal memiliki dua baris")
+	}
+	var debit, credit float64
+	for i, l := range entry.Lines {
+		if l.AccountCode == "" {
+			return fmt.Errorf("baris %d: kode akun kosong", i+1)
+		}
+		if l.Debit < 0 || l.Credit < 0 {
+			return fmt.Errorf("baris %d: nominal tidak boleh negatif", i+1)
+		}
+		if (l.Debit == 0) == (l.Credit == 0) {
+			return fmt.Errorf("baris %d: isi salah satu dari debit atau kredit", i+1)
+		}
+		debit += l.Debit
+		credit += l.Credit
+	}
+	if Round2(debit) != Round2(credit) {
+		return fmt.Errorf("jurnal tidak seimbang: debit %.2f, kredit %.2f", debit, credit)
+	}
+	return nil
+}
+
+// Post memvalidasi lalu menyimpan jurnal ke koleksi journal_entries
+func Post(db *mongo.Database, entry model.JournalEntry) (id primitive.ObjectID, err error) {
+	if err = Validate(entry); err != nil {
+		return
+	}
+	entry.ID = primitive.NewObjectID()
+	if entry.Date.IsZero() {
+		entry.Date = time.Now()
+	}
+	entry.CreatedAt = time.Now()
+	return atdb.InsertOneDoc(db, JournalCollection, entry)
+}
+
+// Reverse membalik semua jurnal aktif milik sebuah transaksi sumber dengan jurnal pembalik
+func Reverse(db *mongo.Database, sourceType string, sourceID primitive.ObjectID) (err error) {
+	return reverse(db, sourceType, sourceID, false)
+}
+
+// ReverseSameDate membalik jurnal aktif sumber dengan jurnal pembalik bertanggal sama dengan jurnal aslinya,
+// dipakai saat transaksi diubah atau dihapus dan untuk membatalkan jurnal penutup tahun tanpa menggeser saldo ke periode lain
+func ReverseSameDate(db *mongo.Database, sourceType string, sourceID primitive.ObjectID) (err error) {
+	return reverse(db, sourceType, sourceID, true)
+}
+
+func reverse(db *mongo.Database, sourceType string, sourceID primitive.ObjectID, sameDate bool) (err error) {
+	filter := bson.M{"source_type": sourceType, "source_id": sourceID, "reversed": bson.M{"$ne": true}}
+	entries, err := atdb.GetAllDoc[[]model.JournalEntry](db, JournalCollection, filter)
+	if err != nil {
+		return
+	}
+	for _, entry := range entries {
+		date := time.Now()
+		if sameDate {
+			date = entry.Date
+		}
+		reversal := model.JournalEntry{
+			Owner:       entry.Owner,
+			Date:        date,
+			Description: "Pembalik: " + entry.Description,
+			SourceType:  SourceReversal,
+			SourceID:    sourceID,
+			ReversalOf:  &entry.ID,
+		}
+		for _, l := range entry.Lines {
+			reversal.Lines = append(reversal.Lines, model.JournalLine{
+				AccountCode: l.AccountCode,
+				AccountName: l.AccountName,
+				Debit:       l.Credit,
+				Credit:      l.Debit,
+			})
+		}
+		if _, err = Post(db, reversal); err != nil {
+			return
+		}
+		if _, err = atdb.UpdateOneDoc(db, JournalCollection, bson.M{"_id": entry.ID}, bson.M{"reversed": true}); err != nil {
+			return
+		}
+	}
+	return
+}
+
+// Repost membalik jurnal lama milik transaksi pada tanggal aslinya lalu memposting jurnal yang baru,
+// sehingga laba rugi bulan transaksi lama ikut terkoreksi dan sama dengan laporan dari transaksi
+func Repost(db *mongo.Database, entry model.JournalEntry) (err error) {
+	if err = ReverseSameDate(db, entry.SourceType, entry.SourceID); err != nil {
+		return
+	}
+	_, err = Post(db, entry)
+	return
+}
+
+// TrialBalance menghitung neraca saldo milik owner dari jurnal dengan tanggal sampai asOf (nol berarti semua)
+func TrialBalance(db *mongo.Database, owner string, asOf time.Time) (lines []model.TrialBalanceLine, err error) {
+	match := bson.M{"owner": owner}
+	if !asOf.IsZero() {
+		match["date"] = bson.M{"$lte": asOf}
+	}
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: match}},
+		{{Key: "$unwind", Value: "$lines"}},
+		{{Key: "$group", Value: bson.M{
+			"_id":          "$lines.account_code",
+			"account_name": bson.M{"$first": "$lines.account_name"},
+			"debit":        bson.M{"$sum": "$lines.debit"},
+			"credit":       bson.M{"$sum": "$lines.credit"},
+		}}},
+		{{Key: "$sort", Value: bson.M{"_id": 1}}},
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	cursor, err := db.Collection(JournalCollection).Aggregate(ctx, pipeline)
+	if err != nil {
+		return
+	}
+	defer cursor.Close(ctx)
+	if err = cursor.All(ctx, &lines); err != nil {
+		return
+	}
+	for i := range lines {
+		lines[i].Debit = Round2(lines[i].Debit)
+		lines[i].Credit = Round2(lines[i].Credit)
+		lines[i].Balance = Round2(lines[i].Debit - lines[i].Credit)
+	}
+	return
+}
+
+// EnsureDefaultAccounts menambahkan akun standar yang belum ada di bagan akun milik owner
+func EnsureDefaultAccounts(db *mongo.Database, owner string) (inserted int, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	for _, acc := range DefaultAccounts {
+		acc.Owner = owner
+		acc.CreatedAt = time.Now()
+		acc.UpdatedAt = acc.CreatedAt
+		var res *mongo.UpdateResult
+		res, err = db.Collection(AccountCollection).UpdateOne(ctx,
+			bson.M{"code": acc.Code, "owner": owner},
+			bson.M{"$setOnInsert": acc},
+			options.Update().SetUpsert(true))
+		if err != nil {
+			return
+		}
+		if res.UpsertedCount > 0 {
+			inserted++
+		}
+	}
+	return
+}
+
+// TypeFromCode menebak jenis akun dari digit pertama kode akun
+func TypeFromCode(code string) string {
+	if code == "" {
+		return ""
+	}
+	switch code[0] {
+	case '1':
+		return TypeAsset
+	case '2':
+		return TypeLiability
+	case '3':
+		return TypeEquity
+	case '4':
+		return TypeRevenue
+	default:
+		return TypeExpense
+	}
+}
+
+// AccountTypes mengembalikan peta kode akun ke jenis akun dari bagan akun standar dan chart_of_accounts milik owner
+func AccountTypes(db *mongo.Database, owner string) (types map[string]string, err error) {
+	types = make(map[string]string)
+	for _, acc := range DefaultAccounts {
+		types[acc.Code] = acc.Type
+	}
+	accounts, err := atdb.GetAllDoc[[]model.Account](db, AccountCollection, bson.M{"owner": owner})
+	if err != nil {
+		return
+	}
+	for _, acc := range accounts {
+		types[acc.Code] = acc.Type
+	}
+	return
+}
+
+// IsCashAccount bernilai true untuk akun kas dan bank
+func IsCashAccount(code string) bool {
+	return code == AkunKas || code == AkunBank
+}
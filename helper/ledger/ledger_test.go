@@ -0,0 +1,115 @@
+package ledger
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gocroot/model"
+)
+
+func TestSalesEntryBalanced(t *testing.T) {
+	trx := model.SalesTransaction{
+		CustomerName:  "Budi",
+		TotalAmount:   150000,
+		PaymentMethod: "tunai",
+		Products: []model.Product{
+			{Name: "Kopi", Price: 50000, Cost: 30000, Quantity: 2},
+			{Name: "Gula", Price: 50000, Cost: 20000},
+		},
+	}
+	entry := SalesEntry(trx)
+	if err := Validate(entry); err != nil {
+		t.Fatal(err)
+	}
+	if entry.Lines[0].AccountCode != AkunKas {
+		t.Errorf("akun debit = %s, want %s", entry.Lines[0].AccountCode, AkunKas)
+	}
+	if len(entry.Lines) != 4 || entry.Lines[2].Debit != 80000 {
+		t.Errorf("HPP tidak sesuai: %+v", entry.Lines)
+	}
+
+	trx.PaymentStatus = "belum lunas"
+	if got := SalesEntry(trx).Lines[0].AccountCode; got != AkunPiutangUsaha {
+		t.Errorf("penjualan kredit didebit ke %s, want %s", got, AkunPiutangUsaha)
+	}
+}
+
+func TestExpenseEntryAccount(t *testing.T) {
+	entry := ExpenseEntry(model.ExpenseTransaction{ExpenseName: "Sewa ruko", Amount: 2500000, Category: "Sewa", PaymentMethod: "transfer bank"})
+	if err := Validate(entry); err != nil {
+		t.Fatal(err)
+	}
+	if entry.Lines[0].AccountCode != AkunBebanSewa || entry.Lines[1].AccountCode != AkunBank {
+		t.Errorf("akun tidak sesuai: %+v", entry.Lines)
+	}
+}
+
+func TestValidateRejectsUnbalanced(t *testing.T) {
+	entry := model.JournalEntry{Lines: []model.JournalLine{
+		{AccountCode: AkunKas, Debit: 100},
+		{AccountCode: AkunPendapatan, Credit: 90},
+	}}
+	if Validate(entry) == nil {
+		t.Error("jurnal tidak seimbang seharusnya ditolak")
+	}
+}
+
+func TestSalesEntryWithTax(t *testing.T) {
+	entry := SalesEntry(model.SalesTransaction{TotalAmount: 111000, TaxAmount: 11000, PaymentMethod: "tunai"})
+	if err := Validate(entry); err != nil {
+		t.Fatal(err)
+	}
+	if entry.Lines[1].Credit != 100000 || entry.Lines[2].AccountCode != AkunPPNKeluaran || entry.Lines[2].Credit != 11000 {
+		t.Errorf("PPN keluaran tidak sesuai: %+v", entry.Lines)
+	}
+}
+
+func TestPurchaseEntryWithTax(t *testing.T) {
+	entry := PurchaseEntry(model.PurchaseOrder{TotalAmount: 111000, TaxAmount: 11000})
+	if err := Validate(entry); err != nil {
+		t.Fatal(err)
+	}
+	if entry.Lines[0].Debit != 100000 || entry.Lines[1].AccountCode != AkunPPNMasukan || entry.Lines[2].Credit != 111000 {
+		t.Errorf("PPN masukan tidak sesuai: %+v", entry.Lines)
+	}
+}
+
+func TestReturnEntry(t *testing.T) {
+	ret := model.SalesReturn{TotalAmount: 55500, TaxAmount: 5500, Cost: 20000, PaymentMethod: "transfer"}
+	entry := ReturnEntry(ret, true)
+	if err := Validate(entry); err != nil {
+		t.Fatal(err)
+	}
+	if entry.Lines[0].AccountCode != AkunReturPenjualan || entry.Lines[0].Debit != 50000 || entry.Lines[2].AccountCode != AkunBank {
+		t.Errorf("retur refund tidak sesuai: %+v", entry.Lines)
+	}
+	if entry := ReturnEntry(ret, false); entry.Lines[2].AccountCode != AkunPiutangUsaha {
+		t.Errorf("nota kredit harus mengurangi piutang: %+v", entry.Lines)
+	}
+}
+
+func TestClosingEntry(t *testing.T) {
+	lines := []model.TrialBalanceLine{
+		{AccountCode: AkunKas, Debit: 500000},
+		{AccountCode: AkunPendapatan, AccountName: "Pendapatan Penjualan", Credit: 1000000},
+		{AccountCode: AkunReturPenjualan, AccountName: "Retur Penjualan", Debit: 50000},
+		{AccountCode: AkunHPP, AccountName: "Harga Pokok Penjualan", Debit: 400000},
+		{AccountCode: AkunBebanSewa, AccountName: "Beban Sewa", Debit: 300000, Credit: 300000},
+	}
+	entry, net := ClosingEntry("62811", time.Date(2026, 12, 31, 23, 59, 59, 0, time.UTC), lines, map[string]string{})
+	if err := Validate(entry); err != nil {
+		t.Fatal(err)
+	}
+	if net != 550000 || len(entry.Lines) != 4 {
+		t.Fatalf("net = %v lines = %+v", net, entry.Lines)
+	}
+	last := entry.Lines[3]
+	if last.AccountCode != AkunLabaDitahan || last.Credit != 550000 || entry.Lines[0].Debit != 1000000 {
+		t.Errorf("jurnal penutup tidak sesuai: %+v", entry.Lines)
+	}
+
+	entry, net = ClosingEntry("62811", time.Now(), []model.TrialBalanceLine{{AccountCode: AkunBebanGaji, Debit: 200000}}, nil)
+	if net != -200000 || entry.Lines[1].AccountCode != AkunLabaDitahan || entry.Lines[1].Debit != 200000 {
+		t.Errorf("rugi harus mendebit laba ditahan: %+v", entry.Lines)
+	}
+}
@@ -0,0 +1,91 @@
+package ledger
+
+import (
+	"github.com/gocroot/helper/repo"
+	"github.com/gocroot/model"
+)
+
+// Nama koleksi untuk bagan akun dan jurnal umum
+const (
+	AccountCollection = repo.AccountCollection
+	JournalCollection = repo.JournalCollection
+)
+
+// Jenis akun
+const (
+	TypeAsset     = "asset"
+	TypeLiability = "liability"
+	TypeEquity    = "equity"
+	TypeRevenue   = "revenue"
+	TypeExpense   = "expense"
+)
+
+// Jenis sumber jurnal
+const (
+	SourceSales    = "sales"
+	SourceExpense  = "expense"
+	SourceManual   = "manual"
+	SourceReversal = "reversal"
+	SourcePurchase = "purchase"
+	SourcePayable  = "payable_payment"
+	SourceReceipt  = "sales_payment"
+	SourceOpening  = "opening"
+	SourceReturn   = "sales_return"
+	SourceClosing  = "closing"
+)
+
+// Kode akun standar yang dipakai saat posting otomatis
+const (
+	AkunKas              = "1101"
+	AkunBank             = "1102"
+	AkunPiutangUsaha     = "1201"
+	AkunPersediaan       = "1301"
+	AkunPPNMasukan       = "1401"
+	AkunUtangUsaha       = "2101"
+	AkunPPNKeluaran      = "2102"
+	AkunModalPemilik     = "3101"
+	AkunLabaDitahan      = "3201"
+	AkunPendapatan       = "4101"
+	AkunReturPenjualan   = "4102"
+	AkunHPP              = "5101"
+	AkunBebanOperasional = "6101"
+	AkunBebanGaji        = "6102"
+	AkunBebanPemasaran   = "6103"
+	AkunBebanSewa        = "6104"
+	AkunBebanUtilitas    = "6105"
+)
+
+// DefaultAccounts adalah bagan akun standar untuk toko
+var DefaultAccounts = []model.Account{
+	{Code: AkunKas, Name: "Kas", Type: TypeAsset},
+	{Code: AkunBank, Name: "Bank", Type: TypeAsset},
+	{Code: AkunPiutangUsaha, Name: "Piutang Usaha", Type: TypeAsset},
+	{Code: AkunPersediaan, Name: "Persediaan Barang", Type: TypeAsset},
+	{Code: AkunPPNMasukan, Name: "PPN Masukan", Type: TypeAsset},
+	{Code: AkunUtangUsaha, Name: "Utang Usaha", Type: TypeLiability},
+	{Code: AkunPPNKeluaran, Name: "PPN Keluaran", Type: TypeLiability},
+	{Code: AkunModalPemilik, Name: "Modal Pemilik", Type: TypeEquity},
+	{Code: AkunLabaDitahan, Name: "Laba Ditahan", Type: TypeEquity},
+	{Code: AkunPendapatan, Name: "Pendapatan Penjualan", Type: TypeRevenue},
+	{Code: AkunReturPenjualan, Name: "Retur Penjualan", Type: TypeRevenue},
+	{Code: AkunHPP, Name: "Harga Pokok Penjualan", Type: TypeExpense},
+	{Code: AkunBebanOperasional, Name: "Beban Operasional", Type: TypeExpense},
+	{Code: AkunBebanGaji, Name: "Beban Gaji", Type: TypeExpense},
+	{Code: AkunBebanPemasaran, Name: "Beban Pemasaran", Type: TypeExpense},
+	{Code: AkunBebanSewa, Name: "Beban Sewa", Type: TypeExpense},
+	{Code: AkunBebanUtilitas, Name: "Beban Utilitas", Type: TypeExpense},
+}
+
+// CreditStatuses adalah payment_status penjualan yang berarti belum dibayar (piutang)
+var CreditStatuses = []string{"unpaid", "partial", "belum lunas", "belum bayar", "pending", "bon"}
+
+// expenseCategoryAccount memetakan ExpenseTransaction.Category ke akun beban
+var expenseCategoryAccount = map[string]string{
+	"gaji":      AkunBebanGaji,
+	"marketing": AkunBebanPemasaran,
+	"pemasaran": AkunBebanPemasaran,
+	"sewa":      AkunBebanSewa,
+	"listrik":   AkunBebanUtilitas,
+	"air":       AkunBebanUtilitas,
+	"utilitas":  AkunBebanUtilitas,
+}
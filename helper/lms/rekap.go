@@ -11,12 +11,12 @@ import (
 
 func GetRekapPendaftaranUsers(db *mongo.Database) (rkp RekapitulasiUser, err error) {
 	//copy data user
-	users, err := GetAllUser(config.Mongoconn)
+	users, err := GetAllUser(config.Mongoconn())
 	if err != nil {
 		err = errors.New("GetAllUser:" + err.Error())
 		return
 	}
-	_, err = atdb.InsertManyDocs[User](config.Mongoconn, "lmsusers", users)
+	_, err = atdb.InsertManyDocs[User](config.Mongoconn(), "lmsusers", users)
 	if err != nil {
 		err = errors.New("InsertManyDocs:" + err.Error())
 		return
@@ -26,7 +26,7 @@ func GetRekapPendaftaranUsers(db *mongo.Database) (rkp RekapitulasiUser, err err
 		"profileapproved": 1,
 		"roles":           "User",
 	}
-	count1, err := atdb.GetCountDoc(config.Mongoconn, "lmsusers", filter)
+	count1, err := atdb.GetCountDoc(config.Mongoconn(), "lmsusers", filter)
 	if err != nil {
 		return
 	}
@@ -34,7 +34,7 @@ func GetRekapPendaftaranUsers(db *mongo.Database) (rkp RekapitulasiUser, err err
 		"profileapproved": 2,
 		"roles":           "User",
 	}
-	count2, err := atdb.GetCountDoc(config.Mongoconn, "lmsusers", filter)
+	count2, err := atdb.GetCountDoc(config.Mongoconn(), "lmsusers", filter)
 	if err != nil {
 		return
 	}
@@ -42,7 +42,7 @@ func GetRekapPendaftaranUsers(db *mongo.Database) (rkp RekapitulasiUser, err err
 		"profileapproved": 3,
 		"roles":           "User",
 	}
-	count3, err := atdb.GetCountDoc(config.Mongoconn, "lmsusers", filter)
+	count3, err := atdb.GetCountDoc(config.Mongoconn(), "lmsusers", filter)
 	if err != nil {
 		return
 	}
@@ -50,11 +50,11 @@ func GetRekapPendaftaranUsers(db *mongo.Database) (rkp RekapitulasiUser, err err
 		"profileapproved": 4,
 		"roles":           "User",
 	}
-	count4, err := atdb.GetCountDoc(config.Mongoconn, "lmsusers", filter)
+	count4, err := atdb.GetCountDoc(config.Mongoconn(), "lmsusers", filter)
 	if err != nil {
 		return
 	}
-	count5, err := atdb.GetCountDoc(config.Mongoconn, "lmsusers", bson.M{"roles": "User"})
+	count5, err := atdb.GetCountDoc(config.Mongoconn(), "lmsusers", bson.M{"roles": "User"})
 	if err != nil {
 		return
 	}
@@ -70,7 +70,7 @@ func GetRekapPendaftaranUsers(db *mongo.Database) (rkp RekapitulasiUser, err err
 		Total:               count5,
 	}
 	//drop collection user
-	err = atdb.DropCollection(config.Mongoconn, "lmsusers")
+	err = atdb.DropCollection(config.Mongoconn(), "lmsusers")
 	if err != nil {
 		return
 	}
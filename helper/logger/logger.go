@@ -0,0 +1,86 @@
+package logger
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+)
+
+// HeaderRequestID adalah header untuk menerima dan meneruskan ID request
+const HeaderRequestID = "X-Request-ID"
+
+var (
+	level = new(slog.LevelVar)
+	base  = New(os.Stdout)
+)
+
+func init() {
+	// Log bawaan paket log ikut tercatat sebagai JSON berlevel info
+	slog.SetDefault(base)
+}
+
+type requestIDKey struct{}
+
+// New membuat logger JSON ke w yang mengikuti level global
+func New(w io.Writer) *slog.Logger {
+	return slog.New(slog.NewJSONHandler(w, &slog.HandlerOptions{Level: level}))
+}
+
+// ParseLevel mengubah debug, info, warn atau error menjadi level slog
+func ParseLevel(s string) (slog.Level, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "debug":
+		return slog.LevelDebug, nil
+	case "", "info":
+		return slog.LevelInfo, nil
+	case "warn":
+		return slog.LevelWarn, nil
+	case "error":
+		return slog.LevelError, nil
+	}
+	return slog.LevelInfo, fmt.Errorf("level log %q tidak dikenal, gunakan debug, info, warn atau error", s)
+}
+
+// SetLevel mengatur level minimum log yang ditulis
+func SetLevel(s string) error {
+	l, err := ParseLevel(s)
+	if err != nil {
+		return err
+	}
+	level.Set(l)
+	return nil
+}
+
+// NewRequestID membuat ID acak untuk satu request
+func NewRequestID() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// WithRequestID menyimpan ID request ke context
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestID mengambil ID request dari context, kosong jika tidak ada
+func RequestID(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// FromContext mengembalikan logger yang menyertakan request_id dari context
+func FromContext(ctx context.Context) *slog.Logger {
+	if id := RequestID(ctx); id != "" {
+		return base.With("request_id", id)
+	}
+	return base
+}
@@ -0,0 +1,55 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+)
+
+func TestFromContextAddsRequestID(t *testing.T) {
+	var buf bytes.Buffer
+	old := base
+	base = New(&buf)
+	t.Cleanup(func() { base = old })
+
+	FromContext(WithRequestID(context.Background(), "abc123")).Info("halo", "phone", "62811")
+	var line map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &line); err != nil {
+		t.Fatalf("log bukan JSON: %s", buf.String())
+	}
+	if line["request_id"] != "abc123" || line["msg"] != "halo" || line["phone"] != "62811" {
+		t.Errorf("log = %v", line)
+	}
+
+	buf.Reset()
+	FromContext(context.Background()).Info("tanpa id")
+	if bytes.Contains(buf.Bytes(), []byte("request_id")) {
+		t.Errorf("request_id kosong tidak perlu ditulis: %s", buf.String())
+	}
+	if RequestID(nil) != "" {
+		t.Error("RequestID(nil) harus kosong")
+	}
+}
+
+func TestSetLevel(t *testing.T) {
+	t.Cleanup(func() { level.Set(slog.LevelInfo) })
+	var buf bytes.Buffer
+	log := New(&buf)
+
+	if err := SetLevel("warn"); err != nil {
+		t.Fatal(err)
+	}
+	log.Info("tidak ditulis")
+	log.Warn("ditulis")
+	if bytes.Contains(buf.Bytes(), []byte("tidak ditulis")) || !bytes.Contains(buf.Bytes(), []byte("ditulis")) {
+		t.Errorf("level warn = %s", buf.String())
+	}
+	if err := SetLevel("verbose"); err == nil {
+		t.Error("level tidak dikenal harus error")
+	}
+	if a, b := NewRequestID(), NewRequestID(); len(a) != 16 || a == b {
+		t.Errorf("NewRequestID = %q, %q", a, b)
+	}
+}
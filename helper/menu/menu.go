@@ -2,11 +2,12 @@ package menu
 
 import (
 	"context"
-	"fmt"
+	"log/slog"
 	"strconv"
 	"time"
 
 	"github.com/gocroot/helper/atdb"
+	"github.com/gocroot/helper/logger"
 	"github.com/gocroot/helper/tiket"
 	"github.com/whatsauth/itmodel"
 	"go.mongodb.org/mongo-driver/bson"
@@ -14,7 +15,8 @@ import (
 )
 
 // Mengecek apakah pesan adalah nomor menu, jika nomor menu maka akan mengubahnya menjadi keyword
-func MenuSessionHandler(msg *itmodel.IteungMessage, db *mongo.Database) string {
+func MenuSessionHandler(ctx context.Context, msg *itmodel.IteungMessage, db *mongo.Database) string {
+	log := logger.FromContext(ctx).With("phone", msg.Phone_number)
 	// check apakah nomor adalah admin atau user untuk menentukan startmenu
 	var startmenu string
 	if !tiket.IsAdmin(msg.Phone_number, db) {
@@ -22,53 +24,45 @@ func MenuSessionHandler(msg *itmodel.IteungMessage, db *mongo.Database) string {
 	} else {
 		startmenu = "adminmenu"
 	}
-	fmt.Println("Startmenu determined:", startmenu) // Debugging startmenu
+	log.Debug("menu awal ditentukan", "startmenu", startmenu)
 
 	// check apakah ada session, klo ga ada insert session baru
 	Sesdoc, ses, err := CheckSession(msg.Phone_number, db)
 	if err != nil {
-		fmt.Println("Error checking session:", err) // Debug error check session
+		log.Warn("gagal memeriksa session menu", "error", err)
 		return err.Error()
 	}
-	fmt.Println("Session exists:", ses, "Session document:", Sesdoc) // Debug session state and document
-	fmt.Println("Session Menu List:", Sesdoc.Menulist)
+	log.Debug("session menu", "ada", ses, "menulist", Sesdoc.Menulist)
 
 	if !ses { // jika tidak ada session atau session=false maka return menu utama user dan update session isi list nomor menunya
-		reply, err := GetMenuFromKeywordAndSetSession(startmenu, Sesdoc, db)
+		reply, err := GetMenuFromKeywordAndSetSession(ctx, startmenu, Sesdoc, db)
 		if err != nil {
-			fmt.Println("Error getting menu from keyword:", err) // Debug error in menu retrieval
+			log.Warn("gagal mengambil menu awal", "keyword", startmenu, "error", err)
 			return err.Error()
 		}
-		fmt.Println("Reply on new session:", reply) // Debug reply from the new session setup
+		log.Debug("balasan session baru", "reply", reply)
 		return reply
 	}
 
-	// jika ada session maka cek menu
-	fmt.Println("Existing session found, checking menu") // Debug existing session found
-
-	// check apakah pesan integer
+	// jika ada session maka cek menu, apakah pesan integer
 	menuno, err := strconv.Atoi(msg.Message)
 	if err == nil { // kalo pesan adalah nomor
-		fmt.Println("Message is a valid number:", menuno) // Debug if message is a valid number
-		for _, menu := range Sesdoc.Menulist {            // looping di menu list dari session
-			fmt.Println("Checking menu number:", menu.No, "with keyword:", menu.Keyword) // Debug each menu item in session
-
+		log.Debug("pesan berupa nomor menu", "nomor", menuno)
+		for _, menu := range Sesdoc.Menulist { // looping di menu list dari session
 			if menuno == menu.No { // jika nomor menu sama dengan nomor yang ada di pesan
-				fmt.Println("Menu number matches:", menuno) // Debug when menu number matches
-				reply, err := GetMenuFromKeywordAndSetSession(menu.Keyword, Sesdoc, db)
+				reply, err := GetMenuFromKeywordAndSetSession(ctx, menu.Keyword, Sesdoc, db)
 				if err != nil {
-					fmt.Println("Error getting menu for keyword:", menu.Keyword) // Debug error for keyword menu retrieval
+					// Keyword tanpa menu diteruskan ke modul atau jawaban bot
+					log.Debug("nomor menu tanpa submenu, diteruskan sebagai keyword", "keyword", menu.Keyword, "error", err)
 					msg.Message = menu.Keyword
 					return ""
 				}
-				fmt.Println("Reply for matching menu:", reply) // Debug reply after matching menu
 				return reply
 			}
 		}
-		fmt.Println("Menu number not found:", menuno) // Debug if menu number not found
+		log.Info("nomor menu tidak ada di session", "nomor", menuno)
 		return "Mohon maaf nomor menu yang anda masukkan tidak ada di daftar menu"
 	}
-	fmt.Println("Message is not a valid number:", msg.Message) // Debug if message is not a number
 	// kalo pesan bukan nomor return kosong
 	return ""
 }
@@ -98,22 +92,21 @@ func CheckSession(phonenumber string, db *mongo.Database) (session Session, resu
 	return
 }
 
-func GetMenuFromKeywordAndSetSession(keyword string, session Session, db *mongo.Database) (msg string, err error) {
+func GetMenuFromKeywordAndSetSession(ctx context.Context, keyword string, session Session, db *mongo.Database) (msg string, err error) {
+	log := logger.FromContext(ctx).With("phone", session.PhoneNumber, "keyword", keyword)
 	// Ambil dokumen menu berdasarkan keyword
 	dt, err := atdb.GetOneDoc[Menu](db, "menu", bson.M{"keyword": keyword})
 	if err != nil {
-		fmt.Println("Error fetching menu from DB with keyword:", keyword, "Error:", err)
+		log.Debug("menu tidak ditemukan", "error", err)
 		return "", err
 	}
-	fmt.Println("Menu data fetched:", dt) // Debug untuk melihat data menu yang diambil
 
 	// Update session dengan list menu dari data yang diambil
-	result, err := atdb.UpdateOneDoc(db, "session", bson.M{"phonenumber": session.PhoneNumber}, bson.M{"list": dt.List})
+	_, err = atdb.UpdateOneDoc(db, "session", bson.M{"phonenumber": session.PhoneNumber}, bson.M{"list": dt.List})
 	if err != nil {
-		fmt.Println("Error updating session with menu list:", dt.List, "Error:", err)
+		log.Warn("gagal menyimpan daftar menu ke session", "error", err)
 		return "", err
 	}
-	fmt.Println("Session updated with menu list:", dt.List, "Result:", result) // Debug untuk memastikan session diupdate
 
 	// Bangun pesan yang akan dikirim ke pengguna
 	msg = dt.Header + "\n"
@@ -121,16 +114,15 @@ func GetMenuFromKeywordAndSetSession(keyword string, session Session, db *mongo.
 		msg += strconv.Itoa(item.No) + ". " + item.Konten + "\n"
 	}
 	msg += dt.Footer
-	fmt.Println("Generated message:", msg) // Debug pesan yang dihasilkan
+	log.Debug("menu dikirim", "jumlah", len(dt.List))
 	return
 }
 
 func InjectSessionMenu(menulist []MenuList, phonenumber string, db *mongo.Database) error {
-	result, err := atdb.UpdateOneDoc(db, "session", bson.M{"phonenumber": phonenumber}, bson.M{"list": menulist})
+	_, err := atdb.UpdateOneDoc(db, "session", bson.M{"phonenumber": phonenumber}, bson.M{"list": menulist})
 	if err != nil {
-		fmt.Println("Error injecting session menu list:", menulist, "Error:", err)
+		slog.Warn("gagal menyisipkan daftar menu ke session", "phone", phonenumber, "error", err)
 		return err
 	}
-	fmt.Println("Session menu list injected successfully for phone number:", phonenumber, "Result:", result)
 	return nil
 }
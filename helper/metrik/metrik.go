@@ -0,0 +1,95 @@
+package metrik
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/gocroot/helper/logger"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.mongodb.org/mongo-driver/event"
+)
+
+// Registry menampung semua metrik aplikasi yang ditampilkan di /metrics
+var Registry = prometheus.NewRegistry()
+
+var (
+	requestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "http_request_duration_seconds",
+		Help:    "Lama request HTTP per route.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+	requestErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "http_request_errors_total",
+		Help: "Jumlah respon HTTP dengan status 4xx atau 5xx per route.",
+	}, []string{"method", "route", "status"})
+	outboundPosts = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "atapi_post_requests_total",
+		Help: "Jumlah POST ke API luar lewat atapi, termasuk pengiriman pesan WhatsApp ke host api.wa.my.id.",
+	}, []string{"host", "path", "result"})
+	outboundDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "atapi_post_duration_seconds",
+		Help:    "Lama POST ke API luar lewat atapi.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"host", "path"})
+	mongoDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "mongo_command_duration_seconds",
+		Help:    "Lama perintah MongoDB per jenis perintah.",
+		Buckets: []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5},
+	}, []string{"command", "result"})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		requestDuration, requestErrors, outboundPosts, outboundDuration, mongoDuration,
+	)
+}
+
+// Handler menampilkan metrik dalam format teks Prometheus
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{})
+}
+
+// ObserveRequest mencatat lama request dan menghitung respon error per pola route
+func ObserveRequest(method, route string, status int, d time.Duration) {
+	code := strconv.Itoa(status)
+	requestDuration.WithLabelValues(method, route, code).Observe(d.Seconds())
+	if status >= 400 {
+		requestErrors.WithLabelValues(method, route, code).Inc()
+	}
+}
+
+// ObservePost mencatat hasil POST atapi. Gagal jika ada error atau status bukan 2xx.
+// Label hanya memakai host dan path agar token atau query tidak ikut tersimpan.
+func ObservePost(target string, status int, err error, d time.Duration) {
+	host, path := target, ""
+	if u, perr := url.Parse(target); perr == nil {
+		host, path = u.Host, u.Path
+	}
+	result := ResultSuccess
+	if err != nil || status < 200 || status > 299 {
+		result = ResultFailure
+	}
+	outboundPosts.WithLabelValues(host, path, result).Inc()
+	outboundDuration.WithLabelValues(host, path).Observe(d.Seconds())
+}
+
+// MongoMonitor mencatat lama setiap perintah MongoDB dan menulis log debug beserta request_id dari context operasi
+func MongoMonitor() *event.CommandMonitor {
+	return &event.CommandMonitor{
+		Succeeded: func(ctx context.Context, e *event.CommandSucceededEvent) {
+			mongoDuration.WithLabelValues(e.CommandName, ResultSuccess).Observe(e.Duration.Seconds())
+			logger.FromContext(ctx).Debug("mongo", "command", e.CommandName, "duration", e.Duration)
+		},
+		Failed: func(ctx context.Context, e *event.CommandFailedEvent) {
+			mongoDuration.WithLabelValues(e.CommandName, ResultFailure).Observe(e.Duration.Seconds())
+			logger.FromContext(ctx).Warn("mongo gagal", "command", e.CommandName, "duration", e.Duration, "error", e.Failure)
+		},
+	}
+}
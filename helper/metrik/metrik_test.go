@@ -0,0 +1,45 @@
+package metrik
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestObserveRequest(t *testing.T) {
+	ObserveRequest("GET", "/sales/{id:objectid}", http.StatusOK, 20*time.Millisecond)
+	ObserveRequest("GET", "/sales/{id:objectid}", http.StatusNotFound, time.Millisecond)
+
+	if n := testutil.ToFloat64(requestErrors.WithLabelValues("GET", "/sales/{id:objectid}", "404")); n != 1 {
+		t.Errorf("error 404 = %v", n)
+	}
+	if n := testutil.CollectAndCount(requestErrors); n != 1 {
+		t.Errorf("status 200 tidak boleh dihitung error, seri = %d", n)
+	}
+}
+
+func TestObservePost(t *testing.T) {
+	ObservePost("https://api.wa.my.id/api/v2/send/message/text?token=rahasia", http.StatusOK, nil, time.Millisecond)
+	ObservePost("https://api.wa.my.id/api/v2/send/message/text", http.StatusBadGateway, nil, time.Millisecond)
+	ObservePost("https://api.wa.my.id/api/v2/send/message/text", 0, errors.New("timeout"), time.Millisecond)
+
+	path := "/api/v2/send/message/text"
+	if n := testutil.ToFloat64(outboundPosts.WithLabelValues("api.wa.my.id", path, ResultSuccess)); n != 1 {
+		t.Errorf("sukses = %v", n)
+	}
+	if n := testutil.ToFloat64(outboundPosts.WithLabelValues("api.wa.my.id", path, ResultFailure)); n != 2 {
+		t.Errorf("gagal = %v", n)
+	}
+
+	rec := httptest.NewRecorder()
+	Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	body := rec.Body.String()
+	if strings.Contains(body, "rahasia") || !strings.Contains(body, "atapi_post_requests_total") || !strings.Contains(body, "go_goroutines") {
+		t.Errorf("isi /metrics tidak sesuai")
+	}
+}
@@ -0,0 +1,7 @@
+package metrik
+
+// Nilai label result
+const (
+	ResultSuccess = "success"
+	ResultFailure = "failure"
+)
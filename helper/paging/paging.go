@@ -0,0 +1,219 @@
+package paging
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Batas jumlah data per halaman
+const (
+	DefaultLimit = 50
+	MaxLimit     = 500
+	MaxPage      = math.MaxInt64 / MaxLimit // Halaman terbesar yang skip-nya tidak melewati int64
+)
+
+// Options menjelaskan field yang bisa dicari, difilter dan diurutkan pada satu koleksi
+type Options struct {
+	SearchFields []string          // Field yang dicari dengan ?q= (case-insensitive)
+	DateField    string            // Field tanggal untuk ?from= dan ?to= (YYYY-MM-DD)
+	Filters      map[string]string // Parameter query ke field untuk filter nilai persis, misalnya category
+	SortFields   []string          // Field yang boleh dipakai di ?sort=, awali dengan - untuk menurun
+}
+
+// Query adalah hasil parsing parameter ?page=&limit=&sort=&q=&cursor=&count=
+type Query struct {
+	Filter    bson.M
+	Sort      bson.D
+	Page      int64
+	Limit     int64
+	Cursor    primitive.ObjectID // Jika diisi, data diambil setelah _id ini (urut _id menurun)
+	SkipCount bool               // Total tidak dihitung, default untuk cursor kecuali ?count=1
+}
+
+// Meta adalah informasi halaman yang dikirim di header respon
+type Meta struct {
+	Page       int64
+	Limit      int64
+	Total      int64
+	TotalPages int64
+	Counted    bool // false jika Total dan TotalPages tidak dihitung
+	NextCursor string
+}
+
+// Parse membaca parameter query list endpoint dan menggabungkannya dengan filter dasar (misalnya owner)
+func Parse(values url.Values, base bson.M, opt Options) (q Query, err error) {
+	q.Filter = bson.M{}
+	for k, v := range base {
+		q.Filter[k] = v
+	}
+	if q.Page, err = positive(values.Get("page"), 1); err != nil {
+		return q, errors.New("page harus angka positif")
+	}
+	if q.Page > MaxPage {
+		return q, errors.New("page terlalu besar")
+	}
+	if q.Limit, err = positive(values.Get("limit"), DefaultLimit); err != nil {
+		return q, errors.New("limit harus angka positif")
+	}
+	if q.Limit > MaxLimit {
+		q.Limit = MaxLimit
+	}
+
+	for param, field := range opt.Filters {
+		if v := values.Get(param); v != "" {
+			q.Filter[field] = v
+		}
+	}
+	if s := strings.TrimSpace(values.Get("q")); s != "" && len(opt.SearchFields) > 0 {
+		pattern := primitive.Regex{Pattern: regexp.QuoteMeta(s), Options: "i"}
+		or := make([]bson.M, 0, len(opt.SearchFields))
+		for _, f := range opt.SearchFields {
+			or = append(or, bson.M{f: pattern})
+		}
+		q.Filter["$or"] = or
+	}
+	if opt.DateField != "" {
+		rng := bson.M{}
+		if s := values.Get("from"); s != "" {
+			from, perr := time.Parse("2006-01-02", s)
+			if perr != nil {
+				return q, errors.New("format from harus YYYY-MM-DD")
+			}
+			rng["$gte"] = from
+		}
+		if s := values.Get("to"); s != "" {
+			to, perr := time.Parse("2006-01-02", s)
+			if perr != nil {
+				return q, errors.New("format to harus YYYY-MM-DD")
+			}
+			rng["$lt"] = to.AddDate(0, 0, 1)
+		}
+		if len(rng) > 0 {
+			q.Filter[opt.DateField] = rng
+		}
+	}
+
+	if c := values.Get("cursor"); c != "" {
+		if q.Cursor, err = primitive.ObjectIDFromHex(c); err != nil {
+			return q, errors.New("cursor tidak valid")
+		}
+		q.Sort = bson.D{{Key: "_id", Value: -1}}
+		// Cursor dipakai untuk koleksi besar, hitung total penuh hanya jika diminta
+		q.SkipCount = values.Get("count") != "1"
+		return
+	}
+	q.Sort, err = parseSort(values.Get("sort"), opt.SortFields)
+	return
+}
+
+// parseSort mengubah ?sort=field atau ?sort=-field menjadi urutan mongo, selalu diakhiri _id agar stabil
+func parseSort(s string, allowed []string) (sort bson.D, err error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return bson.D{{Key: "_id", Value: -1}}, nil
+	}
+	dir := 1
+	if strings.HasPrefix(s, "-") {
+		dir = -1
+		s = s[1:]
+	}
+	if s == "_id" {
+		return bson.D{{Key: "_id", Value: dir}}, nil
+	}
+	for _, f := range allowed {
+		if f == s {
+			return bson.D{{Key: s, Value: dir}, {Key: "_id", Value: dir}}, nil
+		}
+	}
+	return nil, errors.New("sort tidak didukung: " + s)
+}
+
+// ByID memeriksa apakah data diurutkan hanya berdasarkan _id menurun sehingga cursor bisa dipakai
+func (q Query) ByID() bool {
+	return len(q.Sort) == 1 && q.Sort[0].Key == "_id" && q.Sort[0].Value == -1
+}
+
+// Find mengambil satu halaman data beserta total jumlah data yang cocok dengan filter, kecuali q.SkipCount
+func Find[T any](db *mongo.Database, collection string, q Query) (data []T, meta Meta, err error) {
+	return FindContext[T](context.TODO(), db, collection, q)
+}
+
+// FindContext sama dengan Find tetapi memakai context dari pemanggil, misalnya context request
+func FindContext[T any](ctx context.Context, db *mongo.Database, collection string, q Query) (data []T, meta Meta, err error) {
+	coll := db.Collection(collection)
+	meta = Meta{Page: q.Page, Limit: q.Limit}
+	if !q.SkipCount {
+		if meta.Total, err = coll.CountDocuments(ctx, q.Filter); err != nil {
+			return
+		}
+		meta.TotalPages = (meta.Total + q.Limit - 1) / q.Limit
+		meta.Counted = true
+	}
+
+	filter := q.Filter
+	opts := options.Find().SetSort(q.Sort).SetLimit(q.Limit)
+	if q.Cursor.IsZero() {
+		opts.SetSkip((q.Page - 1) * q.Limit)
+	} else {
+		filter = bson.M{"$and": []bson.M{q.Filter, {"_id": bson.M{"$lt": q.Cursor}}}}
+	}
+	cur, err := coll.Find(ctx, filter, opts)
+	if err != nil {
+		return
+	}
+	defer cur.Close(ctx)
+	if err = cur.All(ctx, &data); err != nil {
+		return
+	}
+	if q.ByID() && int64(len(data)) == q.Limit {
+		var last struct {
+			ID primitive.ObjectID `bson:"_id"`
+		}
+		var raw []byte
+		if raw, err = bson.Marshal(data[len(data)-1]); err != nil {
+			return
+		}
+		if err = bson.Unmarshal(raw, &last); err != nil {
+			return
+		}
+		meta.NextCursor = last.ID.Hex()
+	}
+	return
+}
+
+// WriteHeaders menulis informasi halaman ke header respon agar body list tetap berupa array.
+// X-Total-Count dan X-Total-Pages hanya ditulis jika total dihitung.
+func WriteHeaders(w http.ResponseWriter, meta Meta) {
+	if meta.Counted {
+		w.Header().Set("X-Total-Count", strconv.FormatInt(meta.Total, 10))
+		w.Header().Set("X-Total-Pages", strconv.FormatInt(meta.TotalPages, 10))
+	}
+	w.Header().Set("X-Page", strconv.FormatInt(meta.Page, 10))
+	w.Header().Set("X-Limit", strconv.FormatInt(meta.Limit, 10))
+	if meta.NextCursor != "" {
+		w.Header().Set("X-Next-Cursor", meta.NextCursor)
+	}
+}
+
+func positive(s string, def int64) (int64, error) {
+	if s == "" {
+		return def, nil
+	}
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || n < 1 {
+		return 0, errors.New("bukan angka positif")
+	}
+	return n, nil
+}
@@ -0,0 +1,100 @@
+package paging
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var testOptions = Options{
+	SearchFields: []string{"customer_name", "invoice_number"},
+	DateField:    "transactionDate",
+	Filters:      map[string]string{"payment_method": "payment_method"},
+	SortFields:   []string{"transactionDate", "total_amount"},
+}
+
+func TestParseDefaults(t *testing.T) {
+	q, err := Parse(url.Values{}, bson.M{"owner": "62811"}, testOptions)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if q.Page != 1 || q.Limit != DefaultLimit {
+		t.Errorf("page/limit = %d/%d", q.Page, q.Limit)
+	}
+	if q.Filter["owner"] != "62811" || len(q.Filter) != 1 {
+		t.Errorf("filter = %v", q.Filter)
+	}
+	if !q.ByID() {
+		t.Errorf("sort default harus _id menurun, dapat %v", q.Sort)
+	}
+}
+
+func TestParseFilters(t *testing.T) {
+	v := url.Values{}
+	v.Set("page", "3")
+	v.Set("limit", "1000")
+	v.Set("q", "a.b")
+	v.Set("from", "2024-01-01")
+	v.Set("to", "2024-01-31")
+	v.Set("payment_method", "cash")
+	v.Set("sort", "-total_amount")
+	base := bson.M{"owner": "62811"}
+	q, err := Parse(v, base, testOptions)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if q.Page != 3 || q.Limit != MaxLimit {
+		t.Errorf("page/limit = %d/%d", q.Page, q.Limit)
+	}
+	if q.Filter["payment_method"] != "cash" {
+		t.Errorf("payment_method = %v", q.Filter["payment_method"])
+	}
+	or := q.Filter["$or"].([]bson.M)
+	if len(or) != 2 || or[0]["customer_name"].(primitive.Regex).Pattern != `a\.b` {
+		t.Errorf("$or = %v", or)
+	}
+	rng := q.Filter["transactionDate"].(bson.M)
+	if !rng["$lt"].(time.Time).Equal(time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("to harus inklusif, dapat %v", rng["$lt"])
+	}
+	if q.Sort[0].Key != "total_amount" || q.Sort[0].Value != -1 || q.ByID() {
+		t.Errorf("sort = %v", q.Sort)
+	}
+	if len(base) != 1 {
+		t.Error("filter dasar tidak boleh diubah")
+	}
+}
+
+func TestParseCursor(t *testing.T) {
+	id := primitive.NewObjectID()
+	v := url.Values{}
+	v.Set("cursor", id.Hex())
+	v.Set("sort", "total_amount")
+	q, err := Parse(v, nil, testOptions)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if q.Cursor != id || !q.ByID() || !q.SkipCount {
+		t.Errorf("cursor mode harus urut _id tanpa hitung total, dapat %v %v", q.Sort, q.SkipCount)
+	}
+	v.Set("count", "1")
+	if q, _ = Parse(v, nil, testOptions); q.SkipCount {
+		t.Error("?count=1 harus menghitung total")
+	}
+	if q, _ = Parse(url.Values{}, nil, testOptions); q.SkipCount {
+		t.Error("halaman biasa harus menghitung total")
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	for _, kv := range [][2]string{{"page", "0"}, {"page", "9223372036854775807"}, {"limit", "x"}, {"from", "01-01-2024"}, {"sort", "password"}, {"cursor", "abc"}} {
+		v := url.Values{}
+		v.Set(kv[0], kv[1])
+		if _, err := Parse(v, nil, testOptions); err == nil {
+			t.Errorf("%s=%s harus error", kv[0], kv[1])
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package pajak
+
+import (
+	"encoding/csv"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/gocroot/helper/ledger"
+	"github.com/gocroot/model"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// fakturItem adalah satu baris OF (objek faktur)
+type fakturItem struct {
+	name     string
+	qty      int
+	base     float64
+	discount float64
+	tax      float64
+}
+
+// WriteEFaktur menulis CSV impor e-Faktur keluaran dari penjualan ber-PPN.
+// Nomor faktur dikosongkan karena NSFP diberikan DJP, kolom referensi diisi nomor invoice toko.
+func WriteEFaktur(w io.Writer, sales []model.SalesTransaction, buyers map[primitive.ObjectID]Buyer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.WriteAll(EFakturRows(sales, buyers)); err != nil {
+		return err
+	}
+	return cw.Error()
+}
+
+// EFakturRows menyusun baris FK dan OF per penjualan. Nominal dibulatkan ke bawah ke rupiah penuh
+// dan jumlah DPP/PPN pada FK adalah total baris OF agar lolos validasi e-Faktur.
+func EFakturRows(sales []model.SalesTransaction, buyers map[primitive.ObjectID]Buyer) [][]string {
+	rows := append([][]string{}, efakturHeader...)
+	for _, trx := range sales {
+		items := fakturItems(trx)
+		if trx.TaxAmount <= 0 || len(items) == 0 {
+			continue
+		}
+		var dpp, ppn float64
+		var objects [][]string
+		for _, it := range items {
+			dpp += it.base
+			ppn += it.tax
+			objects = append(objects, []string{
+				"OF", "", it.name, amount(ledger.Round2((it.base + it.discount) / float64(it.qty))), strconv.Itoa(it.qty),
+				amount(it.base + it.discount), amount(it.discount), amount(it.base), amount(it.tax), "0", "0",
+			})
+		}
+
+		buyer := buyers[trx.CustomerID]
+		npwp := digits(buyer.NPWP)
+		if npwp == "" {
+			npwp = NPWPKosong
+		}
+		name := buyer.Name
+		if name == "" {
+			name = trx.CustomerName
+		}
+		address := strings.TrimSpace(buyer.Address)
+		if address == "" {
+			address = "-"
+		}
+		date := trx.TransactionDate
+		rows = append(rows, []string{
+			"FK", "01", "0", "", strconv.Itoa(int(date.Month())), strconv.Itoa(date.Year()), date.Format("02/01/2006"),
+			npwp, name, address, amount(dpp), amount(ppn), "0", "", "0", "0", "0", "0", trx.InvoiceNumber, "",
+		})
+		rows = append(rows, objects...)
+	}
+	return rows
+}
+
+// fakturItems menghitung DPP dan PPN per item kena pajak. Item bertarif nol tidak masuk faktur kode 01.
+func fakturItems(trx model.SalesTransaction) (items []fakturItem) {
+	if len(trx.Products) == 0 {
+		for _, tl := range trx.TaxLines {
+			if tl.Rate > 0 {
+				base := math.Floor(tl.Base)
+				items = append(items, fakturItem{name: "Penjualan " + trx.InvoiceNumber, qty: 1, base: base, tax: math.Floor(base * tl.Rate / 100)})
+			}
+		}
+		return
+	}
+	for _, p := range trx.Products {
+		if p.TaxRate == nil || *p.TaxRate <= 0 {
+			continue
+		}
+		qty := quantity(p)
+		gross, _ := Split(p.Price*float64(qty), *p.TaxRate, trx.PriceIncludesTax)
+		base, _ := Split(p.Price*float64(qty)-p.Discount, *p.TaxRate, trx.PriceIncludesTax)
+		base = math.Floor(base)
+		items = append(items, fakturItem{name: p.Name, qty: qty, base: base, discount: math.Max(math.Floor(gross)-base, 0), tax: math.Floor(base * *p.TaxRate / 100)})
+	}
+	return
+}
+
+func amount(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+func digits(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if r >= '0' && r <= '9' {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
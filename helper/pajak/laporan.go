@@ -0,0 +1,99 @@
+package pajak
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/gocroot/helper/atdb"
+	"github.com/gocroot/helper/audit"
+	"github.com/gocroot/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// LoadRates mengambil tarif PPN khusus produk dan kategori milik owner beserta tarif default toko
+func LoadRates(db *mongo.Database, owner string, store model.StoreProfile) (rates Rates, err error) {
+	rates = Rates{Default: StoreRate(store), Product: make(map[primitive.ObjectID]float64), Category: make(map[string]float64)}
+	filter := bson.M{"owner": owner, "tax_rate": bson.M{"$type": "number"}}
+	products, err := atdb.GetAllDoc[[]model.Product](db, ProductCollection, filter)
+	if err != nil {
+		return
+	}
+	for _, p := range products {
+		rates.Product[p.ID] = *p.TaxRate
+	}
+	categories, err := atdb.GetAllDoc[[]model.Category](db, CategoryCollection, filter)
+	if err != nil {
+		return
+	}
+	for _, c := range categories {
+		rates.Category[strings.ToLower(strings.TrimSpace(c.Name))] = *c.TaxRate
+	}
+	return
+}
+
+// TaxedSales mengambil penjualan ber-PPN owner dalam satu bulan (YYYY-MM), urut tanggal transaksi
+func TaxedSales(db *mongo.Database, owner, month string) (sales []model.SalesTransaction, err error) {
+	start, end, err := ParseMonth(month)
+	if err != nil {
+		return
+	}
+	sales, err = atdb.GetAllDoc[[]model.SalesTransaction](db, SalesCollection, audit.Live(bson.M{
+		"owner":           owner,
+		"transactionDate": bson.M{"$gte": start, "$lte": end},
+		"tax_amount":      bson.M{"$gt": 0},
+	}))
+	sort.Slice(sales, func(i, j int) bool { return sales[i].TransactionDate.Before(sales[j].TransactionDate) })
+	return
+}
+
+// MonthlyReport menyusun laporan PPN keluaran dan masukan owner untuk satu bulan (YYYY-MM)
+func MonthlyReport(db *mongo.Database, owner, month string) (report model.PPNReport, err error) {
+	start, end, err := ParseMonth(month)
+	if err != nil {
+		return
+	}
+	sales, err := TaxedSales(db, owner, month)
+	if err != nil {
+		return
+	}
+	period := bson.M{"$gte": start, "$lte": end}
+	expenses, err := atdb.GetAllDoc[[]model.ExpenseTransaction](db, ExpenseCollection, audit.Live(bson.M{"owner": owner, "expense_date": period, "tax_amount": bson.M{"$gt": 0}}))
+	if err != nil {
+		return
+	}
+	purchases, err := atdb.GetAllDoc[[]model.PurchaseOrder](db, PurchaseCollection, bson.M{"owner": owner, "received_at": period, "tax_amount": bson.M{"$gt": 0}})
+	if err != nil {
+		return
+	}
+	returns, err := atdb.GetAllDoc[[]model.SalesReturn](db, ReturnCollection, bson.M{"owner": owner, "return_date": period, "tax_amount": bson.M{"$gt": 0}})
+	if err != nil {
+		return
+	}
+	report = Summarize(month, sales, expenses, purchases)
+	SubtractReturns(&report, returns)
+	return
+}
+
+// Buyers mengambil NPWP, nama dan alamat pelanggan pada penjualan
+func Buyers(db *mongo.Database, owner string, sales []model.SalesTransaction) (buyers map[primitive.ObjectID]Buyer, err error) {
+	buyers = make(map[primitive.ObjectID]Buyer)
+	var ids []primitive.ObjectID
+	for _, trx := range sales {
+		if !trx.CustomerID.IsZero() {
+			ids = append(ids, trx.CustomerID)
+		}
+	}
+	if len(ids) == 0 {
+		return
+	}
+	customers, err := atdb.GetAllDoc[[]model.Customer](db, CustomerCollection, bson.M{"owner": owner, "_id": bson.M{"$in": ids}})
+	if err != nil {
+		return
+	}
+	for _, c := range customers {
+		buyers[c.ID] = Buyer{NPWP: c.NPWP, Name: c.Name, Address: c.Address}
+	}
+	return
+}
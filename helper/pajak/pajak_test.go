@@ -0,0 +1,161 @@
+package pajak
+
+import (
+	"bytes"
+	"encoding/csv"
+	"testing"
+	"time"
+
+	"github.com/gocroot/model"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func rate(v float64) *float64 { return &v }
+
+func TestApplyRates(t *testing.T) {
+	special := primitive.NewObjectID()
+	rates := Rates{
+		Default:  11,
+		Product:  map[primitive.ObjectID]float64{special: 0},
+		Category: map[string]float64{"elektronik": 12},
+	}
+	trx := model.SalesTransaction{Products: []model.Product{
+		{Name: "Kopi", Price: 10000, Quantity: 2},
+		{Name: "TV", Price: 1000000, Category: "Elektronik"},
+		{ID: special, Name: "Beras", Price: 50000, Category: "Elektronik"},
+	}}
+	Apply(&trx, rates, false)
+	if len(trx.TaxLines) != 3 || trx.TaxLines[0].Rate != 0 || trx.TaxLines[2].Rate != 12 {
+		t.Fatalf("tax lines = %+v", trx.TaxLines)
+	}
+	if trx.TaxAmount != 122200 || trx.TotalAmount != 1192200 {
+		t.Errorf("tax = %v total = %v", trx.TaxAmount, trx.TotalAmount)
+	}
+	if *trx.Products[0].TaxRate != 11 || *trx.Products[2].TaxRate != 0 {
+		t.Errorf("tarif item tidak tersimpan: %+v", trx.Products)
+	}
+
+	Clear(&trx)
+	if trx.TaxAmount != 0 || trx.TaxLines != nil || trx.Products[0].TaxRate != nil {
+		t.Errorf("Clear tidak menghapus pajak: %+v", trx)
+	}
+}
+
+func TestApplyInclusive(t *testing.T) {
+	trx := model.SalesTransaction{Products: []model.Product{{Name: "Kopi", Price: 111000}}}
+	Apply(&trx, Rates{Default: 11}, true)
+	if trx.TotalAmount != 111000 || trx.TaxAmount != 11000 || trx.TaxLines[0].Base != 100000 {
+		t.Errorf("trx = %+v", trx)
+	}
+
+	// Penjualan tanpa item memakai total_amount dan tarif default
+	trx = model.SalesTransaction{TotalAmount: 100000}
+	Apply(&trx, Rates{Default: 11}, false)
+	if trx.TotalAmount != 111000 || trx.TaxAmount != 11000 {
+		t.Errorf("trx = %+v", trx)
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	sales := []model.SalesTransaction{
+		{TaxAmount: 11000, TaxLines: []model.TaxLine{{Code: CodePPN, Rate: 11, Base: 100000, Amount: 11000}}},
+		{TaxAmount: 12000, TaxLines: []model.TaxLine{{Code: CodePPN, Rate: 12, Base: 100000, Amount: 12000}}},
+		{TotalAmount: 50000},
+	}
+	expenses := []model.ExpenseTransaction{{Amount: 55500, TaxAmount: 5500}, {Amount: 10000}}
+	purchases := []model.PurchaseOrder{{TotalAmount: 222000, TaxAmount: 22000}}
+	report := Summarize("2026-01", sales, expenses, purchases)
+	if report.Output.Count != 2 || report.Output.Tax != 23000 || len(report.Output.ByRate) != 2 {
+		t.Errorf("output = %+v", report.Output)
+	}
+	if report.Input.Count != 2 || report.Input.Base != 250000 || report.Input.Tax != 27500 {
+		t.Errorf("input = %+v", report.Input)
+	}
+	if report.Net != -4500 || report.Status != StatusLebihBayar {
+		t.Errorf("net = %v status = %s", report.Net, report.Status)
+	}
+}
+
+func TestWriteEFaktur(t *testing.T) {
+	customer := primitive.NewObjectID()
+	sales := []model.SalesTransaction{
+		{
+			InvoiceNumber:    "INV/2026/01/0001",
+			TransactionDate:  time.Date(2026, 1, 15, 10, 0, 0, 0, time.UTC),
+			CustomerID:       customer,
+			CustomerName:     "PT Maju",
+			PriceIncludesTax: true,
+			TaxAmount:        11000,
+			Products: []model.Product{
+				{Name: "Kopi", Price: 55500, Quantity: 2, TaxRate: rate(11)},
+				{Name: "Beras", Price: 10000, TaxRate: rate(0)},
+			},
+		},
+		{CustomerName: "Umum", TotalAmount: 5000},
+	}
+	buyers := map[primitive.ObjectID]Buyer{customer: {NPWP: "01.234.567.8-901.000", Name: "PT Maju Jaya", Address: "Bandung"}}
+
+	var buf bytes.Buffer
+	if err := WriteEFaktur(&buf, sales, buyers); err != nil {
+		t.Fatal(err)
+	}
+	r := csv.NewReader(&buf)
+	r.FieldsPerRecord = -1
+	rows, err := r.ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 5 {
+		t.Fatalf("rows = %v", rows)
+	}
+	fk, of := rows[3], rows[4]
+	if fk[0] != "FK" || fk[4] != "1" || fk[5] != "2026" || fk[6] != "15/01/2026" || fk[7] != "012345678901000" || fk[8] != "PT Maju Jaya" {
+		t.Errorf("FK = %v", fk)
+	}
+	if fk[10] != "100000" || fk[11] != "11000" || fk[18] != "INV/2026/01/0001" {
+		t.Errorf("FK = %v", fk)
+	}
+	if of[0] != "OF" || of[2] != "Kopi" || of[3] != "50000" || of[4] != "2" || of[7] != "100000" || of[8] != "11000" {
+		t.Errorf("OF = %v", of)
+	}
+}
+
+func TestPPh21TER(t *testing.T) {
+	cases := []struct {
+		gross  float64
+		status string
+		want   float64
+	}{
+		{5400000, "TK/0", 0},
+		{10000000, "TK/0", 200000},     // Kategori A, 9,65 juta sampai 10,05 juta: 2%
+		{10000000, "K/1", 150000},      // Kategori B, 9,2 juta sampai 10,75 juta: 1,5%
+		{10000000, "K/3", 150000},      // Kategori C, 9,8 juta sampai 10,95 juta: 1,5%
+		{6500000, "tk/2", 16250},       // Batas atas termasuk lapisan itu: 0,25%
+		{10454000, "", 261350},         // Status kosong dianggap TK/0
+		{2000000000, "K/3", 680000000}, // Di atas lapisan terakhir: 34%
+	}
+	for _, c := range cases {
+		if got := PPh21TER(c.gross, c.status); got != c.want {
+			t.Errorf("PPh21TER(%v, %q) = %v, want %v", c.gross, c.status, got, c.want)
+		}
+	}
+}
+
+func TestPPh21December(t *testing.T) {
+	// Bruto 10 juta/bulan, TK/0: neto 120jt - 6jt - 2,4jt = 111,6jt, PKP 57,6jt, PPh setahun 2.880.000.
+	// Januari sampai November sudah dipotong TER 2% = 11 x 200.000.
+	if got := PPh21December(120000000, 2400000, 12, "TK/0", 2200000); got != 680000 {
+		t.Errorf("PPh21December = %v, want 680000", got)
+	}
+	// Penghasilan setahun di bawah PTKP: potongan TER dikembalikan
+	if got := PPh21December(48000000, 0, 12, "K/1", 30000); got != -30000 {
+		t.Errorf("PPh21December di bawah PTKP = %v, want -30000", got)
+	}
+	// Biaya jabatan untuk 6 bulan bekerja maksimal 3 juta
+	if got := PPh21Annual(120000000, 0, 6, "TK/0"); got != Pasal17(63000000) {
+		t.Errorf("PPh21Annual 6 bulan = %v", got)
+	}
+	if got := Pasal17(300000000); got != 3000000+28500000+12500000 {
+		t.Errorf("Pasal17 = %v", got)
+	}
+}
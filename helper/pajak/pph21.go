@@ -0,0 +1,158 @@
+package pajak
+
+import (
+	"math"
+	"strings"
+)
+
+// ptkp adalah penghasilan tidak kena pajak setahun per status (PMK 101/2016)
+var ptkp = map[string]float64{
+	"TK/0": 54000000, "TK/1": 58500000, "TK/2": 63000000, "TK/3": 67500000,
+	"K/0": 58500000, "K/1": 63000000, "K/2": 67500000, "K/3": 72000000,
+}
+
+// pasal17 adalah lapisan penghasilan kena pajak setahun dan tarifnya (UU HPP)
+var pasal17 = []struct {
+	limit float64
+	rate  float64
+}{
+	{60000000, 5},
+	{250000000, 15},
+	{500000000, 25},
+	{5000000000, 30},
+	{math.Inf(1), 35},
+}
+
+// Biaya jabatan 5% dari penghasilan bruto, maksimal 6 juta setahun
+const (
+	biayaJabatanRate = 5
+	biayaJabatanMax  = 6000000
+)
+
+// ValidTaxStatus memeriksa status PTKP, status kosong dianggap TK/0
+func ValidTaxStatus(status string) bool {
+	if status == "" {
+		return true
+	}
+	_, ok := ptkp[normalizeStatus(status)]
+	return ok
+}
+
+// PTKP mengembalikan PTKP setahun untuk status seperti TK/0 atau K/1, status tidak dikenal dianggap TK/0
+func PTKP(status string) float64 {
+	if v, ok := ptkp[normalizeStatus(status)]; ok {
+		return v
+	}
+	return ptkp["TK/0"]
+}
+
+// Pasal17 menghitung PPh terutang setahun dari penghasilan kena pajak
+func Pasal17(pkp float64) (tax float64) {
+	var lower float64
+	for _, l := range pasal17 {
+		if pkp <= lower {
+			break
+		}
+		tax += (math.Min(pkp, l.limit) - lower) * l.rate / 100
+		lower = l.limit
+	}
+	return math.Floor(tax)
+}
+
+// terCategory memetakan status PTKP ke kategori tarif efektif rata-rata (PP 58/2023)
+var terCategory = map[string]string{
+	"TK/0": "A", "TK/1": "A", "K/0": "A",
+	"TK/2": "B", "TK/3": "B", "K/1": "B", "K/2": "B",
+	"K/3": "C",
+}
+
+// terBracket adalah batas atas bruto sebulan (termasuk batas itu) dan tarif efektifnya dalam persen
+type terBracket struct {
+	limit float64
+	rate  float64
+}
+
+// terMonthly adalah tabel tarif efektif rata-rata bulanan kategori A, B dan C (lampiran PP 58/2023)
+var terMonthly = map[string][]terBracket{
+	"A": {
+		{5400000, 0}, {5650000, 0.25}, {5950000, 0.5}, {6300000, 0.75}, {6750000, 1},
+		{7500000, 1.25}, {8550000, 1.5}, {9650000, 1.75}, {10050000, 2}, {10350000, 2.25},
+		{10700000, 2.5}, {11050000, 3}, {11600000, 3.5}, {12500000, 4}, {13750000, 5},
+		{15100000, 6}, {16950000, 7}, {19750000, 8}, {24150000, 9}, {26450000, 10},
+		{28000000, 11}, {30050000, 12}, {32400000, 13}, {35400000, 14}, {39100000, 15},
+		{43850000, 16}, {47800000, 17}, {51400000, 18}, {56300000, 19}, {62200000, 20},
+		{68600000, 21}, {77500000, 22}, {89000000, 23}, {103000000, 24}, {125000000, 25},
+		{157000000, 26}, {206000000, 27}, {337000000, 28}, {454000000, 29}, {550000000, 30},
+		{695000000, 31}, {910000000, 32}, {1400000000, 33}, {math.Inf(1), 34},
+	},
+	"B": {
+		{6200000, 0}, {6500000, 0.25}, {6850000, 0.5}, {7300000, 0.75}, {9200000, 1},
+		{10750000, 1.5}, {11250000, 2}, {11600000, 2.5}, {12600000, 3}, {13600000, 4},
+		{14950000, 5}, {16400000, 6}, {18450000, 7}, {21850000, 8}, {26000000, 9},
+		{27700000, 10}, {29350000, 11}, {31450000, 12}, {33950000, 13}, {37100000, 14},
+		{41100000, 15}, {45800000, 16}, {49500000, 17}, {53800000, 18}, {58500000, 19},
+		{64000000, 20}, {71000000, 21}, {80000000, 22}, {93000000, 23}, {109000000, 24},
+		{129000000, 25}, {163000000, 26}, {211000000, 27}, {374000000, 28}, {459000000, 29},
+		{555000000, 30}, {704000000, 31}, {957000000, 32}, {1405000000, 33}, {math.Inf(1), 34},
+	},
+	"C": {
+		{6600000, 0}, {6950000, 0.25}, {7350000, 0.5}, {7800000, 0.75}, {8850000, 1},
+		{9800000, 1.25}, {10950000, 1.5}, {11200000, 1.75}, {12050000, 2}, {12950000, 3},
+		{14150000, 4}, {15550000, 5}, {17050000, 6}, {19500000, 7}, {22700000, 8},
+		{26600000, 9}, {28100000, 10}, {30100000, 11}, {32600000, 12}, {35400000, 13},
+		{38900000, 14}, {43000000, 15}, {47400000, 16}, {51200000, 17}, {55800000, 18},
+		{60400000, 19}, {66700000, 20}, {74500000, 21}, {83200000, 22}, {95600000, 23},
+		{110000000, 24}, {134000000, 25}, {169000000, 26}, {221000000, 27}, {390000000, 28},
+		{463000000, 29}, {561000000, 30}, {709000000, 31}, {965000000, 32}, {1419000000, 33},
+		{math.Inf(1), 34},
+	},
+}
+
+// TERCategory mengembalikan kategori TER (A, B atau C) untuk status PTKP, status tidak dikenal dianggap TK/0
+func TERCategory(status string) string {
+	if c, ok := terCategory[normalizeStatus(status)]; ok {
+		return c
+	}
+	return "A"
+}
+
+// TERRate mengembalikan tarif efektif bulanan (persen) untuk penghasilan bruto sebulan
+func TERRate(gross float64, status string) float64 {
+	for _, b := range terMonthly[TERCategory(status)] {
+		if gross <= b.limit {
+			return b.rate
+		}
+	}
+	return 0
+}
+
+// PPh21TER menghitung PPh 21 pegawai tetap untuk masa Januari sampai November: bruto sebulan dikali TER
+func PPh21TER(gross float64, status string) float64 {
+	if gross <= 0 {
+		return 0
+	}
+	return math.Floor(gross * TERRate(gross, status) / 100)
+}
+
+// PPh21Annual menghitung PPh 21 terutang setahun dengan tarif Pasal 17: bruto setahun dikurangi biaya jabatan
+// (5%, maksimal 500 ribu per bulan bekerja), iuran pensiun/JHT pegawai dan PTKP
+func PPh21Annual(gross, pension float64, months int, status string) float64 {
+	jabatan := math.Min(gross*biayaJabatanRate/100, biayaJabatanMax/12*float64(months))
+	pkp := gross - jabatan - pension - PTKP(status)
+	if pkp <= 0 {
+		return 0
+	}
+	// PKP dibulatkan ke bawah dalam ribuan rupiah
+	pkp = math.Floor(pkp/1000) * 1000
+	return Pasal17(pkp)
+}
+
+// PPh21December menghitung PPh 21 masa Desember: PPh setahun dengan tarif Pasal 17 dikurangi PPh 21 yang sudah
+// dipotong dengan TER pada Januari sampai November. Hasil negatif adalah kelebihan potong yang dikembalikan ke pegawai.
+func PPh21December(yearGross, yearPension float64, months int, status string, withheld float64) float64 {
+	return PPh21Annual(yearGross, yearPension, months, status) - withheld
+}
+
+func normalizeStatus(status string) string {
+	return strings.ToUpper(strings.ReplaceAll(status, " ", ""))
+}
@@ -0,0 +1,181 @@
+package pajak
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gocroot/helper/ledger"
+	"github.com/gocroot/model"
+)
+
+// StoreRate adalah tarif PPN default toko
+func StoreRate(store model.StoreProfile) float64 {
+	if store.PPNRate > 0 {
+		return store.PPNRate
+	}
+	return DefaultPPNRate
+}
+
+// Of menentukan tarif PPN item penjualan
+func (r Rates) Of(p model.Product) float64 {
+	if rate, ok := r.Product[p.ID]; ok {
+		return rate
+	}
+	if rate, ok := r.Category[strings.ToLower(strings.TrimSpace(p.Category))]; ok {
+		return rate
+	}
+	return r.Default
+}
+
+// ValidRate memeriksa tarif pajak dalam persen
+func ValidRate(rate float64) bool {
+	return rate >= 0 && rate <= 100
+}
+
+// Split memecah nominal menjadi DPP dan PPN. Untuk harga termasuk pajak, DPP = nominal x 100/(100+tarif).
+func Split(amount, rate float64, inclusive bool) (base, tax float64) {
+	if inclusive {
+		tax = ledger.Round2(amount * rate / (100 + rate))
+		return ledger.Round2(amount) - tax, tax
+	}
+	base = ledger.Round2(amount)
+	return base, ledger.Round2(base * rate / 100)
+}
+
+// Apply menghitung PPN penjualan toko PKP dari harga dan jumlah item: mengisi tarif tiap item, tax_lines,
+// tax_amount serta total_amount. Penjualan tanpa item dihitung dari total_amount dengan tarif default.
+func Apply(trx *model.SalesTransaction, rates Rates, inclusive bool) {
+	gross := make(map[float64]float64)
+	if len(trx.Products) == 0 {
+		gross[rates.Default] = trx.TotalAmount
+	}
+	for i := range trx.Products {
+		rate := rates.Of(trx.Products[i])
+		trx.Products[i].TaxRate = &rate
+		gross[rate] += trx.Products[i].Price*float64(quantity(trx.Products[i])) - trx.Products[i].Discount
+	}
+
+	rateList := make([]float64, 0, len(gross))
+	for rate := range gross {
+		rateList = append(rateList, rate)
+	}
+	sort.Float64s(rateList)
+
+	trx.PriceIncludesTax = inclusive
+	trx.TaxLines = nil
+	var tax, total float64
+	for _, rate := range rateList {
+		base, amount := Split(gross[rate], rate, inclusive)
+		trx.TaxLines = append(trx.TaxLines, model.TaxLine{Code: CodePPN, Rate: rate, Base: base, Amount: amount})
+		tax += amount
+		total += base + amount
+	}
+	trx.TaxAmount = ledger.Round2(tax)
+	trx.TotalAmount = ledger.Round2(total)
+}
+
+// Clear menghapus PPN dari penjualan toko yang bukan PKP
+func Clear(trx *model.SalesTransaction) {
+	trx.TaxAmount = 0
+	trx.TaxLines = nil
+	trx.PriceIncludesTax = false
+	for i := range trx.Products {
+		trx.Products[i].TaxRate = nil
+	}
+}
+
+// ParseMonth mengubah YYYY-MM menjadi rentang waktu satu bulan penuh
+func ParseMonth(month string) (start, end time.Time, err error) {
+	start, err = time.Parse("2006-01", month)
+	if err != nil {
+		return
+	}
+	end = start.AddDate(0, 1, 0).Add(-time.Nanosecond)
+	return
+}
+
+// Summarize menyusun laporan PPN bulanan: keluaran dari tax_lines penjualan, masukan dari pengeluaran dan purchase order
+func Summarize(period string, sales []model.SalesTransaction, expenses []model.ExpenseTransaction, purchases []model.PurchaseOrder) (report model.PPNReport) {
+	report.Period = period
+	byRate := make(map[float64]*model.TaxLine)
+	var rateList []float64
+	for _, trx := range sales {
+		if trx.TaxAmount <= 0 {
+			continue
+		}
+		report.Output.Count++
+		for _, tl := range trx.TaxLines {
+			sum, ok := byRate[tl.Rate]
+			if !ok {
+				sum = &model.TaxLine{Code: CodePPN, Rate: tl.Rate}
+				byRate[tl.Rate] = sum
+				rateList = append(rateList, tl.Rate)
+			}
+			sum.Base += tl.Base
+			sum.Amount += tl.Amount
+			report.Output.Base += tl.Base
+		}
+		report.Output.Tax += trx.TaxAmount
+	}
+	sort.Float64s(rateList)
+	for _, rate := range rateList {
+		sum := byRate[rate]
+		sum.Base, sum.Amount = ledger.Round2(sum.Base), ledger.Round2(sum.Amount)
+		report.Output.ByRate = append(report.Output.ByRate, *sum)
+	}
+
+	for _, exp := range expenses {
+		if exp.TaxAmount > 0 {
+			report.Input.Count++
+			report.Input.Base += exp.Amount - exp.TaxAmount
+			report.Input.Tax += exp.TaxAmount
+		}
+	}
+	for _, po := range purchases {
+		if po.TaxAmount > 0 {
+			report.Input.Count++
+			report.Input.Base += po.TotalAmount - po.TaxAmount
+			report.Input.Tax += po.TaxAmount
+		}
+	}
+
+	report.Output.Base, report.Output.Tax = ledger.Round2(report.Output.Base), ledger.Round2(report.Output.Tax)
+	report.Input.Base, report.Input.Tax = ledger.Round2(report.Input.Base), ledger.Round2(report.Input.Tax)
+	settle(&report)
+	return
+}
+
+// SubtractReturns mencatat retur penjualan ber-PPN pada laporan, PPN retur mengurangi PPN keluaran
+func SubtractReturns(report *model.PPNReport, returns []model.SalesReturn) {
+	report.Returns = model.PPNSection{}
+	for _, ret := range returns {
+		if ret.TaxAmount > 0 {
+			report.Returns.Count++
+			report.Returns.Base += ret.TotalAmount - ret.TaxAmount
+			report.Returns.Tax += ret.TaxAmount
+		}
+	}
+	report.Returns.Base, report.Returns.Tax = ledger.Round2(report.Returns.Base), ledger.Round2(report.Returns.Tax)
+	settle(report)
+}
+
+// settle menghitung PPN kurang atau lebih bayar
+func settle(report *model.PPNReport) {
+	report.Net = ledger.Round2(report.Output.Tax - report.Returns.Tax - report.Input.Tax)
+	switch {
+	case report.Net > 0:
+		report.Status = StatusKurangBayar
+	case report.Net < 0:
+		report.Status = StatusLebihBayar
+	default:
+		report.Status = StatusNihil
+	}
+}
+
+func quantity(p model.Product) int {
+	if p.Quantity <= 0 {
+		return 1
+	}
+	return p.Quantity
+}
@@ -0,0 +1,54 @@
+package pajak
+
+import (
+	"github.com/gocroot/helper/repo"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Kode jenis pajak pada TaxLine
+const CodePPN = "PPN"
+
+// DefaultPPNRate adalah tarif PPN umum (persen) jika toko belum mengatur tarif sendiri
+const DefaultPPNRate = 11
+
+// Nama koleksi yang dibaca untuk tarif dan laporan PPN
+const (
+	SalesCollection    = repo.SalesCollection
+	ExpenseCollection  = repo.ExpenseCollection
+	PurchaseCollection = repo.PurchaseCollection
+	ProductCollection  = repo.ProductCollection
+	CategoryCollection = repo.CategoryCollection
+	CustomerCollection = repo.CustomerCollection
+	ReturnCollection   = repo.ReturnCollection
+)
+
+// Status laporan PPN bulanan
+const (
+	StatusKurangBayar = "kurang bayar"
+	StatusLebihBayar  = "lebih bayar"
+	StatusNihil       = "nihil"
+)
+
+// NPWPKosong dipakai di e-Faktur untuk pembeli yang tidak memiliki NPWP
+const NPWPKosong = "000000000000000"
+
+// Rates adalah sumber tarif PPN: tarif khusus produk, lalu kategori, lalu tarif default toko
+type Rates struct {
+	Default  float64
+	Product  map[primitive.ObjectID]float64
+	Category map[string]float64 // Kunci nama kategori huruf kecil
+}
+
+// Buyer adalah identitas pembeli pada faktur pajak
+type Buyer struct {
+	NPWP    string
+	Name    string
+	Address string
+}
+
+// efakturHeader adalah tiga baris judul kolom wajib pada file impor e-Faktur keluaran
+var efakturHeader = [][]string{
+	{"FK", "KD_JENIS_TRANSAKSI", "FG_PENGGANTI", "NOMOR_FAKTUR", "MASA_PAJAK", "TAHUN_PAJAK", "TANGGAL_FAKTUR", "NPWP", "NAMA", "ALAMAT_LENGKAP", "JUMLAH_DPP", "JUMLAH_PPN", "JUMLAH_PPNBM", "ID_KETERANGAN_TAMBAHAN", "FG_UANG_MUKA", "UANG_MUKA_DPP", "UANG_MUKA_PPN", "UANG_MUKA_PPNBM", "REFERENSI", "KODE_DOKUMEN_PENDUKUNG"},
+	{"LT", "NPWP", "NAMA", "JALAN", "BLOK", "NOMOR", "RT", "RW", "KECAMATAN", "KELURAHAN", "KABUPATEN", "PROPINSI", "KODE_POS", "NOMOR_TELEPON"},
+	{"OF", "KODE_OBJEK", "NAMA", "HARGA_SATUAN", "JUMLAH_BARANG", "HARGA_TOTAL", "DISKON", "DPP", "PPN", "TARIF_PPNBM", "PPNBM"},
+}
@@ -0,0 +1,115 @@
+package pembelian
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gocroot/helper/atdb"
+	"github.com/gocroot/helper/ledger"
+	"github.com/gocroot/helper/stok"
+	"github.com/gocroot/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// CanTransition memeriksa apakah status purchase order boleh berpindah dari from ke to
+func CanTransition(from, to string) bool {
+	return nextStatus[from] == to
+}
+
+// Normalize menghitung subtotal tiap item dan total purchase order ditambah PPN masukan
+func Normalize(po *model.PurchaseOrder) (err error) {
+	if len(po.Items) == 0 {
+		return errors.New("purchase order minimal memiliki satu item")
+	}
+	if po.TaxAmount < 0 {
+		return errors.New("tax_amount tidak boleh negatif")
+	}
+	var total float64
+	for i, item := range po.Items {
+		if item.ProductID.IsZero() {
+			return fmt.Errorf("item %d: product_id wajib diisi", i+1)
+		}
+		if item.Quantity <= 0 || item.UnitCost < 0 {
+			return fmt.Errorf("item %d: quantity harus lebih dari nol dan unit_cost tidak boleh negatif", i+1)
+		}
+		po.Items[i].Subtotal = ledger.Round2(float64(item.Quantity) * item.UnitCost)
+		total += po.Items[i].Subtotal
+	}
+	po.TaxAmount = ledger.Round2(po.TaxAmount)
+	po.TotalAmount = ledger.Round2(total + po.TaxAmount)
+	return
+}
+
+// Receive menambah stok semua item, membuat utang usaha dan memposting jurnal persediaan pada utang usaha
+func Receive(db *mongo.Database, po *model.PurchaseOrder) (payable model.Payable, err error) {
+	po.ReceivedAt = time.Now()
+	for _, item := range po.Items {
+		if _, err = stok.ReceivePurchase(db, po.Owner, item.ProductID, item.Quantity, item.UnitCost, po.ID, "Penerimaan PO dari "+po.SupplierName); err != nil {
+			return
+		}
+	}
+	payable = model.Payable{
+		ID:              primitive.NewObjectID(),
+		Owner:           po.Owner,
+		SupplierID:      po.SupplierID,
+		SupplierName:    po.SupplierName,
+		PurchaseOrderID: po.ID,
+		Amount:          po.TotalAmount,
+		Status:          PayableOpen,
+		CreatedAt:       time.Now(),
+		UpdatedAt:       time.Now(),
+	}
+	if _, err = atdb.InsertOneDoc(db, PayableCollection, payable); err != nil {
+		return
+	}
+	po.PayableID = payable.ID
+	if po.TotalAmount > 0 {
+		_, err = ledger.Post(db, ledger.PurchaseEntry(*po))
+	}
+	return
+}
+
+// Bill mencatat nomor faktur dan jatuh tempo dari supplier pada utang usaha
+func Bill(db *mongo.Database, po *model.PurchaseOrder, invoiceNumber string, dueDate time.Time) (err error) {
+	po.BilledAt = time.Now()
+	po.InvoiceNumber = invoiceNumber
+	update := bson.M{"invoice_number": invoiceNumber, "updated_at": time.Now()}
+	if !dueDate.IsZero() {
+		update["due_date"] = dueDate
+	}
+	_, err = atdb.UpdateOneDoc(db, PayableCollection, bson.M{"_id": po.PayableID}, update)
+	return
+}
+
+// Pay mencatat pembayaran utang usaha dan memposting jurnalnya
+func Pay(db *mongo.Database, payable *model.Payable, amount float64, paymentMethod string) (err error) {
+	outstanding := ledger.Round2(payable.Amount - payable.PaidAmount)
+	if amount <= 0 || ledger.Round2(amount) > outstanding {
+		return fmt.Errorf("nominal pembayaran harus antara 0 dan sisa utang %.2f", outstanding)
+	}
+	// Filter paid_amount lama mencegah dua pembayaran bersamaan melebihi sisa utang
+	filter := bson.M{"_id": payable.ID, "paid_amount": payable.PaidAmount}
+	payable.PaidAmount = ledger.Round2(payable.PaidAmount + amount)
+	payable.Status = PayablePartial
+	if payable.PaidAmount >= payable.Amount {
+		payable.Status = PayablePaid
+	}
+	payable.UpdatedAt = time.Now()
+	result, err := db.Collection(PayableCollection).UpdateOne(context.TODO(), filter, bson.M{"$set": bson.M{
+		"paid_amount": payable.PaidAmount,
+		"status":      payable.Status,
+		"updated_at":  payable.UpdatedAt,
+	}})
+	if err != nil {
+		return
+	}
+	if result.MatchedCount == 0 {
+		return errors.New("utang sedang dibayar oleh transaksi lain, silakan ulangi")
+	}
+	_, err = ledger.Post(db, ledger.PayablePaymentEntry(*payable, amount, paymentMethod))
+	return
+}
@@ -0,0 +1,32 @@
+package pembelian
+
+import "github.com/gocroot/helper/repo"
+
+// Nama koleksi supplier, purchase order dan utang usaha
+const (
+	SupplierCollection      = repo.SupplierCollection
+	PurchaseOrderCollection = repo.PurchaseCollection
+	PayableCollection       = repo.PayableCollection
+)
+
+// Status purchase order
+const (
+	StatusDraft    = "draft"
+	StatusOrdered  = "ordered"
+	StatusReceived = "received"
+	StatusBilled   = "billed"
+)
+
+// Status utang usaha
+const (
+	PayableOpen    = "open"
+	PayablePartial = "partial"
+	PayablePaid    = "paid"
+)
+
+// nextStatus adalah urutan status purchase order yang diizinkan
+var nextStatus = map[string]string{
+	StatusDraft:    StatusOrdered,
+	StatusOrdered:  StatusReceived,
+	StatusReceived: StatusBilled,
+}
@@ -0,0 +1,143 @@
+package piutang
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/gocroot/helper/atdb"
+	"github.com/gocroot/helper/audit"
+	"github.com/gocroot/helper/ledger"
+	"github.com/gocroot/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var ErrConcurrentPayment = errors.New("penjualan sedang dibayar oleh transaksi lain, silakan ulangi")
+
+// Status menentukan status pembayaran dari total penjualan dan total yang sudah dibayar
+func Status(total, paid float64) string {
+	switch {
+	case ledger.Round2(paid) <= 0:
+		return StatusUnpaid
+	case ledger.Round2(paid) < ledger.Round2(total):
+		return StatusPartial
+	default:
+		return StatusPaid
+	}
+}
+
+// Outstanding menghitung sisa piutang setelah pembayaran dan nota kredit retur.
+// Penjualan tunai lama yang belum punya paid_amount dianggap lunas.
+func Outstanding(trx model.SalesTransaction) float64 {
+	if !ledger.IsCredit(trx.PaymentStatus) && len(trx.Payments) == 0 {
+		return 0
+	}
+	return ledger.Round2(trx.TotalAmount - trx.PaidAmount - trx.CreditedAmount)
+}
+
+// AddPayment menambahkan cicilan pada penjualan kredit, memperbarui status dan memposting jurnal pelunasan piutang
+func AddPayment(db *mongo.Database, trx *model.SalesTransaction, payment model.SalesPayment) (err error) {
+	outstanding := Outstanding(*trx)
+	if outstanding <= 0 {
+		return errors.New("penjualan sudah lunas")
+	}
+	if payment.Amount <= 0 || ledger.Round2(payment.Amount) > outstanding {
+		return fmt.Errorf("nominal pembayaran harus antara 0 dan sisa piutang %.2f", outstanding)
+	}
+	payment.ID = primitive.NewObjectID()
+	payment.Amount = ledger.Round2(payment.Amount)
+	if payment.PaidAt.IsZero() {
+		payment.PaidAt = time.Now()
+	}
+
+	// Filter paid_amount lama mencegah dua pembayaran bersamaan melebihi sisa piutang
+	filter := bson.M{"_id": trx.ID, "paid_amount": trx.PaidAmount}
+	if trx.PaidAmount == 0 {
+		filter["paid_amount"] = bson.M{"$in": bson.A{0, nil}}
+	}
+	paid := ledger.Round2(trx.PaidAmount + payment.Amount)
+	update := bson.M{
+		"$push": bson.M{"payments": payment},
+		"$set":  bson.M{"paid_amount": paid, "payment_status": Status(trx.TotalAmount-trx.CreditedAmount, paid)},
+	}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	err = db.Collection(SalesCollection).FindOneAndUpdate(ctx, filter, update, opts).Decode(trx)
+	if err == mongo.ErrNoDocuments {
+		return ErrConcurrentPayment
+	}
+	if err != nil {
+		return
+	}
+	_, err = ledger.Post(db, ledger.ReceiptEntry(*trx, payment))
+	return
+}
+
+// Aging mengambil penjualan kredit milik owner yang belum lunas lalu menyusun umur piutang per pelanggan
+func Aging(db *mongo.Database, owner string, asOf time.Time) (report model.AgingReport, err error) {
+	sales, err := atdb.GetAllDoc[[]model.SalesTransaction](db, SalesCollection, audit.Live(bson.M{
+		"owner":           owner,
+		"payment_status":  bson.M{"$in": ledger.CreditStatuses},
+		"transactionDate": bson.M{"$lte": asOf},
+	}))
+	if err != nil {
+		return
+	}
+	return BuildAging(sales, asOf), nil
+}
+
+// BuildAging mengelompokkan sisa piutang per pelanggan ke 0-30, 31-60, 61-90 dan lebih dari 90 hari.
+// Umur dihitung dari jatuh tempo jika ada, selain itu dari tanggal transaksi.
+func BuildAging(sales []model.SalesTransaction, asOf time.Time) (report model.AgingReport) {
+	report.AsOf = asOf.Format("2006-01-02")
+	report.Rows = []model.AgingRow{}
+	rows := map[string]*model.AgingRow{}
+	for _, trx := range sales {
+		amount := Outstanding(trx)
+		if amount <= 0 {
+			continue
+		}
+		since := trx.TransactionDate
+		if !trx.DueDate.IsZero() {
+			since = trx.DueDate
+		}
+		days := int(asOf.Sub(since).Hours() / 24)
+
+		row, ok := rows[trx.CustomerName]
+		if !ok {
+			row = &model.AgingRow{CustomerName: trx.CustomerName}
+			rows[trx.CustomerName] = row
+		}
+		addToBucket(row, days, amount)
+		addToBucket(&report.Totals, days, amount)
+	}
+	for _, row := range rows {
+		report.Rows = append(report.Rows, *row)
+	}
+	sort.Slice(report.Rows, func(i, j int) bool {
+		return report.Rows[i].Total > report.Rows[j].Total
+	})
+	return
+}
+
+func addToBucket(row *model.AgingRow, days int, amount float64) {
+	switch {
+	case days <= 30:
+		row.Current = ledger.Round2(row.Current + amount)
+	case days <= 60:
+		row.Days31To60 = ledger.Round2(row.Days31To60 + amount)
+	case days <= 90:
+		row.Days61To90 = ledger.Round2(row.Days61To90 + amount)
+	default:
+		row.Over90 = ledger.Round2(row.Over90 + amount)
+	}
+	row.Total = ledger.Round2(row.Total + amount)
+	row.Invoices++
+}
@@ -0,0 +1,51 @@
+package piutang
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gocroot/model"
+)
+
+func TestStatus(t *testing.T) {
+	cases := []struct {
+		total, paid float64
+		want        string
+	}{
+		{100000, 0, StatusUnpaid},
+		{100000, 40000, StatusPartial},
+		{100000, 100000, StatusPaid},
+	}
+	for _, c := range cases {
+		if got := Status(c.total, c.paid); got != c.want {
+			t.Errorf("Status(%.0f, %.0f) = %s, want %s", c.total, c.paid, got, c.want)
+		}
+	}
+}
+
+func TestBuildAging(t *testing.T) {
+	asOf := time.Date(2024, 6, 30, 0, 0, 0, 0, time.UTC)
+	sales := []model.SalesTransaction{
+		{CustomerName: "Budi", TotalAmount: 100000, PaymentStatus: "bon", TransactionDate: asOf.AddDate(0, 0, -10)},
+		{CustomerName: "Budi", TotalAmount: 200000, PaidAmount: 50000, PaymentStatus: StatusPartial, TransactionDate: asOf.AddDate(0, 0, -45)},
+		{CustomerName: "Sari", TotalAmount: 300000, PaymentStatus: StatusUnpaid, TransactionDate: asOf.AddDate(0, 0, -120)},
+		{CustomerName: "Sari", TotalAmount: 80000, PaymentStatus: "lunas", TransactionDate: asOf.AddDate(0, 0, -120)},
+	}
+	report := BuildAging(sales, asOf)
+	if len(report.Rows) != 2 {
+		t.Fatalf("jumlah pelanggan %d, want 2", len(report.Rows))
+	}
+	if report.Totals.Current != 100000 || report.Totals.Days31To60 != 150000 || report.Totals.Over90 != 300000 {
+		t.Errorf("total umur piutang tidak sesuai: %+v", report.Totals)
+	}
+	if report.Rows[0].CustomerName != "Sari" || report.Rows[0].Invoices != 1 {
+		t.Errorf("urutan pelanggan tidak sesuai: %+v", report.Rows)
+	}
+}
+
+func TestOutstandingAfterCreditNote(t *testing.T) {
+	trx := model.SalesTransaction{TotalAmount: 100000, PaidAmount: 30000, CreditedAmount: 20000, PaymentStatus: StatusPartial}
+	if got := Outstanding(trx); got != 50000 {
+		t.Errorf("sisa piutang = %v, want 50000", got)
+	}
+}
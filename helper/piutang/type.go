@@ -0,0 +1,13 @@
+package piutang
+
+import "github.com/gocroot/helper/repo"
+
+// Nama koleksi penjualan yang menyimpan piutang beserta cicilannya
+const SalesCollection = repo.SalesCollection
+
+// Status pembayaran penjualan yang diatur otomatis dari total cicilan
+const (
+	StatusUnpaid  = "unpaid"
+	StatusPartial = "partial"
+	StatusPaid    = "paid"
+)
@@ -0,0 +1,196 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+
+	"github.com/gocroot/helper/paging"
+	"github.com/gocroot/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ErrDuplicateID dikembalikan fake jika dokumen dengan _id yang sama sudah ada
+var ErrDuplicateID = errors.New("repo: _id sudah ada")
+
+// NewFake membuat repository di memori untuk pengujian handler tanpa mongo.
+// Filter mendukung persamaan, $ne, $gt, $gte, $lt, $lte, $in, $nin, $exists, regex, $or dan $and.
+func NewFake() Repositories {
+	return Repositories{
+		Products:   &Fake[model.Product]{},
+		Categories: &Fake[model.Category]{},
+		Sales:      &Fake[model.SalesTransaction]{},
+		Customers:  &Fake[model.Customer]{},
+		Expenses:   &Fake[model.ExpenseTransaction]{},
+		Employees:  &Fake[model.Employee]{},
+		Suppliers:  &Fake[model.Supplier]{},
+		Reports:    &Fake[model.LaporanAkuntan]{},
+	}
+}
+
+// Fake adalah koleksi di memori. Dokumen disimpan dalam bentuk bson sehingga tag bson model tetap berlaku.
+type Fake[T any] struct {
+	mu   sync.Mutex
+	docs []bson.M
+}
+
+func (f *Fake[T]) Insert(ctx context.Context, doc T) error {
+	stored, err := toDoc(doc)
+	if err != nil {
+		return err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, ok := stored["_id"]; !ok {
+		stored["_id"] = primitive.NewObjectID()
+	}
+	for _, d := range f.docs {
+		if equal(d["_id"], true, stored["_id"]) {
+			return ErrDuplicateID
+		}
+	}
+	f.docs = append(f.docs, stored)
+	return nil
+}
+
+func (f *Fake[T]) Get(ctx context.Context, filter bson.M) (doc T, err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, d := range f.docs {
+		if match(d, filter) {
+			return fromDoc[T](d)
+		}
+	}
+	return doc, ErrNotFound
+}
+
+func (f *Fake[T]) List(ctx context.Context, filter bson.M) (docs []T, err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, d := range f.docs {
+		if match(d, filter) {
+			var doc T
+			if doc, err = fromDoc[T](d); err != nil {
+				return
+			}
+			docs = append(docs, doc)
+		}
+	}
+	return
+}
+
+// Find meniru paging.Find: total dihitung dari filter, lalu diurutkan, dipotong per halaman atau setelah cursor
+func (f *Fake[T]) Find(ctx context.Context, q paging.Query) (data []T, meta paging.Meta, err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var found []bson.M
+	for _, d := range f.docs {
+		if match(d, q.Filter) {
+			found = append(found, d)
+		}
+	}
+	meta = paging.Meta{Page: q.Page, Limit: q.Limit}
+	if !q.SkipCount {
+		meta.Total, meta.Counted = int64(len(found)), true
+		if q.Limit > 0 {
+			meta.TotalPages = (meta.Total + q.Limit - 1) / q.Limit
+		}
+	}
+
+	sort.SliceStable(found, func(i, j int) bool {
+		for _, key := range q.Sort {
+			a, _ := lookup(found[i], key.Key)
+			b, _ := lookup(found[j], key.Key)
+			c, _ := compare(a, b)
+			if c == 0 {
+				continue
+			}
+			if dir, _ := toNumber(key.Value); dir < 0 {
+				return c > 0
+			}
+			return c < 0
+		}
+		return false
+	})
+	if !q.Cursor.IsZero() {
+		var after []bson.M
+		for _, d := range found {
+			if c, ok := compare(d["_id"], q.Cursor); ok && c < 0 {
+				after = append(after, d)
+			}
+		}
+		found = after
+	} else if skip := (q.Page - 1) * q.Limit; skip > 0 {
+		if skip > int64(len(found)) {
+			skip = int64(len(found))
+		}
+		found = found[skip:]
+	}
+	if q.Limit > 0 && int64(len(found)) > q.Limit {
+		found = found[:q.Limit]
+	}
+
+	for _, d := range found {
+		var doc T
+		if doc, err = fromDoc[T](d); err != nil {
+			return
+		}
+		data = append(data, doc)
+	}
+	if q.ByID() && q.Limit > 0 && int64(len(found)) == q.Limit {
+		if id, ok := found[len(found)-1]["_id"].(primitive.ObjectID); ok {
+			meta.NextCursor = id.Hex()
+		}
+	}
+	return
+}
+
+func (f *Fake[T]) Update(ctx context.Context, filter bson.M, update bson.M) (int64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for i, d := range f.docs {
+		if !match(d, filter) {
+			continue
+		}
+		updated, err := apply(d, update)
+		if err != nil {
+			return 0, err
+		}
+		f.docs[i] = updated
+		return 1, nil
+	}
+	return 0, nil
+}
+
+func (f *Fake[T]) Delete(ctx context.Context, filter bson.M) (int64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for i, d := range f.docs {
+		if match(d, filter) {
+			f.docs = append(f.docs[:i], f.docs[i+1:]...)
+			return 1, nil
+		}
+	}
+	return 0, nil
+}
+
+// toDoc mengubah struct menjadi dokumen bson seperti yang disimpan mongo
+func toDoc(v interface{}) (doc bson.M, err error) {
+	raw, err := bson.Marshal(v)
+	if err != nil {
+		return
+	}
+	err = bson.Unmarshal(raw, &doc)
+	return
+}
+
+func fromDoc[T any](doc bson.M) (v T, err error) {
+	raw, err := bson.Marshal(doc)
+	if err != nil {
+		return
+	}
+	err = bson.Unmarshal(raw, &v)
+	return
+}
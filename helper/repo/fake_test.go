@@ -0,0 +1,144 @@
+package repo
+
+import (
+	"context"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/gocroot/helper/paging"
+	"github.com/gocroot/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var ctx = context.Background()
+
+func seedProducts(t *testing.T, f ProductRepository) []model.Product {
+	base := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	products := []model.Product{
+		{ID: primitive.NewObjectID(), Owner: "62811", Name: "Kopi Susu", Category: "minuman", Price: 18000, Stock: 5, CreatedAt: base},
+		{ID: primitive.NewObjectID(), Owner: "62811", Name: "Teh Tarik", Category: "minuman", Price: 15000, Stock: 0, CreatedAt: base.AddDate(0, 0, 1)},
+		{ID: primitive.NewObjectID(), Owner: "62811", Name: "Roti Bakar", Category: "makanan", Price: 20000, Stock: 7, CreatedAt: base.AddDate(0, 0, 2)},
+		{ID: primitive.NewObjectID(), Owner: "62899", Name: "Kopi Hitam", Category: "minuman", Price: 10000, Stock: 3, CreatedAt: base},
+	}
+	for _, p := range products {
+		if err := f.Insert(ctx, p); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return products
+}
+
+func TestFakeGetAndFilter(t *testing.T) {
+	f := NewFake().Products
+	products := seedProducts(t, f)
+
+	got, err := f.Get(ctx, bson.M{"_id": products[0].ID, "owner": "62811", "deleted_at": nil})
+	if err != nil || got.Name != "Kopi Susu" || !got.CreatedAt.Equal(products[0].CreatedAt) {
+		t.Fatalf("Get = %+v, %v", got, err)
+	}
+	if _, err = f.Get(ctx, bson.M{"_id": products[0].ID, "owner": "62899"}); err != ErrNotFound {
+		t.Errorf("owner lain harus ErrNotFound, dapat %v", err)
+	}
+	if err = f.Insert(ctx, products[0]); err != ErrDuplicateID {
+		t.Errorf("insert _id ganda = %v", err)
+	}
+
+	cases := map[string]bson.M{
+		"gte price":   {"owner": "62811", "price": bson.M{"$gte": 18000}},
+		"stock habis": {"owner": "62811", "stock": bson.M{"$lte": 0}},
+		"in kategori": {"category": bson.M{"$in": []string{"makanan"}}},
+		"tanggal":     {"createdAt": bson.M{"$gt": time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)}},
+		"regex":       {"name": primitive.Regex{Pattern: "kopi", Options: "i"}},
+		"or":          {"$or": []bson.M{{"name": "Teh Tarik"}, {"category": "makanan"}}},
+	}
+	want := map[string]int{"gte price": 2, "stock habis": 1, "in kategori": 1, "tanggal": 2, "regex": 2, "or": 2}
+	for name, filter := range cases {
+		list, err := f.List(ctx, filter)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(list) != want[name] {
+			t.Errorf("%s: %d data, want %d", name, len(list), want[name])
+		}
+	}
+}
+
+func TestFakeFindPaging(t *testing.T) {
+	f := NewFake().Products
+	seedProducts(t, f)
+	opt := paging.Options{SearchFields: []string{"name"}, SortFields: []string{"price"}}
+
+	v := url.Values{"sort": {"-price"}, "limit": {"2"}}
+	q, err := paging.Parse(v, bson.M{"owner": "62811"}, opt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, meta, err := f.Find(ctx, q)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if meta.Total != 3 || meta.TotalPages != 2 || len(data) != 2 || data[0].Name != "Roti Bakar" || data[1].Name != "Kopi Susu" {
+		t.Errorf("halaman 1 = %+v %+v", meta, data)
+	}
+	v.Set("page", "2")
+	q, _ = paging.Parse(v, bson.M{"owner": "62811"}, opt)
+	if data, _, _ = f.Find(ctx, q); len(data) != 1 || data[0].Name != "Teh Tarik" {
+		t.Errorf("halaman 2 = %+v", data)
+	}
+
+	// Urutan default _id menurun memberi cursor ke halaman berikutnya
+	q, _ = paging.Parse(url.Values{"limit": {"2"}}, bson.M{"owner": "62811"}, opt)
+	first, meta, _ := f.Find(ctx, q)
+	if meta.NextCursor == "" || len(first) != 2 {
+		t.Fatalf("cursor kosong: %+v", meta)
+	}
+	q, _ = paging.Parse(url.Values{"limit": {"2"}, "cursor": {meta.NextCursor}}, bson.M{"owner": "62811"}, opt)
+	rest, meta, _ := f.Find(ctx, q)
+	if len(rest) != 1 || rest[0].ID == first[0].ID || rest[0].ID == first[1].ID || meta.NextCursor != "" || meta.Counted {
+		t.Errorf("halaman cursor = %+v %+v", rest, meta)
+	}
+}
+
+func TestFakeUpdateDelete(t *testing.T) {
+	f := NewFake().Products
+	products := seedProducts(t, f)
+	filter := bson.M{"_id": products[1].ID, "owner": "62811", "deleted_at": nil}
+
+	rate := 11.0
+	matched, err := f.Update(ctx, filter, bson.M{
+		"$set": bson.M{"name": "Teh Tarik Besar", "tax_rate": rate},
+		"$inc": bson.M{"stock": 4},
+	})
+	if err != nil || matched != 1 {
+		t.Fatalf("Update = %d, %v", matched, err)
+	}
+	got, _ := f.Get(ctx, filter)
+	if got.Name != "Teh Tarik Besar" || got.Stock != 4 || got.TaxRate == nil || *got.TaxRate != 11 {
+		t.Errorf("setelah update = %+v", got)
+	}
+	if _, err = f.Update(ctx, filter, bson.M{"$unset": bson.M{"tax_rate": ""}}); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ = f.Get(ctx, filter); got.TaxRate != nil {
+		t.Errorf("tax_rate harus terhapus, dapat %v", *got.TaxRate)
+	}
+
+	// Dokumen yang ditandai terhapus tidak lagi cocok dengan filter Live
+	f.Update(ctx, filter, bson.M{"$set": bson.M{"deleted_at": time.Now()}})
+	if _, err = f.Get(ctx, filter); err != ErrNotFound {
+		t.Errorf("dokumen terhapus masih ditemukan: %v", err)
+	}
+	if n, _ := f.Update(ctx, filter, bson.M{"$set": bson.M{"name": "x"}}); n != 0 {
+		t.Errorf("update dokumen terhapus matched %d", n)
+	}
+
+	deleted, err := f.Delete(ctx, bson.M{"_id": products[2].ID})
+	if err != nil || deleted != 1 {
+		t.Fatalf("Delete = %d, %v", deleted, err)
+	}
+	if deleted, _ = f.Delete(ctx, bson.M{"_id": products[2].ID}); deleted != 0 {
+		t.Errorf("hapus ulang = %d", deleted)
+	}
+}
@@ -0,0 +1,384 @@
+package repo
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// match memeriksa apakah dokumen cocok dengan filter mongo, dipakai oleh Fake
+func match(doc bson.M, filter bson.M) bool {
+	for key, cond := range filter {
+		switch key {
+		case "$or":
+			ok := false
+			for _, sub := range subFilters(cond) {
+				if match(doc, sub) {
+					ok = true
+					break
+				}
+			}
+			if !ok {
+				return false
+			}
+		case "$and":
+			for _, sub := range subFilters(cond) {
+				if !match(doc, sub) {
+					return false
+				}
+			}
+		default:
+			val, found := lookup(doc, key)
+			if !matchValue(val, found, cond) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// matchValue mencocokkan satu field dengan kondisi berupa nilai, regex atau dokumen operator
+func matchValue(val interface{}, found bool, cond interface{}) bool {
+	if re, ok := cond.(primitive.Regex); ok {
+		return matchRegex(val, re)
+	}
+	ops, ok := operators(cond)
+	if !ok {
+		return equal(val, found, cond)
+	}
+	for op, arg := range ops {
+		var ok bool
+		switch op {
+		case "$eq":
+			ok = equal(val, found, arg)
+		case "$ne":
+			ok = !equal(val, found, arg)
+		case "$gt", "$gte", "$lt", "$lte":
+			c, comparable := compare(val, arg)
+			ok = found && comparable &&
+				(op == "$gt" && c > 0 || op == "$gte" && c >= 0 || op == "$lt" && c < 0 || op == "$lte" && c <= 0)
+		case "$in", "$nin":
+			in := false
+			for _, v := range list(arg) {
+				if equal(val, found, v) {
+					in = true
+					break
+				}
+			}
+			ok = in == (op == "$in")
+		case "$exists":
+			want, _ := arg.(bool)
+			ok = found == want
+		case "$regex":
+			re := primitive.Regex{Pattern: fmt.Sprint(arg)}
+			if o, has := ops["$options"]; has {
+				re.Options = fmt.Sprint(o)
+			}
+			ok = matchRegex(val, re)
+		case "$options":
+			ok = true
+		default:
+			panic("repo: operator filter tidak didukung fake: " + op)
+		}
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// operators mengembalikan isi kondisi jika semua kuncinya operator seperti $gte
+func operators(cond interface{}) (bson.M, bool) {
+	m, ok := asDoc(cond)
+	if !ok || len(m) == 0 {
+		return nil, false
+	}
+	for k := range m {
+		if !strings.HasPrefix(k, "$") {
+			return nil, false
+		}
+	}
+	return m, true
+}
+
+func matchRegex(val interface{}, re primitive.Regex) bool {
+	s, ok := val.(string)
+	if !ok {
+		return false
+	}
+	pattern := re.Pattern
+	if strings.Contains(re.Options, "i") {
+		pattern = "(?i)" + pattern
+	}
+	ok, _ = regexp.MatchString(pattern, s)
+	return ok
+}
+
+// equal meniru persamaan mongo: nil cocok dengan field kosong atau tidak ada,
+// array cocok jika salah satu elemennya sama
+func equal(val interface{}, found bool, arg interface{}) bool {
+	if arg == nil {
+		return !found || val == nil
+	}
+	if !found {
+		return false
+	}
+	if arr, ok := val.(primitive.A); ok {
+		if _, argIsList := arg.(primitive.A); !argIsList {
+			for _, v := range arr {
+				if equal(v, true, arg) {
+					return true
+				}
+			}
+			return false
+		}
+	}
+	if c, ok := compare(val, arg); ok {
+		return c == 0
+	}
+	return reflect.DeepEqual(normalize(val), normalize(arg))
+}
+
+// compare mengurutkan dua nilai bertipe sama, nil paling kecil seperti urutan mongo
+func compare(a, b interface{}) (int, bool) {
+	a, b = normalize(a), normalize(b)
+	if a == nil || b == nil {
+		switch {
+		case a == nil && b == nil:
+			return 0, true
+		case a == nil:
+			return -1, true
+		default:
+			return 1, true
+		}
+	}
+	switch x := a.(type) {
+	case float64:
+		if y, ok := b.(float64); ok {
+			return cmp(x < y, x > y), true
+		}
+	case string:
+		if y, ok := b.(string); ok {
+			return strings.Compare(x, y), true
+		}
+	case primitive.DateTime:
+		if y, ok := b.(primitive.DateTime); ok {
+			return cmp(x < y, x > y), true
+		}
+	case primitive.ObjectID:
+		if y, ok := b.(primitive.ObjectID); ok {
+			return bytes.Compare(x[:], y[:]), true
+		}
+	case bool:
+		if y, ok := b.(bool); ok {
+			return cmp(!x && y, x && !y), true
+		}
+	}
+	return 0, false
+}
+
+func cmp(less, greater bool) int {
+	if less {
+		return -1
+	}
+	if greater {
+		return 1
+	}
+	return 0
+}
+
+// normalize menyamakan tipe nilai filter dengan nilai hasil decode bson
+func normalize(v interface{}) interface{} {
+	switch x := v.(type) {
+	case nil:
+		return nil
+	case time.Time:
+		return primitive.NewDateTimeFromTime(x)
+	case *time.Time:
+		if x == nil {
+			return nil
+		}
+		return primitive.NewDateTimeFromTime(*x)
+	case primitive.DateTime, primitive.ObjectID, bool:
+		return x
+	}
+	if n, ok := toNumber(v); ok {
+		return n
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.String {
+		return rv.String()
+	}
+	if rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return nil
+		}
+		return normalize(rv.Elem().Interface())
+	}
+	return v
+}
+
+func toNumber(v interface{}) (float64, bool) {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(rv.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), true
+	}
+	return 0, false
+}
+
+// lookup mengambil nilai field, mendukung path bertitik seperti items.product_id
+func lookup(doc bson.M, key string) (interface{}, bool) {
+	var cur interface{} = doc
+	for _, part := range strings.Split(key, ".") {
+		switch d := cur.(type) {
+		case bson.M, bson.D:
+			sub, _ := asDoc(d)
+			v, ok := sub[part]
+			if !ok {
+				return nil, false
+			}
+			cur = v
+		case primitive.A:
+			var vals primitive.A
+			for _, el := range d {
+				if sub, ok := asDoc(el); ok {
+					if v, ok := sub[part]; ok {
+						vals = append(vals, v)
+					}
+				}
+			}
+			if len(vals) == 0 {
+				return nil, false
+			}
+			cur = vals
+		default:
+			return nil, false
+		}
+	}
+	return cur, true
+}
+
+func subFilters(v interface{}) (filters []bson.M) {
+	for _, item := range list(v) {
+		if m, ok := asDoc(item); ok {
+			filters = append(filters, m)
+		}
+	}
+	return
+}
+
+// asDoc mengubah bson.M, map atau bson.D menjadi bson.M
+func asDoc(v interface{}) (bson.M, bool) {
+	switch m := v.(type) {
+	case bson.M:
+		return m, true
+	case map[string]interface{}:
+		return m, true
+	case bson.D:
+		out := make(bson.M, len(m))
+		for _, e := range m {
+			out[e.Key] = e.Value
+		}
+		return out, true
+	}
+	return nil, false
+}
+
+// list mengubah slice bertipe apa pun menjadi []interface{}
+func list(v interface{}) []interface{} {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return nil
+	}
+	out := make([]interface{}, rv.Len())
+	for i := range out {
+		out[i] = rv.Index(i).Interface()
+	}
+	return out
+}
+
+// apply menjalankan dokumen update $set, $unset, $inc, $push dan $addToSet pada salinan dokumen
+func apply(doc bson.M, update bson.M) (bson.M, error) {
+	out := bson.M{}
+	for k, v := range doc {
+		out[k] = v
+	}
+	for op, arg := range update {
+		fields, ok := asDoc(arg)
+		if !ok {
+			return nil, fmt.Errorf("repo: argumen %s harus dokumen", op)
+		}
+		for field, v := range fields {
+			if strings.Contains(field, ".") {
+				return nil, fmt.Errorf("repo: fake belum mendukung field bertitik %s", field)
+			}
+			stored, err := storable(v)
+			if err != nil {
+				return nil, err
+			}
+			switch op {
+			case "$set":
+				out[field] = stored
+			case "$unset":
+				delete(out, field)
+			case "$inc":
+				cur, _ := toNumber(out[field])
+				inc, _ := toNumber(stored)
+				out[field] = keepNumberType(out[field], stored, cur+inc)
+			case "$push", "$addToSet":
+				arr, _ := out[field].(primitive.A)
+				if op == "$addToSet" {
+					exists := false
+					for _, el := range arr {
+						if equal(el, true, stored) {
+							exists = true
+							break
+						}
+					}
+					if exists {
+						continue
+					}
+				}
+				out[field] = append(append(primitive.A{}, arr...), stored)
+			default:
+				return nil, fmt.Errorf("repo: operator update tidak didukung fake: %s", op)
+			}
+		}
+	}
+	return out, nil
+}
+
+// storable mengubah nilai update menjadi bentuk yang sama dengan hasil decode bson
+func storable(v interface{}) (interface{}, error) {
+	doc, err := toDoc(bson.M{"v": v})
+	if err != nil {
+		return nil, err
+	}
+	return doc["v"], nil
+}
+
+// keepNumberType mempertahankan tipe angka lama setelah $inc
+func keepNumberType(old, inc interface{}, n float64) interface{} {
+	ref := old
+	if ref == nil {
+		ref = inc
+	}
+	switch ref.(type) {
+	case int32:
+		return int32(n)
+	case int64:
+		return int64(n)
+	}
+	return n
+}
@@ -0,0 +1,94 @@
+package repo
+
+import (
+	"context"
+
+	"github.com/gocroot/helper/paging"
+	"github.com/gocroot/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Mongo membuat repository di atas database mongo. db dipanggil setiap operasi
+// sehingga koneksi boleh dibuat belakangan.
+func Mongo(db func() *mongo.Database) Repositories {
+	return Repositories{
+		Products:   mongoCollection[model.Product]{db: db, name: ProductCollection},
+		Categories: mongoCollection[model.Category]{db: db, name: CategoryCollection},
+		Sales:      mongoCollection[model.SalesTransaction]{db: db, name: SalesCollection},
+		Customers:  mongoCollection[model.Customer]{db: db, name: CustomerCollection},
+		Expenses:   mongoCollection[model.ExpenseTransaction]{db: db, name: ExpenseCollection},
+		Employees:  mongoCollection[model.Employee]{db: db, name: EmployeeCollection},
+		Suppliers:  mongoCollection[model.Supplier]{db: db, name: SupplierCollection},
+		Reports:    mongoCollection[model.LaporanAkuntan]{db: db, name: ReportCollection},
+	}
+}
+
+// WithTimeout membatasi context dengan Timeout, batas waktu request yang lebih awal tetap berlaku
+func WithTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return context.WithTimeout(ctx, Timeout)
+}
+
+type mongoCollection[T any] struct {
+	db   func() *mongo.Database
+	name string
+}
+
+func (c mongoCollection[T]) coll() *mongo.Collection {
+	return c.db().Collection(c.name)
+}
+
+func (c mongoCollection[T]) Insert(ctx context.Context, doc T) error {
+	ctx, cancel := WithTimeout(ctx)
+	defer cancel()
+	_, err := c.coll().InsertOne(ctx, doc)
+	return err
+}
+
+func (c mongoCollection[T]) Get(ctx context.Context, filter bson.M) (doc T, err error) {
+	ctx, cancel := WithTimeout(ctx)
+	defer cancel()
+	err = c.coll().FindOne(ctx, filter).Decode(&doc)
+	return
+}
+
+func (c mongoCollection[T]) List(ctx context.Context, filter bson.M) (docs []T, err error) {
+	ctx, cancel := WithTimeout(ctx)
+	defer cancel()
+	cur, err := c.coll().Find(ctx, filter)
+	if err != nil {
+		return
+	}
+	defer cur.Close(ctx)
+	err = cur.All(ctx, &docs)
+	return
+}
+
+func (c mongoCollection[T]) Find(ctx context.Context, q paging.Query) ([]T, paging.Meta, error) {
+	ctx, cancel := WithTimeout(ctx)
+	defer cancel()
+	return paging.FindContext[T](ctx, c.db(), c.name, q)
+}
+
+func (c mongoCollection[T]) Update(ctx context.Context, filter bson.M, update bson.M) (int64, error) {
+	ctx, cancel := WithTimeout(ctx)
+	defer cancel()
+	res, err := c.coll().UpdateOne(ctx, filter, update)
+	if err != nil {
+		return 0, err
+	}
+	return res.MatchedCount, nil
+}
+
+func (c mongoCollection[T]) Delete(ctx context.Context, filter bson.M) (int64, error) {
+	ctx, cancel := WithTimeout(ctx)
+	defer cancel()
+	res, err := c.coll().DeleteOne(ctx, filter)
+	if err != nil {
+		return 0, err
+	}
+	return res.DeletedCount, nil
+}
@@ -0,0 +1,102 @@
+package repo
+
+import (
+	"context"
+	"time"
+
+	"github.com/gocroot/helper/paging"
+	"github.com/gocroot/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Nama koleksi data toko. Helper lain merujuk konstanta ini agar satu koleksi hanya punya satu nama.
+const (
+	ProductCollection  = "products"
+	CategoryCollection = "kategori"
+	SalesCollection    = "transaksi_penjualan"
+	CustomerCollection = "customers"
+	ExpenseCollection  = "expense_transaction"
+	EmployeeCollection = "employee"
+	SupplierCollection = "suppliers"
+	ReportCollection   = "financial_reports"
+	ReturnCollection   = "sales_returns"
+	SnapshotCollection = "financial_statements"
+	MovementCollection = "stock_movements"
+	PurchaseCollection = "purchase_orders"
+	PayableCollection  = "payables"
+	AccountCollection  = "chart_of_accounts"
+	JournalCollection  = "journal_entries"
+	StoreCollection    = "store_profile"
+	PayrollCollection  = "payroll_runs"
+)
+
+// Timeout adalah batas waktu satu operasi database jika context request tidak punya batas lebih awal
+const Timeout = 10 * time.Second
+
+// ErrNotFound dikembalikan Get jika tidak ada dokumen yang cocok, sama dengan error driver mongo
+var ErrNotFound = mongo.ErrNoDocuments
+
+// Collection adalah operasi dasar satu koleksi. Filter dan update memakai bentuk dokumen mongo.
+type Collection[T any] interface {
+	Insert(ctx context.Context, doc T) error
+	Get(ctx context.Context, filter bson.M) (T, error)
+	List(ctx context.Context, filter bson.M) ([]T, error)
+	Find(ctx context.Context, q paging.Query) ([]T, paging.Meta, error)
+	Update(ctx context.Context, filter bson.M, update bson.M) (matched int64, err error)
+	Delete(ctx context.Context, filter bson.M) (deleted int64, err error)
+}
+
+// ProductRepository menyimpan data produk
+type ProductRepository interface {
+	Collection[model.Product]
+}
+
+// CategoryRepository menyimpan data kategori produk
+type CategoryRepository interface {
+	Collection[model.Category]
+}
+
+// SalesRepository menyimpan transaksi penjualan
+type SalesRepository interface {
+	Collection[model.SalesTransaction]
+}
+
+// CustomerRepository menyimpan data pelanggan
+type CustomerRepository interface {
+	Collection[model.Customer]
+}
+
+// ExpenseRepository menyimpan transaksi pengeluaran
+type ExpenseRepository interface {
+	Collection[model.ExpenseTransaction]
+}
+
+// EmployeeRepository menyimpan data karyawan
+type EmployeeRepository interface {
+	Collection[model.Employee]
+}
+
+// SupplierRepository menyimpan data supplier
+type SupplierRepository interface {
+	Collection[model.Supplier]
+}
+
+// ReportRepository menyimpan laporan keuangan
+type ReportRepository interface {
+	Collection[model.LaporanAkuntan]
+}
+
+// Repositories adalah kumpulan repository yang dipakai handler untuk data entitasnya.
+// Audit log, stok, jurnal dan nomor invoice masih ditulis helper masing-masing langsung
+// ke mongo, jadi handler yang memakainya belum bisa dijalankan penuh di atas fake.
+type Repositories struct {
+	Products   ProductRepository
+	Categories CategoryRepository
+	Sales      SalesRepository
+	Customers  CustomerRepository
+	Expenses   ExpenseRepository
+	Employees  EmployeeRepository
+	Suppliers  SupplierRepository
+	Reports    ReportRepository
+}
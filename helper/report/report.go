@@ -3,7 +3,7 @@ package report
 import (
 	"context"
 	"errors"
-	"log"
+	"log/slog"
 	"sort"
 	"strconv"
 	"strings"
@@ -268,7 +268,7 @@ func GetRankDataRepoMasukHarian(db *mongo.Database, filterhari bson.M) (ranklist
 		filter := bson.M{"username": username, "_id": filterhari}
 		cur, err := pushrepo.Find(context.Background(), filter)
 		if err != nil {
-			log.Println("Failed to find pushrepo data:", err)
+			slog.Error("gagal membaca data pushrepo", "username", username, "error", err)
 			return
 		}
 
@@ -278,7 +278,7 @@ func GetRankDataRepoMasukHarian(db *mongo.Database, filterhari bson.M) (ranklist
 		for cur.Next(context.Background()) {
 			var report model.PushReport
 			if err := cur.Decode(&report); err != nil {
-				log.Println("Failed to decode pushrepo data:", err)
+				slog.Error("gagal membaca data pushrepo", "username", username, "error", err)
 				return
 			}
 			repoCommits[report.Repo]++
@@ -0,0 +1,127 @@
+package respon
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/gocroot/helper/at"
+	"github.com/gocroot/helper/paging"
+	"github.com/gocroot/helper/validasi"
+)
+
+// Success menulis respon sukses. Daftar kosong tetap ditulis sebagai [] agar frontend tidak menerima null.
+func Success(w http.ResponseWriter, status int, message string, data interface{}) {
+	at.WriteJSON(w, status, Envelope{Status: StatusSuccess, Message: message, Data: nonNil(data)})
+}
+
+// Warn menulis respon sukses untuk data yang sudah tersimpan tetapi langkah lanjutannya gagal,
+// agar klien tidak mengulang request dan membuat data ganda
+func Warn(w http.ResponseWriter, status int, message, warning string, data interface{}) {
+	at.WriteJSON(w, status, Envelope{Status: StatusSuccess, Message: message, Warning: warning, Data: nonNil(data)})
+}
+
+// OK menulis respon 200 berisi data tanpa pesan
+func OK(w http.ResponseWriter, data interface{}) {
+	Success(w, http.StatusOK, "", data)
+}
+
+// List menulis satu halaman data beserta meta, header X-Total-Count dan kawan-kawan tetap diisi
+func List(w http.ResponseWriter, data interface{}, meta paging.Meta) {
+	paging.WriteHeaders(w, meta)
+	at.WriteJSON(w, http.StatusOK, Envelope{Status: StatusSuccess, Data: nonNil(data), Meta: newMeta(meta)})
+}
+
+// Error menulis respon gagal dengan code sesuai status HTTP, detail biasanya isi error aslinya
+func Error(w http.ResponseWriter, status int, message, detail string) {
+	Fail(w, status, CodeFor(status), message, detail)
+}
+
+// Fail menulis respon gagal dengan code tertentu, misalnya CodePeriodClosed
+func Fail(w http.ResponseWriter, status int, code, message, detail string) {
+	FailData(w, status, code, message, detail, nil)
+}
+
+// FailData menulis respon gagal beserta data pendukung, misalnya daftar kekurangan stok
+func FailData(w http.ResponseWriter, status int, code, message, detail string, data interface{}) {
+	at.WriteJSON(w, status, Envelope{Status: StatusError, Code: code, Message: message, Detail: detail, Data: data})
+}
+
+// Invalid menulis respon 400 validation_failed berisi kesalahan per field dari validasi.Struct.
+// Error lain ditulis sebagai bad_request dengan pesannya di message.
+func Invalid(w http.ResponseWriter, err error) {
+	var fields validasi.Errors
+	if !errors.As(err, &fields) {
+		Fail(w, http.StatusBadRequest, CodeBadRequest, err.Error(), "")
+		return
+	}
+	at.WriteJSON(w, http.StatusBadRequest, Envelope{
+		Status:  StatusError,
+		Code:    CodeValidation,
+		Message: "Data yang dikirim tidak valid",
+		Errors:  fields,
+	})
+}
+
+// Decode membaca body JSON ke v lalu memeriksa tag validate. Respon error sudah ditulis saat hasilnya false.
+func Decode(w http.ResponseWriter, req *http.Request, v interface{}) bool {
+	if err := json.NewDecoder(req.Body).Decode(v); err != nil {
+		message := "Body JSON tidak valid"
+		if errors.Is(err, io.EOF) {
+			message = "Body JSON wajib diisi"
+		}
+		Fail(w, http.StatusBadRequest, CodeBadRequest, message, err.Error())
+		return false
+	}
+	if err := validasi.Struct(v); err != nil {
+		Invalid(w, err)
+		return false
+	}
+	return true
+}
+
+// CodeFor memetakan status HTTP ke code galat bawaan
+func CodeFor(status int) string {
+	switch status {
+	case http.StatusBadRequest:
+		return CodeBadRequest
+	case http.StatusUnauthorized:
+		return CodeUnauthorized
+	case http.StatusForbidden:
+		return CodeForbidden
+	case http.StatusNotFound:
+		return CodeNotFound
+	case http.StatusMethodNotAllowed:
+		return CodeMethodNotAllowed
+	case http.StatusConflict:
+		return CodeConflict
+	case http.StatusUnprocessableEntity:
+		return CodeValidation
+	case http.StatusTooManyRequests:
+		return CodeTooManyRequests
+	case http.StatusNotImplemented:
+		return CodeNotImplemented
+	case http.StatusBadGateway, http.StatusGatewayTimeout:
+		return CodeUpstream
+	case http.StatusServiceUnavailable:
+		return CodeUnavailable
+	}
+	if status >= 500 {
+		return CodeInternal
+	}
+	return strings.ReplaceAll(strings.ToLower(http.StatusText(status)), " ", "_")
+}
+
+// nonNil mengganti slice nil dengan slice kosong
+func nonNil(data interface{}) interface{} {
+	if data == nil {
+		return nil
+	}
+	if v := reflect.ValueOf(data); v.Kind() == reflect.Slice && v.IsNil() {
+		return reflect.MakeSlice(v.Type(), 0, 0).Interface()
+	}
+	return data
+}
@@ -0,0 +1,121 @@
+package respon
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gocroot/helper/paging"
+)
+
+func decode(t *testing.T, rec *httptest.ResponseRecorder) map[string]interface{} {
+	t.Helper()
+	var body map[string]interface{}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("body bukan JSON: %s", rec.Body)
+	}
+	return body
+}
+
+func TestSuccessEmptyList(t *testing.T) {
+	rec := httptest.NewRecorder()
+	var items []string
+	Success(rec, http.StatusCreated, "Tersimpan", items)
+	if rec.Code != http.StatusCreated || strings.TrimSpace(rec.Body.String()) != `{"status":"success","message":"Tersimpan","data":[]}` {
+		t.Errorf("respon = %d %s", rec.Code, rec.Body)
+	}
+}
+
+func TestWarn(t *testing.T) {
+	rec := httptest.NewRecorder()
+	Warn(rec, http.StatusCreated, "Order diterima", "pesan ke penjual gagal dikirim", nil)
+	body := decode(t, rec)
+	if rec.Code != http.StatusCreated || body["status"] != StatusSuccess || body["warning"] != "pesan ke penjual gagal dikirim" {
+		t.Errorf("respon = %d %s", rec.Code, rec.Body)
+	}
+}
+
+func TestList(t *testing.T) {
+	rec := httptest.NewRecorder()
+	List(rec, []int{1, 2}, paging.Meta{Page: 2, Limit: 2, Total: 5, TotalPages: 3, Counted: true})
+	body := decode(t, rec)
+	meta := body["meta"].(map[string]interface{})
+	if body["status"] != StatusSuccess || meta["total"] != 5.0 || meta["total_pages"] != 3.0 || rec.Header().Get("X-Total-Count") != "5" {
+		t.Errorf("respon = %s %v", rec.Body, rec.Header())
+	}
+}
+
+func TestListCursorWithoutCount(t *testing.T) {
+	rec := httptest.NewRecorder()
+	List(rec, []int{1}, paging.Meta{Page: 1, Limit: 1, NextCursor: "abc"})
+	meta := decode(t, rec)["meta"].(map[string]interface{})
+	if _, ok := meta["total"]; ok || meta["next_cursor"] != "abc" || rec.Header().Get("X-Total-Count") != "" {
+		t.Errorf("respon = %s %v", rec.Body, rec.Header())
+	}
+}
+
+func TestError(t *testing.T) {
+	rec := httptest.NewRecorder()
+	Error(rec, http.StatusNotFound, "Produk tidak ditemukan", "mongo: no documents in result")
+	body := decode(t, rec)
+	if body["status"] != StatusError || body["code"] != CodeNotFound || body["detail"] != "mongo: no documents in result" {
+		t.Errorf("respon = %s", rec.Body)
+	}
+
+	rec = httptest.NewRecorder()
+	FailData(rec, http.StatusConflict, CodeInsufficientStock, "Stok tidak mencukupi", "", []string{"Kopi"})
+	body = decode(t, rec)
+	if rec.Code != http.StatusConflict || body["code"] != CodeInsufficientStock || len(body["data"].([]interface{})) != 1 {
+		t.Errorf("respon = %s", rec.Body)
+	}
+}
+
+func TestDecode(t *testing.T) {
+	type request struct {
+		Name   string  `json:"name" validate:"required"`
+		Amount float64 `json:"amount" validate:"positive"`
+	}
+	cases := []struct {
+		body   string
+		ok     bool
+		code   string
+		fields int
+	}{
+		{`{"name":"Sewa","amount":100}`, true, "", 0},
+		{``, false, CodeBadRequest, 0},
+		{`{"name":`, false, CodeBadRequest, 0},
+		{`{"amount":-1}`, false, CodeValidation, 2},
+	}
+	for _, c := range cases {
+		rec := httptest.NewRecorder()
+		var v request
+		ok := Decode(rec, httptest.NewRequest("POST", "/", strings.NewReader(c.body)), &v)
+		if ok != c.ok {
+			t.Errorf("%q: ok = %v", c.body, ok)
+			continue
+		}
+		if ok {
+			continue
+		}
+		var env Envelope
+		json.Unmarshal(rec.Body.Bytes(), &env)
+		if rec.Code != http.StatusBadRequest || env.Code != c.code || len(env.Errors) != c.fields {
+			t.Errorf("%q: respon = %d %s", c.body, rec.Code, rec.Body)
+		}
+	}
+}
+
+func TestCodeFor(t *testing.T) {
+	for status, want := range map[int]string{
+		http.StatusUnauthorized:        CodeUnauthorized,
+		http.StatusBadGateway:          CodeUpstream,
+		http.StatusInternalServerError: CodeInternal,
+		http.StatusGone:                "gone",
+	} {
+		if got := CodeFor(status); got != want {
+			t.Errorf("CodeFor(%d) = %q, want %q", status, got, want)
+		}
+	}
+}
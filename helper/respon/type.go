@@ -0,0 +1,64 @@
+package respon
+
+import (
+	"github.com/gocroot/helper/paging"
+	"github.com/gocroot/helper/validasi"
+)
+
+// Nilai field status pada envelope
+const (
+	StatusSuccess = "success"
+	StatusError   = "error"
+)
+
+// Kode galat yang bisa dibaca mesin. Frontend memakai code, bukan teks message yang bisa berubah.
+const (
+	CodeBadRequest       = "bad_request"       // body, parameter query atau ID tidak bisa dibaca
+	CodeValidation       = "validation_failed" // isi body tidak lolos validasi, rinciannya di errors
+	CodeUnauthorized     = "unauthorized"
+	CodeForbidden        = "forbidden"
+	CodeNotFound         = "not_found"
+	CodeMethodNotAllowed = "method_not_allowed"
+	CodeConflict         = "conflict"
+	CodeTooManyRequests  = "too_many_requests"
+	CodeInternal         = "internal_error"
+	CodeNotImplemented   = "not_implemented"
+	CodeUpstream         = "upstream_failed" // layanan luar seperti gateway WhatsApp gagal
+	CodeUnavailable      = "service_unavailable"
+
+	// Kode domain akuntansi
+	CodePeriodClosed      = "period_closed"
+	CodeInsufficientStock = "insufficient_stock"
+	CodePromoUsedUp       = "promo_used_up"
+)
+
+// Envelope adalah bentuk semua respon JSON API akuntansi.
+// Sukses: status, message dan data (meta untuk list). Gagal: status, code, message, detail dan errors per field.
+type Envelope struct {
+	Status  string                `json:"status"`
+	Code    string                `json:"code,omitempty"`
+	Message string                `json:"message,omitempty"`
+	Detail  string                `json:"detail,omitempty"`
+	Warning string                `json:"warning,omitempty"` // Langkah lanjutan yang gagal setelah data tersimpan
+	Errors  []validasi.FieldError `json:"errors,omitempty"`
+	Data    interface{}           `json:"data,omitempty"`
+	Meta    *Meta                 `json:"meta,omitempty"`
+}
+
+// Meta adalah info halaman list endpoint, nilainya sama dengan header X-Total-Count dan kawan-kawan.
+// Total dan total_pages tidak dikirim untuk halaman cursor tanpa ?count=1.
+type Meta struct {
+	Page       int64  `json:"page"`
+	Limit      int64  `json:"limit"`
+	Total      *int64 `json:"total,omitempty"`
+	TotalPages *int64 `json:"total_pages,omitempty"`
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+func newMeta(m paging.Meta) *Meta {
+	meta := &Meta{Page: m.Page, Limit: m.Limit, NextCursor: m.NextCursor}
+	if m.Counted {
+		meta.Total, meta.TotalPages = &m.Total, &m.TotalPages
+	}
+	return meta
+}
@@ -0,0 +1,81 @@
+package retur
+
+import (
+	"bytes"
+	"strconv"
+
+	"github.com/gocroot/helper/invoice"
+	"github.com/gocroot/model"
+	"github.com/raykov/gofpdf"
+)
+
+// RenderNote membuat PDF A5 nota kredit untuk retur dengan nota kredit atau nota retur untuk pengembalian uang
+func RenderNote(ret model.SalesReturn, store model.StoreProfile) ([]byte, error) {
+	pdf := gofpdf.New("P", "mm", "A5", "")
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+	pdf.AddPage()
+	width, _ := pdf.GetPageSize()
+	left, _, right, _ := pdf.GetMargins()
+	width -= left + right
+
+	pdf.SetFont("Arial", "B", 13)
+	pdf.CellFormat(width, 7, tr(store.Name), "", 1, "L", false, 0, "")
+	pdf.SetFont("Arial", "", 9)
+	if store.Address != "" {
+		pdf.CellFormat(width, 4, tr(store.Address), "", 1, "L", false, 0, "")
+	}
+	pdf.Ln(3)
+	title := "NOTA RETUR"
+	if ret.Settlement == SettlementCreditNote {
+		title = "NOTA KREDIT"
+	}
+	pdf.SetFont("Arial", "B", 12)
+	pdf.CellFormat(width, 7, title, "", 1, "C", false, 0, "")
+	pdf.Ln(2)
+
+	pdf.SetFont("Arial", "", 9)
+	rows := [][2]string{
+		{"Nomor", ret.ReturnNumber},
+		{"Tanggal", ret.ReturnDate.Format("02-01-2006")},
+		{"Faktur asal", ret.InvoiceNumber},
+		{"Pembeli", ret.CustomerName},
+	}
+	if ret.Reason != "" {
+		rows = append(rows, [2]string{"Alasan", ret.Reason})
+	}
+	for _, row := range rows {
+		pdf.CellFormat(30, 5, row[0], "", 0, "L", false, 0, "")
+		pdf.CellFormat(width-30, 5, ": "+tr(row[1]), "", 1, "L", false, 0, "")
+	}
+	pdf.Ln(3)
+
+	pdf.SetFont("Arial", "B", 9)
+	pdf.SetFillColor(230, 230, 230)
+	pdf.CellFormat(width-60, 6, "Item", "1", 0, "L", true, 0, "")
+	pdf.CellFormat(20, 6, "Jumlah", "1", 0, "R", true, 0, "")
+	pdf.CellFormat(40, 6, "Nilai", "1", 1, "R", true, 0, "")
+	pdf.SetFont("Arial", "", 9)
+	for _, it := range ret.Items {
+		pdf.CellFormat(width-60, 6, tr(it.Name), "1", 0, "L", false, 0, "")
+		pdf.CellFormat(20, 6, strconv.Itoa(it.Quantity), "1", 0, "R", false, 0, "")
+		pdf.CellFormat(40, 6, invoice.Rupiah(it.Amount), "1", 1, "R", false, 0, "")
+	}
+	pdf.Ln(2)
+	if ret.TaxAmount > 0 {
+		pdf.CellFormat(width-40, 5, "PPN yang dibatalkan", "", 0, "R", false, 0, "")
+		pdf.CellFormat(40, 5, invoice.Rupiah(ret.TaxAmount), "", 1, "R", false, 0, "")
+	}
+	pdf.SetFont("Arial", "B", 11)
+	label := "Dikembalikan (" + ret.PaymentMethod + ")"
+	if ret.Settlement == SettlementCreditNote {
+		label = "Mengurangi piutang"
+	}
+	pdf.CellFormat(width-40, 7, tr(label), "T", 0, "R", false, 0, "")
+	pdf.CellFormat(40, 7, invoice.Rupiah(ret.TotalAmount), "T", 1, "R", false, 0, "")
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
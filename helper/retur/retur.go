@@ -0,0 +1,190 @@
+package retur
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gocroot/helper/atdb"
+	"github.com/gocroot/helper/invoice"
+	"github.com/gocroot/helper/ledger"
+	"github.com/gocroot/helper/pajak"
+	"github.com/gocroot/helper/piutang"
+	"github.com/gocroot/helper/stok"
+	"github.com/gocroot/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// Build menghitung retur dari penjualan: nilai per item mengikuti harga setelah diskon dan PPN saat dijual.
+// Jumlah retur per item tidak boleh melebihi sisa yang belum diretur. Nota kredit dibatasi sisa piutang,
+// pengembalian uang dibatasi yang sudah dibayar dikurangi pengembalian sebelumnya.
+func Build(sale model.SalesTransaction, req Request) (ret model.SalesReturn, err error) {
+	if len(sale.Products) == 0 {
+		err = ErrNoItems
+		return
+	}
+	if len(req.Items) == 0 {
+		err = errors.New("item retur wajib diisi")
+		return
+	}
+	ret = model.SalesReturn{
+		Owner:         sale.Owner,
+		SalesID:       sale.ID,
+		InvoiceNumber: sale.InvoiceNumber,
+		CustomerID:    sale.CustomerID,
+		CustomerName:  sale.CustomerName,
+		Settlement:    req.Settlement,
+		PaymentMethod: strings.TrimSpace(req.PaymentMethod),
+		Reason:        strings.TrimSpace(req.Reason),
+	}
+
+	requested := make(map[int]int)
+	for _, it := range req.Items {
+		if it.Line < 0 || it.Line >= len(sale.Products) {
+			err = fmt.Errorf("item baris %d tidak ada pada penjualan", it.Line)
+			return
+		}
+		p := sale.Products[it.Line]
+		sold := p.Quantity
+		if sold <= 0 {
+			sold = 1
+		}
+		requested[it.Line] += it.Quantity
+		if it.Quantity <= 0 || requested[it.Line]+p.Returned > sold {
+			err = fmt.Errorf("%s: jumlah retur harus antara 1 dan %d", p.Name, sold-p.Returned)
+			return
+		}
+
+		share := float64(it.Quantity) / float64(sold)
+		value := (p.Price*float64(sold) - p.Discount) * share
+		item := model.ReturnItem{Line: it.Line, ProductID: p.ID, Name: p.Name, Quantity: it.Quantity, Cost: p.Cost, Damaged: it.Damaged}
+		switch {
+		case sale.TaxAmount > 0 && p.TaxRate != nil && sale.PriceIncludesTax:
+			item.Amount = ledger.Round2(value)
+			_, item.TaxAmount = pajak.Split(item.Amount, *p.TaxRate, true)
+		case sale.TaxAmount > 0 && p.TaxRate != nil:
+			base := ledger.Round2(value)
+			_, item.TaxAmount = pajak.Split(base, *p.TaxRate, false)
+			item.Amount = ledger.Round2(base + item.TaxAmount)
+		default:
+			item.Amount = ledger.Round2(value)
+		}
+		ret.Items = append(ret.Items, item)
+		ret.TotalAmount += item.Amount
+		ret.TaxAmount += item.TaxAmount
+		if !it.Damaged {
+			ret.Cost += p.Cost * float64(it.Quantity)
+		}
+	}
+	ret.TotalAmount = ledger.Round2(ret.TotalAmount)
+	ret.TaxAmount = ledger.Round2(ret.TaxAmount)
+	ret.Cost = ledger.Round2(ret.Cost)
+	if ret.TotalAmount <= 0 {
+		err = errors.New("nilai retur harus lebih dari nol")
+		return
+	}
+	if remaining := ledger.Round2(sale.TotalAmount - sale.ReturnedAmount); ret.TotalAmount > remaining {
+		err = fmt.Errorf("nilai retur melebihi sisa nilai penjualan %.2f", remaining)
+		return
+	}
+
+	outstanding := piutang.Outstanding(sale)
+	switch req.Settlement {
+	case SettlementCreditNote:
+		if ret.TotalAmount > outstanding {
+			err = fmt.Errorf("nota kredit melebihi sisa piutang %.2f, gunakan refund", outstanding)
+		}
+	case SettlementRefund:
+		paid := sale.TotalAmount - sale.CreditedAmount - outstanding
+		refundable := ledger.Round2(paid - (sale.ReturnedAmount - sale.CreditedAmount))
+		if ret.TotalAmount > refundable {
+			err = fmt.Errorf("pengembalian uang melebihi yang sudah dibayar %.2f, gunakan credit_note", refundable)
+		}
+		if ret.PaymentMethod == "" {
+			ret.PaymentMethod = sale.PaymentMethod
+		}
+	default:
+		err = errors.New("settlement harus refund atau credit_note")
+	}
+	return
+}
+
+// Create menyimpan retur penjualan: menandai item yang diretur pada penjualan, mengembalikan barang ke stok
+// dan memposting jurnal retur. Nota kredit mengurangi piutang sehingga status pembayaran penjualan ikut diperbarui.
+func Create(db *mongo.Database, sale model.SalesTransaction, req Request, createdBy string) (ret model.SalesReturn, err error) {
+	if ret, err = Build(sale, req); err != nil {
+		return
+	}
+	ret.ID = primitive.NewObjectID()
+	ret.ReturnDate = time.Now()
+	ret.CreatedAt = ret.ReturnDate
+	ret.CreatedBy = createdBy
+	if ret.ReturnNumber, err = invoice.NextReturnNumber(db, sale.Owner, ret.ReturnDate); err != nil {
+		return
+	}
+
+	// Filter returned_amount lama mencegah dua retur bersamaan melebihi jumlah yang dijual
+	filter := bson.M{"_id": sale.ID, "owner": sale.Owner, "returned_amount": sale.ReturnedAmount}
+	if sale.ReturnedAmount == 0 {
+		filter["returned_amount"] = bson.M{"$in": bson.A{0, nil}}
+	}
+	set := bson.M{"returned_amount": ledger.Round2(sale.ReturnedAmount + ret.TotalAmount)}
+	undo := bson.M{"returned_amount": sale.ReturnedAmount}
+	for _, it := range ret.Items {
+		key := fmt.Sprintf("products.%d.returned", it.Line)
+		set[key] = sale.Products[it.Line].Returned + returnedQty(ret, it.Line)
+		undo[key] = sale.Products[it.Line].Returned
+	}
+	if ret.Settlement == SettlementCreditNote {
+		credited := ledger.Round2(sale.CreditedAmount + ret.TotalAmount)
+		set["credited_amount"] = credited
+		set["payment_status"] = piutang.StatusPaid
+		if ledger.Round2(sale.TotalAmount-credited-sale.PaidAmount) > 0 {
+			set["payment_status"] = piutang.Status(sale.TotalAmount-credited, sale.PaidAmount)
+		}
+		undo["credited_amount"] = sale.CreditedAmount
+		undo["payment_status"] = sale.PaymentStatus
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	result, err := db.Collection(SalesCollection).UpdateOne(ctx, filter, bson.M{"$set": set})
+	if err != nil {
+		return
+	}
+	if result.MatchedCount == 0 {
+		err = ErrConcurrentReturn
+		return
+	}
+	if _, err = atdb.InsertOneDoc(db, ReturnCollection, ret); err != nil {
+		db.Collection(SalesCollection).UpdateOne(context.Background(), bson.M{"_id": sale.ID}, bson.M{"$set": undo})
+		return
+	}
+
+	for _, it := range ret.Items {
+		if it.Damaged || it.ProductID.IsZero() {
+			continue
+		}
+		_, err = stok.Move(db, sale.Owner, it.ProductID, it.Quantity, stok.MoveReturn, "sales_return", ret.ID, "Retur "+ret.ReturnNumber)
+		if err != nil && err != mongo.ErrNoDocuments {
+			return
+		}
+		err = nil
+	}
+	_, err = ledger.Post(db, ledger.ReturnEntry(ret, ret.Settlement == SettlementRefund))
+	return
+}
+
+// returnedQty menjumlahkan unit yang diretur pada satu baris penjualan
+func returnedQty(ret model.SalesReturn, line int) (qty int) {
+	for _, it := range ret.Items {
+		if it.Line == line {
+			qty += it.Quantity
+		}
+	}
+	return
+}
@@ -0,0 +1,79 @@
+package retur
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/gocroot/model"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func sale() model.SalesTransaction {
+	rate := 11.0
+	return model.SalesTransaction{
+		ID:            primitive.NewObjectID(),
+		InvoiceNumber: "INV/2026/000001",
+		CustomerName:  "Sari",
+		Products: []model.Product{
+			{ID: primitive.NewObjectID(), Name: "Kopi", Price: 20000, Quantity: 4, Discount: 8000, Cost: 8000, TaxRate: &rate},
+			{ID: primitive.NewObjectID(), Name: "Roti", Price: 10000, Quantity: 2, Cost: 4000, TaxRate: &rate},
+		},
+		TotalAmount:   101010, // DPP 72.000 + 19.000, PPN 10.010
+		TaxAmount:     10010,
+		PaymentMethod: "tunai",
+		PaymentStatus: "paid",
+		PaidAmount:    101010,
+	}
+}
+
+func TestBuildPartialRefund(t *testing.T) {
+	ret, err := Build(sale(), Request{Settlement: SettlementRefund, Items: []RequestItem{{Line: 0, Quantity: 2}, {Line: 1, Quantity: 1, Damaged: true}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Kopi: (80.000 - 8.000) / 2 = 36.000 + PPN 3.960, Roti: 10.000 + PPN 1.100
+	if ret.TotalAmount != 51060 || ret.TaxAmount != 5060 {
+		t.Errorf("total %v pajak %v", ret.TotalAmount, ret.TaxAmount)
+	}
+	// Roti rusak tidak kembali ke stok sehingga harga pokoknya tidak dihitung
+	if ret.Cost != 16000 || ret.PaymentMethod != "tunai" {
+		t.Errorf("cost %v method %q", ret.Cost, ret.PaymentMethod)
+	}
+}
+
+func TestBuildRejects(t *testing.T) {
+	s := sale()
+	s.Products[0].Returned = 3
+	cases := map[string]Request{
+		"melebihi sisa":     {Settlement: SettlementRefund, Items: []RequestItem{{Line: 0, Quantity: 2}}},
+		"baris tidak ada":   {Settlement: SettlementRefund, Items: []RequestItem{{Line: 5, Quantity: 1}}},
+		"tanpa item":        {Settlement: SettlementRefund},
+		"nota kredit lunas": {Settlement: SettlementCreditNote, Items: []RequestItem{{Line: 1, Quantity: 1}}},
+		"settlement":        {Settlement: "tukar", Items: []RequestItem{{Line: 1, Quantity: 1}}},
+	}
+	for name, req := range cases {
+		if _, err := Build(s, req); err == nil {
+			t.Errorf("%s: harus ditolak", name)
+		}
+	}
+	if _, err := Build(model.SalesTransaction{TotalAmount: 1000}, Request{Settlement: SettlementRefund, Items: []RequestItem{{Quantity: 1}}}); err != ErrNoItems {
+		t.Errorf("err = %v", err)
+	}
+}
+
+func TestBuildCreditNote(t *testing.T) {
+	s := sale()
+	s.PaymentStatus, s.PaidAmount = "unpaid", 0
+	ret, err := Build(s, Request{Settlement: SettlementCreditNote, Items: []RequestItem{{Line: 1, Quantity: 2}}})
+	if err != nil || ret.TotalAmount != 22200 {
+		t.Fatalf("ret = %+v err = %v", ret, err)
+	}
+	if _, err = Build(s, Request{Settlement: SettlementRefund, Items: []RequestItem{{Line: 1, Quantity: 2}}}); err == nil {
+		t.Error("refund penjualan yang belum dibayar harus ditolak")
+	}
+
+	pdf, err := RenderNote(ret, model.StoreProfile{Name: "Toko"})
+	if err != nil || !bytes.HasPrefix(pdf, []byte("%PDF")) {
+		t.Errorf("pdf err = %v", err)
+	}
+}
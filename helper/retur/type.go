@@ -0,0 +1,41 @@
+package retur
+
+import (
+	"errors"
+
+	"github.com/gocroot/helper/repo"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Nama koleksi retur dan penjualan
+const (
+	ReturnCollection = repo.ReturnCollection
+	SalesCollection  = repo.SalesCollection
+)
+
+// Cara penyelesaian retur
+const (
+	SettlementRefund     = "refund"      // Uang dikembalikan ke pembeli
+	SettlementCreditNote = "credit_note" // Nota kredit mengurangi piutang penjualan
+)
+
+// Request adalah permintaan retur atas satu penjualan
+type Request struct {
+	SalesID       primitive.ObjectID `json:"sales_id"`
+	Items         []RequestItem      `json:"items" validate:"required"`
+	Settlement    string             `json:"settlement"`               // refund atau credit_note
+	PaymentMethod string             `json:"payment_method,omitempty"` // Metode pengembalian uang, default metode penjualan
+	Reason        string             `json:"reason,omitempty"`
+}
+
+// RequestItem adalah item penjualan yang diretur, ditunjuk dengan urutannya pada penjualan
+type RequestItem struct {
+	Line     int  `json:"line" validate:"nonnegative"`
+	Quantity int  `json:"quantity" validate:"positive"`
+	Damaged  bool `json:"damaged,omitempty"` // Barang rusak tidak dikembalikan ke stok
+}
+
+var (
+	ErrConcurrentReturn = errors.New("penjualan sedang diubah oleh transaksi lain, silakan ulangi")
+	ErrNoItems          = errors.New("penjualan tanpa rincian item tidak bisa diretur per item")
+)
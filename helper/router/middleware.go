@@ -0,0 +1,170 @@
+package router
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gocroot/helper/logger"
+	"github.com/gocroot/helper/metrik"
+	"github.com/gocroot/helper/respon"
+	"golang.org/x/time/rate"
+)
+
+// Logging memberi setiap request ID (dari header X-Request-ID atau dibuat baru) yang disimpan ke context
+// dan dikirim balik di header respon, lalu mencatat method, pola route, status dan durasi ke log dan metrik
+var Logging = Middleware{Name: "logging", Wrap: func(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		id := r.Header.Get(logger.HeaderRequestID)
+		if id == "" || len(id) > 64 {
+			id = logger.NewRequestID()
+		}
+		w.Header().Set(logger.HeaderRequestID, id)
+		slot := &matched{}
+		ctx := context.WithValue(logger.WithRequestID(r.Context(), id), matchedKey{}, slot)
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(rec, r.WithContext(ctx))
+
+		route := slot.route.Path
+		if route == "" {
+			route = "unmatched"
+		}
+		elapsed := time.Since(start)
+		metrik.ObserveRequest(r.Method, route, rec.status, elapsed)
+		log := logger.FromContext(ctx).With("method", r.Method, "path", r.URL.Path, "route", route, "status", rec.status, "duration", elapsed)
+		switch {
+		case rec.status >= 500:
+			log.Error("request")
+		case rec.status >= 400:
+			log.Warn("request")
+		default:
+			log.Info("request")
+		}
+	})
+}}
+
+// limiterTTL adalah lama IP tanpa request sebelum limiter-nya dibuang dari memori
+const limiterTTL = 10 * time.Minute
+
+// RateLimit membatasi jumlah request per IP klien, kelebihannya dijawab 429.
+// X-Forwarded-For hanya dibaca jika request datang dari salah satu proxy, lihat ParseProxies.
+func RateLimit(limit rate.Limit, burst int, proxies []*net.IPNet) Middleware {
+	l := newIPLimiter(limit, burst, limiterTTL)
+	return Middleware{Name: "ratelimit", Wrap: func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !l.get(ClientIP(r, proxies), time.Now()).Allow() {
+				w.Header().Set("Retry-After", "1")
+				respon.Error(w, http.StatusTooManyRequests, "Terlalu banyak permintaan", "Tunggu sebentar lalu coba lagi")
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}}
+}
+
+// ipLimiter menyimpan limiter per IP dan membuang IP yang tidak aktif selama ttl agar memori tidak terus bertambah
+type ipLimiter struct {
+	mu        sync.Mutex
+	limit     rate.Limit
+	burst     int
+	ttl       time.Duration
+	entries   map[string]*ipEntry
+	lastSweep time.Time
+}
+
+type ipEntry struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+func newIPLimiter(limit rate.Limit, burst int, ttl time.Duration) *ipLimiter {
+	return &ipLimiter{limit: limit, burst: burst, ttl: ttl, entries: map[string]*ipEntry{}}
+}
+
+func (l *ipLimiter) get(ip string, now time.Time) *rate.Limiter {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if now.Sub(l.lastSweep) >= l.ttl {
+		for k, e := range l.entries {
+			if now.Sub(e.lastSeen) >= l.ttl {
+				delete(l.entries, k)
+			}
+		}
+		l.lastSweep = now
+	}
+	e, found := l.entries[ip]
+	if !found {
+		e = &ipEntry{limiter: rate.NewLimiter(l.limit, l.burst)}
+		l.entries[ip] = e
+	}
+	e.lastSeen = now
+	return e.limiter
+}
+
+// ParseProxies membaca daftar IP atau CIDR proxy tepercaya, misalnya dari config TrustedProxies
+func ParseProxies(list []string) (proxies []*net.IPNet, err error) {
+	for _, p := range list {
+		if !strings.Contains(p, "/") {
+			if ip := net.ParseIP(p); ip != nil && ip.To4() != nil {
+				p += "/32"
+			} else {
+				p += "/128"
+			}
+		}
+		var n *net.IPNet
+		if _, n, err = net.ParseCIDR(p); err != nil {
+			return nil, err
+		}
+		proxies = append(proxies, n)
+	}
+	return
+}
+
+// ClientIP mengambil IP klien dari koneksi. Jika koneksi datang dari proxy tepercaya, X-Forwarded-For dibaca dari
+// kanan dan IP pertama yang bukan proxy dipakai, sehingga isi header yang dikarang klien tidak berpengaruh.
+func ClientIP(r *http.Request, proxies []*net.IPNet) string {
+	ip := r.RemoteAddr
+	if host, _, err := net.SplitHostPort(ip); err == nil {
+		ip = host
+	}
+	if !trusted(ip, proxies) {
+		return ip
+	}
+	hops := strings.Split(r.Header.Get("X-Forwarded-For"), ",")
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop := strings.TrimSpace(hops[i])
+		if net.ParseIP(hop) == nil {
+			break
+		}
+		ip = hop
+		if !trusted(hop, proxies) {
+			break
+		}
+	}
+	return ip
+}
+
+func trusted(ip string, proxies []*net.IPNet) bool {
+	parsed := net.ParseIP(ip)
+	for _, p := range proxies {
+		if parsed != nil && p.Contains(parsed) {
+			return true
+		}
+	}
+	return false
+}
+
+// statusRecorder menyimpan status respon untuk log
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (s *statusRecorder) WriteHeader(status int) {
+	s.status = status
+	s.ResponseWriter.WriteHeader(status)
+}
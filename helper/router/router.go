@@ -0,0 +1,158 @@
+package router
+
+import (
+	"context"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/gocroot/helper/respon"
+	"github.com/gorilla/mux"
+)
+
+// Router mencocokkan method dan pola path ke handler, memasang middleware per route
+// dan menjawab 405 beserta header Allow jika path ada tetapi method-nya tidak.
+type Router struct {
+	mux     *mux.Router
+	routes  []Route
+	handler http.Handler
+}
+
+// New membuat router dengan handler 404 dan middleware yang dipasang di semua request
+func New(notFound http.HandlerFunc, global ...Middleware) *Router {
+	rt := &Router{mux: mux.NewRouter()}
+	rt.mux.NotFoundHandler = notFound
+	rt.mux.MethodNotAllowedHandler = http.HandlerFunc(rt.methodNotAllowed)
+	rt.handler = chain(rt.mux, global)
+	return rt
+}
+
+// Add mendaftarkan route. Route ganda untuk method dan path yang sama membuat panic seperti http.ServeMux.
+func (rt *Router) Add(routes ...Route) {
+	for _, route := range routes {
+		for _, existing := range rt.routes {
+			if existing.Method == route.Method && existing.Path == route.Path {
+				panic("router: route ganda " + route.Method + " " + route.Path)
+			}
+		}
+		rt.routes = append(rt.routes, route)
+		rt.mux.Handle(expand(route.Path), withRoute(route, chain(route.Handler, route.Middleware))).Methods(route.Method)
+	}
+}
+
+// ServeHTTP menjalankan middleware global lalu route yang cocok
+func (rt *Router) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	rt.handler.ServeHTTP(w, r)
+}
+
+// Routes mengembalikan tabel route sesuai urutan pendaftaran
+func (rt *Router) Routes() []Info {
+	infos := make([]Info, 0, len(rt.routes))
+	for _, route := range rt.routes {
+		info := Info{Method: route.Method, Path: route.Path}
+		for _, m := range route.Middleware {
+			info.Middleware = append(info.Middleware, m.Name)
+		}
+		infos = append(infos, info)
+	}
+	return infos
+}
+
+// Allowed mengembalikan method yang punya route untuk path request, terurut
+func (rt *Router) Allowed(r *http.Request) (methods []string) {
+	seen := map[string]bool{}
+	for _, route := range rt.routes {
+		if seen[route.Method] {
+			continue
+		}
+		probe := r.Clone(r.Context())
+		probe.Method = route.Method
+		var match mux.RouteMatch
+		if rt.mux.Match(probe, &match) && match.MatchErr == nil {
+			seen[route.Method] = true
+			methods = append(methods, route.Method)
+		}
+	}
+	sort.Strings(methods)
+	return
+}
+
+func (rt *Router) methodNotAllowed(w http.ResponseWriter, r *http.Request) {
+	allow := strings.Join(rt.Allowed(r), ", ")
+	w.Header().Set("Allow", allow)
+	respon.Error(w, http.StatusMethodNotAllowed, "Method "+r.Method+" tidak diizinkan untuk "+r.URL.Path, "Gunakan "+allow)
+}
+
+// Param mengambil parameter path, misalnya id dari /sales/{id:objectid}
+func Param(r *http.Request, name string) string {
+	return mux.Vars(r)[name]
+}
+
+// Current mengembalikan route yang sedang menangani request
+func Current(r *http.Request) (route Route, ok bool) {
+	route, ok = r.Context().Value(routeKey{}).(Route)
+	return
+}
+
+// GET membuat route GET
+func GET(path string, h http.HandlerFunc, mw ...Middleware) Route {
+	return Route{Method: http.MethodGet, Path: path, Handler: h, Middleware: mw}
+}
+
+// POST membuat route POST
+func POST(path string, h http.HandlerFunc, mw ...Middleware) Route {
+	return Route{Method: http.MethodPost, Path: path, Handler: h, Middleware: mw}
+}
+
+// PUT membuat route PUT
+func PUT(path string, h http.HandlerFunc, mw ...Middleware) Route {
+	return Route{Method: http.MethodPut, Path: path, Handler: h, Middleware: mw}
+}
+
+// DELETE membuat route DELETE
+func DELETE(path string, h http.HandlerFunc, mw ...Middleware) Route {
+	return Route{Method: http.MethodDelete, Path: path, Handler: h, Middleware: mw}
+}
+
+// With memasang middleware di depan middleware milik setiap route
+func With(mw []Middleware, routes ...Route) []Route {
+	out := make([]Route, len(routes))
+	for i, route := range routes {
+		route.Middleware = append(append([]Middleware{}, mw...), route.Middleware...)
+		out[i] = route
+	}
+	return out
+}
+
+// chain memasang middleware sehingga middleware pertama dijalankan paling awal
+func chain(h http.Handler, mw []Middleware) http.Handler {
+	for i := len(mw) - 1; i >= 0; i-- {
+		h = mw[i].Wrap(h)
+	}
+	return h
+}
+
+// withRoute menyimpan route ke context agar middleware bisa membaca pola path-nya,
+// middleware global seperti Logging menerimanya lewat slot matched
+func withRoute(route Route, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if slot, ok := r.Context().Value(matchedKey{}).(*matched); ok {
+			slot.route = route
+		}
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), routeKey{}, route)))
+	})
+}
+
+var typedParam = regexp.MustCompile(`\{(\w+):(\w+)\}`)
+
+// expand mengganti tipe parameter seperti {id:objectid} menjadi regex gorilla/mux
+func expand(path string) string {
+	return typedParam.ReplaceAllStringFunc(path, func(p string) string {
+		m := typedParam.FindStringSubmatch(p)
+		if re, ok := paramTypes[m[2]]; ok {
+			return "{" + m[1] + ":" + re + "}"
+		}
+		return p
+	})
+}
@@ -0,0 +1,178 @@
+package router
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gocroot/helper/logger"
+)
+
+func reply(body string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(body + Param(r, "id")))
+	}
+}
+
+func serve(rt *Router, method, path string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	rt.ServeHTTP(rec, httptest.NewRequest(method, path, nil))
+	return rec
+}
+
+func TestTypedParams(t *testing.T) {
+	rt := New(http.NotFound)
+	rt.Add(
+		GET("/sales/{id:objectid}", reply("sale:")),
+		GET("/sales/invoice", reply("invoice")),
+		POST("/products/{id:objectid}/stock", reply("stock:")),
+		GET("/data/faq/{id}", reply("faq:")),
+	)
+	cases := map[string]string{
+		"GET /sales/65a1b2c3d4e5f60718293a4b":           "sale:65a1b2c3d4e5f60718293a4b",
+		"GET /sales/invoice":                            "invoice",
+		"POST /products/65a1b2c3d4e5f60718293a4b/stock": "stock:65a1b2c3d4e5f60718293a4b",
+		"GET /data/faq/abc":                             "faq:abc",
+	}
+	for req, want := range cases {
+		parts := strings.SplitN(req, " ", 2)
+		if got := serve(rt, parts[0], parts[1]).Body.String(); got != want {
+			t.Errorf("%s = %q, want %q", req, got, want)
+		}
+	}
+	if rec := serve(rt, "GET", "/sales/bukan-id"); rec.Code != http.StatusNotFound {
+		t.Errorf("ID yang bukan ObjectID harus 404, dapat %d", rec.Code)
+	}
+}
+
+func TestMethodNotAllowed(t *testing.T) {
+	rt := New(http.NotFound)
+	rt.Add(
+		GET("/promos", reply("list")),
+		POST("/promos", reply("create")),
+		DELETE("/promos", reply("delete")),
+	)
+	rec := serve(rt, "PATCH", "/promos")
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("status = %d, want 405", rec.Code)
+	}
+	if allow := rec.Header().Get("Allow"); allow != "DELETE, GET, POST" {
+		t.Errorf("Allow = %q", allow)
+	}
+	if !strings.Contains(rec.Body.String(), `"code":"method_not_allowed"`) {
+		t.Errorf("body = %s", rec.Body)
+	}
+	if rec := serve(rt, "GET", "/tidak-ada"); rec.Code != http.StatusNotFound {
+		t.Errorf("path tanpa route harus 404, dapat %d", rec.Code)
+	}
+}
+
+func TestMiddlewareOrderAndIntrospection(t *testing.T) {
+	var order []string
+	mark := func(name string) Middleware {
+		return Middleware{Name: name, Wrap: func(next http.Handler) http.Handler {
+			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				route, _ := Current(r)
+				order = append(order, name+" "+route.Path)
+				next.ServeHTTP(w, r)
+			})
+		}}
+	}
+	rt := New(http.NotFound)
+	rt.Add(With([]Middleware{mark("auth")}, GET("/sales/{id:objectid}", reply(""), mark("tenant")))...)
+	rt.Add(GET("/", reply("home")))
+
+	serve(rt, "GET", "/sales/65a1b2c3d4e5f60718293a4b")
+	if strings.Join(order, ",") != "auth /sales/{id:objectid},tenant /sales/{id:objectid}" {
+		t.Errorf("urutan middleware = %v", order)
+	}
+
+	routes := rt.Routes()
+	if len(routes) != 2 || !routes[0].Uses("auth") || !routes[0].Uses("tenant") || routes[1].Uses("auth") {
+		t.Errorf("Routes = %+v", routes)
+	}
+}
+
+func TestDuplicateRoutePanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("route ganda harus panic")
+		}
+	}()
+	rt := New(http.NotFound)
+	rt.Add(GET("/sales", reply("")), GET("/sales", reply("")))
+}
+
+func TestRateLimit(t *testing.T) {
+	rt := New(http.NotFound)
+	rt.Add(POST("/login", reply("ok"), RateLimit(0, 2, nil)))
+	codes := []int{}
+	for i := 0; i < 3; i++ {
+		codes = append(codes, serve(rt, "POST", "/login").Code)
+	}
+	if codes[0] != http.StatusOK || codes[1] != http.StatusOK || codes[2] != http.StatusTooManyRequests {
+		t.Errorf("status = %v, want 200 200 429", codes)
+	}
+}
+
+func TestClientIP(t *testing.T) {
+	proxies, err := ParseProxies([]string{"10.0.0.0/8", "35.191.0.1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cases := []struct {
+		remote, forwarded, want string
+	}{
+		{"203.0.113.7:5000", "1.2.3.4", "203.0.113.7"},               // klien langsung tidak bisa memalsukan IP
+		{"10.1.2.3:5000", "1.2.3.4", "1.2.3.4"},                      // lewat load balancer
+		{"10.1.2.3:5000", "6.6.6.6, 1.2.3.4, 35.191.0.1", "1.2.3.4"}, // isi paling kiri dikarang klien
+		{"10.1.2.3:5000", "", "10.1.2.3"},
+	}
+	for _, c := range cases {
+		r := httptest.NewRequest("POST", "/login", nil)
+		r.RemoteAddr = c.remote
+		if c.forwarded != "" {
+			r.Header.Set("X-Forwarded-For", c.forwarded)
+		}
+		if got := ClientIP(r, proxies); got != c.want {
+			t.Errorf("ClientIP(%s, %q) = %s, want %s", c.remote, c.forwarded, got, c.want)
+		}
+	}
+	if _, err := ParseProxies([]string{"lb"}); err == nil {
+		t.Error("proxy bukan IP harus error")
+	}
+}
+
+func TestIPLimiterEvictsIdle(t *testing.T) {
+	l := newIPLimiter(1, 1, time.Minute)
+	start := time.Now()
+	l.get("1.1.1.1", start)
+	l.get("2.2.2.2", start.Add(50*time.Second))
+	l.get("3.3.3.3", start.Add(70*time.Second))
+	if _, found := l.entries["1.1.1.1"]; found || len(l.entries) != 2 {
+		t.Errorf("entries = %v", l.entries)
+	}
+}
+
+func TestLoggingRequestID(t *testing.T) {
+	var seen string
+	rt := New(http.NotFound, Logging)
+	rt.Add(GET("/sales/{id:objectid}", func(w http.ResponseWriter, r *http.Request) {
+		seen = logger.RequestID(r.Context())
+	}))
+
+	req := httptest.NewRequest("GET", "/sales/65a1b2c3d4e5f60718293a4b", nil)
+	req.Header.Set(logger.HeaderRequestID, "dari-gateway")
+	rec := httptest.NewRecorder()
+	rt.ServeHTTP(rec, req)
+	if seen != "dari-gateway" || rec.Header().Get(logger.HeaderRequestID) != "dari-gateway" {
+		t.Errorf("request ID dari header = %q, respon %q", seen, rec.Header().Get(logger.HeaderRequestID))
+	}
+
+	rec = serve(rt, "GET", "/tidak-ada")
+	if id := rec.Header().Get(logger.HeaderRequestID); len(id) != 16 {
+		t.Errorf("request tanpa header harus mendapat ID baru, dapat %q", id)
+	}
+}
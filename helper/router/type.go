@@ -0,0 +1,50 @@
+package router
+
+import "net/http"
+
+// Middleware membungkus handler route. Name dipakai saat tabel route ditampilkan.
+type Middleware struct {
+	Name string
+	Wrap func(http.Handler) http.Handler
+}
+
+// Route adalah satu baris tabel route. Path memakai parameter {nama} atau {nama:tipe},
+// tipe bisa objectid, int atau regex.
+type Route struct {
+	Method     string
+	Path       string
+	Handler    http.HandlerFunc
+	Middleware []Middleware
+}
+
+// Info adalah ringkasan route untuk introspeksi tabel route
+type Info struct {
+	Method     string   `json:"method"`
+	Path       string   `json:"path"`
+	Middleware []string `json:"middleware,omitempty"`
+}
+
+// Uses memeriksa apakah route memakai middleware dengan nama tersebut
+func (i Info) Uses(name string) bool {
+	for _, m := range i.Middleware {
+		if m == name {
+			return true
+		}
+	}
+	return false
+}
+
+// paramTypes adalah tipe parameter path yang bisa dipakai di pola route
+var paramTypes = map[string]string{
+	"objectid": "[0-9a-fA-F]{24}",
+	"int":      "[0-9]+",
+}
+
+type routeKey struct{}
+
+// matched diisi route yang cocok agar middleware global bisa membacanya setelah handler selesai
+type matched struct {
+	route Route
+}
+
+type matchedKey struct{}
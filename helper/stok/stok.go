@@ -0,0 +1,356 @@
+package stok
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/gocroot/helper/atdb"
+	"github.com/gocroot/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var ErrInsufficientStock = errors.New("stok tidak mencukupi")
+
+// IsValidType memeriksa jenis mutasi stok
+func IsValidType(movType string) bool {
+	switch movType {
+	case MoveSale, MovePurchase, MoveAdjustment, MoveReturn, MoveOpening:
+		return true
+	}
+	return false
+}
+
+// incStock menambah atau mengurangi stok produk milik owner secara atomik. Jika requireStock bernilai true,
+// pengurangan hanya terjadi bila stok saat ini mencukupi sehingga stok tidak pernah minus.
+func incStock(db *mongo.Database, owner string, productID primitive.ObjectID, delta int, requireStock bool) (product model.Product, err error) {
+	filter := bson.M{"_id": productID, "owner": owner}
+	if requireStock && delta < 0 {
+		filter["stock"] = bson.M{"$gte": -delta}
+	}
+	update := bson.M{"$inc": bson.M{"stock": delta}, "$set": bson.M{"updatedAt": time.Now()}}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	err = db.Collection(ProductCollection).FindOneAndUpdate(ctx, filter, update, opts).Decode(&product)
+	if err == mongo.ErrNoDocuments && requireStock && delta < 0 {
+		// Bedakan produk yang tidak ada dengan stok yang kurang
+		if _, errGet := atdb.GetOneDoc[model.Product](db, ProductCollection, bson.M{"_id": productID, "owner": owner}); errGet == nil {
+			err = ErrInsufficientStock
+		}
+	}
+	return
+}
+
+func recordMovement(db *mongo.Database, product model.Product, delta int, movType, sourceType string, sourceID primitive.ObjectID, notes string) (mv model.StockMovement, err error) {
+	mv = model.StockMovement{
+		ID:          primitive.NewObjectID(),
+		Owner:       product.Owner,
+		ProductID:   product.ID,
+		ProductName: product.Name,
+		Type:        movType,
+		Quantity:    delta,
+		StockAfter:  product.Stock,
+		UnitCost:    product.Cost,
+		SourceType:  sourceType,
+		SourceID:    sourceID,
+		Notes:       notes,
+		CreatedAt:   time.Now(),
+	}
+	_, err = atdb.InsertOneDoc(db, MovementCollection, mv)
+	return
+}
+
+// Move mengubah stok satu produk milik owner secara atomik dan mencatat mutasinya.
+// Pengurangan stok ditolak dengan ErrInsufficientStock jika stok tidak cukup.
+func Move(db *mongo.Database, owner string, productID primitive.ObjectID, delta int, movType, sourceType string, sourceID primitive.ObjectID, notes string) (mv model.StockMovement, err error) {
+	if !IsValidType(movType) {
+		err = errors.New("jenis mutasi stok tidak valid")
+		return
+	}
+	if delta == 0 {
+		err = errors.New("jumlah mutasi stok tidak boleh nol")
+		return
+	}
+	product, err := incStock(db, owner, productID, delta, true)
+	if err != nil {
+		return
+	}
+	return recordMovement(db, product, delta, movType, sourceType, sourceID, notes)
+}
+
+// ReceivePurchase menambah stok dari pembelian dan memperbarui harga pokok dengan rata-rata tertimbang secara atomik
+func ReceivePurchase(db *mongo.Database, owner string, productID primitive.ObjectID, qty int, unitCost float64, sourceID primitive.ObjectID, notes string) (mv model.StockMovement, err error) {
+	if qty <= 0 {
+		err = errors.New("jumlah penerimaan harus lebih dari nol")
+		return
+	}
+	oldStock := bson.M{"$max": bson.A{bson.M{"$ifNull": bson.A{"$stock", 0}}, 0}}
+	oldValue := bson.M{"$multiply": bson.A{oldStock, bson.M{"$ifNull": bson.A{"$cost", 0}}}}
+	newCost := bson.M{"$divide": bson.A{
+		bson.M{"$add": bson.A{oldValue, float64(qty) * unitCost}},
+		bson.M{"$add": bson.A{oldStock, qty}},
+	}}
+	update := mongo.Pipeline{
+		{{Key: "$set", Value: bson.M{
+			"cost":      newCost,
+			"stock":     bson.M{"$add": bson.A{bson.M{"$ifNull": bson.A{"$stock", 0}}, qty}},
+			"updatedAt": time.Now(),
+		}}},
+	}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var product model.Product
+	err = db.Collection(ProductCollection).FindOneAndUpdate(ctx, bson.M{"_id": productID, "owner": owner}, update, opts).Decode(&product)
+	if err != nil {
+		return
+	}
+	// Mutasi mencatat harga beli, bukan harga pokok rata-rata
+	product.Cost = unitCost
+	return recordMovement(db, product, qty, MovePurchase, "purchase_order", sourceID, notes)
+}
+
+// RecordSet mencatat mutasi penyesuaian saat stok diset langsung ke nilai tertentu
+func RecordSet(db *mongo.Database, before, after model.Product, notes string) (err error) {
+	delta := after.Stock - before.Stock
+	if delta == 0 {
+		return
+	}
+	movType := MoveAdjustment
+	if before.ID.IsZero() {
+		movType = MoveOpening
+	}
+	_, err = recordMovement(db, after, delta, movType, "product", after.ID, notes)
+	return
+}
+
+// ReserveSale mengurangi stok semua item penjualan sekaligus.
+// Jika ada item yang stoknya kurang dan backorder tidak diizinkan, semua pengurangan dibatalkan dan shortages dikembalikan.
+// Jika backorder diizinkan, stok boleh minus dan item ditandai Backorder.
+// Harga pokok dan nama item yang kosong diisi dari data produk.
+func ReserveSale(db *mongo.Database, trx *model.SalesTransaction) (shortages []Shortage, err error) {
+	type applied struct {
+		index   int
+		delta   int
+		product model.Product
+	}
+	var done []applied
+	var recorded []primitive.ObjectID
+	rollback := func() {
+		for _, a := range done {
+			incStock(db, trx.Owner, a.product.ID, -a.delta, false)
+		}
+	}
+
+	for i, item := range trx.Products {
+		if item.ID.IsZero() {
+			continue
+		}
+		qty := saleQty(item)
+		var product model.Product
+		product, err = incStock(db, trx.Owner, item.ID, -qty, true)
+		if err == ErrInsufficientStock {
+			var current model.Product
+			current, err = atdb.GetOneDoc[model.Product](db, ProductCollection, bson.M{"_id": item.ID, "owner": trx.Owner})
+			if err != nil {
+				rollback()
+				return
+			}
+			if !trx.AllowBackorder {
+				shortages = append(shortages, Shortage{ProductID: item.ID, Name: current.Name, Requested: qty, Available: current.Stock})
+				continue
+			}
+			product, err = incStock(db, trx.Owner, item.ID, -qty, false)
+			if err == nil {
+				available := current.Stock
+				if available < 0 {
+					available = 0
+				}
+				trx.Products[i].Backorder = qty - available
+				trx.Backorder = true
+			}
+		}
+		if err != nil {
+			rollback()
+			return
+		}
+		done = append(done, applied{index: i, delta: -qty, product: product})
+	}
+
+	if len(shortages) > 0 {
+		rollback()
+		err = ErrInsufficientStock
+		return
+	}
+
+	for _, a := range done {
+		item := &trx.Products[a.index]
+		if item.Cost == 0 {
+			item.Cost = a.product.Cost
+		}
+		if item.Name == "" {
+			item.Name = a.product.Name
+		}
+		var mv model.StockMovement
+		if mv, err = recordMovement(db, a.product, a.delta, MoveSale, "sales", trx.ID, "Penjualan kepada "+trx.CustomerName); err != nil {
+			rollback()
+			dropMovements(db, recorded)
+			return
+		}
+		recorded = append(recorded, mv.ID)
+	}
+	return
+}
+
+// RestockSale mengembalikan stok semua item penjualan, misalnya saat penjualan dibatalkan
+func RestockSale(db *mongo.Database, trx model.SalesTransaction, notes string) (err error) {
+	for _, item := range trx.Products {
+		if item.ID.IsZero() {
+			continue
+		}
+		qty := saleQty(item)
+		if _, err = Move(db, trx.Owner, item.ID, qty, MoveReturn, "sales", trx.ID, notes); err != nil && err != mongo.ErrNoDocuments {
+			return
+		}
+		err = nil
+	}
+	return
+}
+
+// AdjustSale memindahkan stok sebesar selisih kuantitas item saat penjualan before diubah menjadi after.
+// Tambahan kuantitas ditolak dengan ErrInsufficientStock beserta shortages jika stok kurang, kecuali
+// after.AllowBackorder, dan semua perubahan stok dibatalkan. Nama dan harga pokok item yang kosong diisi dari data produk.
+func AdjustSale(db *mongo.Database, before model.SalesTransaction, after *model.SalesTransaction) (shortages []Shortage, err error) {
+	deltas := map[primitive.ObjectID]int{}
+	var ids []primitive.ObjectID
+	collect := func(items []model.Product, sign int) {
+		for _, item := range items {
+			if item.ID.IsZero() {
+				continue
+			}
+			if _, found := deltas[item.ID]; !found {
+				ids = append(ids, item.ID)
+			}
+			deltas[item.ID] += sign * saleQty(item)
+		}
+	}
+	collect(before.Products, 1)
+	collect(after.Products, -1)
+
+	products := map[primitive.ObjectID]model.Product{}
+	var done, recorded []primitive.ObjectID
+	rollback := func() {
+		for _, id := range done {
+			incStock(db, after.Owner, id, -deltas[id], false)
+		}
+	}
+	for _, id := range ids {
+		delta := deltas[id]
+		var product model.Product
+		if delta == 0 {
+			if product, err = atdb.GetOneDoc[model.Product](db, ProductCollection, bson.M{"_id": id, "owner": after.Owner}); err == nil {
+				products[id] = product
+			}
+			err = nil
+			continue
+		}
+		product, err = incStock(db, after.Owner, id, delta, true)
+		if err == ErrInsufficientStock {
+			var current model.Product
+			current, err = atdb.GetOneDoc[model.Product](db, ProductCollection, bson.M{"_id": id, "owner": after.Owner})
+			if err != nil {
+				rollback()
+				return
+			}
+			if !after.AllowBackorder {
+				shortages = append(shortages, Shortage{ProductID: id, Name: current.Name, Requested: -delta, Available: current.Stock})
+				continue
+			}
+			if product, err = incStock(db, after.Owner, id, delta, false); err == nil {
+				available := current.Stock
+				if available < 0 {
+					available = 0
+				}
+				markBackorder(after, id, -delta-available)
+			}
+		}
+		if err == mongo.ErrNoDocuments && delta > 0 {
+			// Produk yang sudah dihapus tidak perlu menerima stok kembali
+			err = nil
+			continue
+		}
+		if err != nil {
+			rollback()
+			return
+		}
+		done = append(done, id)
+		products[id] = product
+	}
+
+	if len(shortages) > 0 {
+		rollback()
+		err = ErrInsufficientStock
+		return
+	}
+
+	for i := range after.Products {
+		item := &after.Products[i]
+		product, found := products[item.ID]
+		if !found {
+			continue
+		}
+		if item.Cost == 0 {
+			item.Cost = product.Cost
+		}
+		if item.Name == "" {
+			item.Name = product.Name
+		}
+	}
+	for _, id := range done {
+		movType := MoveSale
+		if deltas[id] > 0 {
+			movType = MoveReturn
+		}
+		var mv model.StockMovement
+		if mv, err = recordMovement(db, products[id], deltas[id], movType, "sales", after.ID, "Perubahan penjualan kepada "+after.CustomerName); err != nil {
+			rollback()
+			dropMovements(db, recorded)
+			return
+		}
+		recorded = append(recorded, mv.ID)
+	}
+	return
+}
+
+// dropMovements menghapus mutasi yang sudah tercatat saat perubahan stoknya dibatalkan,
+// sehingga kartu stok tetap sama dengan stok produk
+func dropMovements(db *mongo.Database, ids []primitive.ObjectID) {
+	if len(ids) > 0 {
+		atdb.DeleteManyDocs(db, MovementCollection, bson.M{"_id": bson.M{"$in": ids}})
+	}
+}
+
+// markBackorder menandai item pertama dengan produk id sebagai menunggu stok sebanyak qty
+func markBackorder(trx *model.SalesTransaction, id primitive.ObjectID, qty int) {
+	for i := range trx.Products {
+		if trx.Products[i].ID == id {
+			trx.Products[i].Backorder = qty
+			trx.Backorder = true
+			return
+		}
+	}
+}
+
+// saleQty adalah kuantitas item penjualan, item tanpa kuantitas dihitung satu unit
+func saleQty(item model.Product) int {
+	if item.Quantity <= 0 {
+		return 1
+	}
+	return item.Quantity
+}
@@ -0,0 +1,29 @@
+package stok
+
+import (
+	"github.com/gocroot/helper/repo"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Nama koleksi produk dan mutasi stok
+const (
+	ProductCollection  = repo.ProductCollection
+	MovementCollection = repo.MovementCollection
+)
+
+// Jenis mutasi stok
+const (
+	MoveSale       = "sale"
+	MovePurchase   = "purchase"
+	MoveAdjustment = "adjustment"
+	MoveReturn     = "return"
+	MoveOpening    = "opening"
+)
+
+// Shortage adalah item penjualan yang stoknya tidak mencukupi
+type Shortage struct {
+	ProductID primitive.ObjectID `json:"product_id"`
+	Name      string             `json:"name"`
+	Requested int                `json:"requested"`
+	Available int                `json:"available"`
+}
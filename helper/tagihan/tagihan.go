@@ -0,0 +1,140 @@
+package tagihan
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gocroot/config"
+	"github.com/gocroot/helper/atapi"
+	"github.com/gocroot/helper/atdb"
+	"github.com/gocroot/helper/audit"
+	"github.com/gocroot/helper/invoice"
+	"github.com/gocroot/helper/ledger"
+	"github.com/gocroot/helper/repo"
+	"github.com/gocroot/model"
+	"github.com/whatsauth/itmodel"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// ErrNoPhone dikembalikan CustomerPhone jika pelanggan belum memiliki nomor WhatsApp
+var ErrNoPhone = errors.New("belum memiliki nomor telepon")
+
+// CustomerPhone mencari nomor WhatsApp pelanggan dari customer_id, atau dari nama pelanggan untuk penjualan lama
+func CustomerPhone(db *mongo.Database, trx model.SalesTransaction) (phone string, err error) {
+	filter := bson.M{"owner": trx.Owner, "name": trx.CustomerName}
+	if !trx.CustomerID.IsZero() {
+		filter = bson.M{"owner": trx.Owner, "_id": trx.CustomerID}
+	}
+	customer, err := atdb.GetOneDoc[model.Customer](db, repo.CustomerCollection, filter)
+	if err != nil {
+		return
+	}
+	phone = invoice.NormalizePhone(customer.Phone)
+	if phone == "" {
+		err = fmt.Errorf("pelanggan %s %w", customer.Name, ErrNoPhone)
+	}
+	return
+}
+
+// Send mengirim PDF faktur ke nomor WhatsApp dengan caption
+func Send(db *mongo.Database, trx *model.SalesTransaction, store model.StoreProfile, phone, caption string) (err error) {
+	if err = invoice.Assign(db, trx); err != nil {
+		return
+	}
+	pdf, err := invoice.Render(*trx, store, invoice.LayoutA4)
+	if err != nil {
+		return
+	}
+	dt := &itmodel.DocumentMessage{
+		To:        invoice.NormalizePhone(phone),
+		IsGroup:   false,
+		Base64Doc: base64.StdEncoding.EncodeToString(pdf),
+		Filename:  strings.ReplaceAll(trx.InvoiceNumber, "/", "-") + ".pdf",
+		Caption:   caption,
+	}
+	status, resp, err := atapi.PostStructWithToken[model.Response]("Token", config.WAAPIToken, dt, config.WAAPIDocMessage)
+	if err == nil {
+		err = gatewayError(status, resp)
+	}
+	return
+}
+
+// gatewayError mengubah jawaban gateway WhatsApp selain 2xx menjadi error agar faktur tidak dianggap terkirim
+func gatewayError(status int, resp model.Response) error {
+	if status >= 200 && status < 300 {
+		return nil
+	}
+	return fmt.Errorf("gateway WhatsApp menjawab %d: %s %s", status, resp.Status, resp.Response)
+}
+
+// SendInvoice mengirim faktur penjualan ke pelanggan memakai template kirimfaktur. Nomor kosong berarti nomor pelanggan.
+func SendInvoice(db *mongo.Database, trx *model.SalesTransaction, phone string) (err error) {
+	if phone == "" {
+		if phone, err = CustomerPhone(db, *trx); err != nil {
+			return
+		}
+	}
+	store := invoice.Store(db, trx.Owner)
+	return Send(db, trx, store, phone, invoice.FillTemplate(invoice.Template(db, invoice.TemplateInvoice, store), *trx, store))
+}
+
+// KirimPengingatPiutang dijalankan oleh cron untuk mengirim faktur beserta pengingat ke pelanggan yang piutangnya lewat N hari.
+// Setiap akun penjual memakai reminder_days dan template dari profil tokonya sendiri.
+func KirimPengingatPiutang(db *mongo.Database) (err error) {
+	filter := audit.Live(bson.M{"payment_status": bson.M{"$in": ledger.CreditStatuses}})
+	owners, err := atdb.GetAllDistinct[string](db, filter, "owner", invoice.SalesCollection)
+	if err != nil {
+		return
+	}
+	var errs []error
+	for _, owner := range owners {
+		if errOwner := kirimPengingatOwner(db, owner); errOwner != nil {
+			errs = append(errs, fmt.Errorf("owner %s: %w", owner, errOwner))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func kirimPengingatOwner(db *mongo.Database, owner string) (err error) {
+	store := invoice.Store(db, owner)
+	days := store.ReminderDays
+	if days <= 0 {
+		days = invoice.DefaultReminderDays
+	}
+	now := time.Now()
+	sales, err := atdb.GetAllDoc[[]model.SalesTransaction](db, invoice.SalesCollection, audit.Live(bson.M{
+		"owner":           owner,
+		"payment_status":  bson.M{"$in": ledger.CreditStatuses},
+		"transactionDate": bson.M{"$lt": now.AddDate(0, 0, -days)},
+	}))
+	if err != nil {
+		return
+	}
+	templ := invoice.Template(db, invoice.TemplateReminder, store)
+	var errs []error
+	for _, trx := range sales {
+		if !invoice.DueForReminder(trx, days, now) {
+			continue
+		}
+		phone, errTrx := CustomerPhone(db, trx)
+		if errors.Is(errTrx, ErrNoPhone) || errTrx == mongo.ErrNoDocuments {
+			// Pelanggan tanpa nomor tidak bisa diingatkan, bukan kegagalan pengiriman
+			continue
+		}
+		// reminded_at hanya diisi setelah gateway menerima pesan, yang gagal dicoba lagi pada jadwal berikutnya
+		if errTrx == nil {
+			errTrx = Send(db, &trx, store, phone, invoice.FillTemplate(templ, trx, store))
+		}
+		if errTrx == nil {
+			_, errTrx = atdb.UpdateOneDoc(db, invoice.SalesCollection, bson.M{"_id": trx.ID}, bson.M{"reminded_at": now})
+		}
+		if errTrx != nil {
+			errs = append(errs, fmt.Errorf("penjualan %s: %w", trx.ID.Hex(), errTrx))
+		}
+	}
+	return errors.Join(errs...)
+}
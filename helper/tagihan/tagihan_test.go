@@ -0,0 +1,18 @@
+package tagihan
+
+import (
+	"testing"
+
+	"github.com/gocroot/model"
+)
+
+func TestGatewayError(t *testing.T) {
+	if err := gatewayError(200, model.Response{}); err != nil {
+		t.Errorf("200 = %v", err)
+	}
+	for _, status := range []int{0, 401, 500, 503} {
+		if err := gatewayError(status, model.Response{Response: "token expired"}); err == nil {
+			t.Errorf("status %d dianggap terkirim", status)
+		}
+	}
+}
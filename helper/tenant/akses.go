@@ -0,0 +1,102 @@
+package tenant
+
+import (
+	"context"
+	"net/http"
+	"strings"
+)
+
+// Peran akun pada endpoint akuntansi, diambil dari Userdomyikado.Role
+const (
+	RoleOwner      = "owner"
+	RoleCashier    = "cashier"
+	RoleAccountant = "accountant"
+)
+
+// Session adalah identitas akun yang sudah diverifikasi oleh middleware
+type Session struct {
+	Phone string // Nomor telepon akun yang login
+	Owner string // Nomor telepon pemilik toko, sama dengan Phone untuk role owner
+	Role  string
+}
+
+type sessionKey struct{}
+
+// protectedPrefixes adalah awalan path endpoint akuntansi yang wajib login
+var protectedPrefixes = []string{
+	"/products", "/product-id", "/customers", "/customer-id", "/sales", "/expense", "/employee",
+	"/categories", "/category", "/reports", "/report-id", "/accounts", "/journals", "/trial-balance",
+	"/stock-movements", "/suppliers", "/supplier-id", "/purchase-order", "/payables",
+	"/store-profile", "/invoice-templates", "/tenant", "/export", "/import", "/payroll",
+	"/attendance", "/pos", "/promos", "/audit", "/periods", "/recurring-expenses",
+}
+
+// cashierReadOnlyPrefixes adalah data yang hanya boleh dibaca kasir: penjualan (penjualan dari kasir wajib lewat
+// checkout agar harga dan total dihitung server), bagan akun, purchase order dan utang usaha
+var cashierReadOnlyPrefixes = []string{"/sales", "/accounts", "/purchase-order", "/payables"}
+
+// reportPrefixes adalah laporan, buku besar, payroll, rekap kehadiran, audit log dan periode akuntansi yang tidak boleh dilihat kasir
+var reportPrefixes = []string{"/reports", "/report-id", "/journals", "/trial-balance", "/export", "/payroll", "/attendance", "/audit", "/periods"}
+
+// IsProtected memeriksa apakah path termasuk endpoint akuntansi
+func IsProtected(path string) bool {
+	return hasPrefix(path, protectedPrefixes)
+}
+
+// NormalizeRole mengubah role kosong menjadi owner agar akun penjual lama tetap bisa masuk
+func NormalizeRole(role string) string {
+	role = strings.ToLower(strings.TrimSpace(role))
+	if role == "" {
+		return RoleOwner
+	}
+	return role
+}
+
+// Allowed memeriksa hak akses role terhadap method dan path.
+// Kasir tidak boleh menghapus data, mengubah data pegawai, kode promo, penjualan, bagan akun, pembelian dan utang,
+// melihat laporan maupun ekspor dan impor data, dan mencatat penjualan hanya lewat checkout kasir. Pengaturan toko hanya untuk owner.
+func Allowed(role, method, path string) bool {
+	switch role {
+	case RoleOwner:
+		return true
+	case RoleAccountant:
+		return !ownerOnly(method, path)
+	case RoleCashier:
+		if method == http.MethodDelete || ownerOnly(method, path) || strings.HasPrefix(path, "/import") {
+			return false
+		}
+		if method != http.MethodGet && (strings.HasPrefix(path, "/employee") || strings.HasPrefix(path, "/promos") || hasPrefix(path, cashierReadOnlyPrefixes)) {
+			return false
+		}
+		return !hasPrefix(path, reportPrefixes) && !strings.HasSuffix(path, "-export-csv")
+	}
+	return false
+}
+
+// ownerOnly adalah pengaturan toko dan klaim data lama
+func ownerOnly(method, path string) bool {
+	if strings.HasPrefix(path, "/tenant") {
+		return true
+	}
+	return method == http.MethodPut && (path == "/store-profile" || path == "/invoice-templates")
+}
+
+// WithSession menyimpan session ke context request
+func WithSession(req *http.Request, s Session) *http.Request {
+	return req.WithContext(context.WithValue(req.Context(), sessionKey{}, s))
+}
+
+// FromRequest mengambil session yang disimpan oleh middleware
+func FromRequest(req *http.Request) (s Session, ok bool) {
+	s, ok = req.Context().Value(sessionKey{}).(Session)
+	return
+}
+
+func hasPrefix(path string, prefixes []string) bool {
+	for _, p := range prefixes {
+		if strings.HasPrefix(path, p) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,85 @@
+package tenant
+
+import "testing"
+
+func TestAllowed(t *testing.T) {
+	cases := []struct {
+		role, method, path string
+		want               bool
+	}{
+		{RoleOwner, "DELETE", "/sales/{id:objectid}", true},
+		{RoleOwner, "POST", "/tenant/staff", true},
+		{RoleCashier, "POST", "/sales", false},
+		{RoleCashier, "GET", "/products", true},
+		{RoleCashier, "GET", "/sales/{id:objectid}", true},
+		{RoleCashier, "DELETE", "/sales/{id:objectid}", false},
+		{RoleCashier, "DELETE", "/products", false},
+		{RoleCashier, "GET", "/reports/profit-loss", false},
+		{RoleCashier, "GET", "/trial-balance", false},
+		{RoleCashier, "GET", "/sales-export-csv", false},
+		{RoleCashier, "PUT", "/store-profile", false},
+		{RoleCashier, "GET", "/export", false},
+		{RoleCashier, "POST", "/import/products", false},
+		{RoleCashier, "GET", "/employee", true},
+		{RoleCashier, "PUT", "/employee", false},
+		{RoleCashier, "POST", "/payroll/runs", false},
+		{RoleAccountant, "POST", "/payroll/runs", true},
+		{RoleCashier, "GET", "/attendance/summary", false},
+		{RoleAccountant, "GET", "/attendance/summary", true},
+		{RoleCashier, "POST", "/pos/checkout", true},
+		{RoleAccountant, "POST", "/sales", true},
+		{RoleCashier, "POST", "/promos", false},
+		{RoleCashier, "PUT", "/sales/{id:objectid}", false},
+		{RoleCashier, "POST", "/sales/payments", false},
+		{RoleCashier, "GET", "/sales/payments", true},
+		{RoleCashier, "GET", "/accounts", true},
+		{RoleCashier, "POST", "/accounts", false},
+		{RoleCashier, "POST", "/accounts/default", false},
+		{RoleCashier, "GET", "/purchase-orders", true},
+		{RoleCashier, "POST", "/purchase-orders", false},
+		{RoleCashier, "PUT", "/purchase-orders/status", false},
+		{RoleCashier, "GET", "/payables", true},
+		{RoleCashier, "POST", "/payables/pay", false},
+		{RoleAccountant, "PUT", "/sales/{id:objectid}", true},
+		{RoleAccountant, "POST", "/payables/pay", true},
+		{RoleCashier, "GET", "/audit", false},
+		{RoleAccountant, "POST", "/audit/restore", true},
+		{RoleCashier, "GET", "/periods", false},
+		{RoleAccountant, "POST", "/periods/close", true},
+		{RoleCashier, "DELETE", "/recurring-expenses", false},
+		{RoleAccountant, "POST", "/recurring-expenses/skip", true},
+		{RoleAccountant, "POST", "/import/opening-balances", true},
+		{RoleAccountant, "GET", "/reports/balance-sheet", true},
+		{RoleAccountant, "DELETE", "/expense", true},
+		{RoleAccountant, "PUT", "/store-profile", false},
+		{RoleAccountant, "GET", "/tenant/staff", false},
+		{"admin", "GET", "/products", false},
+	}
+	for _, c := range cases {
+		if got := Allowed(c.role, c.method, c.path); got != c.want {
+			t.Errorf("Allowed(%q, %q, %q) = %v, want %v", c.role, c.method, c.path, got, c.want)
+		}
+	}
+}
+
+func TestIsProtected(t *testing.T) {
+	for _, p := range []string{"/products", "/sales/payments", "/expenses", "/reports/ar-aging", "/tenant/staff"} {
+		if !IsProtected(p) {
+			t.Errorf("IsProtected(%q) = false", p)
+		}
+	}
+	for _, p := range []string{"/", "/login", "/register", "/data/gis/lokasi", "/auth/login"} {
+		if IsProtected(p) {
+			t.Errorf("IsProtected(%q) = true", p)
+		}
+	}
+}
+
+func TestNormalizeRole(t *testing.T) {
+	if NormalizeRole("") != RoleOwner {
+		t.Error("role kosong harus menjadi owner")
+	}
+	if NormalizeRole(" Cashier ") != RoleCashier {
+		t.Error("role harus dinormalisasi ke huruf kecil")
+	}
+}
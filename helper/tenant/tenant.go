@@ -0,0 +1,102 @@
+package tenant
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gocroot/helper/at"
+	"github.com/gocroot/helper/atdb"
+	"github.com/gocroot/helper/repo"
+	"github.com/gocroot/helper/watoken"
+	"github.com/gocroot/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Field adalah nama field pemilik data, berisi nomor telepon akun penjual dari token Login
+const Field = "owner"
+
+// Collections adalah koleksi yang datanya dipisah per akun penjual
+var Collections = []string{
+	repo.ProductCollection, repo.CustomerCollection, repo.SalesCollection, repo.ExpenseCollection,
+	repo.EmployeeCollection, repo.CategoryCollection, repo.ReportCollection, repo.SnapshotCollection,
+	repo.AccountCollection, repo.JournalCollection, repo.MovementCollection, repo.SupplierCollection,
+	repo.PurchaseCollection, repo.PayableCollection, repo.StoreCollection,
+}
+
+// Owner mengambil akun penjual dari header login yang dibuat oleh LoginAkunPenjual
+func Owner(req *http.Request, publicKey string) (owner string, err error) {
+	token := at.GetLoginFromHeader(req)
+	if token == "" {
+		return "", errors.New("header login tidak ditemukan")
+	}
+	return watoken.DecodeGetId(publicKey, token)
+}
+
+// Resolve memverifikasi token login lalu mengambil role dan pemilik toko dari koleksi user.
+// Akun kasir dan akuntan memakai data milik Userdomyikado.Owner.
+func Resolve(db *mongo.Database, req *http.Request, publicKey string) (s Session, err error) {
+	phone, err := Owner(req, publicKey)
+	if err != nil {
+		return
+	}
+	user, err := atdb.GetOneDoc[model.Userdomyikado](db, "user", bson.M{"phonenumber": phone})
+	if err != nil {
+		return s, errors.New("akun penjual tidak ditemukan")
+	}
+	s = Session{Phone: phone, Owner: phone, Role: NormalizeRole(user.Role)}
+	if s.Role != RoleOwner {
+		if user.Owner == "" {
+			return s, errors.New("akun " + s.Role + " belum terhubung ke toko")
+		}
+		s.Owner = user.Owner
+	}
+	return
+}
+
+// Filter menambahkan pemilik data ke filter query
+func Filter(owner string, filter bson.M) bson.M {
+	if filter == nil {
+		filter = bson.M{}
+	}
+	filter[Field] = owner
+	return filter
+}
+
+// ErrBackfilled dikembalikan Backfill jika sudah ada data yang memiliki pemilik
+var ErrBackfilled = errors.New("data sudah memiliki pemilik, backfill hanya boleh dijalankan sekali sebelum toko lain memakai aplikasi")
+
+// Backfill menandai data lama yang belum memiliki pemilik sebagai milik owner, yaitu satu akun penjual
+// yang ditetapkan di konfigurasi LEGACY_OWNER. Migrasi ini hanya dijalankan sekali dari run/backfillowner
+// dan ditolak begitu ada satu dokumen yang sudah memiliki pemilik.
+func Backfill(db *mongo.Database, owner string) (claimed map[string]int64, err error) {
+	if owner == "" {
+		return nil, errors.New("pemilik data lama belum diatur")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+	for _, coll := range Collections {
+		var n int64
+		n, err = db.Collection(coll).CountDocuments(ctx, bson.M{Field: bson.M{"$exists": true}}, options.Count().SetLimit(1))
+		if err != nil {
+			return
+		}
+		if n > 0 {
+			return nil, fmt.Errorf("%s: %w", coll, ErrBackfilled)
+		}
+	}
+	claimed = map[string]int64{}
+	for _, coll := range Collections {
+		var res *mongo.UpdateResult
+		res, err = db.Collection(coll).UpdateMany(ctx, bson.M{Field: bson.M{"$exists": false}}, bson.M{"$set": bson.M{Field: owner}})
+		if err != nil {
+			return
+		}
+		claimed[coll] = res.ModifiedCount
+	}
+	return
+}
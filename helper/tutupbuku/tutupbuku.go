@@ -0,0 +1,185 @@
+package tutupbuku
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/gocroot/helper/atdb"
+	"github.com/gocroot/helper/keuangan"
+	"github.com/gocroot/helper/ledger"
+	"github.com/gocroot/model"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Parse membaca periode YYYY-MM atau YYYY menjadi jenis dan rentang tanggalnya, memakai UTC seperti laporan keuangan
+func Parse(period string) (kind string, start, end time.Time, err error) {
+	if start, err = time.Parse("2006-01", period); err == nil {
+		return TypeMonth, start, start.AddDate(0, 1, 0).Add(-time.Nanosecond), nil
+	}
+	if start, err = time.Parse("2006", period); err == nil {
+		return TypeYear, start, start.AddDate(1, 0, 0).Add(-time.Nanosecond), nil
+	}
+	return "", start, end, ErrPeriodFormat
+}
+
+// Keys mengembalikan kunci bulan (YYYY-MM) dan tahun (YYYY) dari tanggal-tanggal transaksi, tanggal nol diabaikan
+func Keys(dates ...time.Time) (keys []string) {
+	seen := map[string]bool{}
+	for _, d := range dates {
+		if d.IsZero() {
+			continue
+		}
+		d = d.UTC()
+		for _, k := range []string{d.Format("2006-01"), d.Format("2006")} {
+			if !seen[k] {
+				seen[k] = true
+				keys = append(keys, k)
+			}
+		}
+	}
+	sort.Strings(keys)
+	return
+}
+
+// Guard memastikan semua tanggal berada di periode yang masih terbuka, ClosedError jika ada yang sudah ditutup
+func Guard(db *mongo.Database, owner string, dates ...time.Time) error {
+	keys := Keys(dates...)
+	if len(keys) == 0 {
+		return nil
+	}
+	closed, err := atdb.GetAllDoc[[]model.AccountingPeriod](db, PeriodCollection, bson.M{"owner": owner, "status": StatusClosed, "period": bson.M{"$in": keys}})
+	if err != nil {
+		return err
+	}
+	if len(closed) > 0 {
+		sort.Slice(closed, func(i, j int) bool { return closed[i].Period < closed[j].Period })
+		return ClosedError{Period: closed[0].Period}
+	}
+	return nil
+}
+
+// Close menutup bulan (YYYY-MM) atau tahun (YYYY) dan menyimpan laba ruginya agar angka yang dilaporkan tetap.
+// Menutup tahun juga menutup bulan-bulannya yang masih terbuka lalu memposting jurnal penutup ke Laba Ditahan.
+func Close(db *mongo.Database, owner, period, user string, now time.Time) (p model.AccountingPeriod, err error) {
+	kind, start, end, err := Parse(period)
+	if err != nil {
+		return
+	}
+	if end.After(now) {
+		return p, ErrNotEnded
+	}
+	existing, err := atdb.GetOneDoc[model.AccountingPeriod](db, PeriodCollection, bson.M{"owner": owner, "period": period})
+	if err == nil && existing.Status == StatusClosed {
+		return p, ErrAlreadyClosed
+	}
+	if err != nil && err != mongo.ErrNoDocuments {
+		return
+	}
+	if kind == TypeYear {
+		for m := 1; m <= 12; m++ {
+			if _, err = Close(db, owner, fmt.Sprintf("%s-%02d", period, m), user, now); err != nil && err != ErrAlreadyClosed {
+				return
+			}
+		}
+	}
+
+	report, err := keuangan.ProfitLoss(db, owner, start.Format("2006-01-02"), end.Format("2006-01-02"))
+	if err != nil {
+		return
+	}
+	p = model.AccountingPeriod{
+		ID:       existing.ID,
+		Owner:    owner,
+		Period:   period,
+		Type:     kind,
+		Status:   StatusClosed,
+		Start:    start,
+		End:      end,
+		Report:   &report,
+		ClosedAt: now,
+		ClosedBy: user,
+	}
+	if p.ID.IsZero() {
+		p.ID = primitive.NewObjectID()
+	}
+	if kind == TypeYear {
+		if err = postClosingEntry(db, &p); err != nil {
+			return
+		}
+	}
+	err = save(db, p)
+	return
+}
+
+// postClosingEntry memindahkan saldo pendapatan dan beban sampai akhir tahun ke Laba Ditahan
+func postClosingEntry(db *mongo.Database, p *model.AccountingPeriod) (err error) {
+	lines, err := ledger.TrialBalance(db, p.Owner, p.End)
+	if err != nil {
+		return
+	}
+	types, err := ledger.AccountTypes(db, p.Owner)
+	if err != nil {
+		return
+	}
+	entry, netIncome := ledger.ClosingEntry(p.Owner, p.End, lines, types)
+	entry.SourceID = p.ID
+	p.RetainedEarnings = netIncome
+	if len(entry.Lines) == 0 {
+		return
+	}
+	id, err := ledger.Post(db, entry)
+	if err != nil {
+		return
+	}
+	p.ClosingEntryID = &id
+	return
+}
+
+// Reopen membuka kembali periode yang ditutup. Bulan di dalam tahun yang masih ditutup tidak bisa dibuka,
+// membuka tahun membalik jurnal penutupnya pada tanggal yang sama.
+func Reopen(db *mongo.Database, owner, period, user string, now time.Time) (p model.AccountingPeriod, err error) {
+	kind, start, _, err := Parse(period)
+	if err != nil {
+		return
+	}
+	p, err = atdb.GetOneDoc[model.AccountingPeriod](db, PeriodCollection, bson.M{"owner": owner, "period": period, "status": StatusClosed})
+	if err == mongo.ErrNoDocuments {
+		return p, ErrNotClosed
+	}
+	if err != nil {
+		return
+	}
+	if kind == TypeMonth {
+		if err = Guard(db, owner, start); err != nil {
+			if closed, ok := err.(ClosedError); !ok || closed.Period != period {
+				return
+			}
+			err = nil
+		}
+	}
+	if kind == TypeYear && p.ClosingEntryID != nil {
+		if err = ledger.ReverseSameDate(db, ledger.SourceClosing, p.ID); err != nil {
+			return
+		}
+	}
+	p.Status = StatusOpen
+	p.ReopenedAt = now
+	p.ReopenedBy = user
+	p.ClosingEntryID = nil
+	p.RetainedEarnings = 0
+	err = save(db, p)
+	return
+}
+
+// save menyimpan status periode, satu dokumen per owner dan periode
+func save(db *mongo.Database, p model.AccountingPeriod) (err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	_, err = db.Collection(PeriodCollection).ReplaceOne(ctx, bson.M{"owner": p.Owner, "period": p.Period}, p, options.Replace().SetUpsert(true))
+	return
+}
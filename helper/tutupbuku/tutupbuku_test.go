@@ -0,0 +1,40 @@
+package tutupbuku
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	kind, start, end, err := Parse("2026-02")
+	if err != nil || kind != TypeMonth || start.Day() != 1 || end.Day() != 28 || end.Month() != time.February {
+		t.Errorf("bulan: %v %v %v %v", kind, start, end, err)
+	}
+	kind, _, end, err = Parse("2025")
+	if err != nil || kind != TypeYear || end.Format("2006-01-02") != "2025-12-31" {
+		t.Errorf("tahun: %v %v %v", kind, end, err)
+	}
+	if _, _, _, err = Parse("02-2026"); err != ErrPeriodFormat {
+		t.Errorf("format salah harus ditolak: %v", err)
+	}
+}
+
+func TestKeys(t *testing.T) {
+	jan := time.Date(2026, 1, 31, 20, 0, 0, 0, time.UTC)
+	wib := time.Date(2026, 2, 1, 3, 0, 0, 0, time.FixedZone("WIB", 7*3600)) // 31 Januari 20:00 UTC
+	keys := Keys(jan, wib, time.Time{}, time.Date(2025, 12, 5, 0, 0, 0, 0, time.UTC))
+	if strings.Join(keys, ",") != "2025,2025-12,2026,2026-01" {
+		t.Errorf("keys = %v", keys)
+	}
+	if Keys(time.Time{}) != nil {
+		t.Error("tanggal nol harus diabaikan")
+	}
+}
+
+func TestClosedError(t *testing.T) {
+	err := error(ClosedError{Period: "2026-01"})
+	if !strings.Contains(err.Error(), "2026-01 sudah ditutup") {
+		t.Errorf("pesan = %q", err.Error())
+	}
+}
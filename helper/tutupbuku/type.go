@@ -0,0 +1,34 @@
+package tutupbuku
+
+import "errors"
+
+// PeriodCollection adalah koleksi status periode akuntansi per toko
+const PeriodCollection = "accounting_periods"
+
+// Jenis periode
+const (
+	TypeMonth = "month"
+	TypeYear  = "year"
+)
+
+// Status periode
+const (
+	StatusClosed = "closed"
+	StatusOpen   = "open"
+)
+
+// ClosedError dikembalikan jika transaksi menyentuh periode yang sudah ditutup
+type ClosedError struct {
+	Period string
+}
+
+func (e ClosedError) Error() string {
+	return "periode " + e.Period + " sudah ditutup, buka kembali periode tersebut untuk mengubah transaksinya"
+}
+
+var (
+	ErrPeriodFormat  = errors.New("periode harus berformat YYYY-MM untuk bulan atau YYYY untuk tahun")
+	ErrNotEnded      = errors.New("periode belum berakhir sehingga belum bisa ditutup")
+	ErrAlreadyClosed = errors.New("periode sudah ditutup")
+	ErrNotClosed     = errors.New("periode tidak sedang ditutup")
+)
@@ -0,0 +1,42 @@
+package validasi
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Tag struct yang dibaca Struct, aturan dipisah koma, misalnya `validate:"required,positive"`
+const Tag = "validate"
+
+// Kode aturan yang dikembalikan di FieldError.Code
+const (
+	RuleRequired    = "required"
+	RulePositive    = "positive"    // angka lebih dari 0
+	RuleNonNegative = "nonnegative" // angka 0 atau lebih
+	RuleMin         = "min"         // min=n: angka minimal n, atau panjang teks/daftar minimal n
+	RuleMax         = "max"         // max=n: angka maksimal n, atau panjang teks/daftar maksimal n
+	RuleOneOf       = "oneof"       // oneof=a b c
+	RulePhone       = "phone"
+	RuleEmail       = "email"
+)
+
+// PhonePattern adalah format nomor WhatsApp akun: diawali 62 lalu 9 sampai 15 digit
+var PhonePattern = regexp.MustCompile(`^62\d{9,15}$`)
+
+// FieldError adalah kesalahan satu field input, Field memakai nama json dan path seperti items[0].quantity
+type FieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// Errors adalah daftar kesalahan input dari Struct
+type Errors []FieldError
+
+func (e Errors) Error() string {
+	msgs := make([]string, len(e))
+	for i, f := range e {
+		msgs[i] = f.Field + ": " + f.Message
+	}
+	return strings.Join(msgs, "; ")
+}
@@ -0,0 +1,207 @@
+package validasi
+
+import (
+	"fmt"
+	"net/mail"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Struct memeriksa tag validate pada struct beserta struct dan slice struct di dalamnya.
+// Hasilnya nil jika valid, atau Errors berisi satu kesalahan per field.
+func Struct(v interface{}) error {
+	var errs Errors
+	walk(reflect.ValueOf(v), "", &errs)
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+// Phone memeriksa format nomor telepon akun, sama dengan aturan phone
+func Phone(s string) bool {
+	return PhonePattern.MatchString(s)
+}
+
+// Email memeriksa format alamat email tanpa nama tampilan, sama dengan aturan email
+func Email(s string) bool {
+	addr, err := mail.ParseAddress(s)
+	return err == nil && addr.Address == s
+}
+
+func walk(v reflect.Value, path string, errs *Errors) {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return
+		}
+		v = v.Elem()
+	}
+	switch v.Kind() {
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			name := fieldName(f)
+			if name == "-" {
+				continue
+			}
+			// Field struct tertanam diratakan seperti encoding/json, termasuk yang tipenya tidak diekspor
+			if f.Anonymous && name == "" {
+				walk(v.Field(i), path, errs)
+				continue
+			}
+			if !f.IsExported() {
+				continue
+			}
+			if name == "" {
+				name = f.Name
+			}
+			if path != "" {
+				name = path + "." + name
+			}
+			if rules := f.Tag.Get(Tag); rules != "" {
+				if fe := check(v.Field(i), rules); fe != nil {
+					fe.Field = name
+					*errs = append(*errs, *fe)
+					continue
+				}
+			}
+			walk(v.Field(i), name, errs)
+		}
+	case reflect.Slice, reflect.Array:
+		elem := v.Type().Elem()
+		for elem.Kind() == reflect.Ptr {
+			elem = elem.Elem()
+		}
+		if elem.Kind() != reflect.Struct {
+			return
+		}
+		for i := 0; i < v.Len(); i++ {
+			walk(v.Index(i), fmt.Sprintf("%s[%d]", path, i), errs)
+		}
+	}
+}
+
+// fieldName mengambil nama field dari tag json, kosong jika tag tidak menyebut nama
+func fieldName(f reflect.StructField) string {
+	name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+	return name
+}
+
+// check menjalankan aturan satu field berurutan dan berhenti di aturan pertama yang gagal
+func check(v reflect.Value, rules string) *FieldError {
+	required := false
+	for _, r := range strings.Split(rules, ",") {
+		if r == RuleRequired {
+			required = true
+		}
+	}
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			if required {
+				return fail(RuleRequired, "wajib diisi")
+			}
+			return nil
+		}
+		v = v.Elem()
+	}
+	if required && empty(v) {
+		return fail(RuleRequired, "wajib diisi")
+	}
+	// Teks kosong yang tidak wajib tidak diperiksa format atau panjangnya
+	if v.Kind() == reflect.String && v.String() == "" {
+		return nil
+	}
+
+	for _, r := range strings.Split(rules, ",") {
+		rule, arg, _ := strings.Cut(strings.TrimSpace(r), "=")
+		switch rule {
+		case RuleRequired:
+		case RulePositive:
+			if n, ok := number(v); !ok || n <= 0 {
+				return fail(rule, "harus lebih dari 0")
+			}
+		case RuleNonNegative:
+			if n, ok := number(v); !ok || n < 0 {
+				return fail(rule, "tidak boleh negatif")
+			}
+		case RuleMin, RuleMax:
+			limit, err := strconv.ParseFloat(arg, 64)
+			if err != nil {
+				panic("validasi: argumen " + rule + " bukan angka: " + arg)
+			}
+			n, unit := size(v)
+			if rule == RuleMin && n < limit {
+				return fail(rule, "minimal "+arg+unit)
+			}
+			if rule == RuleMax && n > limit {
+				return fail(rule, "maksimal "+arg+unit)
+			}
+		case RuleOneOf:
+			options := strings.Fields(arg)
+			val := fmt.Sprint(v)
+			found := false
+			for _, o := range options {
+				if o == val {
+					found = true
+					break
+				}
+			}
+			if !found {
+				return fail(rule, "harus salah satu dari: "+strings.Join(options, ", "))
+			}
+		case RulePhone:
+			if v.Kind() != reflect.String || !Phone(v.String()) {
+				return fail(rule, "harus diawali 62 lalu 9 sampai 15 digit")
+			}
+		case RuleEmail:
+			if v.Kind() != reflect.String || !Email(v.String()) {
+				return fail(rule, "format email tidak valid")
+			}
+		default:
+			panic("validasi: aturan tidak dikenal: " + rule)
+		}
+	}
+	return nil
+}
+
+func fail(code, message string) *FieldError {
+	return &FieldError{Code: code, Message: message}
+}
+
+// empty menganggap teks berisi spasi saja dan daftar tanpa isi sebagai kosong
+func empty(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.String:
+		return strings.TrimSpace(v.String()) == ""
+	case reflect.Slice, reflect.Map:
+		return v.Len() == 0
+	}
+	return v.IsZero()
+}
+
+func number(v reflect.Value) (float64, bool) {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), true
+	}
+	return 0, false
+}
+
+// size mengembalikan nilai angka, atau panjang teks dan daftar beserta satuannya untuk pesan
+func size(v reflect.Value) (float64, string) {
+	switch v.Kind() {
+	case reflect.String:
+		return float64(utf8.RuneCountInString(v.String())), " karakter"
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return float64(v.Len()), " item"
+	}
+	n, _ := number(v)
+	return n, ""
+}
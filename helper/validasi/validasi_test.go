@@ -0,0 +1,97 @@
+package validasi
+
+import (
+	"testing"
+)
+
+type item struct {
+	Name     string  `json:"name" validate:"required"`
+	Quantity int     `json:"quantity" validate:"positive"`
+	Discount float64 `json:"discount,omitempty" validate:"nonnegative,max=100"`
+}
+
+type audit struct {
+	Note string `json:"note" validate:"max=5"`
+	Kind string `json:"kind" validate:"oneof=a b"`
+}
+
+type order struct {
+	audit
+	Phone  string   `json:"phone" validate:"required,phone"`
+	Email  string   `json:"email" validate:"email"`
+	Type   string   `json:"type" validate:"oneof=cash credit"`
+	Items  []item   `json:"items" validate:"required,min=1"`
+	Total  *float64 `json:"total" validate:"positive"`
+	Hidden string   `json:"-" validate:"required"`
+}
+
+func TestStructValid(t *testing.T) {
+	total := 10.0
+	o := order{
+		Phone: "6281234567890",
+		Items: []item{{Name: "Kopi", Quantity: 2}},
+		Total: &total,
+	}
+	if err := Struct(&o); err != nil {
+		t.Errorf("Struct = %v", err)
+	}
+}
+
+func TestStructErrors(t *testing.T) {
+	total := 0.0
+	o := order{
+		audit: audit{Note: "terlalu panjang", Kind: "c"},
+		Phone: "081234567890",
+		Email: "bukan email",
+		Type:  "transfer",
+		Items: []item{{Name: "Kopi", Quantity: 1}, {Name: " ", Quantity: 0, Discount: -1}},
+		Total: &total,
+	}
+	err := Struct(o)
+	errs, ok := err.(Errors)
+	if !ok {
+		t.Fatalf("Struct = %v, want Errors", err)
+	}
+	want := []FieldError{
+		{"note", RuleMax, "maksimal 5 karakter"},
+		{"kind", RuleOneOf, "harus salah satu dari: a, b"},
+		{"phone", RulePhone, "harus diawali 62 lalu 9 sampai 15 digit"},
+		{"email", RuleEmail, "format email tidak valid"},
+		{"type", RuleOneOf, "harus salah satu dari: cash, credit"},
+		{"items[1].name", RuleRequired, "wajib diisi"},
+		{"items[1].quantity", RulePositive, "harus lebih dari 0"},
+		{"items[1].discount", RuleNonNegative, "tidak boleh negatif"},
+		{"total", RulePositive, "harus lebih dari 0"},
+	}
+	if len(errs) != len(want) {
+		t.Fatalf("errors = %v", errs)
+	}
+	for i := range want {
+		if errs[i] != want[i] {
+			t.Errorf("errors[%d] = %+v, want %+v", i, errs[i], want[i])
+		}
+	}
+}
+
+func TestStructRequired(t *testing.T) {
+	errs, _ := Struct(order{Items: []item{}}).(Errors)
+	if len(errs) != 2 || errs[0].Field != "phone" || errs[1].Field != "items" || errs[1].Code != RuleRequired {
+		t.Errorf("errors = %v", errs)
+	}
+	if got := errs.Error(); got != "phone: wajib diisi; items: wajib diisi" {
+		t.Errorf("Error() = %q", got)
+	}
+}
+
+func TestPhoneEmail(t *testing.T) {
+	for s, want := range map[string]bool{"628123456789": true, "08123456789": false, "62812": false, "62812345678a": false} {
+		if Phone(s) != want {
+			t.Errorf("Phone(%q) = %v", s, !want)
+		}
+	}
+	for s, want := range map[string]bool{"toko@contoh.id": true, "Toko <toko@contoh.id>": false, "toko": false} {
+		if Email(s) != want {
+			t.Errorf("Email(%q) = %v", s, !want)
+		}
+	}
+}
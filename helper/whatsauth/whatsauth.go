@@ -1,6 +1,7 @@
 package whatsauth
 
 import (
+	"context"
 	"strings"
 
 	"github.com/gocroot/helper/atapi"
@@ -8,6 +9,7 @@ import (
 	"github.com/gocroot/helper/hub"
 	"github.com/gocroot/helper/kimseok"
 	"github.com/gocroot/helper/lms"
+	"github.com/gocroot/helper/logger"
 	"github.com/gocroot/helper/menu"
 	"github.com/gocroot/helper/normalize"
 	"github.com/gocroot/helper/tiket"
@@ -21,11 +23,15 @@ import (
 	"go.mongodb.org/mongo-driver/mongo"
 )
 
-func WebHook(profile itmodel.Profile, msg itmodel.IteungMessage, db *mongo.Database) (resp itmodel.Response, err error) {
+// WebHook menangani pesan masuk dari gateway WhatsApp, request_id dari ctx ikut ke log, modul dan pengiriman balasan
+func WebHook(ctx context.Context, profile itmodel.Profile, msg itmodel.IteungMessage, db *mongo.Database) (resp itmodel.Response, err error) {
 	if IsLoginRequest(msg, profile.QRKeyword) { //untuk whatsauth request login
-		resp, err = HandlerQRLogin(msg, profile, db)
+		resp, err = HandlerQRLogin(ctx, msg, profile, db)
 	} else { //untuk membalas pesan masuk
-		resp, err = HandlerIncomingMessage(msg, profile, db)
+		resp, err = HandlerIncomingMessage(ctx, msg, profile, db)
+	}
+	if err != nil {
+		logger.FromContext(ctx).Error("webhook whatsapp gagal", "bot", profile.Phonenumber, "phone", msg.Phone_number, "error", err)
 	}
 	return
 }
@@ -59,7 +65,7 @@ func GetUUID(msg itmodel.IteungMessage, keyword string) string {
 	return strings.Replace(msg.Message, keyword, "", 1)
 }
 
-func HandlerQRLogin(msg itmodel.IteungMessage, profile itmodel.Profile, db *mongo.Database) (resp itmodel.Response, err error) {
+func HandlerQRLogin(ctx context.Context, msg itmodel.IteungMessage, profile itmodel.Profile, db *mongo.Database) (resp itmodel.Response, err error) {
 	dt := &itmodel.WhatsauthRequest{
 		Uuid:        GetUUID(msg, profile.QRKeyword),
 		Phonenumber: msg.Phone_number,
@@ -70,14 +76,16 @@ func HandlerQRLogin(msg itmodel.IteungMessage, profile itmodel.Profile, db *mong
 	if err != nil {
 		return
 	}
-	_, resp, err = atapi.PostStructWithToken[itmodel.Response]("Token", structtoken.Token, dt, profile.URLQRLogin)
+	_, resp, err = atapi.PostStructWithTokenContext[itmodel.Response](ctx, "Token", structtoken.Token, dt, profile.URLQRLogin)
 	return
 }
 
-func HandlerIncomingMessage(msg itmodel.IteungMessage, profile itmodel.Profile, db *mongo.Database) (resp itmodel.Response, err error) {
+func HandlerIncomingMessage(ctx context.Context, msg itmodel.IteungMessage, profile itmodel.Profile, db *mongo.Database) (resp itmodel.Response, err error) {
+	log := logger.FromContext(ctx).With("bot", profile.Phonenumber, "phone", msg.Phone_number, "chat", msg.Chat_number)
 	//cek apakah nomor adalah bot, jika bot maka return empty
 	_, bukanbot := GetAppProfile(msg.Phone_number, db)
 	if bukanbot == nil { //nomor ada di collection profile
+		log.Debug("pesan dari nomor bot diabaikan")
 		return
 	}
 	//jika tidak terdapat sebagai profile bot
@@ -85,12 +93,13 @@ func HandlerIncomingMessage(msg itmodel.IteungMessage, profile itmodel.Profile,
 	var isgrup bool
 	msg.Message = normalize.NormalizeHiddenChar(msg.Message)
 	module.NormalizeAndTypoCorrection(&msg.Message, db, "typo")
-	galathub := hub.HubHandler(profile, msg, db) // check jika hub aktif maka langsung saja ke percakapan hub
-	msgstr = menu.MenuSessionHandler(&msg, db)   //jika pesan adalah nomor,maka akan mengembalikan menu jika ada menu atau keyword
+	galathub := hub.HubHandler(ctx, profile, msg, db) // check jika hub aktif maka langsung saja ke percakapan hub
+	msgstr = menu.MenuSessionHandler(ctx, &msg, db)   //jika pesan adalah nomor,maka akan mengembalikan menu jika ada menu atau keyword
 	modname, group, personal := module.GetModuleName(profile.Phonenumber, msg, db, "module")
+	log.Debug("pesan masuk", "modul", modname, "grup", msg.Chat_server == "g.us", "balasan_menu", msgstr != "")
 	if msg.Chat_server != "g.us" && msgstr == "" { //chat personal
 		if personal && modname != "" {
-			msgstr = mod.Caller(profile, modname, msg, db)
+			msgstr = mod.Caller(ctx, profile, modname, msg, db)
 		} else {
 			msgstr = kimseok.GetMessage(profile, msg, profile.Botname, db)
 		}
@@ -101,7 +110,7 @@ func HandlerIncomingMessage(msg itmodel.IteungMessage, profile itmodel.Profile,
 		//set grup true
 		isgrup = true
 		if group && modname != "" {
-			msgstr = mod.Caller(profile, modname, msg, db)
+			msgstr = mod.Caller(ctx, profile, modname, msg, db)
 		} else if msgstr == "" {
 			msgstr = kimseok.GetMessage(profile, msg, profile.Botname, db)
 		}
@@ -123,11 +132,11 @@ func HandlerIncomingMessage(msg itmodel.IteungMessage, profile itmodel.Profile,
 		IsGroup:  isgrup,
 		Messages: msgstr,
 	}
-	_, resp, err = atapi.PostStructWithToken[itmodel.Response]("Token", profile.Token, dt, profile.URLAPIText)
+	_, resp, err = atapi.PostStructWithTokenContext[itmodel.Response](ctx, "Token", profile.Token, dt, profile.URLAPIText)
 	if err != nil {
 		return
 	}
-
+	log.Info("balasan whatsapp terkirim", "grup", isgrup, "response", resp.Response)
 	return
 }
 
@@ -1,11 +1,15 @@
 package gocroot
 
 import (
+	"github.com/gocroot/config"
+	"github.com/gocroot/helper/logger"
 	"github.com/gocroot/route"
 	"github.com/GoogleCloudPlatform/functions-framework-go/functions"
 )
 
 func init() {
+	// Hentikan start jika konfigurasi lingkungan tidak lengkap
+	logger.SetLevel(config.MustLoad().LogLevel)
 
 	// Mendaftarkan fungsi HTTP untuk Google Cloud Functions
 	functions.HTTP("WebHook", route.URL)
@@ -1,6 +1,7 @@
 package helpdesk
 
 import (
+	"context"
 	"fmt"
 	"strings"
 
@@ -110,7 +111,7 @@ func UserNotFound(Profile itmodel.Profile, Pesan itmodel.IteungMessage, db *mong
 		return err.Error()
 	}
 
-	msg, err := menu.GetMenuFromKeywordAndSetSession("adminpusat", Sesdoc, db)
+	msg, err := menu.GetMenuFromKeywordAndSetSession(context.TODO(), "adminpusat", Sesdoc, db)
 	if err != nil {
 		return err.Error()
 	}
@@ -1,6 +1,10 @@
 package mod
 
 import (
+	"context"
+	"time"
+
+	"github.com/gocroot/helper/logger"
 	"github.com/gocroot/mod/daftar"
 	"github.com/gocroot/mod/helpdesk"
 	"github.com/gocroot/mod/idgrup"
@@ -16,7 +20,12 @@ import (
 	"go.mongodb.org/mongo-driver/mongo"
 )
 
-func Caller(Profile itmodel.Profile, Modulename string, Pesan itmodel.IteungMessage, db *mongo.Database) (reply string) {
+// Caller menjalankan modul sesuai nama, nama modul dan lama prosesnya dicatat ke log beserta request_id
+func Caller(ctx context.Context, Profile itmodel.Profile, Modulename string, Pesan itmodel.IteungMessage, db *mongo.Database) (reply string) {
+	start := time.Now()
+	defer func() {
+		logger.FromContext(ctx).Info("modul whatsapp", "modul", Modulename, "phone", Pesan.Phone_number, "reply_kosong", reply == "", "duration", time.Since(start))
+	}()
 	switch Modulename {
 	case "unsubscribe":
 		reply = unsubscribe.Unsubscribe(Pesan, db)
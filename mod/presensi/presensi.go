@@ -4,10 +4,14 @@ import (
 	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gocroot/helper/atapi"
 	"github.com/gocroot/helper/atdb"
+	"github.com/gocroot/helper/audit"
+	"github.com/gocroot/helper/repo"
+	"github.com/gocroot/model"
 	"github.com/whatsauth/itmodel"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
@@ -170,7 +174,7 @@ func PresensiMasuk(Pesan itmodel.IteungMessage, db *mongo.Database) (reply strin
 	}
 	longitude := fmt.Sprintf("%f", Pesan.Longitude)
 	latitude := fmt.Sprintf("%f", Pesan.Latitude)
-	lokasiuser, err := GetLokasi(db, Pesan.Longitude, Pesan.Latitude)
+	lokasiuser, pegawai, err := GetLokasiPegawai(db, Pesan.Phone_number, Pesan.Longitude, Pesan.Latitude)
 	if err != nil {
 		return "Mohon maaf kak, kakak " + Pesan.Alias_name + " belum berada di lokasi presensi, silahkan menuju lokasi presensi dahulu baru cekin masuk."
 	}
@@ -181,6 +185,8 @@ func PresensiMasuk(Pesan itmodel.IteungMessage, db *mongo.Database) (reply strin
 		PhoneNumber: Pesan.Phone_number,
 		Lokasi:      lokasiuser,
 		IsMasuk:     true,
+		EmployeeID:  pegawai.ID,
+		Owner:       pegawai.Owner,
 		CreatedAt:   time.Now(),
 	}
 	_, err = atdb.InsertOneDoc(db, "presensi", dtuser)
@@ -197,7 +203,7 @@ func PresensiPulang(Pesan itmodel.IteungMessage, db *mongo.Database) (reply stri
 	}
 	longitude := fmt.Sprintf("%f", Pesan.Longitude)
 	latitude := fmt.Sprintf("%f", Pesan.Latitude)
-	lokasiuser, err := GetLokasi(db, Pesan.Longitude, Pesan.Latitude)
+	lokasiuser, pegawai, err := GetLokasiPegawai(db, Pesan.Phone_number, Pesan.Longitude, Pesan.Latitude)
 	if err != nil {
 		return "Mohon maaf kak " + Pesan.Alias_name + ", kakak belum berada di lokasi presensi, silahkan menuju lokasi presensi dahulu baru cekin pulang."
 	}
@@ -208,6 +214,8 @@ func PresensiPulang(Pesan itmodel.IteungMessage, db *mongo.Database) (reply stri
 		PhoneNumber: Pesan.Phone_number,
 		Lokasi:      lokasiuser,
 		IsMasuk:     false,
+		EmployeeID:  pegawai.ID,
+		Owner:       pegawai.Owner,
 		CreatedAt:   time.Now(),
 	}
 	filter := bson.M{"_id": atdb.TodayFilter(), "cekinlokasi.phonenumber": Pesan.Phone_number, "ismasuk": true}
@@ -227,7 +235,49 @@ func PresensiPulang(Pesan itmodel.IteungMessage, db *mongo.Database) (reply stri
 }
 
 func GetLokasi(mongoconn *mongo.Database, long float64, lat float64) (lokasi Lokasi, err error) {
-	filter := bson.M{
+	lokasi, err = atdb.GetOneDoc[Lokasi](mongoconn, "lokasi", lokasiFilter(long, lat))
+	if err != nil {
+		return
+	}
+	return
+}
+
+// GetLokasiPegawai mencari lokasi presensi di titik live location. Lokasi toko hanya berlaku untuk pegawai toko
+// tersebut sehingga pegawai yang terdaftar di beberapa toko tercatat di toko tempat dia berada. Nomor yang bukan
+// pegawai toko di lokasi itu memakai lokasi presensi biasa dan pegawai bernilai kosong.
+func GetLokasiPegawai(mongoconn *mongo.Database, phonenumber string, long float64, lat float64) (lokasi Lokasi, pegawai model.Employee, err error) {
+	lokasis, err := atdb.GetAllDoc[[]Lokasi](mongoconn, "lokasi", lokasiFilter(long, lat))
+	if err != nil {
+		return
+	}
+	var umum []Lokasi
+	for _, l := range lokasis {
+		if l.Owner == "" {
+			umum = append(umum, l)
+			continue
+		}
+		if pegawai, err = GetPegawai(mongoconn, l.Owner, phonenumber); err == nil {
+			return l, pegawai, nil
+		}
+	}
+	pegawai = model.Employee{}
+	if len(umum) == 0 {
+		return lokasi, pegawai, mongo.ErrNoDocuments
+	}
+	return umum[0], pegawai, nil
+}
+
+// GetPegawai mencari data pegawai satu toko dari nomor WhatsApp, nomor di data pegawai boleh berawalan 0, 62 atau +62
+func GetPegawai(mongoconn *mongo.Database, owner string, phonenumber string) (pegawai model.Employee, err error) {
+	nomor := []string{phonenumber, "+" + phonenumber}
+	if strings.HasPrefix(phonenumber, "62") {
+		nomor = append(nomor, "0"+strings.TrimPrefix(phonenumber, "62"))
+	}
+	return atdb.GetOneDoc[model.Employee](mongoconn, repo.EmployeeCollection, audit.Live(bson.M{"owner": owner, "phone_number": bson.M{"$in": nomor}}))
+}
+
+func lokasiFilter(long float64, lat float64) bson.M {
+	return bson.M{
 		"batas": bson.M{
 			"$geoIntersects": bson.M{
 				"$geometry": bson.M{
@@ -237,10 +287,4 @@ func GetLokasi(mongoconn *mongo.Database, long float64, lat float64) (lokasi Lok
 			},
 		},
 	}
-
-	lokasi, err = atdb.GetOneDoc[Lokasi](mongoconn, "lokasi", filter)
-	if err != nil {
-		return
-	}
-	return
 }
@@ -7,10 +7,11 @@ import (
 )
 
 type Lokasi struct { //lokasi yang bisa melakukan presensi
-	ID       primitive.ObjectID `bson:"_id,omitempty"`
-	Nama     string             `bson:"nama,omitempty"`
-	Batas    Geometry           `bson:"batas,omitempty"`
-	Kategori string             `bson:"kategori,omitempty"`
+	ID       primitive.ObjectID `json:"id,omitempty" bson:"_id,omitempty"`
+	Nama     string             `json:"nama,omitempty" bson:"nama,omitempty"`
+	Batas    Geometry           `json:"batas,omitempty" bson:"batas,omitempty"`
+	Kategori string             `json:"kategori,omitempty" bson:"kategori,omitempty"`
+	Owner    string             `json:"-" bson:"owner,omitempty"` //nomor telepon pemilik toko jika lokasi adalah toko
 }
 
 type Geometry struct { //data geometry untuk lokasi presensi
@@ -24,6 +25,8 @@ type PresensiLokasi struct {
 	Lokasi      Lokasi             `bson:"lokasi,omitempty"`
 	Selfie      bool               `bson:"selfie,omitempty"`
 	IsMasuk     bool               `bson:"ismasuk,omitempty"`
+	EmployeeID  primitive.ObjectID `bson:"employee_id,omitempty"` //pegawai toko yang presensi
+	Owner       string             `bson:"owner,omitempty"`       //nomor telepon pemilik toko pegawai
 	CreatedAt   time.Time          `bson:"createdAt"`
 }
 
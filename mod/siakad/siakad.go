@@ -5,6 +5,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"log/slog"
 	"net/http"
 	"regexp"
 	"strings"
@@ -64,7 +65,7 @@ func LoginSiakad(message itmodel.IteungMessage, db *mongo.Database) string {
 	}
 
 	// Logging the SiakadLoginURL
-	fmt.Println("SiakadLoginURL:", conf.SiakadLoginURL)
+	slog.Debug("login siakad", "url", conf.SiakadLoginURL)
 
 	if conf.SiakadLoginURL == "" {
 		return "URL untuk login tidak ditemukan dalam konfigurasi."
@@ -191,7 +192,7 @@ func extractNimandTopik(message string) (string, string) {
 		topik = strings.TrimSpace(topik)
 	}
 
-	fmt.Printf("Extracted NIM: %s, Topik: %s\n", nim, topik)
+	slog.Debug("approve bimbingan siakad", "nim", nim, "topik", topik)
 	return nim, topik
 }
 
@@ -212,7 +213,7 @@ func ApproveBimbingan(message itmodel.IteungMessage, db *mongo.Database) string
 	var conf Config
 	err := db.Collection("config").FindOne(context.TODO(), bson.M{"phonenumber": "62895601060000"}).Decode(&conf)
 	if err != nil {
-		fmt.Printf("Error fetching config: %s\n", err.Error())
+		slog.Error("gagal mengambil config siakad", "error", err)
 		return "Wah kak " + message.Alias_name + " mohon maaf ada kesalahan dalam pengambilan config di database: " + err.Error()
 	}
 
@@ -222,7 +223,7 @@ func ApproveBimbingan(message itmodel.IteungMessage, db *mongo.Database) string
 		"topik": topik,
 	})
 	if err != nil {
-		fmt.Printf("Error creating request body: %s\n", err.Error())
+		slog.Error("gagal membuat body request siakad", "error", err)
 		return "Gagal membuat request body: " + err.Error()
 	}
 
@@ -230,7 +231,7 @@ func ApproveBimbingan(message itmodel.IteungMessage, db *mongo.Database) string
 	client := &http.Client{Timeout: 10 * time.Second}
 	req, err := http.NewRequest("POST", conf.ApproveBimbinganURL, bytes.NewBuffer(requestBody))
 	if err != nil {
-		fmt.Printf("Error creating HTTP request: %s\n", err.Error())
+		slog.Error("gagal membuat request siakad", "error", err)
 		return "Gagal membuat request: " + err.Error()
 	}
 	req.Header.Set("Content-Type", "application/json")
@@ -238,7 +239,7 @@ func ApproveBimbingan(message itmodel.IteungMessage, db *mongo.Database) string
 
 	resp, err := client.Do(req)
 	if err != nil {
-		fmt.Printf("Error sending HTTP request: %s\n", err.Error())
+		slog.Error("gagal mengirim request siakad", "error", err)
 		return "Gagal mengirim request: " + err.Error()
 	}
 	defer resp.Body.Close()
@@ -259,7 +260,7 @@ func ApproveBimbingan(message itmodel.IteungMessage, db *mongo.Database) string
 	var responseMap map[string]string
 	err = json.NewDecoder(resp.Body).Decode(&responseMap)
 	if err != nil {
-		fmt.Printf("Error decoding response: %s\n", err.Error())
+		slog.Error("gagal membaca respon siakad", "error", err)
 		return "Gagal memproses response: " + err.Error()
 	}
 
@@ -283,7 +284,7 @@ func ApproveBimbinganbyPoin(message itmodel.IteungMessage, db *mongo.Database) s
 	var conf Config
 	err := db.Collection("config").FindOne(context.TODO(), bson.M{"phonenumber": "62895601060000"}).Decode(&conf)
 	if err != nil {
-		fmt.Printf("Error fetching config: %s\n", err.Error())
+		slog.Error("gagal mengambil config siakad", "error", err)
 		return "Wah kak " + message.Alias_name + " mohon maaf ada kesalahan dalam pengambilan config di database: " + err.Error()
 	}
 
@@ -293,7 +294,7 @@ func ApproveBimbinganbyPoin(message itmodel.IteungMessage, db *mongo.Database) s
 		"topik": topik,
 	})
 	if err != nil {
-		fmt.Printf("Error creating request body: %s\n", err.Error())
+		slog.Error("gagal membuat body request siakad", "error", err)
 		return "Gagal membuat request body: " + err.Error()
 	}
 
@@ -301,7 +302,7 @@ func ApproveBimbinganbyPoin(message itmodel.IteungMessage, db *mongo.Database) s
 	client := &http.Client{Timeout: 10 * time.Second}
 	req, err := http.NewRequest("POST", conf.ApproveBimbinganByPoinURL, bytes.NewBuffer(requestBody))
 	if err != nil {
-		fmt.Printf("Error creating HTTP request: %s\n", err.Error())
+		slog.Error("gagal membuat request siakad", "error", err)
 		return "Gagal membuat request: " + err.Error()
 	}
 	req.Header.Set("Content-Type", "application/json")
@@ -309,7 +310,7 @@ func ApproveBimbinganbyPoin(message itmodel.IteungMessage, db *mongo.Database) s
 
 	resp, err := client.Do(req)
 	if err != nil {
-		fmt.Printf("Error sending HTTP request: %s\n", err.Error())
+		slog.Error("gagal mengirim request siakad", "error", err)
 		return "Gagal mengirim request: " + err.Error()
 	}
 	defer resp.Body.Close()
@@ -330,7 +331,7 @@ func ApproveBimbinganbyPoin(message itmodel.IteungMessage, db *mongo.Database) s
 	var responseMap map[string]string
 	err = json.NewDecoder(resp.Body).Decode(&responseMap)
 	if err != nil {
-		fmt.Printf("Error decoding response: %s\n", err.Error())
+		slog.Error("gagal membaca respon siakad", "error", err)
 		return "Gagal memproses response: " + err.Error()
 	}
 
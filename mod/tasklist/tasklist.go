@@ -1,7 +1,7 @@
 package tasklist
 
 import (
-	"fmt"
+	"log/slog"
 	"net/http"
 	"strconv"
 	"strings"
@@ -100,7 +100,7 @@ func GetIDandTask(input string) (cleanedStrBefore, cleanedStrAfter string) {
 	// Find the position of the delimiter "|||"
 	pos := strings.Index(input, "|||")
 	if pos == -1 {
-		fmt.Println("Delimiter not found")
+		slog.Debug("pemisah ||| tidak ditemukan di pesan task")
 		return
 	}
 
@@ -116,7 +116,7 @@ func GetIDandTask(input string) (cleanedStrBefore, cleanedStrAfter string) {
 	// Find the position of the last occurrence of "&&"
 	posLastAnd := strings.LastIndex(substrBefore, "-.-")
 	if posLastAnd == -1 {
-		fmt.Println("Delimiter '&&' not found")
+		slog.Debug("pemisah -.- tidak ditemukan di pesan task")
 		return
 	}
 
@@ -8,23 +8,36 @@ import (
 // Product adalah struct untuk produk
 type Product struct {
     ID          primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
+    Owner       string             `bson:"owner,omitempty" json:"-"` // Nomor telepon akun penjual pemilik data
     Name        string  `bson:"name" json:"name"`
-    Price       float64 `bson:"price" json:"price"`
+    Price       float64 `bson:"price" json:"price" validate:"nonnegative"`
     Category    string  `bson:"category" json:"category"`
     Description string  `bson:"description" json:"description"`
-    Stock       int     `bson:"stock" json:"stock"`
+    Stock       int     `bson:"stock" json:"stock" validate:"nonnegative"`
+    Cost        float64 `bson:"cost,omitempty" json:"cost,omitempty" validate:"nonnegative"`         // Harga pokok per unit untuk HPP
+    Quantity    int     `bson:"quantity,omitempty" json:"quantity,omitempty" validate:"nonnegative"` // Jumlah unit saat produk menjadi item penjualan
+    Backorder   int     `bson:"backorder,omitempty" json:"backorder,omitempty"` // Jumlah unit item penjualan yang belum tersedia di stok
+    TaxRate     *float64 `bson:"tax_rate,omitempty" json:"tax_rate,omitempty"`  // Tarif PPN (persen) khusus produk, kosong berarti ikut kategori atau toko
+    Discount    float64 `bson:"discount,omitempty" json:"discount,omitempty" validate:"nonnegative"`   // Potongan rupiah item penjualan untuk seluruh kuantitas, termasuk bagian diskon keranjang
+    Returned    int     `bson:"returned,omitempty" json:"returned,omitempty"`   // Jumlah unit item penjualan yang sudah diretur
     CreatedAt   time.Time   `bson:"createdAt" json:"createdAt"`
     UpdatedAt   time.Time   `bson:"updatedAt" json:"updatedAt"`
+    DeletedAt   *time.Time  `bson:"deleted_at,omitempty" json:"deleted_at,omitempty"` // Waktu data dihapus, data terhapus tidak ikut transaksi dan laporan
+    DeletedBy   string      `bson:"deleted_by,omitempty" json:"deleted_by,omitempty"` // Nomor telepon akun yang menghapus
 }
 
 type Customer struct {
 	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
-	Name      string             `bson:"name" json:"name"`
-	Email     string             `bson:"email" json:"email"`
+	Owner     string             `bson:"owner,omitempty" json:"-"`
+	Name      string             `bson:"name" json:"name" validate:"required"`
+	Email     string             `bson:"email" json:"email" validate:"email"`
 	Phone     string             `bson:"phone" json:"phone"`
 	Address   string             `bson:"address" json:"address"`
+	NPWP      string             `bson:"npwp,omitempty" json:"npwp,omitempty"` // NPWP pembeli untuk faktur pajak
 	CreatedAt time.Time          `bson:"createdAt" json:"createdAt"`
 	UpdatedAt time.Time          `bson:"updatedAt" json:"updatedAt"`
+	DeletedAt *time.Time         `bson:"deleted_at,omitempty" json:"deleted_at,omitempty"`
+	DeletedBy string             `bson:"deleted_by,omitempty" json:"deleted_by,omitempty"`
 }
 
 type User struct {
@@ -38,30 +51,182 @@ type User struct {
 // ExpenseTransaction adalah struct untuk transaksi pengeluaran
 type ExpenseTransaction struct {
     ID            primitive.ObjectID    `bson:"_id,omitempty" json:"id,omitempty"`
-    ExpenseName  string    `bson:"expense_name" json:"expense_name"`   // Nama pengeluaran (misalnya: sewa, gaji, dll.)
-    Amount        float64   `bson:"amount" json:"amount"`               // Jumlah uang yang dikeluarkan
+    Owner         string                `bson:"owner,omitempty" json:"-"`
+    ExpenseName  string    `bson:"expense_name" json:"expense_name" validate:"required"`   // Nama pengeluaran (misalnya: sewa, gaji, dll.)
+    Amount        float64   `bson:"amount" json:"amount" validate:"positive"`               // Jumlah uang yang dikeluarkan
+    TaxAmount     float64   `bson:"tax_amount,omitempty" json:"tax_amount,omitempty" validate:"nonnegative"` // PPN masukan yang sudah termasuk dalam amount
     Category      string    `bson:"category" json:"category"`           // Kategori pengeluaran (misalnya: operasional, marketing, dll.)
     PaymentMethod string   `bson:"payment_method" json:"payment_method"` // Metode pembayaran (misalnya: transfer bank, tunai)
     ExpenseDate  time.Time `bson:"expense_date" json:"expense_date"`   // Tanggal pengeluaran
     Notes         string    `bson:"notes" json:"notes,omitempty"`       // Catatan tambahan (opsional)
     CreatedAt    time.Time `bson:"created_at" json:"created_at"`       // Waktu transaksi dibuat
     UpdatedAt    time.Time `bson:"updated_at" json:"updated_at"`       // Waktu transaksi terakhir diperbarui
+    DeletedAt    *time.Time `bson:"deleted_at,omitempty" json:"deleted_at,omitempty"`
+    DeletedBy    string     `bson:"deleted_by,omitempty" json:"deleted_by,omitempty"`
+    RecurringID  *primitive.ObjectID `bson:"recurring_id,omitempty" json:"recurring_id,omitempty"` // Template pengeluaran rutin yang membuatnya
 }
 
 
 // SalesTransaction adalah struct untuk transaksi penjualan
 type SalesTransaction struct {
     ID            primitive.ObjectID    `bson:"_id,omitempty" json:"id,omitempty"`
+    Owner         string                `bson:"owner,omitempty" json:"-"`
+    InvoiceNumber string    `bson:"invoice_number,omitempty" json:"invoice_number,omitempty"` // Nomor faktur berurutan
     TransactionDate time.Time `bson:"transactionDate" json:"transactionDate"`
+    CustomerID    primitive.ObjectID `bson:"customer_id,omitempty" json:"customer_id,omitempty"` // Untuk nomor WhatsApp pelanggan
     CustomerName  string    `bson:"customer_name" json:"customer_name"`
     Products      []Product `bson:"products" json:"products"`
     TotalAmount   float64   `bson:"total_amount" json:"total_amount"`
+    TaxAmount     float64   `bson:"tax_amount,omitempty" json:"tax_amount,omitempty"` // Pajak yang sudah termasuk dalam total
+    TaxLines      []TaxLine `bson:"tax_lines,omitempty" json:"tax_lines,omitempty"`   // Rincian PPN per tarif, dihitung server
+    PriceIncludesTax bool   `bson:"price_includes_tax,omitempty" json:"price_includes_tax,omitempty"` // Harga item sudah termasuk PPN
     PaymentMethod string    `bson:"payment_method" json:"payment_method"`
     PaymentStatus string    `bson:"payment_status" json:"payment_status"`
+    AllowBackorder bool     `bson:"-" json:"allow_backorder,omitempty"`              // Izinkan penjualan melebihi stok
+    Backorder     bool      `bson:"backorder,omitempty" json:"backorder,omitempty"` // Ada item yang menunggu stok
+    PaidAmount    float64   `bson:"paid_amount" json:"paid_amount"`                 // Total yang sudah dibayar
+    DueDate       time.Time `bson:"due_date,omitempty" json:"due_date,omitempty"`   // Jatuh tempo penjualan kredit
+    Payments      []SalesPayment `bson:"payments,omitempty" json:"payments,omitempty"`
+    RemindedAt    time.Time `bson:"reminded_at,omitempty" json:"reminded_at,omitempty"` // Pengingat pembayaran terakhir via WhatsApp
+    Subtotal      float64   `bson:"subtotal,omitempty" json:"subtotal,omitempty"`               // Harga kali kuantitas sebelum diskon, diisi checkout kasir
+    DiscountAmount float64  `bson:"discount_amount,omitempty" json:"discount_amount,omitempty"` // Total diskon item, keranjang dan kode promo
+    PromoCode     string    `bson:"promo_code,omitempty" json:"promo_code,omitempty"`
+    Tendered      float64   `bson:"tendered,omitempty" json:"tendered,omitempty"` // Uang yang diterima kasir
+    Change        float64   `bson:"change,omitempty" json:"change,omitempty"`     // Kembalian
+    Cashier       string    `bson:"cashier,omitempty" json:"cashier,omitempty"`   // Nomor telepon akun yang melakukan checkout
+    ReturnedAmount float64  `bson:"returned_amount,omitempty" json:"returned_amount,omitempty"` // Total nilai retur termasuk PPN
+    CreditedAmount float64  `bson:"credited_amount,omitempty" json:"credited_amount,omitempty"` // Bagian retur yang diselesaikan dengan nota kredit, mengurangi piutang
+    DeletedAt    *time.Time `bson:"deleted_at,omitempty" json:"deleted_at,omitempty"`
+    DeletedBy    string     `bson:"deleted_by,omitempty" json:"deleted_by,omitempty"`
+}
+
+// SalesReturn adalah retur penjualan sebagian atau seluruh item, diselesaikan dengan pengembalian uang atau nota kredit
+type SalesReturn struct {
+    ID            primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
+    Owner         string             `bson:"owner,omitempty" json:"-"`
+    ReturnNumber  string             `bson:"return_number" json:"return_number"` // RTR/<tahun>/<urut>
+    SalesID       primitive.ObjectID `bson:"sales_id" json:"sales_id"`
+    InvoiceNumber string             `bson:"invoice_number,omitempty" json:"invoice_number,omitempty"`
+    CustomerID    primitive.ObjectID `bson:"customer_id,omitempty" json:"customer_id,omitempty"`
+    CustomerName  string             `bson:"customer_name" json:"customer_name"`
+    Items         []ReturnItem       `bson:"items" json:"items"`
+    TaxAmount     float64            `bson:"tax_amount,omitempty" json:"tax_amount,omitempty"` // PPN keluaran yang dibatalkan
+    TotalAmount   float64            `bson:"total_amount" json:"total_amount"`                 // Nilai retur termasuk PPN
+    Cost          float64            `bson:"cost,omitempty" json:"cost,omitempty"`             // Harga pokok item yang kembali ke stok
+    Settlement    string             `bson:"settlement" json:"settlement"`                     // refund atau credit_note
+    PaymentMethod string             `bson:"payment_method,omitempty" json:"payment_method,omitempty"` // Metode pengembalian uang
+    Reason        string             `bson:"reason,omitempty" json:"reason,omitempty"`
+    ReturnDate    time.Time          `bson:"return_date" json:"return_date"`
+    CreatedBy     string             `bson:"created_by,omitempty" json:"created_by,omitempty"`
+    CreatedAt     time.Time          `bson:"createdAt" json:"createdAt"`
+}
+
+// ReturnItem adalah satu item penjualan yang diretur
+type ReturnItem struct {
+    Line      int                `bson:"line" json:"line"` // Urutan item pada penjualan, mulai dari 0
+    ProductID primitive.ObjectID `bson:"product_id,omitempty" json:"product_id,omitempty"`
+    Name      string             `bson:"name" json:"name"`
+    Quantity  int                `bson:"quantity" json:"quantity"`
+    Amount    float64            `bson:"amount" json:"amount"`                             // Nilai retur termasuk PPN setelah diskon
+    TaxAmount float64            `bson:"tax_amount,omitempty" json:"tax_amount,omitempty"`
+    Cost      float64            `bson:"cost,omitempty" json:"cost,omitempty"` // Harga pokok per unit
+    Damaged   bool               `bson:"damaged,omitempty" json:"damaged,omitempty"` // Barang rusak tidak dikembalikan ke stok
+}
+
+// Promo adalah kode promo diskon keranjang untuk checkout kasir
+type Promo struct {
+    ID          primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
+    Owner       string             `bson:"owner,omitempty" json:"-"`
+    Code        string             `bson:"code" json:"code" validate:"required"` // Disimpan huruf besar
+    Name        string             `bson:"name" json:"name"`
+    Type        string             `bson:"type" json:"type" validate:"oneof=percent amount"`   // percent atau amount
+    Value       float64            `bson:"value" json:"value" validate:"positive"` // Persen atau rupiah sesuai Type
+    MinPurchase float64            `bson:"min_purchase,omitempty" json:"min_purchase,omitempty" validate:"nonnegative"` // Minimal belanja setelah diskon item
+    MaxDiscount float64            `bson:"max_discount,omitempty" json:"max_discount,omitempty" validate:"nonnegative"` // Batas diskon promo persen, 0 berarti tanpa batas
+    StartAt     time.Time          `bson:"start_at,omitempty" json:"start_at,omitempty"`
+    EndAt       time.Time          `bson:"end_at,omitempty" json:"end_at,omitempty"`
+    UsageLimit  int                `bson:"usage_limit,omitempty" json:"usage_limit,omitempty" validate:"nonnegative"` // 0 berarti tanpa batas
+    UsedCount   int                `bson:"used_count" json:"used_count"`
+    Active      bool               `bson:"active" json:"active"`
+    CreatedAt   time.Time          `bson:"createdAt" json:"createdAt"`
+    UpdatedAt   time.Time          `bson:"updatedAt" json:"updatedAt"`
+}
+
+// StoreProfile adalah identitas toko yang dicetak di kepala faktur dan struk
+type StoreProfile struct {
+    ID        primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
+    Owner     string             `bson:"owner,omitempty" json:"-"`
+    Name      string             `bson:"name" json:"name"`
+    Address   string             `bson:"address" json:"address"`
+    Phone     string             `bson:"phone" json:"phone"`
+    NPWP      string             `bson:"npwp,omitempty" json:"npwp,omitempty"`
+    Footer    string             `bson:"footer,omitempty" json:"footer,omitempty"` // Catatan di bawah faktur, misalnya rekening bank
+    ReminderDays int             `bson:"reminder_days,omitempty" json:"reminder_days,omitempty"` // Kirim pengingat untuk piutang lebih dari N hari, 0 berarti default
+    PKP       bool               `bson:"pkp,omitempty" json:"pkp,omitempty"`                   // Pengusaha Kena Pajak, penjualan dikenai PPN
+    PPNRate   float64            `bson:"ppn_rate,omitempty" json:"ppn_rate,omitempty"`         // Tarif PPN default (persen), 0 berarti 11
+    PriceIncludesTax bool        `bson:"price_includes_tax,omitempty" json:"price_includes_tax,omitempty"` // Harga jual produk sudah termasuk PPN
+    ShiftStart       string      `bson:"shift_start,omitempty" json:"shift_start,omitempty"`               // Jam masuk pegawai HH:MM WIB, kosong berarti 08:00
+    LateGraceMinutes int         `bson:"late_grace_minutes,omitempty" json:"late_grace_minutes,omitempty"` // Toleransi terlambat dalam menit
+    WorkDays         []int       `bson:"work_days,omitempty" json:"work_days,omitempty"`                   // Hari kerja 0=Minggu sampai 6=Sabtu, kosong berarti Senin sampai Sabtu
+    UpdatedAt time.Time          `bson:"updatedAt" json:"updatedAt"`
+}
+
+// TaxLine adalah rincian pajak satu tarif pada transaksi
+type TaxLine struct {
+    Code   string  `bson:"code" json:"code"`     // Jenis pajak, misalnya PPN
+    Rate   float64 `bson:"rate" json:"rate"`     // Tarif dalam persen
+    Base   float64 `bson:"base" json:"base"`     // Dasar pengenaan pajak (DPP)
+    Amount float64 `bson:"amount" json:"amount"` // Nominal pajak
+}
+
+// SalesPayment adalah satu kali pembayaran (cicilan) atas penjualan kredit
+type SalesPayment struct {
+    ID            primitive.ObjectID `bson:"_id" json:"id"`
+    Amount        float64            `bson:"amount" json:"amount" validate:"positive"`
+    PaymentMethod string             `bson:"payment_method" json:"payment_method"`
+    Notes         string             `bson:"notes,omitempty" json:"notes,omitempty"`
+    PaidAt        time.Time          `bson:"paid_at" json:"paid_at"`
+}
+
+// AgingRow adalah umur piutang satu pelanggan per kelompok hari
+type AgingRow struct {
+    CustomerName string  `json:"customer_name"`
+    Current      float64 `json:"days_0_30"`
+    Days31To60   float64 `json:"days_31_60"`
+    Days61To90   float64 `json:"days_61_90"`
+    Over90       float64 `json:"days_over_90"`
+    Total        float64 `json:"total"`
+    Invoices     int     `json:"invoices"`
+}
+
+// AgingReport adalah laporan umur piutang per pelanggan
+type AgingReport struct {
+    AsOf   string     `json:"asof"`
+    Rows   []AgingRow `json:"rows"`
+    Totals AgingRow   `json:"totals"`
+}
+
+// PPNSection adalah ringkasan PPN keluaran atau masukan dalam satu masa pajak
+type PPNSection struct {
+    Count  int       `json:"count"`
+    Base   float64   `json:"base"`   // Total DPP
+    Tax    float64   `json:"tax"`    // Total PPN
+    ByRate []TaxLine `json:"by_rate,omitempty"`
+}
+
+// PPNReport adalah laporan PPN bulanan: keluaran dari penjualan, masukan dari pengeluaran dan pembelian
+type PPNReport struct {
+    Period string     `json:"period"` // YYYY-MM
+    Output PPNSection `json:"output"`
+    Input  PPNSection `json:"input"`
+    Returns PPNSection `json:"returns"` // Retur penjualan yang mengurangi PPN keluaran
+    Net    float64    `json:"net"`    // PPN keluaran dikurangi retur dan masukan, positif berarti kurang bayar
+    Status string     `json:"status"` // kurang bayar, lebih bayar, nihil
 }
 
 type LaporanAkuntan struct {
     ID        primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
+    Owner     string             `bson:"owner,omitempty" json:"-"`
     StartDate  string             `bson:"startDate" json:"startDate"`   // Menggunakan string
     EndDate    string             `bson:"endDate" json:"endDate"`       // Menggunakan string
     StartDateTime time.Time       `bson:"startDateTime" json:"startDateTime"` // Menyimpan waktu yang sudah diparse
@@ -69,24 +234,359 @@ type LaporanAkuntan struct {
 	Income    float64   `bson:"income" json:"income"`
 	Expenses  float64   `bson:"expenses" json:"expenses"`
 	Profit    float64   `bson:"profit" json:"profit"`
+	Returns     float64                `bson:"returns" json:"returns"`           // Retur penjualan tanpa PPN, sudah dikurangkan dari income
+	COGS        float64                `bson:"cogs" json:"cogs"`                 // Harga pokok penjualan
+	GrossProfit float64                `bson:"grossProfit" json:"grossProfit"`   // Pendapatan dikurangi HPP
+	ExpenseByCategory []CategoryAmount `bson:"expenseByCategory" json:"expenseByCategory"`
 	CreatedAt time.Time `bson:"createdAt" json:"createdAt"`
+	DeletedAt *time.Time         `bson:"deleted_at,omitempty" json:"deleted_at,omitempty"`
+	DeletedBy string             `bson:"deleted_by,omitempty" json:"deleted_by,omitempty"`
+}
+
+// CategoryAmount adalah total nominal per kategori
+type CategoryAmount struct {
+    Category string  `bson:"_id" json:"category"`
+    Amount   float64 `bson:"amount" json:"amount"`
+    Count    int     `bson:"count" json:"count"`
 }
 
 type Employee struct {
     ID           primitive.ObjectID       `json:"id" bson:"_id"`
-    Name    string    `json:"name" bson:"name"`
-    Email        string    `json:"email" bson:"email"`
+    Owner        string                   `bson:"owner,omitempty" json:"-"`
+    Name    string    `json:"name" bson:"name" validate:"required"`
+    Email        string    `json:"email" bson:"email" validate:"email"`
     PhoneNumber  string    `json:"phone_number" bson:"phone_number"`
     Position     string    `json:"position" bson:"position"`
+    BaseSalary   float64        `json:"base_salary,omitempty" bson:"base_salary,omitempty" validate:"nonnegative"` // Gaji pokok bulanan
+    Allowances   []PayComponent `json:"allowances,omitempty" bson:"allowances,omitempty"`   // Tunjangan tetap bulanan
+    Deductions   []PayComponent `json:"deductions,omitempty" bson:"deductions,omitempty"`   // Potongan tetap bulanan, misalnya cicilan kasbon
+    TaxStatus    string    `json:"tax_status,omitempty" bson:"tax_status,omitempty"`         // Status PTKP, misalnya TK/0 atau K/1
+    BPJSKesehatan       bool `json:"bpjs_kesehatan,omitempty" bson:"bpjs_kesehatan,omitempty"`
+    BPJSKetenagakerjaan bool `json:"bpjs_ketenagakerjaan,omitempty" bson:"bpjs_ketenagakerjaan,omitempty"`
+    ShiftStart      string  `json:"shift_start,omitempty" bson:"shift_start,omitempty"`           // Jam masuk HH:MM, kosong berarti mengikuti jam masuk toko
+    AbsentDeduction float64 `json:"absent_deduction,omitempty" bson:"absent_deduction,omitempty" validate:"nonnegative"` // Potongan gaji per hari tidak hadir
+    LateDeduction   float64 `json:"late_deduction,omitempty" bson:"late_deduction,omitempty" validate:"nonnegative"`     // Potongan gaji per hari terlambat
     CreatedAt    time.Time `json:"created_at" bson:"created_at"`
     UpdatedAt    time.Time `json:"updated_at" bson:"updated_at"`
+    DeletedAt    *time.Time `json:"deleted_at,omitempty" bson:"deleted_at,omitempty"`
+    DeletedBy    string     `json:"deleted_by,omitempty" bson:"deleted_by,omitempty"`
+}
+
+// PayComponent adalah satu komponen gaji, misalnya tunjangan transport, potongan kasbon atau iuran BPJS
+type PayComponent struct {
+    Name   string  `json:"name" bson:"name"`
+    Amount float64 `json:"amount" bson:"amount"`
+}
+
+// Payslip adalah slip gaji satu pegawai dalam payroll bulanan
+type Payslip struct {
+    EmployeeID   primitive.ObjectID `json:"employee_id" bson:"employee_id"`
+    Name         string             `json:"name" bson:"name"`
+    Position     string             `json:"position" bson:"position"`
+    TaxStatus    string             `json:"tax_status" bson:"tax_status"`
+    BaseSalary   float64            `json:"base_salary" bson:"base_salary"`
+    Allowances   []PayComponent     `json:"allowances,omitempty" bson:"allowances,omitempty"`
+    Deductions   []PayComponent     `json:"deductions,omitempty" bson:"deductions,omitempty"`
+    BPJSEmployee []PayComponent     `json:"bpjs_employee,omitempty" bson:"bpjs_employee,omitempty"` // Iuran BPJS yang dipotong dari gaji
+    BPJSEmployer []PayComponent     `json:"bpjs_employer,omitempty" bson:"bpjs_employer,omitempty"` // Iuran BPJS yang ditanggung toko
+    Gross        float64            `json:"gross" bson:"gross"`   // Gaji pokok ditambah tunjangan dikurangi potongan kehadiran
+    TaxableGross float64            `json:"taxable_gross" bson:"taxable_gross"` // Bruto PPh 21: gaji ditambah iuran BPJS Kesehatan, JKK dan JKM dari toko
+    Pension      float64            `json:"pension" bson:"pension"`               // Iuran JHT dan JP pegawai yang mengurangi penghasilan neto
+    TaxMethod    string             `json:"tax_method" bson:"tax_method"`         // TER A/B/C untuk Januari sampai November, Pasal 17 untuk Desember
+    PPh21        float64            `json:"pph21" bson:"pph21"`                   // Negatif jika potongan Desember lebih kecil dari yang sudah dipotong
+    NetPay       float64            `json:"net_pay" bson:"net_pay"` // Gaji yang diterima pegawai
+    Cost         float64            `json:"cost" bson:"cost"`       // Biaya toko: bruto ditambah iuran BPJS pemberi kerja
+    Attendance   *AttendanceSummary `json:"attendance,omitempty" bson:"attendance,omitempty"` // Rekap kehadiran bulan payroll
+}
+
+// AttendanceDay adalah presensi satu pegawai pada satu hari
+type AttendanceDay struct {
+    Date        string     `json:"date" bson:"date"` // YYYY-MM-DD
+    CheckIn     *time.Time `json:"check_in,omitempty" bson:"check_in,omitempty"`
+    CheckOut    *time.Time `json:"check_out,omitempty" bson:"check_out,omitempty"`
+    Location    string     `json:"location,omitempty" bson:"location,omitempty"`
+    Late        bool       `json:"late,omitempty" bson:"late,omitempty"`
+    LateMinutes int        `json:"late_minutes,omitempty" bson:"late_minutes,omitempty"`
+    WorkedHours float64    `json:"worked_hours,omitempty" bson:"worked_hours,omitempty"`
+    Absent      bool       `json:"absent,omitempty" bson:"absent,omitempty"` // Hari kerja tanpa presensi masuk
+}
+
+// AttendanceSummary adalah rekap kehadiran bulanan satu pegawai
+type AttendanceSummary struct {
+    EmployeeID  primitive.ObjectID `json:"employee_id" bson:"employee_id"`
+    Name        string             `json:"name" bson:"name"`
+    Month       string             `json:"month" bson:"month"`         // YYYY-MM
+    WorkDays    int                `json:"work_days" bson:"work_days"` // Hari kerja terjadwal yang sudah lewat
+    Present     int                `json:"present" bson:"present"`
+    Late        int                `json:"late" bson:"late"`
+    LateMinutes int                `json:"late_minutes" bson:"late_minutes"`
+    Absent      int                `json:"absent" bson:"absent"`
+    WorkedHours float64            `json:"worked_hours" bson:"worked_hours"`
+    Days        []AttendanceDay    `json:"days,omitempty" bson:"-"`
+}
+
+// PayrollRun adalah payroll satu bulan beserta slip gaji semua pegawai
+type PayrollRun struct {
+    ID            primitive.ObjectID `json:"id,omitempty" bson:"_id,omitempty"`
+    Owner         string             `json:"-" bson:"owner,omitempty"`
+    Period        string             `json:"period" bson:"period"` // YYYY-MM
+    PaymentMethod string             `json:"payment_method" bson:"payment_method"`
+    Payslips      []Payslip          `json:"payslips" bson:"payslips"`
+    TotalGross    float64            `json:"total_gross" bson:"total_gross"`
+    TotalPPh21    float64            `json:"total_pph21" bson:"total_pph21"`
+    TotalNet      float64            `json:"total_net" bson:"total_net"`
+    TotalCost     float64            `json:"total_cost" bson:"total_cost"`
+    ExpenseID     primitive.ObjectID `json:"expense_id,omitempty" bson:"expense_id,omitempty"` // Pengeluaran gaji di expense_transaction
+    CreatedAt     time.Time          `json:"created_at" bson:"created_at"`
+    DeletedAt     *time.Time         `json:"deleted_at,omitempty" bson:"deleted_at,omitempty"`
+    DeletedBy     string             `json:"deleted_by,omitempty" bson:"deleted_by,omitempty"`
 }
 
 // Category adalah struct untuk kategori produk
 type Category struct {
     ID          primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
-    Name        string             `bson:"name" json:"name"`
+    Owner       string             `bson:"owner,omitempty" json:"-"`
+    Name        string             `bson:"name" json:"name" validate:"required"`
     Description string             `bson:"description" json:"description"`
+    TaxRate     *float64           `bson:"tax_rate,omitempty" json:"tax_rate,omitempty"` // Tarif PPN (persen) untuk produk kategori ini
     CreatedAt   time.Time          `bson:"createdAt" json:"createdAt"`
     UpdatedAt   time.Time          `bson:"updatedAt" json:"updatedAt"`
 }
+
+// Account adalah akun pada bagan akun (chart of accounts)
+type Account struct {
+    ID          primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
+    Owner       string             `bson:"owner,omitempty" json:"-"`
+    Code        string             `bson:"code" json:"code" validate:"required"`               // Kode akun, misalnya 1101
+    Name        string             `bson:"name" json:"name" validate:"required"`               // Nama akun, misalnya Kas
+    Type        string             `bson:"type" json:"type" validate:"oneof=asset liability equity revenue expense"`               // asset, liability, equity, revenue, expense
+    Description string             `bson:"description,omitempty" json:"description,omitempty"`
+    CreatedAt   time.Time          `bson:"createdAt" json:"createdAt"`
+    UpdatedAt   time.Time          `bson:"updatedAt" json:"updatedAt"`
+}
+
+// JournalLine adalah satu baris debit atau kredit dalam jurnal
+type JournalLine struct {
+    AccountCode string  `bson:"account_code" json:"account_code" validate:"required"`
+    AccountName string  `bson:"account_name" json:"account_name"`
+    Debit       float64 `bson:"debit" json:"debit" validate:"nonnegative"`
+    Credit      float64 `bson:"credit" json:"credit" validate:"nonnegative"`
+}
+
+// JournalEntry adalah jurnal umum yang selalu seimbang antara debit dan kredit
+type JournalEntry struct {
+    ID          primitive.ObjectID  `bson:"_id,omitempty" json:"id,omitempty"`
+    Owner       string              `bson:"owner,omitempty" json:"-"`
+    Date        time.Time           `bson:"date" json:"date"`
+    Description string              `bson:"description" json:"description"`
+    SourceType  string              `bson:"source_type" json:"source_type"` // sales, expense, manual, reversal
+    SourceID    primitive.ObjectID  `bson:"source_id,omitempty" json:"source_id,omitempty"`
+    Lines       []JournalLine       `bson:"lines" json:"lines" validate:"required"`
+    Reversed    bool                `bson:"reversed,omitempty" json:"reversed,omitempty"`
+    ReversalOf  *primitive.ObjectID `bson:"reversal_of,omitempty" json:"reversal_of,omitempty"`
+    CreatedAt   time.Time           `bson:"createdAt" json:"createdAt"`
+}
+
+// TrialBalanceLine adalah saldo per akun pada neraca saldo
+type TrialBalanceLine struct {
+    AccountCode string  `bson:"_id" json:"account_code"`
+    AccountName string  `bson:"account_name" json:"account_name"`
+    Debit       float64 `bson:"debit" json:"debit"`
+    Credit      float64 `bson:"credit" json:"credit"`
+    Balance     float64 `bson:"-" json:"balance"`
+}
+
+// StatementLine adalah satu pos pada laporan posisi keuangan
+type StatementLine struct {
+    AccountCode string  `bson:"account_code" json:"account_code"`
+    AccountName string  `bson:"account_name" json:"account_name"`
+    Amount      float64 `bson:"amount" json:"amount"`
+}
+
+// BalanceSheet adalah laporan posisi keuangan (neraca) per tanggal
+type BalanceSheet struct {
+    AsOf             time.Time       `bson:"asOf" json:"asOf"`
+    Assets           []StatementLine `bson:"assets" json:"assets"`
+    Liabilities      []StatementLine `bson:"liabilities" json:"liabilities"`
+    Equity           []StatementLine `bson:"equity" json:"equity"`
+    TotalAssets      float64         `bson:"totalAssets" json:"totalAssets"`
+    TotalLiabilities float64         `bson:"totalLiabilities" json:"totalLiabilities"`
+    TotalEquity      float64         `bson:"totalEquity" json:"totalEquity"`
+    Balanced         bool            `bson:"balanced" json:"balanced"`
+}
+
+// CashFlowLine adalah satu arus kas masuk atau keluar per metode pembayaran
+type CashFlowLine struct {
+    Description   string  `bson:"description" json:"description"`
+    PaymentMethod string  `bson:"payment_method" json:"payment_method"`
+    Amount        float64 `bson:"amount" json:"amount"`
+}
+
+// CashFlowSection adalah kelompok aktivitas pada laporan arus kas
+type CashFlowSection struct {
+    Inflows  []CashFlowLine `bson:"inflows" json:"inflows"`
+    Outflows []CashFlowLine `bson:"outflows" json:"outflows"`
+    Net      float64        `bson:"net" json:"net"`
+}
+
+// CashFlowStatement adalah laporan arus kas metode langsung
+type CashFlowStatement struct {
+    StartDate   string          `bson:"startDate" json:"startDate"`
+    EndDate     string          `bson:"endDate" json:"endDate"`
+    OpeningCash float64         `bson:"openingCash" json:"openingCash"`
+    Operating   CashFlowSection `bson:"operating" json:"operating"`
+    Investing   CashFlowSection `bson:"investing" json:"investing"`
+    Financing   CashFlowSection `bson:"financing" json:"financing"`
+    NetChange   float64         `bson:"netChange" json:"netChange"`
+    ClosingCash float64         `bson:"closingCash" json:"closingCash"`
+}
+
+// FinancialStatementSnapshot adalah salinan neraca atau arus kas yang disimpan untuk dibandingkan kemudian
+type FinancialStatementSnapshot struct {
+    ID           primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
+    Owner        string             `bson:"owner,omitempty" json:"-"`
+    Type         string             `bson:"type" json:"type"` // balance_sheet atau cash_flow
+    StartDate    string             `bson:"startDate,omitempty" json:"startDate,omitempty"`
+    EndDate      string             `bson:"endDate" json:"endDate"` // Tanggal posisi untuk neraca
+    BalanceSheet *BalanceSheet      `bson:"balanceSheet,omitempty" json:"balanceSheet,omitempty"`
+    CashFlow     *CashFlowStatement `bson:"cashFlow,omitempty" json:"cashFlow,omitempty"`
+    CreatedAt    time.Time          `bson:"createdAt" json:"createdAt"`
+}
+
+// StockMovement adalah catatan mutasi stok produk
+type StockMovement struct {
+    ID          primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
+    Owner       string             `bson:"owner,omitempty" json:"-"`
+    ProductID   primitive.ObjectID `bson:"product_id" json:"product_id"`
+    ProductName string             `bson:"product_name" json:"product_name"`
+    Type        string             `bson:"type" json:"type"`         // sale, purchase, adjustment, return, opening
+    Quantity    int                `bson:"quantity" json:"quantity"` // Positif untuk stok masuk, negatif untuk stok keluar
+    StockAfter  int                `bson:"stock_after" json:"stock_after"`
+    UnitCost    float64            `bson:"unit_cost,omitempty" json:"unit_cost,omitempty"`
+    SourceType  string             `bson:"source_type,omitempty" json:"source_type,omitempty"`
+    SourceID    primitive.ObjectID `bson:"source_id,omitempty" json:"source_id,omitempty"`
+    Notes       string             `bson:"notes,omitempty" json:"notes,omitempty"`
+    CreatedAt   time.Time          `bson:"createdAt" json:"createdAt"`
+}
+
+// Supplier adalah pemasok barang untuk toko
+type Supplier struct {
+	ID            primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
+	Owner         string             `bson:"owner,omitempty" json:"-"`
+	Name          string             `bson:"name" json:"name" validate:"required"`
+	ContactPerson string             `bson:"contact_person,omitempty" json:"contact_person,omitempty"`
+	Email         string             `bson:"email" json:"email" validate:"email"`
+	Phone         string             `bson:"phone" json:"phone"`
+	Address       string             `bson:"address" json:"address"`
+	CreatedAt     time.Time          `bson:"createdAt" json:"createdAt"`
+	UpdatedAt     time.Time          `bson:"updatedAt" json:"updatedAt"`
+}
+
+// PurchaseOrderItem adalah baris barang pada purchase order
+type PurchaseOrderItem struct {
+	ProductID   primitive.ObjectID `bson:"product_id" json:"product_id"`
+	ProductName string             `bson:"product_name" json:"product_name"`
+	Quantity    int                `bson:"quantity" json:"quantity" validate:"positive"`
+	UnitCost    float64            `bson:"unit_cost" json:"unit_cost" validate:"nonnegative"`
+	Subtotal    float64            `bson:"subtotal" json:"subtotal"`
+}
+
+// PurchaseOrder adalah pesanan pembelian ke supplier dengan status draft, ordered, received, billed
+type PurchaseOrder struct {
+	ID            primitive.ObjectID  `bson:"_id,omitempty" json:"id,omitempty"`
+	Owner         string              `bson:"owner,omitempty" json:"-"`
+	SupplierID    primitive.ObjectID  `bson:"supplier_id" json:"supplier_id"`
+	SupplierName  string              `bson:"supplier_name" json:"supplier_name"`
+	Items         []PurchaseOrderItem `bson:"items" json:"items"`
+	TotalAmount   float64             `bson:"total_amount" json:"total_amount"`
+	TaxAmount     float64             `bson:"tax_amount,omitempty" json:"tax_amount,omitempty"` // PPN masukan dari faktur supplier, ditambahkan ke total
+	Status        string              `bson:"status" json:"status"`
+	InvoiceNumber string              `bson:"invoice_number,omitempty" json:"invoice_number,omitempty"`
+	Notes         string              `bson:"notes,omitempty" json:"notes,omitempty"`
+	OrderedAt     time.Time           `bson:"ordered_at,omitempty" json:"ordered_at,omitempty"`
+	ReceivedAt    time.Time           `bson:"received_at,omitempty" json:"received_at,omitempty"`
+	BilledAt      time.Time           `bson:"billed_at,omitempty" json:"billed_at,omitempty"`
+	PayableID     primitive.ObjectID  `bson:"payable_id,omitempty" json:"payable_id,omitempty"`
+	CreatedAt     time.Time           `bson:"created_at" json:"created_at"`
+	UpdatedAt     time.Time           `bson:"updated_at" json:"updated_at"`
+}
+
+// Payable adalah utang usaha kepada supplier dari purchase order yang sudah diterima
+type Payable struct {
+	ID              primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
+	Owner           string             `bson:"owner,omitempty" json:"-"`
+	SupplierID      primitive.ObjectID `bson:"supplier_id" json:"supplier_id"`
+	SupplierName    string             `bson:"supplier_name" json:"supplier_name"`
+	PurchaseOrderID primitive.ObjectID `bson:"purchase_order_id" json:"purchase_order_id"`
+	InvoiceNumber   string             `bson:"invoice_number,omitempty" json:"invoice_number,omitempty"`
+	Amount          float64            `bson:"amount" json:"amount"`
+	PaidAmount      float64            `bson:"paid_amount" json:"paid_amount"`
+	Status          string             `bson:"status" json:"status"` // open, partial, paid
+	DueDate         time.Time          `bson:"due_date,omitempty" json:"due_date,omitempty"`
+	CreatedAt       time.Time          `bson:"created_at" json:"created_at"`
+	UpdatedAt       time.Time          `bson:"updated_at" json:"updated_at"`
+}
+
+// AuditLog adalah jejak perubahan data keuangan: siapa, kapan, dari IP mana, serta nilai sebelum dan sesudah
+type AuditLog struct {
+    ID        primitive.ObjectID     `bson:"_id,omitempty" json:"id,omitempty"`
+    Owner     string                 `bson:"owner" json:"-"`
+    Entity    string                 `bson:"entity" json:"entity"`       // product, sales, expense, customer, employee, report
+    EntityID  primitive.ObjectID     `bson:"entity_id" json:"entity_id"`
+    Action    string                 `bson:"action" json:"action"`       // create, update, delete, restore
+    Changes   []FieldChange          `bson:"changes,omitempty" json:"changes,omitempty"`
+    Before    map[string]interface{} `bson:"before,omitempty" json:"before,omitempty"`
+    After     map[string]interface{} `bson:"after,omitempty" json:"after,omitempty"`
+    User      string                 `bson:"user" json:"user"`           // Nomor telepon dari token login
+    Role      string                 `bson:"role,omitempty" json:"role,omitempty"`
+    IP        string                 `bson:"ip,omitempty" json:"ip,omitempty"`
+    CreatedAt time.Time              `bson:"createdAt" json:"createdAt"`
+}
+
+// FieldChange adalah perubahan satu field pada AuditLog
+type FieldChange struct {
+    Field  string      `bson:"field" json:"field"`
+    Before interface{} `bson:"before" json:"before"`
+    After  interface{} `bson:"after" json:"after"`
+}
+
+// AccountingPeriod adalah periode akuntansi bulanan atau tahunan milik toko, transaksi di periode tertutup tidak bisa diubah
+type AccountingPeriod struct {
+    ID               primitive.ObjectID  `bson:"_id,omitempty" json:"id,omitempty"`
+    Owner            string              `bson:"owner" json:"-"`
+    Period           string              `bson:"period" json:"period"` // YYYY-MM untuk bulan, YYYY untuk tahun
+    Type             string              `bson:"type" json:"type"`     // month atau year
+    Status           string              `bson:"status" json:"status"` // closed atau open
+    Start            time.Time           `bson:"start" json:"start"`
+    End              time.Time           `bson:"end" json:"end"`
+    Report           *LaporanAkuntan     `bson:"report,omitempty" json:"report,omitempty"`                     // Laba rugi saat periode ditutup
+    ClosingEntryID   *primitive.ObjectID `bson:"closing_entry_id,omitempty" json:"closing_entry_id,omitempty"` // Jurnal penutup tahun
+    RetainedEarnings float64             `bson:"retained_earnings,omitempty" json:"retained_earnings,omitempty"` // Laba bersih yang dipindah ke Laba Ditahan
+    ClosedAt         time.Time           `bson:"closed_at,omitempty" json:"closed_at,omitempty"`
+    ClosedBy         string              `bson:"closed_by,omitempty" json:"closed_by,omitempty"`
+    ReopenedAt       time.Time           `bson:"reopened_at,omitempty" json:"reopened_at,omitempty"`
+    ReopenedBy       string              `bson:"reopened_by,omitempty" json:"reopened_by,omitempty"`
+}
+
+// RecurringExpense adalah template pengeluaran rutin seperti sewa dan listrik yang dibuat otomatis oleh cron
+type RecurringExpense struct {
+    ID            primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
+    Owner         string             `bson:"owner,omitempty" json:"-"`
+    ExpenseName   string             `bson:"expense_name" json:"expense_name" validate:"required"`
+    Amount        float64            `bson:"amount" json:"amount" validate:"positive"`
+    TaxAmount     float64            `bson:"tax_amount,omitempty" json:"tax_amount,omitempty" validate:"nonnegative"`
+    Category      string             `bson:"category" json:"category"`
+    PaymentMethod string             `bson:"payment_method" json:"payment_method"`
+    Notes         string             `bson:"notes,omitempty" json:"notes,omitempty"`
+    Frequency     string             `bson:"frequency" json:"frequency" validate:"oneof=daily weekly monthly yearly"` // daily, weekly, monthly atau yearly
+    StartDate     time.Time          `bson:"start_date" json:"start_date"`
+    EndDate       time.Time          `bson:"end_date,omitempty" json:"end_date,omitempty"` // Kosong berarti tanpa batas
+    NextDate      time.Time          `bson:"next_date" json:"next_date"`                   // Jadwal berikutnya yang belum dibuat
+    Occurrence    int                `bson:"occurrence" json:"occurrence"`                 // Urutan jadwal berikutnya dihitung dari StartDate
+    SkipDates     []time.Time        `bson:"skip_dates,omitempty" json:"skip_dates,omitempty"`
+    NotifyPhone   string             `bson:"notify_phone,omitempty" json:"notify_phone,omitempty"` // Kosong berarti nomor owner
+    Active        bool               `bson:"active" json:"active"`
+    LastRunAt     time.Time          `bson:"last_run_at,omitempty" json:"last_run_at,omitempty"`
+    CreatedAt     time.Time          `bson:"createdAt" json:"createdAt"`
+    UpdatedAt     time.Time          `bson:"updatedAt" json:"updatedAt"`
+}
@@ -81,6 +81,7 @@ type Userdomyikado struct {
 	JumlahAntrian        int                `json:"jumlahantrian,omitempty" bson:"jumlahantrian,omitempty"`
 	Password             string             `json:"password,omitempty" bson:"password,omitempty"`
 	Role                 string             `json:"role,omitempty" bson:"role,omitempty"`
+	Owner                string             `json:"owner,omitempty" bson:"owner,omitempty"` // Nomor telepon pemilik toko untuk role cashier dan accountant
 	// Address              []Address          `json:"address,omitempty" bson:"address,omitempty"`
 }
 
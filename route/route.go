@@ -1,12 +1,38 @@
 package route
 
 import (
+	"net"
 	"net/http"
+	"sync"
+	"time"
 
 	"github.com/gocroot/config"
 	"github.com/gocroot/controller"
-	"github.com/gocroot/helper/at"
-	// "github.com/gocroot/helper/at"
+	"github.com/gocroot/helper/router"
+	"github.com/gocroot/helper/tenant"
+	"golang.org/x/time/rate"
+)
+
+// Middleware route: auth memverifikasi login akun penjual, tenant membatasi akses sesuai role,
+// limit membatasi percobaan login dan pendaftaran per IP, cron hanya menerima scheduler dengan CRON_SECRET
+var (
+	auth   = router.Middleware{Name: "auth", Wrap: controller.RequireLogin}
+	cron   = router.Middleware{Name: "cron", Wrap: controller.RequireCron}
+	role   = router.Middleware{Name: "tenant", Wrap: controller.RequireRole}
+	seller = []router.Middleware{auth, role}
+	limit  = router.RateLimit(rate.Every(6*time.Second), 5, trustedProxies())
+)
+
+// trustedProxies membaca proxy tepercaya dari konfigurasi, config.MustLoad di init sudah memvalidasi isinya
+func trustedProxies() []*net.IPNet {
+	settings, _ := config.Load()
+	proxies, _ := router.ParseProxies(settings.TrustedProxies)
+	return proxies
+}
+
+var (
+	once    sync.Once
+	handler *router.Router
 )
 
 func URL(w http.ResponseWriter, r *http.Request) {
@@ -15,278 +41,244 @@ func URL(w http.ResponseWriter, r *http.Request) {
 	}
 	config.SetEnv()
 
-	var method, path string = r.Method, r.URL.Path
-	switch {
-	case method == "GET" && path == "/":
-		controller.GetHome(w, r)
-	//gis
-	case method == "POST" && path == "/data/gis/lokasi":
-		controller.GetRegion(w, r)
-	//chat bot inbox
-	case method == "POST" && at.URLParam(path, "/webhook/nomor/:nomorwa"):
-		controller.PostInboxNomor(w, r)
-	//masking list nmor official
-	case method == "GET" && path == "/data/phone/all":
-		controller.GetBotList(w, r)
-	//akses data helpdesk layanan user
-	case method == "GET" && path == "/data/user/helpdesk/all":
-		controller.GetHelpdeskAll(w, r)
-	case method == "GET" && path == "/data/user/helpdesk/masuk":
-		controller.GetLatestHelpdeskMasuk(w, r)
-	case method == "GET" && path == "/data/user/helpdesk/selesai":
-		controller.GetLatestHelpdeskSelesai(w, r)
-	//pamong desa data from api
-	case method == "GET" && path == "/data/lms/user":
-		controller.GetDataUserFromApi(w, r)
-	//simpan testimoni dari pamong desa lms api
-	case method == "POST" && path == "/data/lms/testi":
-		controller.PostTestimoni(w, r)
-		//get random 4 testi
-	case method == "GET" && path == "/data/lms/random/testi":
-		controller.GetRandomTesti4(w, r)
-	//mendapatkan data sent item
-	case method == "GET" && at.URLParam(path, "/data/peserta/sent/:id"):
-		controller.GetSentItem(w, r)
-	//simpan feedback unsubs user
-	case method == "POST" && path == "/data/peserta/unsubscribe":
-		controller.PostUnsubscribe(w, r)
-	//generate token linked device
-	case method == "PUT" && path == "/data/user":
-		controller.PutTokenDataUser(w, r)
-	//Menambhahkan data nomor sender untuk broadcast
-	case method == "PUT" && path == "/data/sender":
-		controller.PutNomorBlast(w, r)
-	//mendapatkan data list nomor sender untuk broadcast
-	case method == "GET" && path == "/data/sender":
-		controller.GetDataSenders(w, r)
-	//mendapatkan data list nomor sender yang kena blokir dari broadcast
-	case method == "GET" && path == "/data/blokir":
-		controller.GetDataSendersTerblokir(w, r)
-	//mendapatkan data rekap pengiriman wa blast
-	case method == "GET" && path == "/data/rekap":
-		controller.GetRekapBlast(w, r)
-	//mendapatkan data faq
-	case method == "GET" && at.URLParam(path, "/data/faq/:id"):
-		controller.GetFAQ(w, r)
-	//legacy
-	case method == "PUT" && path == "/data/user/task/doing":
-		controller.PutTaskUser(w, r)
-	case method == "GET" && path == "/data/user/task/done":
-		controller.GetTaskDone(w, r)
-	case method == "POST" && path == "/data/user/task/done":
-		controller.PostTaskUser(w, r)
-	case method == "GET" && path == "/data/pushrepo/kemarin":
-		controller.GetYesterdayDistincWAGroup(w, r)
-
-	//helpdesk
-	//mendapatkan data tiket
-	case method == "GET" && at.URLParam(path, "/data/tiket/closed/:id"):
-		controller.GetClosedTicket(w, r)
-	//simpan feedback tiket user
-	case method == "POST" && path == "/data/tiket/rate":
-		controller.PostMasukanTiket(w, r)
-		// order
-	case method == "POST" && at.URLParam(path, "/data/order/:namalapak"):
-		controller.HandleOrder(w, r)
-	//user data
-	case method == "GET" && path == "/data/user":
-		controller.GetDataUser(w, r)
-	//user pendaftaran
-	// case method == "POST" && path == "/auth/register/users": //mendapatkan email gmail
-	// 	controller.RegisterGmailAuth(w, r)
-	case method == "POST" && path == "/data/user":
-		controller.PostDataUser(w, r)
-	case method == "POST" && path == "/upload/profpic": //upload gambar profile
-	// 	controller.UploadProfilePictureHandler(w, r)
-	// case method == "POST" && path == "/data/user/bio":
-	// 	controller.PostDataBioUser(w, r)
-		/* 	case method == "POST" && at.URLParam(path, "/data/user/wa/:nomorwa"):
-		controller.PostDataUserFromWA(w, r) */
-	//data proyek
-	case method == "GET" && path == "/data/proyek":
-		controller.GetDataProject(w, r)
-	// case method == "GET" && path == "/data/proyek/approved": //akses untuk manager
-	// 	controller.GetEditorApprovedProject(w, r)
-	case method == "POST" && path == "/data/proyek":
-		controller.PostDataProject(w, r)
-	case method == "PUT" && path == "/data/metadatabuku":
-	// 	controller.PutMetaDataProject(w, r)
-	// case method == "PUT" && path == "/data/proyek/publishbuku": //publish buku isbn by manager
-	// 	controller.PutPublishProject(w, r)
-	case method == "PUT" && path == "/data/proyek":
-		controller.PutDataProject(w, r)
-	case method == "DELETE" && path == "/data/proyek":
-		controller.DeleteDataProject(w, r)
-	case method == "GET" && path == "/data/proyek/anggota":
-		controller.GetDataMemberProject(w, r)
-	case method == "GET" && path == "/data/proyek/editor":
-	// 	controller.GetDataEditorProject(w, r)
-	// case method == "DELETE" && path == "/data/proyek/anggota":
-	// 	controller.DeleteDataMemberProject(w, r)
-	// case method == "POST" && path == "/data/proyek/anggota":
-	// 	controller.PostDataMemberProject(w, r)
-	// case method == "POST" && path == "/data/proyek/editor": //set editor oleh owner
-	// 	controller.PostDataEditorProject(w, r)
-	// case method == "PUT" && path == "/data/proyek/editor": //set approved oleh editor
-	// 	controller.PUtApprovedEditorProject(w, r)
-	// //upload cover,draft,pdf,sampul buku project
-	// case method == "POST" && at.URLParam(path, "/upload/coverbuku/:projectid"):
-	// 	controller.UploadCoverBukuWithParamFileHandler(w, r)
-	// case method == "POST" && at.URLParam(path, "/upload/draftbuku/:projectid"):
-	// 	controller.UploadDraftBukuWithParamFileHandler(w, r)
-	// case method == "POST" && at.URLParam(path, "/upload/draftpdfbuku/:projectid"):
-	// 	controller.UploadDraftBukuPDFWithParamFileHandler(w, r)
-	// case method == "POST" && at.URLParam(path, "/upload/sampulpdfbuku/:projectid"):
-	// 	controller.UploadSampulBukuPDFWithParamFileHandler(w, r)
-	// case method == "POST" && at.URLParam(path, "/upload/spk/:projectid"):
-	// 	controller.UploadSPKPDFWithParamFileHandler(w, r)
-	// case method == "POST" && at.URLParam(path, "/upload/spi/:projectid"):
-	// 	controller.UploadSPIPDFWithParamFileHandler(w, r)
-	// case method == "GET" && at.URLParam(path, "/download/draft/:path"): //downoad file draft
-	// 	controller.AksesFileRepoDraft(w, r)
-	// case method == "POST" && path == "/data/proyek/katalog": //post blog katalog
-	// 	controller.PostKatalogBuku(w, r)
-	// case method == "GET" && at.URLParam(path, "/download/dokped/spk/:namaproject"): //base64 namaproject
-	// 	controller.GetFileDraftSPK(w, r)
-	// case method == "GET" && at.URLParam(path, "/download/dokped/spkt/:namaproject"): //base64 namaproject
-	// 	controller.GetFileDraftSPKT(w, r)
-	// case method == "GET" && at.URLParam(path, "/download/dokped/spi/:path"): //base64 path sampul
-	// 	controller.GetFileDraftSPI(w, r)
-
-	case method == "POST" && path == "/data/proyek/menu":
-		controller.PostDataMenuProject(w, r)
-	case method == "POST" && path == "/approvebimbingan":
-		controller.ApproveBimbinganbyPoin(w, r)
-	case method == "DELETE" && path == "/data/proyek/menu":
-		controller.DeleteDataMenuProject(w, r)
-	case method == "POST" && path == "/notif/ux/postlaporan":
-		controller.PostLaporan(w, r)
-	case method == "POST" && path == "/notif/ux/postfeedback":
-		controller.PostFeedback(w, r)
+	once.Do(func() { handler = New() })
+	handler.ServeHTTP(w, r)
+}
 
-	case method == "POST" && path == "/notif/ux/postmeeting":
-		controller.PostMeeting(w, r)
-	case method == "POST" && at.URLParam(path, "/notif/ux/postpresensi/:id"):
-		controller.PostPresensi(w, r)
-	case method == "POST" && at.URLParam(path, "/notif/ux/posttasklists/:id"):
-		controller.PostTaskList(w, r)
-	case method == "POST" && at.URLParam(path, "/webhook/nomor/:nomorwa"):
-		controller.PostInboxNomor(w, r)
-	// LMS
-	case method == "GET" && path == "/lms/refresh/cookie":
-		controller.RefreshLMSCookie(w, r)
-	case method == "GET" && path == "/lms/count/user":
-		controller.GetCountDocUser(w, r)
-	// Google Auth
-	case method == "POST" && path == "/auth/users":
-		controller.Auth(w, r)
-	case method == "POST" && path == "/auth/login":
-		controller.GeneratePasswordHandler(w, r)
-	case method == "POST" && path == "/auth/verify":
-		controller.VerifyPasswordHandler(w, r)
-	case method == "POST" && path == "/auth/resend":
-		controller.ResendPasswordHandler(w, r)
-				// Auth FORM
-	case method == "POST" && path == "/auth/regis":
-		controller.RegisterAkunPenjual(w, r)
-	case method == "POST" && path == "/auth/login/form":
-		controller.LoginAkunPenjual(w, r)
-	// case method == "GET" && path == "/auth/menu":
-	// 	controller.GetMenu(w, r)
-		// Produk
-	case method == "POST" && path == "/products":
-		controller.CreateProduct(w, r)
-	case method == "GET" && path == "/products":
-		controller.GetProducts(w, r)
-	case method == "GET" && path == "/product-id":
-		controller.GetProductByID(w, r)
-	case method == "PUT" && path == "/products":
-		controller.UpdateProduct(w, r)
-	case method == "DELETE" && path == "/products":
-		controller.DeleteProduct(w, r)
-	case method == "GET" && path == "/products-export-csv":
-		controller.ExportProductsToCSV(w, r)
-				// Expense
-	case method == "POST" && path == "/expense":
-		controller.CreateExpenseTransaction(w, r)
-	case method == "GET" && path == "/expenses":
-		controller.GetExpenses(w, r)
-	case method == "GET" && path == "/expense":
-		controller.GetExpenseByID(w, r)
-	case method == "PUT" && path == "/expense":
-		controller.UpdateExpense(w, r)
-	case method == "DELETE" && path == "/expense":
-		controller.DeleteExpense(w, r)
-	case method == "GET" && path == "/expense-export-csv":
-		controller.ExportProductsToCSV(w, r)
-	// Sales
-	case method == "POST" && path == "/sales":
-		controller.CreateSalesTransaction(w, r)
-	case method == "GET" && path == "/sales":
-		controller.GetSalesTransactions(w, r)
-	case method == "GET" && path == "/sales/{id}":
-		controller.GetSalesTransactionByID(w, r)
-	case method == "PUT" && path == "/sales/{id}":
-		controller.UpdateSalesTransaction(w, r)
-	case method == "DELETE" && path == "/sales/{id}":
-		controller.DeleteSalesTransaction(w, r)
-	case method == "GET" && path == "/sales-export-csv":
-		controller.ExportProductsToCSV(w, r)
-	// Pelanggan
-	case method == "POST" && path == "/customers":
-		controller.CreateCustomer(w, r)
-	case method == "GET" && path == "/customers":
-		controller.GetCustomers(w, r)
-	case method == "GET" && path == "/customer-id":
-		controller.GetCustomerByID(w, r)
-	case method == "PUT" && path == "/customers":
-		controller.UpdateCustomer(w, r)
-	case method == "DELETE" && path == "/customers":
-		controller.DeleteCustomer(w, r)
-	// Laporan Akuntan
-	case method == "POST" && path == "/reports":
-		controller.CreateFinancialReport(w, r)
-	case method == "GET" && path == "/reports":
-		controller.GetFinancialReports(w, r)
-	case method == "GET" && path == "/report-id":
-		controller.GetFinancialReportByID(w, r)
-	case method == "DELETE" && path == "/reports":
-		controller.DeleteFinancialReport(w, r)
-			// Pelanggan
-	case method == "POST" && path == "/employee":
-		controller.CreateEmployee(w, r)
-	case method == "GET" && path == "/employees":
-		controller.GetAllEmployees(w, r)
-	case method == "GET" && path == "/employee":
-		controller.GetEmployeeByID(w, r)
-	case method == "PUT" && path == "/employee":
-		controller.UpdateEmployee(w, r)
-	case method == "DELETE" && path == "/employee":
-		controller.DeleteEmployee(w, r)
-				// Produk
-	case method == "POST" && path == "/category":
-		controller.CreateCategory(w, r)
-	case method == "GET" && path == "/categories":
-		controller.GetAllCategory(w, r)
-	case method == "GET" && path == "/category":
-		controller.GetCategoryByID(w, r)
-	case method == "PUT" && path == "/category":
-		controller.UpdateCategory(w, r)
-	case method == "DELETE" && path == "/category":
-		controller.DeleteCategory(w, r)
-		//register
-	case method == "POST" && path == "/register":
-		controller.RegisterAkunPenjual(w, r)
-	case method == "POST" && path == "/login":
-		controller.LoginAkunPenjual(w, r)
-		//Geo
-	case method == "POST" && path == "/roads":
-		controller.GetRoads(w, r)
+// New menyusun router dari tabel Routes. Endpoint akuntansi yang lupa dipasang middleware auth membuat panic saat start.
+func New() *router.Router {
+	rt := router.New(controller.NotFound, router.Logging)
+	rt.Add(Routes()...)
+	for _, info := range rt.Routes() {
+		if tenant.IsProtected(info.Path) && !info.Uses(auth.Name) {
+			panic("route: " + info.Method + " " + info.Path + " wajib memakai middleware auth")
+		}
+	}
+	return rt
+}
 
-	case method == "POST" && path == "/region":
-		controller.GetRegion(w, r)
-	// Google Auth
-	default:
-		controller.NotFound(w, r)
+// Routes adalah tabel semua endpoint, endpoint akuntansi wajib login akun penjual dan dibatasi sesuai role
+func Routes() []router.Route {
+	routes := []router.Route{
+		router.GET("/", controller.GetHome),
+		// metrik prometheus: latensi dan error per route, POST atapi, perintah mongo
+		router.GET("/metrics", controller.GetMetrics),
+		// gis
+		router.POST("/data/gis/lokasi", controller.GetRegion),
+		// job harian: refresh token WhatsApp, rekap, pengingat piutang dan pengeluaran rutin, jalan setiap jam 3 pagi
+		router.POST("/cron/harian", controller.GetNewToken, cron),
+		// chat bot inbox
+		router.POST("/webhook/nomor/{nomorwa}", controller.PostInboxNomor),
+		// masking list nmor official
+		router.GET("/data/phone/all", controller.GetBotList),
+		// akses data helpdesk layanan user
+		router.GET("/data/user/helpdesk/all", controller.GetHelpdeskAll),
+		router.GET("/data/user/helpdesk/masuk", controller.GetLatestHelpdeskMasuk),
+		router.GET("/data/user/helpdesk/selesai", controller.GetLatestHelpdeskSelesai),
+		// pamong desa data from api
+		router.GET("/data/lms/user", controller.GetDataUserFromApi),
+		// simpan testimoni dari pamong desa lms api
+		router.POST("/data/lms/testi", controller.PostTestimoni),
+		// get random 4 testi
+		router.GET("/data/lms/random/testi", controller.GetRandomTesti4),
+		// mendapatkan data sent item
+		router.GET("/data/peserta/sent/{id}", controller.GetSentItem),
+		// simpan feedback unsubs user
+		router.POST("/data/peserta/unsubscribe", controller.PostUnsubscribe),
+		// generate token linked device
+		router.PUT("/data/user", controller.PutTokenDataUser),
+		// Menambhahkan data nomor sender untuk broadcast
+		router.PUT("/data/sender", controller.PutNomorBlast),
+		// mendapatkan data list nomor sender untuk broadcast
+		router.GET("/data/sender", controller.GetDataSenders),
+		// mendapatkan data list nomor sender yang kena blokir dari broadcast
+		router.GET("/data/blokir", controller.GetDataSendersTerblokir),
+		// mendapatkan data rekap pengiriman wa blast
+		router.GET("/data/rekap", controller.GetRekapBlast),
+		// mendapatkan data faq
+		router.GET("/data/faq/{id}", controller.GetFAQ),
+		// legacy
+		router.PUT("/data/user/task/doing", controller.PutTaskUser),
+		router.GET("/data/user/task/done", controller.GetTaskDone),
+		router.POST("/data/user/task/done", controller.PostTaskUser),
+		router.GET("/data/pushrepo/kemarin", controller.GetYesterdayDistincWAGroup),
+		// helpdesk
+		// mendapatkan data tiket
+		router.GET("/data/tiket/closed/{id}", controller.GetClosedTicket),
+		// simpan feedback tiket user
+		router.POST("/data/tiket/rate", controller.PostMasukanTiket),
+		// order
+		router.POST("/data/order/{namalapak}", controller.HandleOrder),
+		// user data
+		router.GET("/data/user", controller.GetDataUser),
+		// user pendaftaran
+		router.POST("/data/user", controller.PostDataUser),
+		// data proyek
+		router.GET("/data/proyek", controller.GetDataProject),
+		router.POST("/data/proyek", controller.PostDataProject),
+		router.PUT("/data/proyek", controller.PutDataProject),
+		router.DELETE("/data/proyek", controller.DeleteDataProject),
+		router.GET("/data/proyek/anggota", controller.GetDataMemberProject),
+		router.POST("/data/proyek/menu", controller.PostDataMenuProject),
+		router.POST("/approvebimbingan", controller.ApproveBimbinganbyPoin),
+		router.DELETE("/data/proyek/menu", controller.DeleteDataMenuProject),
+		router.POST("/notif/ux/postlaporan", controller.PostLaporan),
+		router.POST("/notif/ux/postfeedback", controller.PostFeedback),
+		router.POST("/notif/ux/postmeeting", controller.PostMeeting),
+		router.POST("/notif/ux/postpresensi/{id}", controller.PostPresensi),
+		router.POST("/notif/ux/posttasklists/{id}", controller.PostTaskList),
+		// LMS
+		router.GET("/lms/refresh/cookie", controller.RefreshLMSCookie),
+		router.GET("/lms/count/user", controller.GetCountDocUser),
+		// Google Auth
+		router.POST("/auth/users", controller.Auth),
+		router.POST("/auth/login", controller.GeneratePasswordHandler, limit),
+		router.POST("/auth/verify", controller.VerifyPasswordHandler, limit),
+		router.POST("/auth/resend", controller.ResendPasswordHandler, limit),
+		// Auth FORM
+		router.POST("/auth/regis", controller.RegisterAkunPenjual, limit),
+		router.POST("/auth/login/form", controller.LoginAkunPenjual, limit),
 	}
+	routes = append(routes, router.With(seller,
+		// Produk
+		router.POST("/products", controller.CreateProduct),
+		router.GET("/products", controller.GetProducts),
+		router.GET("/product-id", controller.GetProductByID),
+		router.PUT("/products", controller.UpdateProduct),
+		router.DELETE("/products", controller.DeleteProduct),
+		router.GET("/products-export-csv", controller.ExportProductsToCSV),
+		router.POST("/products/stock", controller.CreateStockMovement),
+		router.POST("/products/{id:objectid}/stock", controller.CreateStockMovement),
+		router.GET("/products/{id:objectid}/stock", controller.GetStockMovements),
+		router.GET("/stock-movements", controller.GetStockMovements),
+		// Expense
+		router.POST("/expense", controller.CreateExpenseTransaction),
+		router.GET("/expenses", controller.GetExpenses),
+		router.GET("/expense", controller.GetExpenseByID),
+		router.PUT("/expense", controller.UpdateExpense),
+		router.DELETE("/expense", controller.DeleteExpense),
+		router.GET("/expense-export-csv", controller.ExportExpensesToCSV),
+		// Kasir
+		router.POST("/pos/checkout", controller.Checkout),
+		router.POST("/pos/quote", controller.QuoteCheckout),
+		router.GET("/promos", controller.GetPromos),
+		router.POST("/promos", controller.CreatePromo),
+		router.PUT("/promos", controller.UpdatePromo),
+		router.DELETE("/promos", controller.DeletePromo),
+		// Sales
+		router.POST("/sales", controller.CreateSalesTransaction),
+		router.GET("/sales", controller.GetSalesTransactions),
+		router.GET("/sales/{id:objectid}", controller.GetSalesTransactionByID),
+		router.PUT("/sales/{id:objectid}", controller.UpdateSalesTransaction),
+		router.DELETE("/sales/{id:objectid}", controller.DeleteSalesTransaction),
+		router.GET("/sales/invoice", controller.GetSalesInvoice),
+		router.POST("/sales/invoice/send", controller.SendSalesInvoice),
+		router.GET("/invoice-templates", controller.GetInvoiceTemplates),
+		router.PUT("/invoice-templates", controller.UpdateInvoiceTemplate),
+		router.GET("/store-profile", controller.GetStoreProfile),
+		router.PUT("/store-profile", controller.UpdateStoreProfile),
+		router.GET("/customers-export-csv", controller.ExportCustomersToCSV),
+		router.GET("/export", controller.ExportData),
+		router.POST("/import/products", controller.ImportProducts),
+		router.POST("/import/customers", controller.ImportCustomers),
+		router.POST("/import/opening-balances", controller.ImportOpeningBalances),
+		router.POST("/tenant/staff", controller.CreateStaff),
+		router.GET("/tenant/staff", controller.GetStaff),
+		router.POST("/sales/returns", controller.CreateSalesReturn),
+		router.GET("/sales/returns", controller.GetSalesReturns),
+		router.GET("/sales-return-id", controller.GetSalesReturnByID),
+		router.GET("/sales/returns/note", controller.GetReturnNotePDF),
+		router.POST("/sales/payments", controller.CreateSalesPayment),
+		router.GET("/sales/payments", controller.GetSalesPayments),
+		router.GET("/sales-export-csv", controller.ExportSalesToCSV),
+		// Audit log dan pemulihan data terhapus
+		router.GET("/audit", controller.GetAuditLogs),
+		router.GET("/audit/deleted", controller.GetDeletedRecords),
+		router.POST("/audit/restore", controller.RestoreRecord),
+		// Pengeluaran rutin
+		router.GET("/recurring-expenses", controller.GetRecurringExpenses),
+		router.POST("/recurring-expenses", controller.CreateRecurringExpense),
+		router.PUT("/recurring-expenses", controller.UpdateRecurringExpense),
+		router.DELETE("/recurring-expenses", controller.DeleteRecurringExpense),
+		router.GET("/recurring-expenses/upcoming", controller.GetUpcomingRecurringExpenses),
+		router.POST("/recurring-expenses/skip", controller.SkipRecurringExpense),
+		// Tutup buku periode akuntansi
+		router.GET("/periods", controller.GetAccountingPeriods),
+		router.POST("/periods/close", controller.CloseAccountingPeriod),
+		router.POST("/periods/reopen", controller.ReopenAccountingPeriod),
+		// Pelanggan
+		router.POST("/customers", controller.CreateCustomer),
+		router.GET("/customers", controller.GetCustomers),
+		router.GET("/customer-id", controller.GetCustomerByID),
+		router.PUT("/customers", controller.UpdateCustomer),
+		router.DELETE("/customers", controller.DeleteCustomer),
+		// Supplier dan pembelian
+		router.POST("/suppliers", controller.CreateSupplier),
+		router.GET("/suppliers", controller.GetSuppliers),
+		router.GET("/supplier-id", controller.GetSupplierByID),
+		router.PUT("/suppliers", controller.UpdateSupplier),
+		router.DELETE("/suppliers", controller.DeleteSupplier),
+		router.POST("/purchase-orders", controller.CreatePurchaseOrder),
+		router.GET("/purchase-orders", controller.GetPurchaseOrders),
+		router.GET("/purchase-order-id", controller.GetPurchaseOrderByID),
+		router.PUT("/purchase-orders", controller.UpdatePurchaseOrder),
+		router.PUT("/purchase-orders/status", controller.UpdatePurchaseOrderStatus),
+		router.DELETE("/purchase-orders", controller.DeletePurchaseOrder),
+		router.GET("/payables", controller.GetPayables),
+		router.POST("/payables/pay", controller.PayPayable),
+		// Laporan Akuntan
+		router.POST("/reports", controller.CreateFinancialReport),
+		router.GET("/reports", controller.GetFinancialReports),
+		router.GET("/reports/profit-loss", controller.GetProfitLoss),
+		router.GET("/reports/ar-aging", controller.GetReceivableAging),
+		router.GET("/reports/balance-sheet", controller.GetBalanceSheet),
+		router.GET("/reports/cash-flow", controller.GetCashFlow),
+		router.GET("/reports/ppn", controller.GetPPNReport),
+		router.GET("/reports/ppn/efaktur", controller.ExportEFaktur),
+		// Payroll
+		router.GET("/payroll/preview", controller.PreviewPayroll),
+		router.POST("/payroll/runs", controller.CreatePayrollRun),
+		router.GET("/payroll/runs", controller.GetPayrollRuns),
+		router.GET("/payroll-id", controller.GetPayrollRunByID),
+		router.DELETE("/payroll/runs", controller.DeletePayrollRun),
+		router.GET("/payroll/payslip", controller.GetPayslipPDF),
+		// Kehadiran pegawai
+		router.GET("/attendance", controller.GetAttendance),
+		router.GET("/attendance/summary", controller.GetAttendanceSummary),
+		router.GET("/attendance/locations", controller.GetAttendanceLocations),
+		router.POST("/attendance/locations", controller.CreateAttendanceLocation),
+		router.DELETE("/attendance/locations", controller.DeleteAttendanceLocation),
+		router.POST("/reports/snapshots", controller.CreateStatementSnapshot),
+		router.GET("/reports/snapshots", controller.GetStatementSnapshots),
+		router.GET("/reports/snapshots/compare", controller.CompareStatementSnapshot),
+		router.GET("/report-id", controller.GetFinancialReportByID),
+		router.DELETE("/reports", controller.DeleteFinancialReport),
+		// Buku besar
+		router.GET("/accounts", controller.GetAccounts),
+		router.POST("/accounts", controller.CreateAccount),
+		router.POST("/accounts/default", controller.InitDefaultAccounts),
+		router.GET("/journals", controller.GetJournalEntries),
+		router.POST("/journals", controller.CreateJournalEntry),
+		router.GET("/trial-balance", controller.GetTrialBalance),
+		// Pegawai
+		router.POST("/employee", controller.CreateEmployee),
+		router.GET("/employees", controller.GetAllEmployees),
+		router.GET("/employee", controller.GetEmployeeByID),
+		router.PUT("/employee", controller.UpdateEmployee),
+		router.DELETE("/employee", controller.DeleteEmployee),
+		// Kategori
+		router.POST("/category", controller.CreateCategory),
+		router.GET("/categories", controller.GetAllCategory),
+		router.GET("/category", controller.GetCategoryByID),
+		router.PUT("/category", controller.UpdateCategory),
+		router.DELETE("/category", controller.DeleteCategory),
+	)...)
+	return append(routes,
+		// register
+		router.POST("/register", controller.RegisterAkunPenjual, limit),
+		router.POST("/login", controller.LoginAkunPenjual, limit),
+		// Geo
+		router.POST("/roads", controller.GetRoads),
+		router.POST("/region", controller.GetRegion),
+	)
 }
@@ -0,0 +1,33 @@
+// Command backfillowner mengisi field owner pada data lama yang dibuat sebelum data dipisah per toko.
+// Semua data tanpa pemilik diberikan ke satu akun penjual dari LEGACY_OWNER dan perintah ini
+// menolak berjalan lagi setelah ada data yang memiliki pemilik.
+package main
+
+import (
+	"log/slog"
+	"os"
+
+	"github.com/gocroot/config"
+	"github.com/gocroot/helper/logger"
+	"github.com/gocroot/helper/tenant"
+)
+
+func main() {
+	s := config.MustLoad()
+	logger.SetLevel(s.LogLevel)
+	if s.LegacyOwner == "" {
+		slog.Error("backfill dibatalkan", "error", config.EnvVarLegacy+" belum diisi")
+		os.Exit(1)
+	}
+	db := config.Mongoconn()
+	if db == nil {
+		slog.Error("backfill dibatalkan", "error", "koneksi mongo gagal")
+		os.Exit(1)
+	}
+	claimed, err := tenant.Backfill(db, s.LegacyOwner)
+	if err != nil {
+		slog.Error("backfill gagal", "owner", s.LegacyOwner, "claimed", claimed, "error", err)
+		os.Exit(1)
+	}
+	slog.Info("backfill selesai", "owner", s.LegacyOwner, "claimed", claimed)
+}
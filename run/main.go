@@ -3,10 +3,13 @@ package main
 import (
 	"net/http"
 
+	"github.com/gocroot/config"
+	"github.com/gocroot/helper/logger"
 	"github.com/gocroot/route"
 )
 
 func main() {
+	logger.SetLevel(config.MustLoad().LogLevel)
 	http.HandleFunc("/", route.URL)
 	http.ListenAndServe(":8080", nil)
 }
\ No newline at end of file